@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupConversationRoutes sets up the inbound WhatsApp webhook, which providers call without
+// a login and which checks signatures instead, and the customer conversation log
+func SetupConversationRoutes(router *gin.Engine, conversationHandler *handlers.ConversationHandler) {
+	webhooks := router.Group("/api/v1/webhooks")
+	{
+		webhooks.GET("/whatsapp", conversationHandler.VerifyWebhook)
+		webhooks.POST("/whatsapp", conversationHandler.ReceiveWebhook)
+	}
+
+	router.GET("/api/v1/customers/:id/conversation", middleware.RequireAuth(), conversationHandler.GetConversation)
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCreditRoutes(router *gin.Engine, creditHandler *handlers.CreditHandler) {
+	// Holds, overrides and releases decide who gets credit, so only managers make them
+	approver := middleware.RequireRole(domain.RoleAdmin, domain.RoleManager)
+
+	v1 := router.Group("/api/v1", middleware.RequireAuth())
+	{
+		customers := v1.Group("/customers")
+		{
+			customers.GET("/:id/credit", creditHandler.GetCreditStatus)
+			customers.GET("/:id/credit/history", creditHandler.GetCreditHistory)
+			customers.POST("/:id/credit/hold", approver, creditHandler.PlaceHold)
+			customers.DELETE("/:id/credit/hold", approver, creditHandler.ReleaseHold)
+			customers.POST("/:id/credit/overrides", approver, creditHandler.GrantOverride)
+		}
+
+		credit := v1.Group("/credit")
+		{
+			credit.GET("/blocked-orders", creditHandler.GetBlockedOrders)
+			credit.POST("/blocked-orders/:id/release", approver, creditHandler.ReleaseOrder)
+			credit.POST("/blocked-orders/:id/reject", approver, creditHandler.RejectOrder)
+			credit.DELETE("/overrides/:id", approver, creditHandler.RevokeOverride)
+			credit.POST("/evaluate", creditHandler.EvaluateCredit)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCurrencyRoutes(router *gin.Engine, currencyHandler *handlers.CurrencyHandler) {
+	// Rates price every foreign-currency order and payment, so only managers set them
+	manager := middleware.RequireRole(domain.RoleAdmin, domain.RoleManager)
+
+	v1 := router.Group("/api/v1", middleware.RequireAuth())
+	{
+		v1.GET("/currencies", currencyHandler.GetCurrencies)
+
+		rates := v1.Group("/exchange-rates")
+		{
+			rates.GET("", currencyHandler.GetExchangeRates)
+			rates.POST("", manager, currencyHandler.SetExchangeRate)
+			rates.DELETE("/:id", manager, currencyHandler.DeleteExchangeRate)
+		}
+	}
+}
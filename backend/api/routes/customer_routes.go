@@ -2,6 +2,7 @@ package routes
 
 import (
 	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +14,7 @@ func SetupCustomerRoutes(router *gin.Engine, customerHandler *handlers.CustomerH
 		customers := v1.Group("/customers")
 		{
 			customers.GET("", customerHandler.GetCustomers)
+			customers.GET("/duplicates", customerHandler.FindDuplicates)
 			customers.GET("/:id", customerHandler.GetCustomer)
 			customers.POST("", customerHandler.CreateCustomer)
 			customers.PUT("/:id", customerHandler.UpdateCustomer)
@@ -26,6 +28,42 @@ func SetupCustomerRoutes(router *gin.Engine, customerHandler *handlers.CustomerH
 			// Documents
 			customers.GET("/:id/documents", customerHandler.GetDocuments)
 			customers.POST("/:id/documents", customerHandler.UploadDocument)
+			customers.GET("/:id/documents/:docId/download", middleware.RequireAuth(), customerHandler.DownloadDocument)
+			customers.GET("/:id/documents/:docId/link", middleware.RequireAuth(), customerHandler.GetDocumentLink)
+			customers.GET("/:id/documents/:docId/thumbnail", middleware.RequireAuth(), customerHandler.GetDocumentThumbnail)
+			customers.GET("/:id/documents/:docId/versions", customerHandler.GetDocumentVersions)
+			customers.POST("/:id/documents/:docId/versions", customerHandler.ReplaceDocument)
+			customers.PUT("/:id/documents/:docId", customerHandler.UpdateDocument)
+			customers.DELETE("/:id/documents/:docId", customerHandler.DeleteDocument)
+			customers.POST("/:id/documents/:docId/restore", customerHandler.RestoreDocument)
+
+			// Duplicates and merging
+			customers.GET("/:id/duplicates", customerHandler.GetCustomerDuplicates)
+			customers.POST("/:id/merge", customerHandler.MergeCustomer)
+			customers.GET("/:id/merges", customerHandler.GetMerges)
+		}
+
+		// Activities across customers
+		activities := v1.Group("/activities")
+		{
+			activities.GET("", customerHandler.ListActivities)
+			activities.GET("/mine", customerHandler.GetMyActivities)
+			activities.GET("/:activityId", customerHandler.GetActivity)
+			activities.PUT("/:activityId", customerHandler.UpdateActivity)
+			activities.DELETE("/:activityId", customerHandler.DeleteActivity)
+			activities.POST("/:activityId/complete", customerHandler.CompleteActivity)
+			activities.POST("/:activityId/reopen", customerHandler.ReopenActivity)
+			activities.POST("/:activityId/snooze", customerHandler.SnoozeActivity)
+		}
+
+		// Follow-up cadences
+		cadences := v1.Group("/cadences")
+		{
+			cadences.GET("", customerHandler.GetCadences)
+			cadences.POST("", customerHandler.CreateCadence)
+			cadences.GET("/:id", customerHandler.GetCadence)
+			cadences.PUT("/:id", customerHandler.UpdateCadence)
+			cadences.DELETE("/:id", customerHandler.DeleteCadence)
 		}
 	}
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDeliveryRoutes(router *gin.Engine, deliveryHandler *handlers.DeliveryHandler) {
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/sales/:id/delivery-notes", deliveryHandler.GetOrderDeliveryNotes)
+
+		notes := v1.Group("/delivery-notes")
+		{
+			notes.GET("", deliveryHandler.GetDeliveryNotes)
+			notes.POST("", deliveryHandler.CreateDeliveryNote)
+			notes.GET("/:id", deliveryHandler.GetDeliveryNote)
+			notes.POST("/:id/deliver", deliveryHandler.ConfirmDelivery)
+		}
+	}
+}
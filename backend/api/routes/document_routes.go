@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDocumentRoutes(router *gin.Engine, documentHandler *handlers.DocumentHandler) {
+	v1 := router.Group("/api/v1")
+	{
+		sales := v1.Group("/sales")
+		{
+			sales.GET("/:id/pdf", documentHandler.SalesOrderPDF)
+			sales.GET("/:id/quote/pdf", documentHandler.SalesQuotePDF)
+			sales.GET("/:id/invoice/pdf", documentHandler.SalesInvoicePDF)
+			sales.GET("/:id/delivery-note/pdf", documentHandler.SalesDeliveryNotePDF)
+		}
+
+		v1.GET("/delivery-notes/:id/pdf", documentHandler.DeliveryNotePDF)
+	}
+}
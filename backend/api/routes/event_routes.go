@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEventRoutes(router *gin.Engine, eventHandler *handlers.EventHandler) {
+	events := router.Group("/api/v1/events", middleware.RequireAuth(), middleware.RequireRole(domain.RoleAdmin))
+	{
+		events.GET("", eventHandler.GetEvents)
+		events.GET("/:id", eventHandler.GetEvent)
+		events.POST("/:id/retry", eventHandler.RetryEvent)
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupExportRoutes(router *gin.Engine, exportHandler *handlers.ExportHandler) {
+	v1 := router.Group("/api/v1")
+	{
+		v1.GET("/exports/:list", exportHandler.Export)
+	}
+}
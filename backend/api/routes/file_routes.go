@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupFileRoutes serves signed links to files on the local disk
+func SetupFileRoutes(router *gin.Engine, fileHandler *handlers.FileHandler) {
+	router.GET("/api/v1/files/*key", fileHandler.ServeFile)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupImportRoutes(router *gin.Engine, importHandler *handlers.ImportHandler) {
+	v1 := router.Group("/api/v1")
+	{
+		imports := v1.Group("/imports")
+		{
+			imports.GET("", importHandler.GetImports)
+			imports.GET("/:id", importHandler.GetImport)
+			imports.POST("/:type", importHandler.StartImport)
+		}
+	}
+}
@@ -21,6 +21,12 @@ func SetupInventoryRoutes(router *gin.Engine, inventoryHandler *handlers.Invento
 			// Categories
 			inventory.GET("/categories", inventoryHandler.GetCategories)
 			inventory.POST("/categories", inventoryHandler.CreateCategory)
+
+			// Warehouses
+			inventory.GET("/warehouses", inventoryHandler.GetWarehouses)
+			inventory.POST("/warehouses", inventoryHandler.CreateWarehouse)
+			inventory.GET("/warehouses/:id/stock", inventoryHandler.GetWarehouseStock)
+			inventory.PUT("/warehouses/:id/stock", inventoryHandler.SetWarehouseStock)
 		}
 	}
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMessageTemplateRoutes(router *gin.Engine, handler *handlers.MessageTemplateHandler) {
+	// Templates word every message customers receive, so only managers change them
+	manager := middleware.RequireRole(domain.RoleAdmin, domain.RoleManager)
+
+	v1 := router.Group("/api/v1/message-templates", middleware.RequireAuth())
+	{
+		v1.GET("", handler.GetTemplates)
+		v1.POST("/preview", handler.PreviewTemplate)
+		v1.GET("/:key", handler.GetTemplate)
+		v1.PUT("/:key", manager, handler.UpdateTemplate)
+		v1.DELETE("/:key", manager, handler.ResetTemplate)
+	}
+}
@@ -2,14 +2,22 @@ package routes
 
 import (
 	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupNotificationRoutes(router *gin.Engine, handler *handlers.NotificationHandler) {
-	v1 := router.Group("/api/v1/notifications")
+	v1 := router.Group("/api/v1/notifications", middleware.RequireAuth())
 	{
 		v1.GET("", handler.GetUnread)
+		v1.GET("/history", handler.GetHistory)
+		v1.GET("/unread-count", handler.GetUnreadCount)
+		v1.GET("/stream", handler.Stream)
+		v1.GET("/preferences", handler.GetPreferences)
+		v1.PUT("/preferences", handler.UpdatePreferences)
+		v1.POST("/read-all", handler.MarkAllAsRead)
 		v1.POST("/:id/read", handler.MarkAsRead)
+		v1.DELETE("/:id", handler.DeleteNotification)
 	}
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupOutboxRoutes(router *gin.Engine, outboxHandler *handlers.OutboxHandler) {
+	messages := router.Group("/api/v1/messages", middleware.RequireAuth(), middleware.RequireRole(domain.RoleAdmin))
+	{
+		messages.GET("", outboxHandler.GetMessages)
+		messages.GET("/:id", outboxHandler.GetMessage)
+		messages.POST("/:id/resend", outboxHandler.ResendMessage)
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPromotionRoutes(router *gin.Engine, promotionHandler *handlers.PromotionHandler) {
+	// Promotions and discount limits decide which discounts need approval, so only
+	// managers change them
+	manager := middleware.RequireRole(domain.RoleAdmin, domain.RoleManager)
+
+	v1 := router.Group("/api/v1", middleware.RequireAuth())
+	{
+		promotions := v1.Group("/promotions")
+		{
+			promotions.GET("", promotionHandler.GetPromotions)
+			promotions.GET("/:id", promotionHandler.GetPromotion)
+			promotions.POST("", manager, promotionHandler.CreatePromotion)
+			promotions.PUT("/:id", manager, promotionHandler.UpdatePromotion)
+			promotions.DELETE("/:id", manager, promotionHandler.DeletePromotion)
+		}
+
+		policies := v1.Group("/discount-policies")
+		{
+			policies.GET("", promotionHandler.GetDiscountPolicies)
+			policies.PUT("", manager, promotionHandler.SetDiscountPolicy)
+		}
+	}
+}
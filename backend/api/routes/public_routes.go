@@ -0,0 +1,14 @@
+package routes
+
+// PublicRoutes are the API routes that answer without a login: signing in and refreshing a
+// session, the settings the login page needs, webhooks that check their own signature, and
+// file links that carry their own signature.
+var PublicRoutes = []string{
+	"/api/v1/auth/login",
+	"/api/v1/auth/logout",
+	"/api/v1/auth/refresh",
+	"/api/v1/auth/revoke",
+	"/api/v1/settings/public",
+	"/api/v1/webhooks/",
+	"/api/v1/files/*key",
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,11 +10,26 @@ import (
 func SetupSalesRoutes(router *gin.Engine, salesHandler *handlers.SalesHandler) {
 	v1 := router.Group("/api/v1")
 	{
-		sales := v1.Group("/sales")
+		sales := v1.Group("/sales", middleware.RequireAuth())
 		{
 			sales.GET("", salesHandler.GetOrders)
 			sales.POST("", salesHandler.CreateOrder)
 			sales.GET("/:id", salesHandler.GetOrder)
+			sales.PUT("/:id", salesHandler.UpdateOrder)
+
+			// Revisions of confirmed orders
+			sales.GET("/:id/revisions", salesHandler.GetRevisions)
+			sales.POST("/:id/revisions", salesHandler.ReviseOrder)
+			sales.GET("/:id/revisions/diff", salesHandler.DiffRevisions)
+			sales.GET("/:id/revisions/:revision", salesHandler.GetRevision)
+
+			// Discount approval
+			sales.POST("/:id/approve", salesHandler.ApproveOrder)
+			sales.POST("/:id/reject", salesHandler.RejectOrder)
+
+			// Payments
+			sales.GET("/:id/payments", salesHandler.GetPayments)
+			sales.POST("/:id/payments", salesHandler.RecordPayment)
 		}
 	}
 }
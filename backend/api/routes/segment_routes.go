@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSegmentRoutes(router *gin.Engine, segmentHandler *handlers.SegmentHandler) {
+	// Tags and segments choose who campaigns reach, so only managers change them or send
+	manager := middleware.RequireRole(domain.RoleAdmin, domain.RoleManager)
+
+	v1 := router.Group("/api/v1", middleware.RequireAuth())
+	{
+		tags := v1.Group("/tags")
+		{
+			tags.GET("", segmentHandler.GetTags)
+			tags.POST("", manager, segmentHandler.CreateTag)
+			tags.DELETE("/:id", manager, segmentHandler.DeleteTag)
+		}
+
+		customers := v1.Group("/customers")
+		{
+			customers.GET("/:id/tags", segmentHandler.GetCustomerTags)
+			customers.POST("/:id/tags", manager, segmentHandler.TagCustomer)
+			customers.DELETE("/:id/tags/:tagId", manager, segmentHandler.UntagCustomer)
+		}
+
+		segments := v1.Group("/segments")
+		{
+			segments.GET("", segmentHandler.GetSegments)
+			segments.POST("", manager, segmentHandler.CreateSegment)
+			segments.POST("/preview", segmentHandler.PreviewSegment)
+			segments.GET("/:id", segmentHandler.GetSegment)
+			segments.PUT("/:id", manager, segmentHandler.UpdateSegment)
+			segments.DELETE("/:id", manager, segmentHandler.DeleteSegment)
+			segments.GET("/:id/customers", segmentHandler.GetMembers)
+			segments.GET("/:id/campaigns", segmentHandler.GetCampaigns)
+			segments.POST("/:id/campaigns", manager, segmentHandler.SendCampaign)
+		}
+	}
+}
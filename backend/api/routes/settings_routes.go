@@ -1,7 +1,9 @@
 package routes
 
 import (
+	"erp-system/internal/domain"
 	"erp-system/internal/handlers"
+	"erp-system/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,8 +12,8 @@ func SetupSettingsRoutes(router *gin.Engine, handler *handlers.SettingsHandler)
 	// Public routes
 	router.GET("/api/v1/settings/public", handler.GetPublicSettings)
 
-	// Protected routes (TODO: Add Auth Middleware when ready)
-	v1 := router.Group("/api/v1/settings")
+	// Credentials live here, so only admins see or change them
+	v1 := router.Group("/api/v1/settings", middleware.RequireAuth(), middleware.RequireRole(domain.RoleAdmin))
 	{
 		v1.GET("", handler.GetSettings)
 		v1.POST("", handler.UpdateSettings)
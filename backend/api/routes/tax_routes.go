@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTaxRoutes(router *gin.Engine, taxHandler *handlers.TaxHandler) {
+	v1 := router.Group("/api/v1")
+	{
+		taxCodes := v1.Group("/tax-codes")
+		{
+			taxCodes.GET("", taxHandler.GetTaxCodes)
+			taxCodes.GET("/:id", taxHandler.GetTaxCode)
+			taxCodes.POST("", taxHandler.CreateTaxCode)
+			taxCodes.PUT("/:id", taxHandler.UpdateTaxCode)
+			taxCodes.DELETE("/:id", taxHandler.DeleteTaxCode)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"erp-system/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTimelineRoutes(router *gin.Engine, timelineHandler *handlers.TimelineHandler) {
+	v1 := router.Group("/api/v1")
+	{
+		customers := v1.Group("/customers")
+		{
+			customers.GET("/:id/timeline", timelineHandler.GetTimeline)
+			customers.GET("/:id/summary", timelineHandler.GetSummary)
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"erp-system/internal/usecases"
 	"erp-system/internal/worker"
 	"erp-system/pkg/database"
+	"erp-system/pkg/storage"
 	"log"
+	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,27 +36,65 @@ func main() {
 	activityRepo := repositories.NewCustomerActivityRepository(db)
 	docRepo := repositories.NewCustomerDocumentRepository(db)
 	settingsRepo := repositories.NewSettingsRepository(db)
-	notifRepo := repositories.NewNotificationRepository(db)
+	notificationHub := services.NewNotificationHub()
+	notifRepo := notificationHub.Publishing(repositories.NewNotificationRepository(db))
 	loginAttemptRepo := repositories.NewLoginAttemptRepository(db)
 	lockoutRepo := repositories.NewAccountLockoutRepository(db)
 	refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
 	branchRepo := repositories.NewBranchRepository(db)
+	promotionRepo := repositories.NewPromotionRepository(db)
+	taxRepo := repositories.NewTaxRepository(db)
+	currencyRepo := repositories.NewCurrencyRepository(db)
+	deliveryRepo := repositories.NewDeliveryRepository(db)
+	creditRepo := repositories.NewCreditRepository(db)
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	eventRepo := repositories.NewEventRepository(db)
 
 	// Services
 	notifService := services.NewNotificationService(settingsRepo)
+	taxService := services.NewTaxService(taxRepo, settingsRepo)
+	currencyService := services.NewCurrencyService(currencyRepo)
+	storageBackend, err := services.OpenStorageBackend(settingsRepo, "./uploads")
+	if err != nil {
+		log.Fatal("❌ Failed to open file storage:", err)
+	}
+	storageService := services.NewStorageService(settingsRepo, storageBackend)
+	documentService := services.NewDocumentService(settingsRepo, storageService)
+	templateService := services.NewTemplateService(repositories.NewMessageTemplateRepository(db), settingsRepo)
+	outboxUseCase := usecases.NewOutboxUseCase(repositories.NewOutboundMessageRepository(db), notifService, templateService)
+	dispatcher := services.NewNotificationDispatcher(subscriptionRepo, userRepo, notifRepo, outboxUseCase)
+	creditService := services.NewCreditService(creditRepo, settingsRepo, userRepo, dispatcher)
+	eventBus := services.NewEventBus(eventRepo)
+	services.SubscribeNotifications(eventBus, dispatcher)
+	services.SubscribeAudit(eventBus, repositories.NewAuditRepository(db))
+	services.NewEventWebhook(settingsRepo, os.Getenv("EVENT_WEBHOOK_ALLOW_PRIVATE") == "true").Subscribe(eventBus)
+	usecases.SubscribeCustomerMessages(eventBus, outboxUseCase, salesRepo)
 
 	// Initialize use cases
 	authUseCase := usecases.NewAuthUseCase(userRepo, loginAttemptRepo, lockoutRepo, refreshTokenRepo)
 	tokenUseCase := usecases.NewTokenUseCase(userRepo, refreshTokenRepo)
-	customerUseCase := usecases.NewCustomerUseCase(customerRepo, activityRepo, docRepo, notifService)
-	salesUseCase := usecases.NewSalesUseCase(salesRepo, customerRepo)
+	customerUseCase := usecases.NewCustomerUseCase(customerRepo, activityRepo, docRepo, userRepo, storageService, services.NewThumbnailService(os.Getenv("PDF_PREVIEW_COMMAND")), outboxUseCase)
+	salesUseCase := usecases.NewSalesUseCase(salesRepo, customerRepo, inventoryRepo, promotionRepo, userRepo, notifRepo, taxService, currencyService, creditService)
 	inventoryUseCase := usecases.NewInventoryUseCase(inventoryRepo)
 	productionUseCase := usecases.NewProductionUseCase(productionRepo)
-	settingsUseCase := usecases.NewSettingsUseCase(settingsRepo)
-	notifUseCase := usecases.NewNotificationUseCase(notifRepo)
+	settingsUseCase := usecases.NewSettingsUseCase(settingsRepo, storageService)
+	notifUseCase := usecases.NewNotificationUseCase(notifRepo, subscriptionRepo, notificationHub)
 	dashboardUseCase := usecases.NewDashboardUsecase(repositories.NewDashboardRepository(db))
 	branchUseCase := usecases.NewBranchUseCase(branchRepo, customerRepo)
 	userUseCase := usecases.NewUserUseCase(userRepo)
+	promotionUseCase := usecases.NewPromotionUseCase(promotionRepo)
+	taxUseCase := usecases.NewTaxUseCase(taxRepo)
+	currencyUseCase := usecases.NewCurrencyUseCase(currencyRepo)
+	documentUseCase := usecases.NewDocumentUseCase(salesRepo, deliveryRepo, documentService)
+	deliveryUseCase := usecases.NewDeliveryUseCase(deliveryRepo, salesRepo, inventoryRepo)
+	creditUseCase := usecases.NewCreditUseCase(creditRepo, customerRepo, salesRepo, creditService)
+	importUseCase := usecases.NewImportUseCase(repositories.NewImportRepository(db), customerRepo, inventoryRepo)
+	exportUseCase := usecases.NewExportUseCase(repositories.NewExportRepository(db), settingsRepo)
+	segmentUseCase := usecases.NewSegmentUseCase(repositories.NewSegmentRepository(db), customerRepo, notifService)
+	timelineUseCase := usecases.NewTimelineUseCase(repositories.NewTimelineRepository(db), customerRepo)
+	messageTemplateUseCase := usecases.NewMessageTemplateUseCase(templateService, customerRepo, salesRepo)
+	conversationUseCase := usecases.NewConversationUseCase(customerRepo, activityRepo, notifRepo, repositories.NewOutboundMessageRepository(db), settingsRepo)
+	eventUseCase := usecases.NewEventUseCase(eventRepo, eventBus)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authUseCase)
@@ -69,6 +109,20 @@ func main() {
 	dashboardHandler := handlers.NewDashboardHandler(dashboardUseCase)
 	branchHandler := handlers.NewBranchHandler(branchUseCase)
 	userHandler := handlers.NewUserHandler(userUseCase)
+	promotionHandler := handlers.NewPromotionHandler(promotionUseCase)
+	taxHandler := handlers.NewTaxHandler(taxUseCase)
+	currencyHandler := handlers.NewCurrencyHandler(currencyUseCase)
+	documentHandler := handlers.NewDocumentHandler(documentUseCase)
+	deliveryHandler := handlers.NewDeliveryHandler(deliveryUseCase)
+	creditHandler := handlers.NewCreditHandler(creditUseCase)
+	importHandler := handlers.NewImportHandler(importUseCase)
+	exportHandler := handlers.NewExportHandler(exportUseCase)
+	segmentHandler := handlers.NewSegmentHandler(segmentUseCase)
+	timelineHandler := handlers.NewTimelineHandler(timelineUseCase)
+	outboxHandler := handlers.NewOutboxHandler(outboxUseCase)
+	messageTemplateHandler := handlers.NewMessageTemplateHandler(messageTemplateUseCase)
+	conversationHandler := handlers.NewConversationHandler(conversationUseCase)
+	eventHandler := handlers.NewEventHandler(eventUseCase)
 
 	// Setup Gin
 	gin.SetMode(gin.ReleaseMode)
@@ -84,7 +138,8 @@ func main() {
 	rateLimiter := middleware.NewRateLimiter(100, 1*time.Minute)
 	router.Use(rateLimiter.Middleware())
 
-	router.Static("/uploads", "./uploads")
+	// Every API route needs a login except the ones listed in routes.PublicRoutes
+	router.Use(middleware.RequireAuthExcept(routes.PublicRoutes...))
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -103,12 +158,32 @@ func main() {
 	routes.SetupDashboardRoutes(router, dashboardHandler)
 	routes.SetupBranchRoutes(router, branchHandler)
 	routes.SetupUserRoutes(router, userHandler)
+	routes.SetupPromotionRoutes(router, promotionHandler)
+	routes.SetupTaxRoutes(router, taxHandler)
+	routes.SetupCurrencyRoutes(router, currencyHandler)
+	routes.SetupDocumentRoutes(router, documentHandler)
+	routes.SetupDeliveryRoutes(router, deliveryHandler)
+	routes.SetupCreditRoutes(router, creditHandler)
+	routes.SetupImportRoutes(router, importHandler)
+	routes.SetupExportRoutes(router, exportHandler)
+	routes.SetupSegmentRoutes(router, segmentHandler)
+	routes.SetupTimelineRoutes(router, timelineHandler)
+	routes.SetupOutboxRoutes(router, outboxHandler)
+	routes.SetupMessageTemplateRoutes(router, messageTemplateHandler)
+	routes.SetupConversationRoutes(router, conversationHandler)
+	routes.SetupEventRoutes(router, eventHandler)
+	if local, ok := storageBackend.(*storage.Local); ok {
+		routes.SetupFileRoutes(router, handlers.NewFileHandler(local))
+	}
 
 	// Ensure main branch exists
 	branchUseCase.EnsureMainBranchExists()
 
 	// Start Background Workers
-	worker.StartReminderWorker(db, notifService)
+	worker.StartReminderWorker(db, outboxUseCase, dispatcher)
+	worker.StartOutboxWorker(outboxUseCase, 4)
+	worker.StartCreditWorker(creditUseCase)
+	worker.StartEventWorker(eventBus)
 
 	// Start server
 	port := "8080"
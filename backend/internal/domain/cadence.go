@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Recurrence frequencies
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+	RecurrenceDays    = "days" // Every Interval days
+)
+
+// ActivityRecurrence repeats an activity: completing one occurrence schedules the next,
+// until the end date or occurrence count is reached
+type ActivityRecurrence struct {
+	Frequency  string     `json:"frequency"`
+	Interval   int        `json:"interval"`   // Periods between occurrences, at least 1
+	Until      *time.Time `json:"until"`      // Last date an occurrence may fall on
+	Count      int        `json:"count"`      // Total occurrences, 0 for no limit
+	Occurrence int        `json:"occurrence"` // Which occurrence this activity is, set by the system
+}
+
+// Validate checks the rule and fills in defaults
+func (r *ActivityRecurrence) Validate() error {
+	switch r.Frequency {
+	case RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly, RecurrenceDays:
+	default:
+		return fmt.Errorf("recurrence frequency must be %s, %s, %s or %s", RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly, RecurrenceDays)
+	}
+	if r.Interval < 0 || r.Count < 0 {
+		return errors.New("recurrence interval and count cannot be negative")
+	}
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.Occurrence < 1 {
+		r.Occurrence = 1
+	}
+	return nil
+}
+
+// Advance returns the occurrence after the one at t
+func (r ActivityRecurrence) Advance(t time.Time) time.Time {
+	switch r.Frequency {
+	case RecurrenceWeekly:
+		return t.AddDate(0, 0, 7*r.Interval)
+	case RecurrenceMonthly:
+		return addMonths(t, r.Interval)
+	}
+	return t.AddDate(0, 0, r.Interval)
+}
+
+// Next finds the first occurrence after from that is later than now, skipping missed ones.
+// ok is false when the series has ended.
+func (r ActivityRecurrence) Next(from, now time.Time) (next ActivityRecurrence, at time.Time, ok bool) {
+	next, at = r, from
+	for {
+		at = next.Advance(at)
+		next.Occurrence++
+		if next.Count > 0 && next.Occurrence > next.Count {
+			return next, at, false
+		}
+		if next.Until != nil && at.After(*next.Until) {
+			return next, at, false
+		}
+		if at.After(now) {
+			return next, at, true
+		}
+	}
+}
+
+// addMonths moves t by n months, keeping the day where it exists and using the last day
+// of shorter months otherwise
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, n, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// CadenceStep is one follow-up a cadence schedules
+type CadenceStep struct {
+	AfterDays   int    `json:"after_days" binding:"gte=0"` // Days after the triggering activity is completed
+	Type        string `json:"type" binding:"required"`
+	Description string `json:"description" binding:"required"`
+}
+
+// FollowUpCadence schedules follow-up activities whenever an activity of the trigger type is
+// completed, e.g. a call 3 days after a quote
+type FollowUpCadence struct {
+	ID          uint          `json:"id" gorm:"primarykey"`
+	Name        string        `json:"name" gorm:"uniqueIndex;not null"`
+	TriggerType string        `json:"trigger_type" gorm:"index;not null"` // Activity type whose completion starts the cadence
+	Active      bool          `json:"active"`
+	Steps       []CadenceStep `json:"steps" gorm:"serializer:json"`
+	CreatedBy   uint          `json:"created_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// SaveCadenceRequest for creating or updating a cadence; cadences are active unless stated
+type SaveCadenceRequest struct {
+	Name        string        `json:"name" binding:"required"`
+	TriggerType string        `json:"trigger_type" binding:"required"`
+	Active      *bool         `json:"active"`
+	Steps       []CadenceStep `json:"steps" binding:"required,min=1,dive"`
+}
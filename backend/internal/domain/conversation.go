@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// ActivityTypeWhatsApp is the activity type of WhatsApp messages logged on a customer
+const ActivityTypeWhatsApp = "whatsapp"
+
+// Message directions
+const (
+	DirectionIn  = "in"  // Sent by the customer
+	DirectionOut = "out" // Sent to the customer
+)
+
+// ConversationEntry is one message in a customer's WhatsApp conversation; received messages
+// come from activities and sent ones from the outbox
+type ConversationEntry struct {
+	Direction  string    `json:"direction"`
+	Body       string    `json:"body"`
+	Status     string    `json:"status,omitempty"` // Outbox status of a sent message
+	ActivityID uint      `json:"activity_id,omitempty"`
+	MessageID  uint      `json:"message_id,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// InboundResult summarises a processed inbound webhook
+type InboundResult struct {
+	Received  int `json:"received"`
+	Logged    int `json:"logged"`
+	Duplicate int `json:"duplicate"` // Redelivered messages that were already logged
+	Unmatched int `json:"unmatched"` // Senders that are not a customer
+	OptedOut  int `json:"opted_out"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"time"
+)
+
+// CreditOverride lets a customer's orders through credit control until it expires.
+// MaxAmount is how far above the credit limit the customer may go; zero means no cap.
+type CreditOverride struct {
+	ID         uint        `json:"id" gorm:"primarykey"`
+	CustomerID uint        `json:"customer_id" gorm:"not null;index"`
+	OrderID    *uint       `json:"order_id"` // Restricts the override to one order
+	MaxAmount  money.Money `json:"max_amount" gorm:"default:0"`
+	Reason     string      `json:"reason" gorm:"not null"`
+	ExpiresAt  time.Time   `json:"expires_at" gorm:"not null;index"`
+	GrantedBy  uint        `json:"granted_by"`
+	RevokedAt  *time.Time  `json:"revoked_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// IsActiveAt reports whether the override can be used at the given time
+func (o *CreditOverride) IsActiveAt(t time.Time) bool {
+	return o.RevokedAt == nil && t.Before(o.ExpiresAt)
+}
+
+// CreditEvent is one entry in a customer's credit history
+type CreditEvent struct {
+	ID          uint        `json:"id" gorm:"primarykey"`
+	CustomerID  uint        `json:"customer_id" gorm:"not null;index"`
+	Type        string      `json:"type" gorm:"not null;index"`
+	OrderID     *uint       `json:"order_id"`
+	OverrideID  *uint       `json:"override_id"`
+	Amount      money.Money `json:"amount" gorm:"default:0"`
+	Balance     money.Money `json:"balance" gorm:"default:0"`
+	CreditLimit money.Money `json:"credit_limit" gorm:"default:0"`
+	Reason      string      `json:"reason"`
+	UserID      *uint       `json:"user_id"` // Nil for automatic events
+	CreatedAt   time.Time   `json:"created_at" gorm:"index"`
+}
+
+// Credit event types
+const (
+	CreditEventHoldPlaced      = "hold_placed"
+	CreditEventHoldReleased    = "hold_released"
+	CreditEventOrderBlocked    = "order_blocked"
+	CreditEventOrderReleased   = "order_released"
+	CreditEventOrderRejected   = "order_rejected"
+	CreditEventOverrideGranted = "override_granted"
+	CreditEventOverrideRevoked = "override_revoked"
+	CreditEventOverrideUsed    = "override_used"
+	CreditEventWarning         = "warning"
+)
+
+// CreditStatus summarises a customer's standing against their credit limit
+type CreditStatus struct {
+	CustomerID        uint             `json:"customer_id"`
+	CreditLimit       money.Money      `json:"credit_limit"`
+	Balance           money.Money      `json:"balance"`
+	Available         money.Money      `json:"available"`
+	Utilisation       float64          `json:"utilisation"` // Percentage of the limit in use
+	OnHold            bool             `json:"on_hold"`
+	HoldReason        string           `json:"hold_reason"`
+	HoldAt            *time.Time       `json:"hold_at"`
+	OverdueCount      int              `json:"overdue_count"`
+	OverdueAmount     money.Money      `json:"overdue_amount"`
+	BlockedOrders     int              `json:"blocked_orders"`
+	ActiveOverrides   []CreditOverride `json:"active_overrides"`
+	PaymentTermsDays  int              `json:"payment_terms_days"`
+	WarningPercentage float64          `json:"warning_percentage"`
+}
+
+// CreditHoldRequest places or releases a manual credit hold
+type CreditHoldRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GrantCreditOverrideRequest. Hours defaults to 24 and is capped at a week.
+type GrantCreditOverrideRequest struct {
+	Reason    string      `json:"reason" binding:"required"`
+	Hours     int         `json:"hours" binding:"gte=0"`
+	MaxAmount money.Money `json:"max_amount"`
+	OrderID   *uint       `json:"order_id"`
+}
+
+// CreditDecisionRequest carries the reason for releasing or rejecting a blocked order
+type CreditDecisionRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported currencies; the base currency is money.DefaultCurrency
+var SupportedCurrencies = []string{"EGP", "USD", "EUR", "GBP", "SAR", "AED"}
+
+// IsSupportedCurrency reports whether the code is one of the supported currencies
+func IsSupportedCurrency(code string) bool {
+	for _, c := range SupportedCurrencies {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCurrency upper-cases a currency code, falling back to the base currency when empty
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return money.DefaultCurrency, nil
+	}
+	if !IsSupportedCurrency(code) {
+		return "", fmt.Errorf("unsupported currency %s", code)
+	}
+	return code, nil
+}
+
+// ExchangeRate is the value of one unit of a foreign currency in the base currency, effective from a date
+type ExchangeRate struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	Currency      string    `json:"currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_date"`
+	Rate          float64   `json:"rate" gorm:"not null"` // Base currency units per 1 unit of Currency
+	EffectiveDate time.Time `json:"effective_date" gorm:"not null;uniqueIndex:idx_exchange_rate_date"`
+	CreatedBy     uint      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SalesPayment is a customer payment against a sales order, in the order currency
+type SalesPayment struct {
+	ID           uint        `json:"id" gorm:"primarykey"`
+	OrderID      uint        `json:"order_id" gorm:"not null;index"`
+	Amount       money.Money `json:"amount" gorm:"not null"`
+	Currency     string      `json:"currency" gorm:"size:3"`
+	ExchangeRate float64     `json:"exchange_rate" gorm:"default:1"` // Rate on the payment date
+	BaseAmount   money.Money `json:"base_amount"`                    // Amount in the base currency at the payment rate
+	FXDifference money.Money `json:"fx_difference"`                  // Realised gain (positive) or loss against the order rate
+	PaymentDate  time.Time   `json:"payment_date" gorm:"not null"`
+	Method       string      `json:"method"` // cash, bank_transfer, cheque, card
+	Reference    string      `json:"reference"`
+	CreatedBy    uint        `json:"created_by"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// SetExchangeRateRequest creates or replaces the rate of a currency for a date
+type SetExchangeRateRequest struct {
+	Currency      string    `json:"currency" binding:"required,len=3"`
+	Rate          float64   `json:"rate" binding:"required,gt=0"`
+	EffectiveDate time.Time `json:"effective_date" binding:"required"`
+}
+
+// RecordPaymentRequest books a payment against a sales order
+type RecordPaymentRequest struct {
+	Amount       money.Money `json:"amount"`
+	PaymentDate  time.Time   `json:"payment_date" binding:"required"`
+	ExchangeRate float64     `json:"exchange_rate" binding:"gte=0"` // Optional, defaults to the rate table
+	Method       string      `json:"method"`
+	Reference    string      `json:"reference"`
+}
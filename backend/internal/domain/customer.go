@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"erp-system/pkg/money"
 	"time"
 )
 
@@ -18,76 +19,192 @@ type Customer struct {
 	Country           string             `json:"country"`
 	PostalCode        string             `json:"postal_code"`
 	TaxNumber         string             `json:"tax_number"`
-	CreditLimit       float64            `json:"credit_limit" gorm:"default:0"`
-	Balance           float64            `json:"balance" gorm:"default:0"`
+	TaxCodeID         *uint              `json:"tax_code_id"`                          // Overrides product tax codes (e.g. exempt customers)
+	Currency          string             `json:"currency" gorm:"size:3;default:'EGP'"` // Default currency for new orders
+	CreditLimit       money.Money        `json:"credit_limit" gorm:"default:0"`        // In the base currency
+	Balance           money.Money        `json:"balance" gorm:"default:0"`             // In the base currency
+	PaymentTermsDays  int                `json:"payment_terms_days" gorm:"default:30"`
+	CreditHold        bool               `json:"credit_hold" gorm:"default:false"`
+	CreditHoldReason  string             `json:"credit_hold_reason"`
+	CreditHoldAt      *time.Time         `json:"credit_hold_at"`
+	CreditHoldBy      *uint              `json:"credit_hold_by"`                 // Nil when placed automatically for overdue invoices
 	Type              string             `json:"type" gorm:"default:'regular'"`  // regular, vip, wholesale
 	Status            string             `json:"status" gorm:"default:'active'"` // active, inactive
 	IsWhatsAppEnabled bool               `json:"is_whatsapp_enabled" gorm:"default:true"`
-	BranchID          *uint              `json:"branch_id"` // Branch assignment
+	PreferredChannel  string             `json:"preferred_channel"`            // whatsapp, sms, email; empty follows the configured order
+	Language          string             `json:"language" gorm:"default:'ar'"` // ar, en; messages use this template variant
+	BranchID          *uint              `json:"branch_id"`                    // Branch assignment
+	MergedIntoID      *uint              `json:"merged_into_id,omitempty"`
 	Branch            *Branch            `json:"branch,omitempty" gorm:"foreignKey:BranchID"`
 	Activities        []CustomerActivity `json:"activities" gorm:"foreignKey:CustomerID"`
-	Documents         []CustomerDocument `json:\"documents\" gorm:\"foreignKey:CustomerID\"`
+	Documents         []CustomerDocument `json:"documents" gorm:"foreignKey:CustomerID"`
+	Tags              []Tag              `json:"tags,omitempty" gorm:"many2many:customer_tags"`
 	CreatedBy         uint               `json:"created_by"`
 	CreatedAt         time.Time          `json:"created_at"`
 	UpdatedAt         time.Time          `json:"updated_at"`
 	DeletedAt         *time.Time         `json:"-" gorm:"index"`
 }
 
+// Channels customer messages can be sent on
+const (
+	ChannelWhatsApp = "whatsapp"
+	ChannelSMS      = "sms"
+	ChannelEmail    = "email"
+)
+
 // CreateCustomerRequest for creating a new customer
 type CreateCustomerRequest struct {
-	Name              string  `json:"name" binding:"required"`
-	Email             string  `json:"email" binding:"omitempty,email"`
-	Phone             string  `json:"phone"`
-	Mobile            string  `json:"mobile"`
-	Address           string  `json:"address"`
-	City              string  `json:"city"`
-	Governorate       string  `json:"governorate"`
-	Country           string  `json:"country"`
-	PostalCode        string  `json:"postal_code"`
-	TaxNumber         string  `json:"tax_number"`
-	CreditLimit       float64 `json:"credit_limit"`
-	Type              string  `json:"type"`
-	IsWhatsAppEnabled bool    `json:"is_whatsapp_enabled"`
+	Name              string      `json:"name" binding:"required"`
+	Email             string      `json:"email" binding:"omitempty,email"`
+	Phone             string      `json:"phone"`
+	Mobile            string      `json:"mobile"`
+	Address           string      `json:"address"`
+	City              string      `json:"city"`
+	Governorate       string      `json:"governorate"`
+	Country           string      `json:"country"`
+	PostalCode        string      `json:"postal_code"`
+	TaxNumber         string      `json:"tax_number"`
+	TaxCodeID         *uint       `json:"tax_code_id"`
+	Currency          string      `json:"currency"`
+	CreditLimit       money.Money `json:"credit_limit"`
+	PaymentTermsDays  int         `json:"payment_terms_days" binding:"gte=0"` // Defaults to 30
+	Type              string      `json:"type"`
+	IsWhatsAppEnabled bool        `json:"is_whatsapp_enabled"`
+	PreferredChannel  string      `json:"preferred_channel" binding:"omitempty,oneof=whatsapp sms email"`
+	Language          string      `json:"language" binding:"omitempty,oneof=ar en"`
 }
 
 // UpdateCustomerRequest for updating a customer
 type UpdateCustomerRequest struct {
-	Name              string  `json:"name"`
-	Email             string  `json:"email" binding:"omitempty,email"`
-	Phone             string  `json:"phone"`
-	Mobile            string  `json:"mobile"`
-	Address           string  `json:"address"`
-	City              string  `json:"city"`
-	Governorate       string  `json:"governorate"`
-	Country           string  `json:"country"`
-	PostalCode        string  `json:"postal_code"`
-	TaxNumber         string  `json:"tax_number"`
-	CreditLimit       float64 `json:"credit_limit"`
-	Type              string  `json:"type"`
-	Status            string  `json:"status"`
-	IsWhatsAppEnabled bool    `json:"is_whatsapp_enabled"`
+	Name              string      `json:"name"`
+	Email             string      `json:"email" binding:"omitempty,email"`
+	Phone             string      `json:"phone"`
+	Mobile            string      `json:"mobile"`
+	Address           string      `json:"address"`
+	City              string      `json:"city"`
+	Governorate       string      `json:"governorate"`
+	Country           string      `json:"country"`
+	PostalCode        string      `json:"postal_code"`
+	TaxNumber         string      `json:"tax_number"`
+	TaxCodeID         *uint       `json:"tax_code_id"`
+	Currency          string      `json:"currency"`
+	CreditLimit       money.Money `json:"credit_limit"`
+	PaymentTermsDays  *int        `json:"payment_terms_days" binding:"omitempty,gte=0"`
+	Type              string      `json:"type"`
+	Status            string      `json:"status"`
+	IsWhatsAppEnabled bool        `json:"is_whatsapp_enabled"`
+	PreferredChannel  string      `json:"preferred_channel" binding:"omitempty,oneof=whatsapp sms email"`
+	Language          string      `json:"language" binding:"omitempty,oneof=ar en"`
 }
 
 // CustomerActivity represents a CRM interaction (Note, Call, Meeting)
 type CustomerActivity struct {
-	ID                  uint       `json:"id" gorm:"primarykey"`
-	CustomerID          uint       `json:"customer_id" gorm:"index"`
-	Customer            Customer   `json:"-" gorm:"foreignKey:CustomerID"` // Relation for Preload
-	Type                string     `json:"type"`                           // note, call, meeting, alert, reminder
-	Description         string     `json:"description"`
-	ReminderDate        *time.Time `json:"reminder_date"`                            // Optional reminder
-	NotificationEnabled bool       `json:"notification_enabled" gorm:"default:true"` // Enable/disable notification
-	IsCompleted         bool       `json:"is_completed" gorm:"default:false"`
-	CreatedBy           uint       `json:"created_by"`
-	CreatedAt           time.Time  `json:"created_at"`
+	ID                  uint                `json:"id" gorm:"primarykey"`
+	CustomerID          uint                `json:"customer_id" gorm:"index"`
+	Customer            *Customer           `json:"customer,omitempty" gorm:"foreignKey:CustomerID"` // Relation for Preload
+	Type                string              `json:"type"`                                            // note, call, meeting, alert, reminder
+	Description         string              `json:"description"`
+	ReminderDate        *time.Time          `json:"reminder_date"`                            // Optional reminder
+	NotificationEnabled bool                `json:"notification_enabled" gorm:"default:true"` // Enable/disable notification
+	NotifiedAt          *time.Time          `json:"notified_at"`                              // When the reminder was delivered; cleared when it is rescheduled
+	AssignedTo          *uint               `json:"assigned_to" gorm:"index"`                 // Salesperson responsible; reminders go to them
+	Assignee            *User               `json:"assignee,omitempty" gorm:"foreignKey:AssignedTo"`
+	IsCompleted         bool                `json:"is_completed" gorm:"default:false;index"`
+	CompletedAt         *time.Time          `json:"completed_at"`
+	CompletedBy         *uint               `json:"completed_by"`
+	Outcome             string              `json:"outcome"` // Note left when completing
+	Recurrence          *ActivityRecurrence `json:"recurrence" gorm:"serializer:json"`
+	PreviousID          *uint               `json:"previous_id" gorm:"index"`           // Activity whose completion created this one
+	CadenceID           *uint               `json:"cadence_id"`                         // Cadence that scheduled this activity
+	Direction           string              `json:"direction,omitempty"`                // "in" for messages the customer sent us
+	ExternalID          string              `json:"external_id,omitempty" gorm:"index"` // Provider message ID of a received message
+	FollowUps           []CustomerActivity  `json:"follow_ups,omitempty" gorm:"-"`      // Activities created by completing this one
+	CreatedBy           uint                `json:"created_by"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
+}
+
+// Activity list statuses
+const (
+	ActivityStatusOpen      = "open"
+	ActivityStatusCompleted = "completed"
+	ActivityStatusOverdue   = "overdue" // Open with a reminder date in the past
+)
+
+// ActivityFilter holds the activity list filters
+type ActivityFilter struct {
+	AssignedTo *uint
+	CustomerID uint
+	Type       string
+	Status     string // open, completed, overdue; empty for all
+}
+
+// SaveActivityRequest creates or replaces an activity. AssignedTo defaults to the creator
+// when adding.
+type SaveActivityRequest struct {
+	Type                string              `json:"type" binding:"required"`
+	Description         string              `json:"description" binding:"required"`
+	ReminderDate        *time.Time          `json:"reminder_date"`
+	AssignedTo          *uint               `json:"assigned_to"`
+	NotificationEnabled *bool               `json:"notification_enabled"`
+	Recurrence          *ActivityRecurrence `json:"recurrence"`
+}
+
+// CompleteActivityRequest marks an activity done with an optional outcome
+type CompleteActivityRequest struct {
+	Outcome string `json:"outcome"`
 }
 
-// CustomerDocument represents an uploaded file
+// SnoozeActivityRequest moves an activity's reminder to Until, or Minutes from now
+type SnoozeActivityRequest struct {
+	Until   *time.Time `json:"until"`
+	Minutes int        `json:"minutes" binding:"omitempty,gt=0"`
+}
+
+// Document categories
+const (
+	DocumentCategoryContract         = "contract"
+	DocumentCategoryMeasurementSheet = "measurement_sheet"
+	DocumentCategoryPhoto            = "photo"
+	DocumentCategoryInvoice          = "invoice"
+	DocumentCategoryOther            = "other"
+)
+
+// DocumentCategories lists every document category
+var DocumentCategories = []string{DocumentCategoryContract, DocumentCategoryMeasurementSheet, DocumentCategoryPhoto, DocumentCategoryInvoice, DocumentCategoryOther}
+
+// CustomerDocument represents an uploaded file. Each version of a document is its own row;
+// versions share the GroupID of the first one and only the latest has no ReplacedAt.
 type CustomerDocument struct {
-	ID         uint      `json:"id" gorm:"primarykey"`
-	CustomerID uint      `json:"customer_id" gorm:"index"`
-	Title      string    `json:"title"`
-	FilePath   string    `json:"file_path"`
-	FileType   string    `json:"file_type"` // pdf, image, etc.
-	UploadedAt time.Time `json:"uploaded_at" gorm:"autoCreateTime"`
+	ID           uint       `json:"id" gorm:"primarykey"`
+	CustomerID   uint       `json:"customer_id" gorm:"index"`
+	Title        string     `json:"title"`
+	Category     string     `json:"category" gorm:"index;default:other"`
+	FilePath     string     `json:"-"`                     // Storage key; older rows hold a /uploads/... path
+	FileType     string     `json:"file_type"`             // pdf, image, spreadsheet, document, text, archive
+	OriginalName string     `json:"original_name"`         // File name as uploaded
+	ContentType  string     `json:"content_type"`          // Sniffed from the content
+	Size         int64      `json:"size"`                  // Bytes
+	Checksum     string     `json:"checksum" gorm:"index"` // SHA-256 of the content; identical uploads share one stored file
+	ThumbnailKey string     `json:"-"`                     // Storage key of the preview image, if one could be made
+	HasThumbnail bool       `json:"has_thumbnail" gorm:"-"`
+	GroupID      uint       `json:"group_id" gorm:"index"` // ID of the first version
+	Version      int        `json:"version" gorm:"default:1"`
+	ReplacedAt   *time.Time `json:"replaced_at,omitempty"` // When a newer version was uploaded
+	UploadedBy   uint       `json:"uploaded_by"`
+	UploadedAt   time.Time  `json:"uploaded_at" gorm:"autoCreateTime"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" gorm:"index"` // In the trash; restorable until purged
+	DeletedBy    *uint      `json:"deleted_by,omitempty"`
+}
+
+// DocumentFilter narrows a customer's document list
+type DocumentFilter struct {
+	Category string
+	Deleted  bool // The trash instead of live documents
+}
+
+// UpdateDocumentRequest renames or recategorises a document
+type UpdateDocumentRequest struct {
+	Title    string `json:"title"`
+	Category string `json:"category"`
 }
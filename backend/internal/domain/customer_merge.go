@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"time"
+)
+
+// CustomerMerge records a duplicate customer folded into a surviving one
+type CustomerMerge struct {
+	ID              uint        `json:"id" gorm:"primarykey"`
+	SurvivorID      uint        `json:"survivor_id" gorm:"not null;index"`
+	MergedID        uint        `json:"merged_id" gorm:"not null;index"`
+	MergedCode      string      `json:"merged_code"`
+	MergedName      string      `json:"merged_name"`
+	Snapshot        string      `json:"-" gorm:"type:text"` // JSON of the merged customer before the merge
+	OrdersMoved     int64       `json:"orders_moved"`
+	ActivitiesMoved int64       `json:"activities_moved"`
+	DocumentsMoved  int64       `json:"documents_moved"`
+	BalanceMoved    money.Money `json:"balance_moved" gorm:"default:0"`
+	Reason          string      `json:"reason"`
+	MergedBy        uint        `json:"merged_by"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// Reasons two customers are reported as duplicates
+const (
+	DuplicateMatchPhone       = "phone"
+	DuplicateMatchEmail       = "email"
+	DuplicateMatchName        = "name"
+	DuplicateMatchSimilarName = "similar_name"
+)
+
+// DuplicateCandidate is a pair of customers that look like the same party
+type DuplicateCandidate struct {
+	Customer  Customer `json:"customer"`
+	Duplicate Customer `json:"duplicate"`
+	Score     float64  `json:"score"`
+	Reasons   []string `json:"reasons"`
+}
+
+// MergeCustomersRequest folds MergeID into the customer in the URL
+type MergeCustomersRequest struct {
+	MergeID uint   `json:"merge_id" binding:"required"`
+	Reason  string `json:"reason"`
+}
@@ -1,9 +1,14 @@
 package domain
 
-import "time"
+import (
+	"erp-system/pkg/money"
+	"time"
+)
 
 // DashboardStats represents the main dashboard statistics
 type DashboardStats struct {
+	Currency string `json:"currency"` // Base currency all amounts are converted to
+
 	// Customer Stats
 	TotalCustomers       int64   `json:"total_customers"`
 	ActiveCustomers      int64   `json:"active_customers"` // Last 30 days
@@ -11,23 +16,23 @@ type DashboardStats struct {
 	CustomerGrowthRate   float64 `json:"customer_growth_rate"` // Percentage
 
 	// Sales Stats
-	TotalSalesToday     float64 `json:"total_sales_today"`
-	TotalSalesThisWeek  float64 `json:"total_sales_this_week"`
-	TotalSalesThisMonth float64 `json:"total_sales_this_month"`
-	TotalSalesThisYear  float64 `json:"total_sales_this_year"`
-	SalesGrowthRate     float64 `json:"sales_growth_rate"` // vs last month
+	TotalSalesToday     money.Money `json:"total_sales_today"`
+	TotalSalesThisWeek  money.Money `json:"total_sales_this_week"`
+	TotalSalesThisMonth money.Money `json:"total_sales_this_month"`
+	TotalSalesThisYear  money.Money `json:"total_sales_this_year"`
+	SalesGrowthRate     float64     `json:"sales_growth_rate"` // vs last month
 
 	// Order Stats
-	PendingOrdersCount   int64   `json:"pending_orders_count"`
-	CompletedOrdersToday int64   `json:"completed_orders_today"`
-	TotalOrdersThisMonth int64   `json:"total_orders_this_month"`
-	AverageOrderValue    float64 `json:"average_order_value"`
+	PendingOrdersCount   int64       `json:"pending_orders_count"`
+	CompletedOrdersToday int64       `json:"completed_orders_today"`
+	TotalOrdersThisMonth int64       `json:"total_orders_this_month"`
+	AverageOrderValue    money.Money `json:"average_order_value"`
 
 	// Inventory Stats
-	TotalProducts         int64   `json:"total_products"`
-	LowStockProductsCount int64   `json:"low_stock_products_count"`
-	OutOfStockCount       int64   `json:"out_of_stock_count"`
-	TotalInventoryValue   float64 `json:"total_inventory_value"`
+	TotalProducts         int64       `json:"total_products"`
+	LowStockProductsCount int64       `json:"low_stock_products_count"`
+	OutOfStockCount       int64       `json:"out_of_stock_count"`
+	TotalInventoryValue   money.Money `json:"total_inventory_value"`
 
 	// Production Stats
 	ProductionOrdersInProgress int64 `json:"production_orders_in_progress"`
@@ -43,18 +48,18 @@ type DashboardStats struct {
 
 // TopProduct represents a top-selling product
 type TopProduct struct {
-	ProductID    uint    `json:"product_id"`
-	ProductName  string  `json:"product_name"`
-	SKU          string  `json:"sku"`
-	QuantitySold int64   `json:"quantity_sold"`
-	Revenue      float64 `json:"revenue"`
+	ProductID    uint        `json:"product_id"`
+	ProductName  string      `json:"product_name"`
+	SKU          string      `json:"sku"`
+	QuantitySold int64       `json:"quantity_sold"`
+	Revenue      money.Money `json:"revenue"`
 }
 
 // DailyRevenue represents revenue for a single day
 type DailyRevenue struct {
-	Date    string  `json:"date"` // YYYY-MM-DD
-	Revenue float64 `json:"revenue"`
-	Orders  int64   `json:"orders"`
+	Date    string      `json:"date"` // YYYY-MM-DD
+	Revenue money.Money `json:"revenue"`
+	Orders  int64       `json:"orders"`
 }
 
 // DashboardFilters for date range filtering
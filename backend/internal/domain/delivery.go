@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// DeliveryNote ships part or all of a sales order from a single warehouse
+type DeliveryNote struct {
+	ID            uint               `json:"id" gorm:"primarykey"`
+	NoteNumber    string             `json:"note_number" gorm:"unique;not null;index"`
+	OrderID       uint               `json:"order_id" gorm:"not null;index"`
+	Order         *SalesOrder        `json:"order,omitempty" gorm:"foreignKey:OrderID"`
+	WarehouseID   uint               `json:"warehouse_id" gorm:"not null;index"`
+	Warehouse     *Warehouse         `json:"warehouse,omitempty" gorm:"foreignKey:WarehouseID"`
+	Status        string             `json:"status" gorm:"default:'shipped'"` // shipped, delivered
+	DriverName    string             `json:"driver_name"`
+	DriverPhone   string             `json:"driver_phone"`
+	VehicleNumber string             `json:"vehicle_number"`
+	DeliveryDate  time.Time          `json:"delivery_date" gorm:"not null"`
+	DeliveredAt   *time.Time         `json:"delivered_at"`
+	ReceivedBy    string             `json:"received_by"`
+	Notes         string             `json:"notes"`
+	CreatedBy     uint               `json:"created_by"`
+	Items         []DeliveryNoteItem `json:"items" gorm:"foreignKey:DeliveryNoteID"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// DeliveryNoteItem is the quantity of one order line carried by a delivery note
+type DeliveryNoteItem struct {
+	ID             uint            `json:"id" gorm:"primarykey"`
+	DeliveryNoteID uint            `json:"delivery_note_id" gorm:"not null;index"`
+	OrderItemID    uint            `json:"order_item_id" gorm:"not null;index"`
+	OrderItem      *SalesOrderItem `json:"order_item,omitempty" gorm:"foreignKey:OrderItemID"`
+	ProductID      uint            `json:"product_id" gorm:"not null"`
+	Product        *Product        `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	Quantity       float64         `json:"quantity" gorm:"not null"`
+}
+
+// Delivery note statuses
+const (
+	DeliveryStatusShipped   = "shipped"
+	DeliveryStatusDelivered = "delivered"
+)
+
+// CreateDeliveryNoteRequest. Without items every outstanding quantity is shipped.
+type CreateDeliveryNoteRequest struct {
+	OrderID       uint                      `json:"order_id" binding:"required"`
+	WarehouseID   uint                      `json:"warehouse_id" binding:"required"`
+	DriverName    string                    `json:"driver_name"`
+	DriverPhone   string                    `json:"driver_phone"`
+	VehicleNumber string                    `json:"vehicle_number"`
+	DeliveryDate  *time.Time                `json:"delivery_date"`
+	Notes         string                    `json:"notes"`
+	Items         []DeliveryNoteItemRequest `json:"items" binding:"dive"`
+}
+
+type DeliveryNoteItemRequest struct {
+	OrderItemID uint    `json:"order_item_id" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// ConfirmDeliveryRequest records the customer's receipt of a delivery note
+type ConfirmDeliveryRequest struct {
+	ReceivedBy  string     `json:"received_by"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+}
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"time"
+
+	"erp-system/pkg/money"
+)
+
+// Domain event types
+const (
+	EventTypeOrderCreated        = "order.created"
+	EventTypeOrderStatusChanged  = "order.status_changed"
+	EventTypeStockBelowReorder   = "stock.below_reorder"
+	EventTypeProductionCompleted = "production.completed"
+	EventTypePaymentReceived     = "payment.received"
+	EventTypeBalanceChanged      = "customer.balance_changed"
+)
+
+// Domain event statuses
+const (
+	EventStatusPending   = "pending"   // Waiting for its subscribers, or for a retry of those that failed
+	EventStatusProcessed = "processed" // Every subscriber handled it
+	EventStatusFailed    = "failed"    // A subscriber still failed after the last attempt
+)
+
+// Event is something that happened in the business. Events are stored in the transaction
+// of the write that causes them and handed to subscribers once it commits.
+type Event interface {
+	EventType() string
+	EventActor() uint // User who caused the event, 0 for the system
+}
+
+// EventFunc builds an event when it is stored, after the write that causes it, so it can
+// carry IDs the database generates
+type EventFunc func() Event
+
+func (f EventFunc) EventType() string { return f().EventType() }
+func (f EventFunc) EventActor() uint  { return f().EventActor() }
+
+// OrderCreated is raised when a sales order is placed, whatever state it starts in
+type OrderCreated struct {
+	OrderID      uint        `json:"order_id"`
+	OrderNumber  string      `json:"order_number"`
+	CustomerID   uint        `json:"customer_id"`
+	CustomerName string      `json:"customer_name"`
+	BranchID     *uint       `json:"branch_id,omitempty"`
+	Status       string      `json:"status"`
+	NetAmount    money.Money `json:"net_amount"`
+	Currency     string      `json:"currency"`
+	CreatedBy    uint        `json:"created_by"`
+}
+
+func (e OrderCreated) EventType() string { return EventTypeOrderCreated }
+func (e OrderCreated) EventActor() uint  { return e.CreatedBy }
+
+// OrderStatusChanged is raised when a sales order moves to another status
+type OrderStatusChanged struct {
+	OrderID     uint   `json:"order_id"`
+	OrderNumber string `json:"order_number"`
+	CustomerID  uint   `json:"customer_id"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	ChangedBy   uint   `json:"changed_by"`
+}
+
+func (e OrderStatusChanged) EventType() string { return EventTypeOrderStatusChanged }
+func (e OrderStatusChanged) EventActor() uint  { return e.ChangedBy }
+
+// StockBelowReorder is raised when a product's stock falls to or below its reorder level
+type StockBelowReorder struct {
+	ProductID     uint   `json:"product_id"`
+	SKU           string `json:"sku"`
+	Name          string `json:"name"`
+	StockQuantity int    `json:"stock_quantity"`
+	ReorderLevel  int    `json:"reorder_level"`
+	ChangedBy     uint   `json:"changed_by"`
+}
+
+func (e StockBelowReorder) EventType() string { return EventTypeStockBelowReorder }
+func (e StockBelowReorder) EventActor() uint  { return e.ChangedBy }
+
+// ProductionCompleted is raised when a production order is completed
+type ProductionCompleted struct {
+	OrderID     uint    `json:"order_id"`
+	OrderNumber string  `json:"order_number"`
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    float64 `json:"quantity"`
+	CreatedBy   uint    `json:"created_by"` // Who planned the order
+	CompletedBy uint    `json:"completed_by"`
+}
+
+func (e ProductionCompleted) EventType() string { return EventTypeProductionCompleted }
+func (e ProductionCompleted) EventActor() uint  { return e.CompletedBy }
+
+// PaymentReceived is raised when a payment is booked on a sales order
+type PaymentReceived struct {
+	PaymentID   uint        `json:"payment_id"`
+	OrderID     uint        `json:"order_id"`
+	OrderNumber string      `json:"order_number"`
+	CustomerID  uint        `json:"customer_id"`
+	Amount      money.Money `json:"amount"`
+	Currency    string      `json:"currency"`
+	Method      string      `json:"method"`
+	ReceivedBy  uint        `json:"received_by"`
+}
+
+func (e PaymentReceived) EventType() string { return EventTypePaymentReceived }
+func (e PaymentReceived) EventActor() uint  { return e.ReceivedBy }
+
+// CustomerBalanceChanged is raised when a sales order is booked, edited or paid. Unlike
+// other events it is also applied: the repository moves the balance by Amount in the same
+// transaction that stores it.
+type CustomerBalanceChanged struct {
+	CustomerID uint        `json:"customer_id"`
+	OrderID    uint        `json:"order_id"`
+	Amount     money.Money `json:"amount"` // In the base currency; negative for payments
+	ChangedBy  uint        `json:"changed_by"`
+}
+
+func (e CustomerBalanceChanged) EventType() string { return EventTypeBalanceChanged }
+func (e CustomerBalanceChanged) EventActor() uint  { return e.ChangedBy }
+
+// DomainEvent is an event in the transactional outbox. A new event goes to every
+// subscriber; after a failure only the subscribers in Pending are retried.
+type DomainEvent struct {
+	ID            uint       `json:"id" gorm:"primarykey"`
+	Type          string     `json:"type" gorm:"size:50;index;not null"`
+	Payload       string     `json:"payload" gorm:"type:text"` // JSON of the typed event
+	ActorID       uint       `json:"actor_id"`
+	Status        string     `json:"status" gorm:"size:20;index;default:'pending'"`
+	Pending       []string   `json:"pending" gorm:"serializer:json"` // Subscribers still to succeed after the first attempt
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index"`
+	ProcessedAt   *time.Time `json:"processed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// DomainEventFilter narrows the domain event list
+type DomainEventFilter struct {
+	Type   string
+	Status string
+}
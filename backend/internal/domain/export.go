@@ -0,0 +1,19 @@
+package domain
+
+// Exportable lists
+const (
+	ExportCustomers        = "customers"
+	ExportSalesOrders      = "sales-orders"
+	ExportProducts         = "products"
+	ExportProductionOrders = "production-orders"
+)
+
+// ExportFilter carries the same filters as the list endpoints; each list uses the ones it supports
+type ExportFilter struct {
+	Search     string // Customers and products
+	Tag        string // Customers
+	SegmentID  uint   // Customers
+	Status     string // Sales and production orders
+	CustomerID uint   // Sales orders
+	CategoryID uint   // Products
+}
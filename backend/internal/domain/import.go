@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Import types
+const (
+	ImportTypeCustomers = "customers"
+	ImportTypeProducts  = "products"
+)
+
+// Import job statuses
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Import row actions
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionError  = "error"
+)
+
+// ImportJob is a bulk import of customers or products from a CSV or XLSX file, run in
+// the background. A dry run validates every row and reports what would change without writing.
+type ImportJob struct {
+	ID            uint              `json:"id" gorm:"primarykey"`
+	Type          string            `json:"type" gorm:"not null;index"`
+	FileName      string            `json:"file_name"`
+	DryRun        bool              `json:"dry_run"`
+	Status        string            `json:"status" gorm:"not null;default:'pending'"`
+	TotalRows     int               `json:"total_rows"`
+	ProcessedRows int               `json:"processed_rows"`
+	CreatedCount  int               `json:"created_count"`
+	UpdatedCount  int               `json:"updated_count"`
+	FailedCount   int               `json:"failed_count"`
+	Error         string            `json:"error,omitempty"`
+	ResultsJSON   string            `json:"-" gorm:"type:text"`
+	Results       []ImportRowResult `json:"results,omitempty" gorm:"-"`
+	CreatedBy     uint              `json:"created_by"`
+	StartedAt     *time.Time        `json:"started_at"`
+	FinishedAt    *time.Time        `json:"finished_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Done reports whether the job has stopped running
+func (j *ImportJob) Done() bool {
+	return j.Status == ImportStatusCompleted || j.Status == ImportStatusFailed
+}
+
+// ImportRowResult is the outcome of one data row; Row is the spreadsheet row number
+// including the header, so it matches what the user sees in Excel
+type ImportRowResult struct {
+	Row    int      `json:"row"`
+	Key    string   `json:"key,omitempty"` // Customer code or product SKU
+	Action string   `json:"action"`
+	ID     uint     `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
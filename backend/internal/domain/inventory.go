@@ -1,27 +1,30 @@
 package domain
 
 import (
+	"erp-system/pkg/money"
 	"time"
 )
 
 // Product represents a product in inventory
 type Product struct {
-	ID            uint       `json:"id" gorm:"primarykey"`
-	SKU           string     `json:"sku" gorm:"unique;not null;index"`
-	Name          string     `json:"name" gorm:"not null"`
-	Description   string     `json:"description"`
-	CategoryID    uint       `json:"category_id"`
-	Category      Category   `json:"category" gorm:"foreignKey:CategoryID"`
-	UnitID        uint       `json:"unit_id"`
-	CostPrice     float64    `json:"cost_price" gorm:"default:0"`
-	SellingPrice  float64    `json:"selling_price" gorm:"default:0"`
-	ReorderLevel  int        `json:"reorder_level" gorm:"default:10"`
-	MaxStockLevel int        `json:"max_stock_level"`
-	StockQuantity int        `json:"stock_quantity" gorm:"default:0"`
-	IsActive      bool       `json:"is_active" gorm:"default:true"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
-	DeletedAt     *time.Time `json:"-" gorm:"index"`
+	ID            uint        `json:"id" gorm:"primarykey"`
+	SKU           string      `json:"sku" gorm:"unique;not null;index"`
+	Name          string      `json:"name" gorm:"not null"`
+	Description   string      `json:"description"`
+	CategoryID    uint        `json:"category_id"`
+	Category      Category    `json:"category" gorm:"foreignKey:CategoryID"`
+	UnitID        uint        `json:"unit_id"`
+	TaxCodeID     *uint       `json:"tax_code_id"`
+	TaxCode       *TaxCode    `json:"tax_code,omitempty" gorm:"foreignKey:TaxCodeID"`
+	CostPrice     money.Money `json:"cost_price" gorm:"default:0"`
+	SellingPrice  money.Money `json:"selling_price" gorm:"default:0"`
+	ReorderLevel  int         `json:"reorder_level" gorm:"default:10"`
+	MaxStockLevel int         `json:"max_stock_level"`
+	StockQuantity int         `json:"stock_quantity" gorm:"default:0"`
+	IsActive      bool        `json:"is_active" gorm:"default:true"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	DeletedAt     *time.Time  `json:"-" gorm:"index"`
 }
 
 // Category represents a product category
@@ -46,28 +49,54 @@ type Warehouse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// WarehouseStock is the on-hand quantity of a product in one warehouse
+type WarehouseStock struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	WarehouseID uint      `json:"warehouse_id" gorm:"not null;uniqueIndex:idx_warehouse_product"`
+	ProductID   uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_warehouse_product"`
+	Product     *Product  `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	Quantity    float64   `json:"quantity" gorm:"default:0"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateWarehouseRequest
+type CreateWarehouseRequest struct {
+	Code      string `json:"code" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+	Address   string `json:"address"`
+	ManagerID uint   `json:"manager_id"`
+}
+
+// SetWarehouseStockRequest sets the counted quantity of a product in a warehouse
+type SetWarehouseStockRequest struct {
+	ProductID uint    `json:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"gte=0"`
+}
+
 // CreateProductRequest
 type CreateProductRequest struct {
-	SKU           string  `json:"sku" binding:"required"`
-	Name          string  `json:"name" binding:"required"`
-	Description   string  `json:"description"`
-	CategoryID    uint    `json:"category_id"`
-	CostPrice     float64 `json:"cost_price" binding:"gte=0"`
-	SellingPrice  float64 `json:"selling_price" binding:"gte=0"`
-	ReorderLevel  int     `json:"reorder_level"`
-	MaxStockLevel int     `json:"max_stock_level"`
-	StockQuantity int     `json:"stock_quantity" binding:"gte=0"`
+	SKU           string      `json:"sku" binding:"required"`
+	Name          string      `json:"name" binding:"required"`
+	Description   string      `json:"description"`
+	CategoryID    uint        `json:"category_id"`
+	TaxCodeID     *uint       `json:"tax_code_id"`
+	CostPrice     money.Money `json:"cost_price"`
+	SellingPrice  money.Money `json:"selling_price"`
+	ReorderLevel  int         `json:"reorder_level"`
+	MaxStockLevel int         `json:"max_stock_level"`
+	StockQuantity int         `json:"stock_quantity" binding:"gte=0"`
 }
 
 // UpdateProductRequest
 type UpdateProductRequest struct {
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	CategoryID    uint    `json:"category_id"`
-	CostPrice     float64 `json:"cost_price"`
-	SellingPrice  float64 `json:"selling_price"`
-	ReorderLevel  int     `json:"reorder_level"`
-	MaxStockLevel int     `json:"max_stock_level"`
-	StockQuantity *int    `json:"stock_quantity"`
-	IsActive      *bool   `json:"is_active"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	CategoryID    uint        `json:"category_id"`
+	TaxCodeID     *uint       `json:"tax_code_id"`
+	CostPrice     money.Money `json:"cost_price"`
+	SellingPrice  money.Money `json:"selling_price"`
+	ReorderLevel  int         `json:"reorder_level"`
+	MaxStockLevel int         `json:"max_stock_level"`
+	StockQuantity *int        `json:"stock_quantity"`
+	IsActive      *bool       `json:"is_active"`
 }
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// Message template keys
+const (
+	TemplateActivityReminder = "activity_reminder"
+	TemplateActivityAlert    = "activity_alert"
+	TemplateOrderStatus      = "order_status"
+	TemplatePaymentReceipt   = "payment_receipt"
+)
+
+// Customer languages
+const (
+	LanguageArabic  = "ar"
+	LanguageEnglish = "en"
+)
+
+// TemplatePlaceholders lists the placeholders templates can use and what they are replaced by.
+// Placeholders a message has no value for are left as they are.
+var TemplatePlaceholders = map[string]string{
+	"customer_name":     "Customer name",
+	"company_name":      "Company name from settings",
+	"description":       "Activity description (reminders and alerts)",
+	"reminder_date":     "Activity reminder date",
+	"order_number":      "Sales order number",
+	"order_status":      "Order status in the customer's language",
+	"installation_date": "The order's delivery date, when installation is scheduled",
+	"order_total":       "Order total with currency",
+	"amount_paid":       "Payment amount with currency (receipts)",
+	"amount_due":        "Outstanding order amount with currency",
+}
+
+// MessageTemplate is the text of a customer message in Arabic and English. Placeholders are
+// written in braces, e.g. {customer_name}. Templates not saved yet use built-in defaults.
+type MessageTemplate struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Key       string    `json:"key" gorm:"uniqueIndex;not null"`
+	Name      string    `json:"name"`
+	SubjectAr string    `json:"subject_ar"`
+	BodyAr    string    `json:"body_ar" gorm:"type:text"`
+	SubjectEn string    `json:"subject_en"`
+	BodyEn    string    `json:"body_en" gorm:"type:text"` // Empty falls back to Arabic
+	IsDefault bool      `json:"is_default" gorm:"-"`      // Not customised yet
+	UpdatedBy uint      `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SaveMessageTemplateRequest for customising a template
+type SaveMessageTemplateRequest struct {
+	Name      string `json:"name"`
+	SubjectAr string `json:"subject_ar"`
+	BodyAr    string `json:"body_ar" binding:"required"`
+	SubjectEn string `json:"subject_en"`
+	BodyEn    string `json:"body_en"`
+}
+
+// PreviewTemplateRequest renders a stored template, or the given draft text, for a customer
+// and optionally an order. Variables override the values taken from them.
+type PreviewTemplateRequest struct {
+	Key        string                      `json:"key"`
+	Template   *SaveMessageTemplateRequest `json:"template"` // Draft text to render instead of the stored template
+	Language   string                      `json:"language" binding:"omitempty,oneof=ar en"`
+	CustomerID uint                        `json:"customer_id"`
+	OrderID    uint                        `json:"order_id"`
+	Variables  map[string]string           `json:"variables"`
+}
+
+// RenderedMessage is a template filled in for one customer
+type RenderedMessage struct {
+	Language string `json:"language"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+}
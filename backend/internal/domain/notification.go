@@ -12,3 +12,9 @@ type Notification struct {
 	IsRead    bool      `json:"is_read" gorm:"default:false"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// NotificationFilter narrows a user's notification history
+type NotificationFilter struct {
+	IsRead *bool  // Nil for read and unread
+	Type   string // info, warning, success, error; empty for all
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// Outbound message statuses
+const (
+	MessageStatusQueued = "queued"
+	MessageStatusSent   = "sent"
+	MessageStatusFailed = "failed" // The last attempt failed and another is scheduled
+	MessageStatusDead   = "dead"   // Given up on; only a resend delivers it
+)
+
+// Outbound message sources
+const (
+	MessageSourceActivity = "activity"
+	MessageSourceReminder = "reminder"
+	MessageSourceOrder    = "order"
+	MessageSourcePayment  = "payment"
+	MessageSourceResend   = "resend"
+	MessageSourceEvent    = "event" // Notifications to users about subscribed events
+)
+
+// OutboundMessage is a customer message in the outbox. Messages are delivered by the outbox
+// worker and retried with exponential backoff until they are sent or run out of attempts.
+type OutboundMessage struct {
+	ID             uint              `json:"id" gorm:"primarykey"`
+	Channel        string            `json:"channel" gorm:"index"` // whatsapp, sms, email
+	Recipient      string            `json:"recipient"`
+	Subject        string            `json:"subject"`
+	Body           string            `json:"body" gorm:"type:text"`
+	CustomerID     *uint             `json:"customer_id" gorm:"index"`
+	Customer       *Customer         `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
+	Source         string            `json:"source"`                                              // activity, reminder, order, payment, resend
+	Template       string            `json:"template"`                                            // Key of the template the message was written from
+	IdempotencyKey *string           `json:"idempotency_key,omitempty" gorm:"uniqueIndex"`        // Queuing the same key twice returns the first message
+	Status         string            `json:"status" gorm:"default:'queued';index:idx_outbox_due"` // queued, sent, failed, dead
+	Attempts       int               `json:"attempts" gorm:"default:0"`
+	MaxAttempts    int               `json:"max_attempts"`
+	NextAttemptAt  time.Time         `json:"next_attempt_at" gorm:"index:idx_outbox_due"`
+	LastError      string            `json:"last_error"`
+	SentAt         *time.Time        `json:"sent_at"`
+	ResendOf       *uint             `json:"resend_of"`
+	CreatedBy      uint              `json:"created_by"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Deliveries     []MessageDelivery `json:"deliveries,omitempty" gorm:"foreignKey:MessageID"`
+}
+
+// MessageDelivery logs one attempt to deliver an outbound message
+type MessageDelivery struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	MessageID  uint      `json:"message_id" gorm:"index"`
+	Attempt    int       `json:"attempt"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OutboundMessageFilter holds the outbox list filters
+type OutboundMessageFilter struct {
+	Status     string
+	Channel    string
+	Source     string
+	CustomerID uint
+}
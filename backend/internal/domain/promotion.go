@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"time"
+)
+
+// Promotion represents a configurable discount rule
+type Promotion struct {
+	ID            uint        `json:"id" gorm:"primarykey"`
+	Name          string      `json:"name" gorm:"not null"`
+	Code          *string     `json:"code" gorm:"uniqueIndex"`  // Coupon code, nil for automatic promotions
+	Type          string      `json:"type" gorm:"not null"`     // percent, fixed
+	Value         float64     `json:"value" gorm:"not null"`    // Percentage or fixed amount per unit
+	ProductID     *uint       `json:"product_id" gorm:"index"`  // Limit to a product
+	CategoryID    *uint       `json:"category_id" gorm:"index"` // Limit to a category
+	MinOrderValue money.Money `json:"min_order_value" gorm:"default:0"`
+	StartDate     *time.Time  `json:"start_date"`
+	EndDate       *time.Time  `json:"end_date"`
+	IsActive      bool        `json:"is_active" gorm:"default:true"`
+	CreatedBy     uint        `json:"created_by"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	DeletedAt     *time.Time  `json:"-" gorm:"index"`
+}
+
+// Promotion types
+const (
+	PromotionTypePercent = "percent"
+	PromotionTypeFixed   = "fixed"
+)
+
+// IsValidAt reports whether the promotion is active on the given date
+func (p *Promotion) IsValidAt(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartDate != nil && t.Before(*p.StartDate) {
+		return false
+	}
+	if p.EndDate != nil && t.After(*p.EndDate) {
+		return false
+	}
+	return true
+}
+
+// AppliesTo reports whether the promotion covers a product in a category
+func (p *Promotion) AppliesTo(productID, categoryID uint) bool {
+	if p.ProductID != nil && *p.ProductID != productID {
+		return false
+	}
+	if p.CategoryID != nil && *p.CategoryID != categoryID {
+		return false
+	}
+	return true
+}
+
+// LineDiscount calculates the discount amount for a line priced in a currency quoted at
+// exchangeRate; fixed amounts are defined in the base currency and converted
+func (p *Promotion) LineDiscount(quantity float64, unitPrice money.Money, exchangeRate float64) money.Money {
+	lineTotal := unitPrice.MulFloat(quantity)
+	var discount money.Money
+	switch p.Type {
+	case PromotionTypePercent:
+		discount = lineTotal.Percent(p.Value)
+	case PromotionTypeFixed:
+		discount = money.FromFloat(p.Value).MulFloat(quantity).Ratio(1, exchangeRate)
+	}
+	return money.Min(discount, lineTotal)
+}
+
+// DiscountPolicy caps the manual discount a role may give without approval
+type DiscountPolicy struct {
+	ID                 uint      `json:"id" gorm:"primarykey"`
+	RoleID             uint      `json:"role_id" gorm:"uniqueIndex;not null"`
+	Role               *Role     `json:"role,omitempty" gorm:"foreignKey:RoleID"`
+	MaxDiscountPercent float64   `json:"max_discount_percent" gorm:"default:0"`
+	ApproverRoleID     uint      `json:"approver_role_id" gorm:"default:2"` // Role notified for approval (Manager)
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// CreatePromotionRequest
+type CreatePromotionRequest struct {
+	Name          string      `json:"name" binding:"required"`
+	Code          string      `json:"code"`
+	Type          string      `json:"type" binding:"required,oneof=percent fixed"`
+	Value         float64     `json:"value" binding:"required,gt=0"`
+	ProductID     *uint       `json:"product_id"`
+	CategoryID    *uint       `json:"category_id"`
+	MinOrderValue money.Money `json:"min_order_value"`
+	StartDate     *time.Time  `json:"start_date"`
+	EndDate       *time.Time  `json:"end_date"`
+	IsActive      *bool       `json:"is_active"`
+}
+
+// UpdatePromotionRequest
+type UpdatePromotionRequest struct {
+	Name          string       `json:"name"`
+	Type          string       `json:"type" binding:"omitempty,oneof=percent fixed"`
+	Value         float64      `json:"value" binding:"gte=0"`
+	ProductID     *uint        `json:"product_id"`
+	CategoryID    *uint        `json:"category_id"`
+	MinOrderValue *money.Money `json:"min_order_value"`
+	StartDate     *time.Time   `json:"start_date"`
+	EndDate       *time.Time   `json:"end_date"`
+	IsActive      *bool        `json:"is_active"`
+}
+
+// SetDiscountPolicyRequest
+type SetDiscountPolicyRequest struct {
+	RoleID             uint    `json:"role_id" binding:"required"`
+	MaxDiscountPercent float64 `json:"max_discount_percent" binding:"gte=0,lte=100"`
+	ApproverRoleID     uint    `json:"approver_role_id"`
+}
+
+// OrderApprovalRequest carries the manager's decision note
+type OrderApprovalRequest struct {
+	Note string `json:"note"`
+}
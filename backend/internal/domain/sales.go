@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"erp-system/pkg/money"
 	"time"
 )
 
@@ -12,14 +13,28 @@ type SalesOrder struct {
 	Customer       Customer         `json:"customer" gorm:"foreignKey:CustomerID"`
 	OrderDate      time.Time        `json:"order_date" gorm:"not null"`
 	DeliveryDate   *time.Time       `json:"delivery_date"`
-	Status         string           `json:"status" gorm:"default:'draft'"` // draft, confirmed, shipped, delivered, cancelled
-	TotalAmount    float64          `json:"total_amount" gorm:"default:0"`
-	TaxAmount      float64          `json:"tax_amount" gorm:"default:0"`
-	DiscountAmount float64          `json:"discount_amount" gorm:"default:0"`
-	NetAmount      float64          `json:"net_amount" gorm:"default:0"`
+	Status         string           `json:"status" gorm:"default:'draft'"` // pending_approval, credit_hold, draft, confirmed, shipped, delivered, cancelled
+	TotalAmount    money.Money      `json:"total_amount" gorm:"default:0"`
+	TaxAmount      money.Money      `json:"tax_amount" gorm:"default:0"`
+	DiscountAmount money.Money      `json:"discount_amount" gorm:"default:0"`
+	NetAmount      money.Money      `json:"net_amount" gorm:"default:0"`
+	Currency       string           `json:"currency" gorm:"size:3;default:'EGP'"`
+	ExchangeRate   float64          `json:"exchange_rate" gorm:"default:1"` // Base currency units per order currency unit at the order date
+	BaseNetAmount  money.Money      `json:"base_net_amount" gorm:"default:0"`
+	PaidAmount     money.Money      `json:"paid_amount" gorm:"default:0"`                // In the order currency
+	TaxPricingMode string           `json:"tax_pricing_mode" gorm:"default:'exclusive'"` // exclusive, inclusive
+	TaxRounding    string           `json:"tax_rounding" gorm:"default:'line'"`          // line, document
+	CouponCode     string           `json:"coupon_code"`
 	Notes          string           `json:"notes"`
 	CreatedBy      uint             `json:"created_by"`
+	ApprovedBy     *uint            `json:"approved_by"`
+	ApprovedAt     *time.Time       `json:"approved_at"`
+	ApprovalNote   string           `json:"approval_note"`
+	CreditNote     string           `json:"credit_note"` // Why credit control blocked or released the order
+	Revision       int              `json:"revision" gorm:"default:1"`
 	Items          []SalesOrderItem `json:"items" gorm:"foreignKey:OrderID"`
+	TaxSummary     []SalesOrderTax  `json:"tax_summary" gorm:"foreignKey:OrderID"`
+	Payments       []SalesPayment   `json:"payments,omitempty" gorm:"foreignKey:OrderID"`
 	CreatedAt      time.Time        `json:"created_at"`
 	UpdatedAt      time.Time        `json:"updated_at"`
 	DeletedAt      *time.Time       `json:"-" gorm:"index"`
@@ -27,17 +42,24 @@ type SalesOrder struct {
 
 // SalesOrderItem represents an item in a sales order
 type SalesOrderItem struct {
-	ID        uint       `json:"id" gorm:"primarykey"`
-	OrderID   uint       `json:"order_id" gorm:"not null;index"`
-	ProductID uint       `json:"product_id" gorm:"not null"`
-	Quantity  float64    `json:"quantity" gorm:"not null"`
-	UnitPrice float64    `json:"unit_price" gorm:"not null"`
-	Discount  float64    `json:"discount" gorm:"default:0"`
-	TaxRate   float64    `json:"tax_rate" gorm:"default:0"`
-	Total     float64    `json:"total" gorm:"not null"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"-" gorm:"index"`
+	ID                uint        `json:"id" gorm:"primarykey"`
+	OrderID           uint        `json:"order_id" gorm:"not null;index"`
+	ProductID         uint        `json:"product_id" gorm:"not null"`
+	Product           *Product    `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	Quantity          float64     `json:"quantity" gorm:"not null"`
+	UnitPrice         money.Money `json:"unit_price" gorm:"not null"`
+	Discount          float64     `json:"discount" gorm:"default:0"` // Manual discount percentage
+	PromotionID       *uint       `json:"promotion_id"`
+	PromotionDiscount money.Money `json:"promotion_discount" gorm:"default:0"`
+	TaxCodeID         *uint       `json:"tax_code_id"`
+	TaxRate           float64     `json:"tax_rate" gorm:"default:0"`
+	TaxAmount         money.Money `json:"tax_amount" gorm:"default:0"`
+	Total             money.Money `json:"total" gorm:"not null"`
+	ShippedQuantity   float64     `json:"shipped_quantity" gorm:"default:0"`
+	DeliveredQuantity float64     `json:"delivered_quantity" gorm:"default:0"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+	DeletedAt         *time.Time  `json:"-" gorm:"index"`
 }
 
 // CreateOrderRequest
@@ -46,13 +68,50 @@ type CreateOrderRequest struct {
 	OrderDate    time.Time                `json:"order_date" binding:"required"`
 	DeliveryDate *time.Time               `json:"delivery_date"`
 	Notes        string                   `json:"notes"`
+	CouponCode   string                   `json:"coupon_code"`
+	Currency     string                   `json:"currency"` // Defaults to the customer currency
 	Items        []CreateOrderItemRequest `json:"items" binding:"required,dive"`
 }
 
 type CreateOrderItemRequest struct {
-	ProductID uint    `json:"product_id" binding:"required"`
-	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
-	UnitPrice float64 `json:"unit_price" binding:"required,gte=0"`
-	Discount  float64 `json:"discount"`
-	TaxRate   float64 `json:"tax_rate"`
+	ProductID uint        `json:"product_id" binding:"required"`
+	Quantity  float64     `json:"quantity" binding:"required,gt=0"`
+	UnitPrice money.Money `json:"unit_price"`
+	Discount  float64     `json:"discount" binding:"gte=0,lte=100"`
+	TaxCodeID *uint       `json:"tax_code_id"` // Overrides the customer/product tax code
+	TaxRate   float64     `json:"tax_rate"`    // Used when no tax code applies
 }
+
+// UpdateOrderRequest replaces the order lines. Lines with an ID change that line,
+// lines without one are added and lines left out are removed.
+type UpdateOrderRequest struct {
+	DeliveryDate *time.Time               `json:"delivery_date"`
+	Notes        *string                  `json:"notes"`
+	CouponCode   *string                  `json:"coupon_code"`
+	Items        []UpdateOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+	Reason       string                   `json:"reason"` // Required when revising a confirmed order
+}
+
+type UpdateOrderItemRequest struct {
+	ID uint `json:"id"`
+	CreateOrderItemRequest
+}
+
+// Printable sales document kinds
+const (
+	SalesDocumentQuote        = "quote"
+	SalesDocumentOrder        = "order"
+	SalesDocumentInvoice      = "invoice"
+	SalesDocumentDeliveryNote = "delivery_note"
+)
+
+// Sales order statuses
+const (
+	OrderStatusPendingApproval = "pending_approval"
+	OrderStatusCreditHold      = "credit_hold"
+	OrderStatusDraft           = "draft"
+	OrderStatusConfirmed       = "confirmed"
+	OrderStatusShipped         = "shipped"
+	OrderStatusDelivered       = "delivered"
+	OrderStatusCancelled       = "cancelled"
+)
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"time"
+)
+
+// SalesOrderRevision keeps a confirmed order as it was before a revision replaced it
+type SalesOrderRevision struct {
+	ID        uint        `json:"id" gorm:"primarykey"`
+	OrderID   uint        `json:"order_id" gorm:"not null;uniqueIndex:idx_order_revision"`
+	Revision  int         `json:"revision" gorm:"not null;uniqueIndex:idx_order_revision"`
+	Snapshot  string      `json:"-" gorm:"type:text;not null"` // JSON of the SalesOrder with its items
+	NetAmount money.Money `json:"net_amount"`
+	Reason    string      `json:"reason"`
+	CreatedBy uint        `json:"created_by"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// FieldChange is one value that differs between two order revisions
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Order line changes
+const (
+	ItemChangeAdded   = "added"
+	ItemChangeRemoved = "removed"
+	ItemChangeChanged = "changed"
+)
+
+type OrderItemChange struct {
+	ItemID    uint          `json:"item_id"`
+	ProductID uint          `json:"product_id"`
+	Change    string        `json:"change"`
+	Fields    []FieldChange `json:"fields,omitempty"`
+}
+
+// SalesOrderDiff lists what changed between two revisions of an order
+type SalesOrderDiff struct {
+	OrderID uint              `json:"order_id"`
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Fields  []FieldChange     `json:"fields"`
+	Items   []OrderItemChange `json:"items"`
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag is a free-form label on customers
+type Tag struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	Name          string    `json:"name" gorm:"uniqueIndex;not null"`
+	Color         string    `json:"color"`
+	CustomerCount int64     `json:"customer_count" gorm:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Segment fields
+const (
+	SegmentFieldCity           = "city"
+	SegmentFieldGovernorate    = "governorate"
+	SegmentFieldType           = "type"
+	SegmentFieldStatus         = "status"
+	SegmentFieldTag            = "tag"
+	SegmentFieldBalance        = "balance"
+	SegmentFieldCreditLimit    = "credit_limit"
+	SegmentFieldTotalPurchases = "total_purchases" // Confirmed, shipped and delivered orders in the base currency
+	SegmentFieldLastOrderDate  = "last_order_date"
+)
+
+// segmentOperators lists the operators each field accepts
+var segmentOperators = map[string][]string{
+	SegmentFieldCity:           {"eq", "neq", "in", "contains"},
+	SegmentFieldGovernorate:    {"eq", "neq", "in", "contains"},
+	SegmentFieldType:           {"eq", "neq", "in"},
+	SegmentFieldStatus:         {"eq", "neq", "in"},
+	SegmentFieldTag:            {"has", "not_has"},
+	SegmentFieldBalance:        {"eq", "gt", "gte", "lt", "lte"},
+	SegmentFieldCreditLimit:    {"eq", "gt", "gte", "lt", "lte"},
+	SegmentFieldTotalPurchases: {"eq", "gt", "gte", "lt", "lte"},
+	SegmentFieldLastOrderDate:  {"before", "after", "within_days", "not_within_days", "never"},
+}
+
+// SegmentRule is one condition on customers. Values are text for text fields, a decimal
+// amount for money fields, YYYY-MM-DD for before/after, a day count for within_days and
+// not_within_days, and a comma-separated list for in.
+type SegmentRule struct {
+	Field    string `json:"field" binding:"required"`
+	Operator string `json:"operator" binding:"required"`
+	Value    string `json:"value"`
+}
+
+// Validate checks the field, operator and value
+func (r SegmentRule) Validate() error {
+	ops, ok := segmentOperators[r.Field]
+	if !ok {
+		return fmt.Errorf("unknown segment field %q", r.Field)
+	}
+	valid := false
+	for _, op := range ops {
+		valid = valid || op == r.Operator
+	}
+	if !valid {
+		return fmt.Errorf("operator %q is not supported for %s, use one of %s", r.Operator, r.Field, strings.Join(ops, ", "))
+	}
+
+	value := strings.TrimSpace(r.Value)
+	switch {
+	case r.Operator == "never":
+		return nil
+	case value == "":
+		return fmt.Errorf("%s %s needs a value", r.Field, r.Operator)
+	case r.Field == SegmentFieldBalance || r.Field == SegmentFieldCreditLimit || r.Field == SegmentFieldTotalPurchases:
+		if _, err := money.Parse(value); err != nil {
+			return fmt.Errorf("%s needs an amount, got %q", r.Field, r.Value)
+		}
+	case r.Operator == "before" || r.Operator == "after":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("%s %s needs a date as YYYY-MM-DD, got %q", r.Field, r.Operator, r.Value)
+		}
+	case r.Operator == "within_days" || r.Operator == "not_within_days":
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s %s needs a number of days, got %q", r.Field, r.Operator, r.Value)
+		}
+	}
+	return nil
+}
+
+// Segment matches
+const (
+	SegmentMatchAll = "all"
+	SegmentMatchAny = "any"
+)
+
+// Segment is a saved, dynamic group of customers; membership is evaluated from its rules on
+// every use, so it always reflects current data
+type Segment struct {
+	ID          uint          `json:"id" gorm:"primarykey"`
+	Name        string        `json:"name" gorm:"uniqueIndex;not null"`
+	Description string        `json:"description"`
+	Match       string        `json:"match" gorm:"default:'all'"` // all, any
+	Rules       []SegmentRule `json:"rules" gorm:"serializer:json"`
+	MemberCount int64         `json:"member_count" gorm:"-"`
+	CreatedBy   uint          `json:"created_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// Campaign statuses
+const (
+	CampaignStatusSending   = "sending"
+	CampaignStatusCompleted = "completed"
+)
+
+// WhatsAppCampaign is a message sent to every member of a segment who accepts WhatsApp
+type WhatsAppCampaign struct {
+	ID           uint       `json:"id" gorm:"primarykey"`
+	SegmentID    uint       `json:"segment_id" gorm:"index"`
+	SegmentName  string     `json:"segment_name"`
+	Message      string     `json:"message" gorm:"type:text"`
+	Status       string     `json:"status"`
+	Recipients   int        `json:"recipients"`
+	SentCount    int        `json:"sent_count"`
+	FailedCount  int        `json:"failed_count"`
+	SkippedCount int        `json:"skipped_count"` // Members without WhatsApp or a phone number
+	CreatedBy    uint       `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
+}
+
+// CustomerFilter holds the customer list filters
+type CustomerFilter struct {
+	Search    string
+	Tag       string
+	SegmentID uint
+}
+
+// CreateTagRequest for creating a tag
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+// CustomerTagsRequest adds tags to a customer by name; unknown tags are created
+type CustomerTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1"`
+}
+
+// SaveSegmentRequest for creating or updating a segment
+type SaveSegmentRequest struct {
+	Name        string        `json:"name" binding:"required"`
+	Description string        `json:"description"`
+	Match       string        `json:"match"`
+	Rules       []SegmentRule `json:"rules" binding:"required,min=1,dive"`
+}
+
+// SendCampaignRequest sends a WhatsApp message to a segment; {name} is replaced by the customer name
+type SendCampaignRequest struct {
+	Message string `json:"message" binding:"required"`
+}
@@ -13,6 +13,55 @@ type SystemSetting struct {
 
 // Default settings keys
 const (
-	SettingWhatsAppURL   = "whatsapp_api_url"
-	SettingWhatsAppToken = "whatsapp_api_token"
+	SettingWhatsAppProvider      = "whatsapp_provider"         // webhook (default, posts to whatsapp_api_url) or cloud
+	SettingWhatsAppURL           = "whatsapp_api_url"          // Gateway URL for the webhook provider
+	SettingWhatsAppToken         = "whatsapp_api_token"        // Bearer token for either provider
+	SettingWhatsAppPhoneNumberID = "whatsapp_phone_number_id"  // Cloud API sender number
+	SettingWhatsAppCloudURL      = "whatsapp_cloud_url"        // Graph API base, default https://graph.facebook.com/v19.0
+	SettingWhatsAppAppSecret     = "whatsapp_app_secret"       // Signs inbound webhooks; they are rejected until it is set
+	SettingWhatsAppVerifyToken   = "whatsapp_verify_token"     // Echoed back when the Cloud API subscribes the webhook
+	SettingWhatsAppOptOut        = "whatsapp_opt_out_keywords" // Comma-separated replies that stop WhatsApp messages
+	SettingSMSURL                = "sms_api_url"               // HTTP SMS gateway
+	SettingSMSToken              = "sms_api_token"
+	SettingSMSSender             = "sms_sender" // Sender ID, if the gateway needs one
+	SettingSMTPHost              = "smtp_host"
+	SettingSMTPPort              = "smtp_port" // Default 587; 465 uses implicit TLS
+	SettingSMTPUsername          = "smtp_username"
+	SettingSMTPPassword          = "smtp_password"
+	SettingSMTPFrom              = "smtp_from"               // e.g. Company <info@example.com>
+	SettingChannelOrder          = "messaging_channel_order" // Comma-separated fallback order, default whatsapp,sms,email
+	SettingEventWebhookURL       = "event_webhook_url"       // Receives every domain event as JSON; empty to send none
+	SettingEventWebhookSecret    = "event_webhook_secret"    // Signs event webhooks in the X-Hub-Signature-256 header
+
+	SettingTaxPricingMode = "tax_pricing_mode" // exclusive, inclusive
+	SettingTaxRounding    = "tax_rounding"     // line, document
+
+	SettingCompanyName      = "company_name"
+	SettingCompanyLogo      = "company_logo" // Storage key of the uploaded logo; older values are /uploads/settings/... paths
+	SettingCompanyAddress   = "company_address"
+	SettingCompanyPhone     = "company_phone"
+	SettingCompanyTaxNumber = "company_tax_number"
+
+	SettingPDFFont     = "pdf_font_path"      // TrueType font with Arabic coverage
+	SettingPDFFontBold = "pdf_font_bold_path" // Optional bold variant
+
+	SettingCreditWarningPercent = "credit_warning_percent"    // Utilisation that triggers a warning, default 80
+	SettingCreditGraceDays      = "credit_overdue_grace_days" // Days past the payment terms before a hold, default 0
+	SettingCreditApproverRole   = "credit_approver_role_id"   // Role notified about blocked orders, default Manager
+
+	SettingExportMaxRows = "export_max_rows" // Row cap for CSV/XLSX exports, default 50000
+
+	SettingDocumentMaxSizeMB    = "document_max_size_mb"   // Largest customer document upload, default 10
+	SettingDocumentAllowedTypes = "document_allowed_types" // Comma-separated MIME types accepted for customer documents
+
+	SettingStorageBackend    = "storage_backend"     // local (default) or s3; read at startup
+	SettingStorageSigningKey = "storage_signing_key" // Signs links to files on the local disk, generated on first start
+	SettingS3Endpoint        = "s3_endpoint"         // e.g. https://s3.amazonaws.com or http://localhost:9000
+	SettingS3Region          = "s3_region"
+	SettingS3Bucket          = "s3_bucket"
+	SettingS3AccessKey       = "s3_access_key"
+	SettingS3SecretKey       = "s3_secret_key"
+	SettingS3PathStyle       = "s3_path_style" // true for MinIO and other stores without bucket host names
+
+	SettingMoneyMinorUnits = "schema_money_minor_units" // Set once amounts are stored in minor units
 )
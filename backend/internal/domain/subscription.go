@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notification events users can subscribe to
+const (
+	EventOrderCreated        = "order_created" // A sales order was placed for a customer of the user's branch
+	EventLowStock            = "low_stock"     // A product's stock fell to its reorder level
+	EventProductionCompleted = "production_completed"
+	EventReminderDue         = "reminder_due"  // An activity reminder assigned to the user is due
+	EventCreditBreach        = "credit_breach" // Credit warnings, holds and orders blocked on credit
+)
+
+// DeliveryInApp delivers a notification to the bell in the app; ChannelEmail and
+// ChannelWhatsApp deliver it through the outbox
+const DeliveryInApp = "in_app"
+
+// NotificationEventType describes an event users can subscribe to. Events that are
+// AudienceOnly only reach the users they are about, such as an activity's assignee; users
+// can turn those off or change their channels but not subscribe to other users' events.
+type NotificationEventType struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	AudienceOnly bool   `json:"audience_only"`
+}
+
+// NotificationEventTypes lists every event users can subscribe to
+var NotificationEventTypes = []NotificationEventType{
+	{Key: EventOrderCreated, Name: "طلب مبيعات جديد في فرعي"},
+	{Key: EventLowStock, Name: "انخفاض المخزون"},
+	{Key: EventProductionCompleted, Name: "اكتمال أمر إنتاج"},
+	{Key: EventReminderDue, Name: "تذكير مستحق", AudienceOnly: true},
+	{Key: EventCreditBreach, Name: "تجاوز الحد الائتماني"},
+}
+
+// FindNotificationEventType returns the event type with the given key
+func FindNotificationEventType(key string) (NotificationEventType, bool) {
+	for _, t := range NotificationEventTypes {
+		if t.Key == key {
+			return t, true
+		}
+	}
+	return NotificationEventType{}, false
+}
+
+// NotificationSubscription is a user's choice for one event. Without one, users hear about
+// events they are the audience of in the app, and about nothing else.
+type NotificationSubscription struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_subscription_user_event;not null"`
+	Event     string    `json:"event" gorm:"uniqueIndex:idx_subscription_user_event;not null"`
+	Enabled   bool      `json:"enabled"`
+	Channels  []string  `json:"channels" gorm:"serializer:json"` // in_app, email, whatsapp
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationPreference holds a user's delivery settings. During quiet hours, in server
+// time, email and WhatsApp notifications wait until the quiet hours end; in-app ones are
+// still created since they make no sound.
+type NotificationPreference struct {
+	UserID          uint      `json:"user_id" gorm:"primarykey;autoIncrement:false"`
+	QuietHoursStart string    `json:"quiet_hours_start"` // HH:MM; empty for no quiet hours
+	QuietHoursEnd   string    `json:"quiet_hours_end"`   // HH:MM; may be earlier than the start to span midnight
+	WhatsAppNumber  string    `json:"whatsapp_number"`   // Where WhatsApp notifications go
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// QuietUntil returns when the quiet hours around t end, or the zero time when t is outside
+// quiet hours
+func (p *NotificationPreference) QuietUntil(t time.Time) time.Time {
+	if p == nil || p.QuietHoursStart == "" || p.QuietHoursEnd == "" {
+		return time.Time{}
+	}
+	start, err1 := time.Parse("15:04", p.QuietHoursStart)
+	end, err2 := time.Parse("15:04", p.QuietHoursEnd)
+	if err1 != nil || err2 != nil || start.Equal(end) {
+		return time.Time{}
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	from, to := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch {
+	case from < to && minute >= from && minute < to:
+		return day.Add(time.Duration(to) * time.Minute)
+	case from > to && minute >= from:
+		return day.AddDate(0, 0, 1).Add(time.Duration(to) * time.Minute)
+	case from > to && minute < to:
+		return day.Add(time.Duration(to) * time.Minute)
+	}
+	return time.Time{}
+}
+
+// NotificationEvent is something that happened which users may be told about
+type NotificationEvent struct {
+	Type      string
+	Title     string
+	Message   string
+	Link      string
+	NotifType string // info, warning, success, error
+	BranchID  *uint  // Subscribers assigned to another branch don't hear about it
+	Audience  []uint // Users the event is about; they hear about it unless they opted out
+	Actor     uint   // User who caused the event; they aren't told about it
+	Key       string // Identifies the event so email and WhatsApp copies are only queued once
+}
+
+// SubscriptionRequest sets a user's choice for one event
+type SubscriptionRequest struct {
+	Event    string   `json:"event" binding:"required"`
+	Enabled  bool     `json:"enabled"`
+	Channels []string `json:"channels" binding:"dive,oneof=in_app email whatsapp"`
+}
+
+// UpdatePreferencesRequest replaces a user's delivery settings and the subscriptions given
+type UpdatePreferencesRequest struct {
+	QuietHoursStart string                `json:"quiet_hours_start"`
+	QuietHoursEnd   string                `json:"quiet_hours_end"`
+	WhatsAppNumber  string                `json:"whatsapp_number"`
+	Subscriptions   []SubscriptionRequest `json:"subscriptions" binding:"dive"`
+}
+
+// Validate checks the quiet hours
+func (r *UpdatePreferencesRequest) Validate() error {
+	if (r.QuietHoursStart == "") != (r.QuietHoursEnd == "") {
+		return fmt.Errorf("quiet hours need both a start and an end")
+	}
+	for _, v := range []string{r.QuietHoursStart, r.QuietHoursEnd} {
+		if _, err := time.Parse("15:04", v); v != "" && err != nil {
+			return fmt.Errorf("quiet hours must be HH:MM, got %q", v)
+		}
+	}
+	return nil
+}
+
+// SubscriptionSetting is a user's effective choice for one event
+type SubscriptionSetting struct {
+	NotificationEventType
+	Enabled  bool     `json:"enabled"`
+	Channels []string `json:"channels"`
+	Custom   bool     `json:"custom"` // False while the user keeps the default
+}
+
+// NotificationPreferences is a user's delivery settings with a setting for every event
+type NotificationPreferences struct {
+	NotificationPreference
+	Subscriptions []SubscriptionSetting `json:"subscriptions"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"time"
+)
+
+// TaxCode represents a configurable tax rule (e.g. 14% VAT, exempt, zero-rated)
+type TaxCode struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	Code      string     `json:"code" gorm:"unique;not null;index"` // e.g., VAT14, EXEMPT, ZERO
+	Name      string     `json:"name" gorm:"not null"`
+	NameEn    string     `json:"name_en"`
+	Type      string     `json:"type" gorm:"default:'standard'"` // standard, exempt, zero_rated
+	Rate      float64    `json:"rate" gorm:"default:0"`          // Percentage
+	IsDefault bool       `json:"is_default" gorm:"default:false"`
+	IsActive  bool       `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"-" gorm:"index"`
+}
+
+// Tax code types
+const (
+	TaxTypeStandard  = "standard"
+	TaxTypeExempt    = "exempt"
+	TaxTypeZeroRated = "zero_rated"
+)
+
+// EffectiveRate returns the rate to charge, which is always zero for exempt and zero-rated codes
+func (t *TaxCode) EffectiveRate() float64 {
+	if t.Type == TaxTypeExempt || t.Type == TaxTypeZeroRated {
+		return 0
+	}
+	return t.Rate
+}
+
+// SalesOrderTax is one line of an order's tax summary, grouped by tax code
+type SalesOrderTax struct {
+	ID            uint        `json:"id" gorm:"primarykey"`
+	OrderID       uint        `json:"order_id" gorm:"not null;index"`
+	TaxCodeID     *uint       `json:"tax_code_id"`
+	Code          string      `json:"code"`
+	Name          string      `json:"name"`
+	Type          string      `json:"type"`
+	Rate          float64     `json:"rate"`
+	TaxableAmount money.Money `json:"taxable_amount"`
+	TaxAmount     money.Money `json:"tax_amount"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// Tax pricing modes and rounding rules
+const (
+	TaxPricingExclusive = "exclusive"
+	TaxPricingInclusive = "inclusive"
+
+	TaxRoundingLine     = "line"
+	TaxRoundingDocument = "document"
+)
+
+// CreateTaxCodeRequest
+type CreateTaxCodeRequest struct {
+	Code      string  `json:"code" binding:"required"`
+	Name      string  `json:"name" binding:"required"`
+	NameEn    string  `json:"name_en"`
+	Type      string  `json:"type" binding:"omitempty,oneof=standard exempt zero_rated"`
+	Rate      float64 `json:"rate" binding:"gte=0,lte=100"`
+	IsDefault bool    `json:"is_default"`
+}
+
+// UpdateTaxCodeRequest
+type UpdateTaxCodeRequest struct {
+	Name      string   `json:"name"`
+	NameEn    string   `json:"name_en"`
+	Type      string   `json:"type" binding:"omitempty,oneof=standard exempt zero_rated"`
+	Rate      *float64 `json:"rate" binding:"omitempty,gte=0,lte=100"`
+	IsDefault *bool    `json:"is_default"`
+	IsActive  *bool    `json:"is_active"`
+}
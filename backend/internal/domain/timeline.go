@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"erp-system/pkg/money"
+	"time"
+)
+
+// Timeline entry types
+const (
+	TimelineActivity     = "activity"
+	TimelineDocument     = "document"
+	TimelineOrder        = "order"
+	TimelineStatus       = "status" // Approvals, credit events, revisions, shipments, deliveries and merges
+	TimelinePayment      = "payment"
+	TimelineNotification = "notification"
+)
+
+// TimelineTypes lists every timeline entry type
+var TimelineTypes = []string{TimelineActivity, TimelineDocument, TimelineOrder, TimelineStatus, TimelinePayment, TimelineNotification}
+
+// ContactActivityTypes are the activity types that count as contact with the customer
+var ContactActivityTypes = []string{"call", "meeting", "visit", "email", "whatsapp"}
+
+// TimelineEntry is one event in a customer's history
+type TimelineEntry struct {
+	Type       string       `json:"type"`
+	Subtype    string       `json:"subtype"` // Activity type, order status, credit event type, etc.
+	Source     string       `json:"-"`       // Table the entry came from, for ordering and cursors
+	RefID      uint         `json:"ref_id"`  // ID of the row in its source
+	OccurredAt time.Time    `json:"occurred_at"`
+	Title      string       `json:"title"`
+	Detail     string       `json:"detail,omitempty"`
+	Amount     *money.Money `json:"amount,omitempty"`
+	Currency   string       `json:"currency,omitempty"`
+	OrderID    *uint        `json:"order_id,omitempty"`
+}
+
+// Before reports whether e comes before other in timeline order: newest first, then by
+// source and newest row
+func (e TimelineEntry) Before(other TimelineEntry) bool {
+	if !e.OccurredAt.Equal(other.OccurredAt) {
+		return e.OccurredAt.After(other.OccurredAt)
+	}
+	if e.Source != other.Source {
+		return e.Source < other.Source
+	}
+	return e.RefID > other.RefID
+}
+
+// TimelineCursor is the position of the last entry of a page
+type TimelineCursor struct {
+	At     time.Time
+	Source string
+	ID     uint
+}
+
+// CustomerSummary holds a customer's headline figures, in the base currency
+type CustomerSummary struct {
+	LifetimeValue     money.Money `json:"lifetime_value"` // Confirmed, shipped and delivered orders
+	TotalPaid         money.Money `json:"total_paid"`
+	OrderCount        int64       `json:"order_count"`
+	AverageOrderValue money.Money `json:"average_order_value"`
+	FirstOrderAt      *time.Time  `json:"first_order_at"`
+	LastOrderAt       *time.Time  `json:"last_order_at"`
+	LastContactAt     *time.Time  `json:"last_contact_at"`
+	LastContactType   string      `json:"last_contact_type,omitempty"`
+}
+
+// CustomerTimeline is a page of a customer's timeline
+type CustomerTimeline struct {
+	Summary    *CustomerSummary `json:"summary,omitempty"` // First page only
+	Entries    []TimelineEntry  `json:"entries"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
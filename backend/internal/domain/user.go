@@ -36,6 +36,25 @@ type RefreshToken struct {
 	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
 }
 
+// Built-in roles
+const (
+	RoleAdmin   = 1
+	RoleManager = 2
+)
+
+// CanSeeCustomer reports whether the user may see a customer's records. Admins and managers
+// see every customer; other users only see customers of their own branch, or customers
+// without a branch.
+func (u *User) CanSeeCustomer(c *Customer) bool {
+	if !u.IsActive || u.DeletedAt != nil {
+		return false
+	}
+	if u.RoleID == RoleAdmin || u.RoleID == RoleManager {
+		return true
+	}
+	return u.BranchID == nil || c.BranchID == nil || *u.BranchID == *c.BranchID
+}
+
 // Role represents user permissions
 type Role struct {
 	ID          uint      `json:"id" gorm:"primarykey"`
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"erp-system/internal/usecases"
+	"erp-system/pkg/messaging"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConversationHandler struct {
+	conversationUseCase *usecases.ConversationUseCase
+}
+
+func NewConversationHandler(uc *usecases.ConversationUseCase) *ConversationHandler {
+	return &ConversationHandler{conversationUseCase: uc}
+}
+
+// VerifyWebhook answers the WhatsApp Cloud API subscription check with the challenge
+func (h *ConversationHandler) VerifyWebhook(c *gin.Context) {
+	challenge, err := h.conversationUseCase.VerifySubscription(c.Query("hub.mode"), c.Query("hub.verify_token"), c.Query("hub.challenge"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.String(http.StatusOK, challenge)
+}
+
+// ReceiveWebhook logs inbound WhatsApp messages; the body must be signed in the
+// X-Hub-Signature-256 header with the configured app secret
+func (h *ConversationHandler) ReceiveWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read body"})
+		return
+	}
+	result, err := h.conversationUseCase.Receive(body, c.GetHeader(messaging.SignatureHeader))
+	if errors.Is(err, usecases.ErrInvalidSignature) {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
+
+// GetConversation returns a customer's WhatsApp messages in both directions
+func (h *ConversationHandler) GetConversation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid customer ID"})
+		return
+	}
+	entries, err := h.conversationUseCase.GetConversation(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": entries})
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreditHandler struct {
+	creditUseCase *usecases.CreditUseCase
+}
+
+func NewCreditHandler(uc *usecases.CreditUseCase) *CreditHandler {
+	return &CreditHandler{creditUseCase: uc}
+}
+
+func (h *CreditHandler) GetCreditStatus(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	status, err := h.creditUseCase.GetCreditStatus(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": status})
+}
+
+func (h *CreditHandler) GetCreditHistory(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	events, err := h.creditUseCase.GetCreditHistory(uint(id), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": events})
+}
+
+func (h *CreditHandler) PlaceHold(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.CreditHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	customer, err := h.creditUseCase.PlaceHold(uint(id), req.Reason, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": customer})
+}
+
+func (h *CreditHandler) ReleaseHold(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.CreditHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	customer, err := h.creditUseCase.ReleaseHold(uint(id), req.Reason, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": customer})
+}
+
+func (h *CreditHandler) GrantOverride(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.GrantCreditOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	override, err := h.creditUseCase.GrantOverride(uint(id), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": override})
+}
+
+func (h *CreditHandler) RevokeOverride(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	userID := c.GetUint("user_id")
+
+	override, err := h.creditUseCase.RevokeOverride(uint(id), userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": override})
+}
+
+func (h *CreditHandler) GetBlockedOrders(c *gin.Context) {
+	customerID, _ := strconv.ParseUint(c.Query("customer_id"), 10, 32)
+	orders, err := h.creditUseCase.GetBlockedOrders(uint(customerID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": orders})
+}
+
+func (h *CreditHandler) ReleaseOrder(c *gin.Context) {
+	h.decideOrder(c, h.creditUseCase.ReleaseOrder)
+}
+
+func (h *CreditHandler) RejectOrder(c *gin.Context) {
+	h.decideOrder(c, h.creditUseCase.RejectOrder)
+}
+
+func (h *CreditHandler) decideOrder(c *gin.Context, decide func(uint, string, uint) (*domain.SalesOrder, error)) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.CreditDecisionRequest
+	_ = c.ShouldBindJSON(&req)
+
+	userID := c.GetUint("user_id")
+
+	order, err := decide(uint(id), req.Reason, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": order})
+}
+
+// EvaluateCredit runs the overdue scan now instead of waiting for the worker
+func (h *CreditHandler) EvaluateCredit(c *gin.Context) {
+	result, err := h.creditUseCase.EvaluateCredit()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
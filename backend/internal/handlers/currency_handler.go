@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyHandler struct {
+	currencyUseCase *usecases.CurrencyUseCase
+}
+
+func NewCurrencyHandler(uc *usecases.CurrencyUseCase) *CurrencyHandler {
+	return &CurrencyHandler{currencyUseCase: uc}
+}
+
+func (h *CurrencyHandler) GetCurrencies(c *gin.Context) {
+	currencies, err := h.currencyUseCase.GetCurrencies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": currencies})
+}
+
+func (h *CurrencyHandler) GetExchangeRates(c *gin.Context) {
+	rates, err := h.currencyUseCase.GetExchangeRates(strings.ToUpper(c.Query("currency")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rates})
+}
+
+func (h *CurrencyHandler) SetExchangeRate(c *gin.Context) {
+	var req domain.SetExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	rate, err := h.currencyUseCase.SetExchangeRate(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rate})
+}
+
+func (h *CurrencyHandler) DeleteExchangeRate(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := h.currencyUseCase.DeleteExchangeRate(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Exchange rate deleted successfully"})
+}
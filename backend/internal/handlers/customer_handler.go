@@ -2,8 +2,14 @@ package handlers
 
 import (
 	"erp-system/internal/domain"
+	"erp-system/internal/services"
 	"erp-system/internal/usecases"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -23,9 +29,14 @@ func NewCustomerHandler(uc *usecases.CustomerUseCase) *CustomerHandler {
 func (h *CustomerHandler) GetCustomers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	search := c.Query("search")
+	segmentID, _ := strconv.ParseUint(c.Query("segment_id"), 10, 32)
+	filter := domain.CustomerFilter{
+		Search:    c.Query("search"),
+		Tag:       c.Query("tag"),
+		SegmentID: uint(segmentID),
+	}
 
-	customers, total, err := h.customerUseCase.GetCustomers(page, limit, search)
+	customers, total, err := h.customerUseCase.GetCustomersFiltered(page, limit, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -71,8 +82,7 @@ func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
 		return
 	}
 
-	// TODO: Get userID from context (currently hardcoded)
-	userID := uint(1)
+	userID := c.GetUint("user_id")
 
 	customer, err := h.customerUseCase.CreateCustomer(req, userID)
 	if err != nil {
@@ -148,68 +158,477 @@ func (h *CustomerHandler) AddActivity(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Type         string  `json:"type" binding:"required"`
-		Description  string  `json:"description" binding:"required"`
-		ReminderDate *string `json:"reminder_date"`
+	var req domain.SaveActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	activity, err := h.customerUseCase.AddActivity(uint(id), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "Activity added successfully", "data": activity})
+}
+
+// ListActivities handles listing activities across customers, filtered by assigned_to,
+// customer_id, type and status (open, overdue, completed)
+func (h *CustomerHandler) ListActivities(c *gin.Context) {
+	filter := domain.ActivityFilter{Type: c.Query("type"), Status: c.Query("status")}
+	if v, err := strconv.ParseUint(c.Query("assigned_to"), 10, 32); err == nil {
+		assignee := uint(v)
+		filter.AssignedTo = &assignee
+	}
+	if v, err := strconv.ParseUint(c.Query("customer_id"), 10, 32); err == nil {
+		filter.CustomerID = uint(v)
+	}
+	h.listActivities(c, filter)
+}
+
+// GetMyActivities handles listing the current user's open activities across customers
+func (h *CustomerHandler) GetMyActivities(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	filter := domain.ActivityFilter{AssignedTo: &userID, Type: c.Query("type"), Status: c.DefaultQuery("status", domain.ActivityStatusOpen)}
+	h.listActivities(c, filter)
+}
+
+func (h *CustomerHandler) listActivities(c *gin.Context, filter domain.ActivityFilter) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	activities, total, err := h.customerUseCase.ListActivities(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch activities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"activities": activities,
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+		},
+	})
+}
+
+// GetActivity handles fetching one activity
+func (h *CustomerHandler) GetActivity(c *gin.Context) {
+	activityID, _ := strconv.ParseUint(c.Param("activityId"), 10, 32)
+	activity, err := h.customerUseCase.GetActivity(uint(activityID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": activity})
+}
+
+// UpdateActivity handles editing an activity
+func (h *CustomerHandler) UpdateActivity(c *gin.Context) {
+	activityID, _ := strconv.ParseUint(c.Param("activityId"), 10, 32)
+	var req domain.SaveActivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	activity, err := h.customerUseCase.UpdateActivity(uint(activityID), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Activity updated successfully", "data": activity})
+}
+
+// DeleteActivity handles deleting an activity
+func (h *CustomerHandler) DeleteActivity(c *gin.Context) {
+	activityID, _ := strconv.ParseUint(c.Param("activityId"), 10, 32)
+	if err := h.customerUseCase.DeleteActivity(uint(activityID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Activity deleted successfully"})
+}
+
+// CompleteActivity handles marking an activity done
+func (h *CustomerHandler) CompleteActivity(c *gin.Context) {
+	activityID, _ := strconv.ParseUint(c.Param("activityId"), 10, 32)
+	var req domain.CompleteActivityRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+			return
+		}
+	}
+
+	userID := c.GetUint("user_id")
+
+	activity, err := h.customerUseCase.CompleteActivity(uint(activityID), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Activity completed", "data": activity})
+}
+
+// ReopenActivity handles undoing a completion
+func (h *CustomerHandler) ReopenActivity(c *gin.Context) {
+	activityID, _ := strconv.ParseUint(c.Param("activityId"), 10, 32)
+	activity, err := h.customerUseCase.ReopenActivity(uint(activityID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
 	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Activity reopened", "data": activity})
+}
+
+// SnoozeActivity handles postponing an activity's reminder
+func (h *CustomerHandler) SnoozeActivity(c *gin.Context) {
+	activityID, _ := strconv.ParseUint(c.Param("activityId"), 10, 32)
+	var req domain.SnoozeActivityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
 		return
 	}
 
-	// TODO: Get userID from context (currently hardcoded)
-	userID := uint(1)
+	activity, err := h.customerUseCase.SnoozeActivity(uint(activityID), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Activity snoozed", "data": activity})
+}
+
+// GetCadences handles listing follow-up cadences
+func (h *CustomerHandler) GetCadences(c *gin.Context) {
+	cadences, err := h.customerUseCase.GetCadences()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch cadences"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": cadences})
+}
+
+// GetCadence handles fetching one cadence
+func (h *CustomerHandler) GetCadence(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	cadence, err := h.customerUseCase.GetCadence(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": cadence})
+}
+
+// CreateCadence handles adding a follow-up cadence
+func (h *CustomerHandler) CreateCadence(c *gin.Context) {
+	var req domain.SaveCadenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	cadence, err := h.customerUseCase.CreateCadence(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": cadence})
+}
+
+// UpdateCadence handles editing a cadence
+func (h *CustomerHandler) UpdateCadence(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.SaveCadenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
 
-	err = h.customerUseCase.AddActivity(uint(id), req.Type, req.Description, userID)
+	cadence, err := h.customerUseCase.UpdateCadence(uint(id), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to add activity", "error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": cadence})
+}
 
-	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "Activity added successfully"})
+// DeleteCadence handles deleting a cadence; activities it already scheduled are kept
+func (h *CustomerHandler) DeleteCadence(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := h.customerUseCase.DeleteCadence(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Cadence deleted"})
 }
 
-// UploadDocument handles uploading a customer document
+// readDocumentUpload reads the "file" form field, answering the request itself when it
+// can't be accepted
+func (h *CustomerHandler) readDocumentUpload(c *gin.Context) (string, []byte, bool) {
+	// Reject oversized bodies before reading them
+	maxSize := h.customerUseCase.MaxDocumentSize()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "message": fmt.Sprintf("File is too large, the limit is %d MB", maxSize>>20)})
+			return "", nil, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "No file uploaded"})
+		return "", nil, false
+	}
+	if file.Size > maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "message": fmt.Sprintf("File is too large, the limit is %d MB", maxSize>>20)})
+		return "", nil, false
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read file"})
+		return "", nil, false
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read file"})
+		return "", nil, false
+	}
+	return file.Filename, data, true
+}
+
+// documentUploadError answers a rejected upload
+func documentUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "message": err.Error()})
+	case err.Error() == "customer not found" || err.Error() == "document not found":
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+	}
+}
+
+// UploadDocument handles uploading a customer document with an optional title and category.
+// The file's type is checked against its content and uploading the same file twice returns
+// the existing document.
 func (h *CustomerHandler) UploadDocument(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid customer ID"})
 		return
 	}
+	filename, data, ok := h.readDocumentUpload(c)
+	if !ok {
+		return
+	}
 
-	// 1. Get file from request
-	file, err := c.FormFile("file")
+	userID := c.GetUint("user_id")
+
+	doc, duplicate, err := h.customerUseCase.UploadDocument(uint(id), c.PostForm("title"), c.PostForm("category"), filename, data, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "No file uploaded"})
+		documentUploadError(c, err)
+		return
+	}
+
+	if duplicate {
+		c.JSON(http.StatusOK, gin.H{"success": true, "message": "This file was already uploaded", "data": doc, "duplicate": true})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "Document uploaded successfully", "data": doc})
+}
+
+// ReplaceDocument handles uploading a new version of a document
+func (h *CustomerHandler) ReplaceDocument(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+	filename, data, ok := h.readDocumentUpload(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	doc, duplicate, err := h.customerUseCase.ReplaceDocument(uint(id), uint(docID), filename, data, userID)
+	if err != nil {
+		documentUploadError(c, err)
+		return
+	}
+
+	if duplicate {
+		c.JSON(http.StatusOK, gin.H{"success": true, "message": "This file is already the current version", "data": doc, "duplicate": true})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "New version uploaded", "data": doc})
+}
+
+// GetDocumentVersions handles fetching the history of a document
+func (h *CustomerHandler) GetDocumentVersions(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+
+	versions, err := h.customerUseCase.GetDocumentVersions(uint(id), uint(docID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": versions})
+}
+
+// UpdateDocument handles renaming or recategorising a document
+func (h *CustomerHandler) UpdateDocument(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+
+	var req domain.UpdateDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
 		return
 	}
 
-	// 2. Get title
-	title := c.PostForm("title")
-	if title == "" {
-		title = file.Filename
+	doc, err := h.customerUseCase.UpdateDocument(uint(id), uint(docID), &req)
+	if err != nil {
+		documentUploadError(c, err)
+		return
 	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": doc})
+}
+
+// GetDocumentThumbnail streams a document's preview image to a signed-in user who may see
+// the customer
+func (h *CustomerHandler) GetDocumentThumbnail(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
 
-	// 3. Save file
-	dst := "uploads/customers/" + strconv.FormatUint(id, 10) + "/" + file.Filename
-	err = c.SaveUploadedFile(file, dst)
+	r, obj, err := h.customerUseCase.OpenThumbnail(uint(id), uint(docID), c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save file"})
+		status := http.StatusNotFound
+		if errors.Is(err, usecases.ErrDocumentAccess) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
 		return
 	}
+	defer r.Close()
 
-	// 4. Save record
-	err = h.customerUseCase.AddDocument(uint(id), title, "/"+dst, "file")
+	c.DataFromReader(http.StatusOK, obj.Size, "image/jpeg", r, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=3600",
+	})
+}
+
+// DownloadDocument streams a document to a signed-in user who may see the customer; ?inline=1
+// lets the browser display it instead of saving it
+func (h *CustomerHandler) DownloadDocument(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+
+	doc, r, obj, err := h.customerUseCase.OpenDocument(uint(id), uint(docID), c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save document record"})
+		status := http.StatusNotFound
+		if errors.Is(err, usecases.ErrDocumentAccess) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
 		return
 	}
+	defer r.Close()
 
-	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "Document uploaded successfully", "path": "/" + dst})
+	name := doc.OriginalName
+	if name == "" {
+		name = filepath.Base(doc.FilePath)
+	}
+	disposition := "attachment"
+	if c.Query("inline") == "1" {
+		disposition = "inline"
+	}
+	contentType := doc.ContentType
+	if contentType == "" {
+		contentType = obj.ContentType
+	}
+
+	c.DataFromReader(http.StatusOK, obj.Size, contentType, r, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": name}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
 }
 
-// GetDocuments handles fetching customer documents
+// GetDocumentLink returns a short-lived link to a document, for opening it in the browser or
+// sharing it without the caller's token; ?inline=1 makes the link display the file
+func (h *CustomerHandler) GetDocumentLink(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+
+	url, expiresAt, err := h.customerUseCase.DocumentURL(uint(id), uint(docID), c.GetUint("user_id"), c.Query("inline") == "1")
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, usecases.ErrDocumentAccess) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"url": url, "expires_at": expiresAt}})
+}
+
+// DeleteDocument handles moving a document to the trash, or removing it for good when it
+// is already there
+func (h *CustomerHandler) DeleteDocument(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+
+	userID := c.GetUint("user_id")
+
+	purged, err := h.customerUseCase.DeleteDocument(uint(id), uint(docID), userID)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, usecases.ErrDocumentAccess) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if purged {
+		c.JSON(http.StatusOK, gin.H{"success": true, "message": "Document permanently deleted"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Document moved to the trash"})
+}
+
+// RestoreDocument handles taking a document out of the trash
+func (h *CustomerHandler) RestoreDocument(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	docID, _ := strconv.ParseUint(c.Param("docId"), 10, 32)
+
+	userID := c.GetUint("user_id")
+
+	doc, err := h.customerUseCase.RestoreDocument(uint(id), uint(docID), userID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, usecases.ErrDocumentAccess) {
+			status = http.StatusForbidden
+		} else if err.Error() == "document not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Document restored", "data": doc})
+}
+
+// GetDocuments handles fetching customer documents; ?category= filters them and ?deleted=true
+// lists the trash
 func (h *CustomerHandler) GetDocuments(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -217,9 +636,10 @@ func (h *CustomerHandler) GetDocuments(c *gin.Context) {
 		return
 	}
 
-	docs, err := h.customerUseCase.GetDocuments(uint(id))
+	filter := domain.DocumentFilter{Category: c.Query("category"), Deleted: c.Query("deleted") == "true"}
+	docs, err := h.customerUseCase.GetDocuments(uint(id), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch documents"})
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
 		return
 	}
 
@@ -250,3 +670,78 @@ func (h *CustomerHandler) ToggleActivityNotification(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Notification toggled successfully"})
 }
+
+// FindDuplicates handles listing likely duplicate customers
+func (h *CustomerHandler) FindDuplicates(c *gin.Context) {
+	threshold, _ := strconv.ParseFloat(c.Query("threshold"), 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	candidates, err := h.customerUseCase.FindDuplicates(0, threshold, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to find duplicates", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": candidates})
+}
+
+// GetCustomerDuplicates handles listing likely duplicates of one customer
+func (h *CustomerHandler) GetCustomerDuplicates(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid customer ID"})
+		return
+	}
+	threshold, _ := strconv.ParseFloat(c.Query("threshold"), 64)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	candidates, err := h.customerUseCase.FindDuplicates(uint(id), threshold, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to find duplicates", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": candidates})
+}
+
+// MergeCustomer handles merging a duplicate into this customer
+func (h *CustomerHandler) MergeCustomer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid customer ID"})
+		return
+	}
+
+	var req domain.MergeCustomersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	merge, err := h.customerUseCase.MergeCustomers(uint(id), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to merge customers", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Customers merged successfully", "data": merge})
+}
+
+// GetMerges handles listing the merges a customer took part in
+func (h *CustomerHandler) GetMerges(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid customer ID"})
+		return
+	}
+
+	merges, err := h.customerUseCase.GetMerges(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch merges", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": merges})
+}
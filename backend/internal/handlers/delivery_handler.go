@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeliveryHandler struct {
+	deliveryUseCase *usecases.DeliveryUseCase
+}
+
+func NewDeliveryHandler(uc *usecases.DeliveryUseCase) *DeliveryHandler {
+	return &DeliveryHandler{deliveryUseCase: uc}
+}
+
+func (h *DeliveryHandler) GetDeliveryNotes(c *gin.Context) {
+	orderID, _ := strconv.ParseUint(c.Query("order_id"), 10, 32)
+	notes, err := h.deliveryUseCase.GetDeliveryNotes(uint(orderID), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": notes})
+}
+
+// GetOrderDeliveryNotes lists the delivery notes issued against one sales order
+func (h *DeliveryHandler) GetOrderDeliveryNotes(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	notes, err := h.deliveryUseCase.GetDeliveryNotes(uint(id), "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": notes})
+}
+
+func (h *DeliveryHandler) GetDeliveryNote(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	note, err := h.deliveryUseCase.GetDeliveryNote(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Delivery note not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": note})
+}
+
+func (h *DeliveryHandler) CreateDeliveryNote(c *gin.Context) {
+	var req domain.CreateDeliveryNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	note, err := h.deliveryUseCase.CreateDeliveryNote(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": note})
+}
+
+func (h *DeliveryHandler) ConfirmDelivery(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.ConfirmDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	note, err := h.deliveryUseCase.ConfirmDelivery(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": note})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentHandler struct {
+	documentUseCase *usecases.DocumentUseCase
+}
+
+func NewDocumentHandler(uc *usecases.DocumentUseCase) *DocumentHandler {
+	return &DocumentHandler{documentUseCase: uc}
+}
+
+// SalesOrderPDF serves the order document; ?type= selects quote, order, invoice or delivery_note
+func (h *DocumentHandler) SalesOrderPDF(c *gin.Context) {
+	h.renderSales(c, c.DefaultQuery("type", domain.SalesDocumentOrder))
+}
+
+func (h *DocumentHandler) SalesQuotePDF(c *gin.Context) {
+	h.renderSales(c, domain.SalesDocumentQuote)
+}
+
+func (h *DocumentHandler) SalesInvoicePDF(c *gin.Context) {
+	h.renderSales(c, domain.SalesDocumentInvoice)
+}
+
+func (h *DocumentHandler) SalesDeliveryNotePDF(c *gin.Context) {
+	h.renderSales(c, domain.SalesDocumentDeliveryNote)
+}
+
+func (h *DocumentHandler) renderSales(c *gin.Context, kind string) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	data, filename, err := h.documentUseCase.SalesDocument(uint(id), kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	servePDF(c, data, filename)
+}
+
+// DeliveryNotePDF serves a delivery note with the quantities it actually ships
+func (h *DocumentHandler) DeliveryNotePDF(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	data, filename, err := h.documentUseCase.DeliveryNoteDocument(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	servePDF(c, data, filename)
+}
+
+func servePDF(c *gin.Context, data []byte, filename string) {
+	disposition := "inline"
+	if c.Query("download") == "true" {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", disposition+"; filename=\""+filename+"\"")
+	c.Data(http.StatusOK, "application/pdf", data)
+}
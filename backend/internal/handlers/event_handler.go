@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	eventUseCase *usecases.EventUseCase
+}
+
+func NewEventHandler(uc *usecases.EventUseCase) *EventHandler {
+	return &EventHandler{eventUseCase: uc}
+}
+
+// GetEvents lists domain events with optional type and status filters
+func (h *EventHandler) GetEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	filter := domain.DomainEventFilter{
+		Type:   c.Query("type"),
+		Status: c.Query("status"),
+	}
+
+	events, total, counts, err := h.eventUseCase.GetEvents(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"events":    events,
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"by_status": counts,
+		},
+	})
+}
+
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid event ID"})
+		return
+	}
+	event, err := h.eventUseCase.GetEvent(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": event})
+}
+
+// RetryEvent schedules a failed event for another attempt by the subscribers that failed it
+func (h *EventHandler) RetryEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid event ID"})
+		return
+	}
+	event, err := h.eventUseCase.RetryEvent(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": event, "message": "Event scheduled for retry"})
+}
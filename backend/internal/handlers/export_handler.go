@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/spreadsheet"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	exportUseCase *usecases.ExportUseCase
+}
+
+func NewExportHandler(uc *usecases.ExportUseCase) *ExportHandler {
+	return &ExportHandler{exportUseCase: uc}
+}
+
+// Export streams a list as a download. It accepts the list endpoints' filters
+// (search, tag, segment_id, status, customer_id, category_id), format=csv|xlsx and lang=ar for Arabic headers.
+func (h *ExportHandler) Export(c *gin.Context) {
+	list := c.Param("list")
+	format := c.DefaultQuery("format", spreadsheet.FormatXLSX)
+	if err := h.exportUseCase.CheckExport(list, format); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	customerID, _ := strconv.ParseUint(c.Query("customer_id"), 10, 32)
+	categoryID, _ := strconv.ParseUint(c.Query("category_id"), 10, 32)
+	segmentID, _ := strconv.ParseUint(c.Query("segment_id"), 10, 32)
+	filter := domain.ExportFilter{
+		Search:     c.Query("search"),
+		Tag:        c.Query("tag"),
+		SegmentID:  uint(segmentID),
+		Status:     c.Query("status"),
+		CustomerID: uint(customerID),
+		CategoryID: uint(categoryID),
+	}
+
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("Content-Disposition", "attachment; filename="+h.exportUseCase.FileName(list, format))
+	c.Status(http.StatusOK)
+	// Headers are already sent, so a failure part-way can only be logged
+	if err := h.exportUseCase.Export(list, format, filter, c.Query("lang") == "ar", c.Writer); err != nil {
+		log.Printf("⚠️ Export of %s failed: %v", list, err)
+	}
+}
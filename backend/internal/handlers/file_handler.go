@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"erp-system/pkg/storage"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler serves files of the local storage backend through signed links
+type FileHandler struct {
+	local *storage.Local
+}
+
+func NewFileHandler(local *storage.Local) *FileHandler {
+	return &FileHandler{local: local}
+}
+
+// ServeFile streams a file when the link's signature is valid and it has not expired
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	opts, err := h.local.Verify(key, c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "This link is invalid or has expired"})
+		return
+	}
+
+	r, obj, err := h.local.Get(key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "File not found"})
+		return
+	}
+	defer r.Close()
+
+	contentType := obj.ContentType
+	if opts.ContentType != "" {
+		contentType = opts.ContentType
+	}
+	c.DataFromReader(http.StatusOK, obj.Size, contentType, r, map[string]string{
+		"Content-Disposition":     h.local.Disposition(opts),
+		"X-Content-Type-Options":  "nosniff",
+		"Content-Security-Policy": "sandbox", // Uploaded SVG and HTML can't run scripts
+		"Cache-Control":           "private, max-age=300",
+	})
+}
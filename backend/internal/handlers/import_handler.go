@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"erp-system/internal/usecases"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize caps uploads at 20 MB
+const maxImportFileSize = 20 << 20
+
+type ImportHandler struct {
+	importUseCase *usecases.ImportUseCase
+}
+
+func NewImportHandler(uc *usecases.ImportUseCase) *ImportHandler {
+	return &ImportHandler{importUseCase: uc}
+}
+
+// StartImport accepts a CSV or XLSX upload in the "file" field; ?dry_run=true only previews it
+func (h *ImportHandler) StartImport(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "No file uploaded"})
+		return
+	}
+	if file.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "File is larger than 20 MB"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read file"})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read file"})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+	userID := c.GetUint("user_id")
+
+	job, err := h.importUseCase.StartImport(c.Param("type"), file.Filename, data, dryRun, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "message": "Import started", "data": job})
+}
+
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid import ID"})
+		return
+	}
+	job, err := h.importUseCase.GetJob(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": job})
+}
+
+func (h *ImportHandler) GetImports(c *gin.Context) {
+	jobs, err := h.importUseCase.GetJobs(c.Query("type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": jobs})
+}
@@ -121,3 +121,54 @@ func (h *InventoryHandler) CreateCategory(c *gin.Context) {
 	}
 	c.JSON(http.StatusCreated, gin.H{"success": true, "data": category})
 }
+
+// Warehouse Endpoints
+func (h *InventoryHandler) GetWarehouses(c *gin.Context) {
+	warehouses, err := h.inventoryUseCase.GetWarehouses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": warehouses})
+}
+
+func (h *InventoryHandler) CreateWarehouse(c *gin.Context) {
+	var req domain.CreateWarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	warehouse, err := h.inventoryUseCase.CreateWarehouse(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": warehouse})
+}
+
+func (h *InventoryHandler) GetWarehouseStock(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	stock, err := h.inventoryUseCase.GetWarehouseStock(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": stock})
+}
+
+func (h *InventoryHandler) SetWarehouseStock(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.SetWarehouseStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	stock, err := h.inventoryUseCase.SetWarehouseStock(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": stock})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MessageTemplateHandler struct {
+	templateUseCase *usecases.MessageTemplateUseCase
+}
+
+func NewMessageTemplateHandler(uc *usecases.MessageTemplateUseCase) *MessageTemplateHandler {
+	return &MessageTemplateHandler{templateUseCase: uc}
+}
+
+// templateError maps template errors to a response
+func templateError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrUnknownTemplate) {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+}
+
+// GetTemplates lists the message templates with the placeholders they can use
+func (h *MessageTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.templateUseCase.GetTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"templates":    templates,
+			"placeholders": domain.TemplatePlaceholders,
+		},
+	})
+}
+
+func (h *MessageTemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateUseCase.GetTemplate(c.Param("key"))
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": template})
+}
+
+func (h *MessageTemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req domain.SaveMessageTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	template, err := h.templateUseCase.UpdateTemplate(c.Param("key"), &req, userID)
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": template, "message": "Template saved"})
+}
+
+// ResetTemplate returns a template to its default text
+func (h *MessageTemplateHandler) ResetTemplate(c *gin.Context) {
+	template, err := h.templateUseCase.ResetTemplate(c.Param("key"))
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": template, "message": "Template reset"})
+}
+
+// PreviewTemplate renders a template for a customer and order without sending it
+func (h *MessageTemplateHandler) PreviewTemplate(c *gin.Context) {
+	var req domain.PreviewTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	rendered, err := h.templateUseCase.Preview(&req)
+	if err != nil {
+		templateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rendered})
+}
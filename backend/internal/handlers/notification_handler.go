@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"erp-system/internal/domain"
 	"erp-system/internal/usecases"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// streamHeartbeat keeps idle notification streams open through proxies
+const streamHeartbeat = 25 * time.Second
+
 type NotificationHandler struct {
 	useCase *usecases.NotificationUseCase
 }
@@ -17,8 +22,7 @@ func NewNotificationHandler(uc *usecases.NotificationUseCase) *NotificationHandl
 }
 
 func (h *NotificationHandler) GetUnread(c *gin.Context) {
-	// TODO: Get real userID from Context/Token
-	userID := uint(1)
+	userID := c.GetUint("user_id")
 
 	notifications, err := h.useCase.GetUnread(userID)
 	if err != nil {
@@ -29,6 +33,45 @@ func (h *NotificationHandler) GetUnread(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": notifications})
 }
 
+// GetHistory pages through the caller's notifications; status is read, unread or empty for all
+func (h *NotificationHandler) GetHistory(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	filter := domain.NotificationFilter{Type: c.Query("type")}
+	switch c.Query("status") {
+	case "read":
+		read := true
+		filter.IsRead = &read
+	case "unread":
+		unread := false
+		filter.IsRead = &unread
+	}
+
+	notifications, total, err := h.useCase.GetHistory(c.GetUint("user_id"), filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"notifications": notifications,
+			"total":         total,
+			"page":          page,
+			"limit":         limit,
+		},
+	})
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	count, err := h.useCase.CountUnread(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to count notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"count": count}})
+}
+
 func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -36,11 +79,96 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 		return
 	}
 
-	err = h.useCase.MarkAsRead(uint(id))
+	err = h.useCase.MarkAsRead(uint(id), c.GetUint("user_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update notification"})
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Notification marked as read"})
 }
+
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	count, err := h.useCase.MarkAllAsRead(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"updated": count}, "message": "All notifications marked as read"})
+}
+
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid notification ID"})
+		return
+	}
+	if err := h.useCase.DeleteNotification(uint(id), c.GetUint("user_id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Notification deleted"})
+}
+
+// GetPreferences returns the caller's delivery settings and their choice for every event
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.useCase.GetPreferences(c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch notification preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": prefs})
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req domain.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	prefs, err := h.useCase.UpdatePreferences(c.GetUint("user_id"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": prefs, "message": "Notification preferences saved"})
+}
+
+// Stream pushes the caller's new notifications as server-sent events. It starts with an
+// unread_count event, then sends a notification event for each new notification.
+func (h *NotificationHandler) Stream(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	events, unsubscribe := h.useCase.Subscribe(userID)
+	defer unsubscribe()
+
+	count, err := h.useCase.CountUnread(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to count notifications"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("unread_count", gin.H{"count": count})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case n, ok := <-events:
+			if !ok {
+				return
+			}
+			c.SSEvent("notification", n)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxHandler struct {
+	outboxUseCase *usecases.OutboxUseCase
+}
+
+func NewOutboxHandler(uc *usecases.OutboxUseCase) *OutboxHandler {
+	return &OutboxHandler{outboxUseCase: uc}
+}
+
+// GetMessages lists outbox messages with optional status, channel, source and customer filters
+func (h *OutboxHandler) GetMessages(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	customerID, _ := strconv.ParseUint(c.Query("customer_id"), 10, 32)
+	filter := domain.OutboundMessageFilter{
+		Status:     c.Query("status"),
+		Channel:    c.Query("channel"),
+		Source:     c.Query("source"),
+		CustomerID: uint(customerID),
+	}
+
+	messages, total, counts, err := h.outboxUseCase.GetMessages(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"messages":  messages,
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+			"by_status": counts,
+		},
+	})
+}
+
+// GetMessage returns a message with its delivery log
+func (h *OutboxHandler) GetMessage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid message ID"})
+		return
+	}
+	msg, err := h.outboxUseCase.GetMessage(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": msg})
+}
+
+// ResendMessage queues a copy of a message for immediate delivery
+func (h *OutboxHandler) ResendMessage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid message ID"})
+		return
+	}
+	msg, err := h.outboxUseCase.Resend(uint(id), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": msg, "message": "Message queued"})
+}
@@ -46,8 +46,7 @@ func (h *ProductionHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	// TODO: Get user ID from context
-	userID := uint(1)
+	userID := c.GetUint("user_id")
 
 	order, err := h.productionUseCase.CreateOrder(&req, userID)
 	if err != nil {
@@ -78,7 +77,7 @@ func (h *ProductionHandler) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.productionUseCase.UpdateOrderStatus(uint(id), req.Status); err != nil {
+	if err := h.productionUseCase.UpdateOrderStatus(uint(id), req.Status, c.GetUint("user_id")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
 		return
 	}
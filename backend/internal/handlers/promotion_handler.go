@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler struct {
+	promotionUseCase *usecases.PromotionUseCase
+}
+
+func NewPromotionHandler(uc *usecases.PromotionUseCase) *PromotionHandler {
+	return &PromotionHandler{promotionUseCase: uc}
+}
+
+// Promotion Endpoints
+func (h *PromotionHandler) GetPromotions(c *gin.Context) {
+	activeOnly := c.Query("active") == "true"
+
+	promotions, err := h.promotionUseCase.GetPromotions(activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": promotions})
+}
+
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	promotion, err := h.promotionUseCase.GetPromotion(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Promotion not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": promotion})
+}
+
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req domain.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	promotion, err := h.promotionUseCase.CreatePromotion(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": promotion})
+}
+
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.UpdatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	promotion, err := h.promotionUseCase.UpdatePromotion(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": promotion})
+}
+
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := h.promotionUseCase.DeletePromotion(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Promotion deleted successfully"})
+}
+
+// Discount Policy Endpoints
+func (h *PromotionHandler) GetDiscountPolicies(c *gin.Context) {
+	policies, err := h.promotionUseCase.GetDiscountPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": policies})
+}
+
+func (h *PromotionHandler) SetDiscountPolicy(c *gin.Context) {
+	var req domain.SetDiscountPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	policy, err := h.promotionUseCase.SetDiscountPolicy(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": policy})
+}
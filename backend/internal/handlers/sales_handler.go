@@ -3,6 +3,7 @@ package handlers
 import (
 	"erp-system/internal/domain"
 	"erp-system/internal/usecases"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -47,8 +48,7 @@ func (h *SalesHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	// TODO: Get user ID from context
-	userID := uint(1)
+	userID := c.GetUint("user_id")
 
 	order, err := h.salesUseCase.CreateOrder(&req, userID)
 	if err != nil {
@@ -68,3 +68,131 @@ func (h *SalesHandler) GetOrder(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": order})
 }
+
+func (h *SalesHandler) ApproveOrder(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.OrderApprovalRequest
+	_ = c.ShouldBindJSON(&req)
+
+	approverID := c.GetUint("user_id")
+
+	order, err := h.salesUseCase.ApproveOrder(uint(id), approverID, req.Note)
+	if errors.Is(err, usecases.ErrNotApprover) {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": order})
+}
+
+func (h *SalesHandler) RejectOrder(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.OrderApprovalRequest
+	_ = c.ShouldBindJSON(&req)
+
+	approverID := c.GetUint("user_id")
+
+	order, err := h.salesUseCase.RejectOrder(uint(id), approverID, req.Note)
+	if errors.Is(err, usecases.ErrNotApprover) {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": order})
+}
+
+func (h *SalesHandler) GetPayments(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	payments, err := h.salesUseCase.GetPayments(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": payments})
+}
+
+func (h *SalesHandler) RecordPayment(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.RecordPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	payment, err := h.salesUseCase.RecordPayment(uint(id), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": payment})
+}
+
+// UpdateOrder edits a draft order
+func (h *SalesHandler) UpdateOrder(c *gin.Context) {
+	h.editOrder(c, h.salesUseCase.UpdateOrder)
+}
+
+// ReviseOrder changes a confirmed order and keeps its previous version as a revision
+func (h *SalesHandler) ReviseOrder(c *gin.Context) {
+	h.editOrder(c, h.salesUseCase.ReviseOrder)
+}
+
+func (h *SalesHandler) editOrder(c *gin.Context, edit func(uint, *domain.UpdateOrderRequest, uint) (*domain.SalesOrder, error)) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.UpdateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	order, err := edit(uint(id), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": order})
+}
+
+func (h *SalesHandler) GetRevisions(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	revisions, err := h.salesUseCase.GetRevisions(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": revisions})
+}
+
+func (h *SalesHandler) GetRevision(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	revision, _ := strconv.Atoi(c.Param("revision"))
+	order, err := h.salesUseCase.GetRevision(uint(id), revision)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": order})
+}
+
+// DiffRevisions compares ?from= and ?to= revisions, defaulting to the latest change
+func (h *SalesHandler) DiffRevisions(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	from, _ := strconv.Atoi(c.Query("from"))
+	to, _ := strconv.Atoi(c.Query("to"))
+	diff, err := h.salesUseCase.DiffRevisions(uint(id), from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": diff})
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SegmentHandler struct {
+	segmentUseCase *usecases.SegmentUseCase
+}
+
+func NewSegmentHandler(uc *usecases.SegmentUseCase) *SegmentHandler {
+	return &SegmentHandler{segmentUseCase: uc}
+}
+
+func (h *SegmentHandler) GetTags(c *gin.Context) {
+	tags, err := h.segmentUseCase.GetTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *SegmentHandler) CreateTag(c *gin.Context) {
+	var req domain.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	tag, err := h.segmentUseCase.CreateTag(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": tag})
+}
+
+func (h *SegmentHandler) DeleteTag(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := h.segmentUseCase.DeleteTag(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Tag deleted"})
+}
+
+func (h *SegmentHandler) GetCustomerTags(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	tags, err := h.segmentUseCase.GetCustomerTags(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *SegmentHandler) TagCustomer(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.CustomerTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	tags, err := h.segmentUseCase.TagCustomer(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *SegmentHandler) UntagCustomer(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	tagID, _ := strconv.ParseUint(c.Param("tagId"), 10, 32)
+	if err := h.segmentUseCase.UntagCustomer(uint(id), uint(tagID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Tag removed"})
+}
+
+func (h *SegmentHandler) GetSegments(c *gin.Context) {
+	segments, err := h.segmentUseCase.GetSegments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": segments})
+}
+
+func (h *SegmentHandler) GetSegment(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	segment, err := h.segmentUseCase.GetSegment(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": segment})
+}
+
+func (h *SegmentHandler) CreateSegment(c *gin.Context) {
+	var req domain.SaveSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	segment, err := h.segmentUseCase.CreateSegment(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": segment})
+}
+
+func (h *SegmentHandler) UpdateSegment(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.SaveSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	segment, err := h.segmentUseCase.UpdateSegment(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": segment})
+}
+
+func (h *SegmentHandler) DeleteSegment(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := h.segmentUseCase.DeleteSegment(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Segment deleted"})
+}
+
+// PreviewSegment counts the customers a set of rules would match, without saving it
+func (h *SegmentHandler) PreviewSegment(c *gin.Context) {
+	var req domain.SaveSegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+	count, err := h.segmentUseCase.PreviewSegment(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"member_count": count}})
+}
+
+func (h *SegmentHandler) GetMembers(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	customers, total, err := h.segmentUseCase.GetMembers(uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"customers": customers,
+			"total":     total,
+			"page":      page,
+			"limit":     limit,
+		},
+	})
+}
+
+func (h *SegmentHandler) SendCampaign(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.SendCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid request", "error": err.Error()})
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	campaign, err := h.segmentUseCase.SendCampaign(uint(id), &req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "message": "Campaign is being sent", "data": campaign})
+}
+
+func (h *SegmentHandler) GetCampaigns(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	campaigns, err := h.segmentUseCase.GetCampaigns(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": campaigns})
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"erp-system/internal/services"
 	"erp-system/internal/usecases"
+	"errors"
+	"io"
 	"net/http"
-	"path/filepath"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxLogoUploadSize stops reading a logo upload once it can no longer be accepted
+const maxLogoUploadSize = 4 << 20
+
 type SettingsHandler struct {
 	useCase *usecases.SettingsUseCase
 }
@@ -22,6 +27,7 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to fetch settings"})
 		return
 	}
+	settings["company_logo_url"] = h.useCase.LogoURL()
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": settings})
 }
 
@@ -46,16 +52,18 @@ func (h *SettingsHandler) GetPublicSettings(c *gin.Context) {
 	if err != nil {
 		// Return defaults if error (e.g. not init)
 		c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{
-			"company_name": "ERP System",
-			"company_logo": "",
+			"company_name":     "ERP System",
+			"company_logo":     "",
+			"company_logo_url": "",
 		}})
 		return
 	}
 
 	// Filter only public data
 	publicData := gin.H{
-		"company_name": settings["company_name"],
-		"company_logo": settings["company_logo"],
+		"company_name":     settings["company_name"],
+		"company_logo":     settings["company_logo"],
+		"company_logo_url": h.useCase.LogoURL(),
 	}
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": publicData})
@@ -68,28 +76,30 @@ func (h *SettingsHandler) UploadLogo(c *gin.Context) {
 		return
 	}
 
-	// Validate ext
-	ext := filepath.Ext(file.Filename)
-	if ext != ".png" && ext != ".jpg" && ext != ".jpeg" && ext != ".svg" {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid file type"})
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read file"})
 		return
 	}
-
-	dst := "uploads/settings/logo" + ext
-	if err := c.SaveUploadedFile(file, dst); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save file"})
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxLogoUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Failed to read file"})
 		return
 	}
 
-	// Save path to settings
-	err = h.useCase.UpdateSettings(map[string]string{
-		"company_logo": "/" + dst,
-	})
-
+	url, err := h.useCase.UploadLogo(file.Filename, data)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update settings"})
+		switch {
+		case errors.Is(err, services.ErrFileTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "message": err.Error()})
+		case errors.Is(err, services.ErrFileType), errors.Is(err, services.ErrFileEmpty):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid file type"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to save file"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Logo uploaded", "path": "/" + dst})
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Logo uploaded", "url": url})
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxHandler struct {
+	taxUseCase *usecases.TaxUseCase
+}
+
+func NewTaxHandler(uc *usecases.TaxUseCase) *TaxHandler {
+	return &TaxHandler{taxUseCase: uc}
+}
+
+func (h *TaxHandler) GetTaxCodes(c *gin.Context) {
+	codes, err := h.taxUseCase.GetTaxCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": codes})
+}
+
+func (h *TaxHandler) GetTaxCode(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	code, err := h.taxUseCase.GetTaxCode(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Tax code not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": code})
+}
+
+func (h *TaxHandler) CreateTaxCode(c *gin.Context) {
+	var req domain.CreateTaxCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	code, err := h.taxUseCase.CreateTaxCode(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": code})
+}
+
+func (h *TaxHandler) UpdateTaxCode(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	var req domain.UpdateTaxCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	code, err := h.taxUseCase.UpdateTaxCode(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": code})
+}
+
+func (h *TaxHandler) DeleteTaxCode(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err := h.taxUseCase.DeleteTaxCode(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Tax code deleted successfully"})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"erp-system/internal/usecases"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimelineHandler struct {
+	timelineUseCase *usecases.TimelineUseCase
+}
+
+func NewTimelineHandler(uc *usecases.TimelineUseCase) *TimelineHandler {
+	return &TimelineHandler{timelineUseCase: uc}
+}
+
+// GetTimeline returns a page of the customer's timeline. Pass next_cursor from the previous
+// page as cursor, and a comma-separated types list to filter entries.
+func (h *TimelineHandler) GetTimeline(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	var types []string
+	if t := c.Query("types"); t != "" {
+		types = strings.Split(t, ",")
+	}
+
+	timeline, err := h.timelineUseCase.GetTimeline(uint(id), c.Query("cursor"), limit, types)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "customer not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": timeline})
+}
+
+func (h *TimelineHandler) GetSummary(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
+	summary, err := h.timelineUseCase.GetSummary(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": summary})
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"erp-system/pkg/auth"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAuth rejects requests without a valid bearer access token, so refresh tokens are
+// refused too, and stores the caller's user and role IDs in the context as "user_id" and "role_id"
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "message": "Authentication required"})
+			return
+		}
+
+		claims, err := auth.ValidateToken(strings.TrimSpace(token))
+		if err != nil || claims.UserID == 0 || claims.Type != auth.TokenTypeAccess {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "message": "Invalid or expired token"})
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("role_id", claims.RoleID)
+		c.Next()
+	}
+}
+
+// RequireRole rejects callers whose role is not one of roles. It runs after RequireAuth.
+func RequireRole(roles ...uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetUint("role_id")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "message": "You do not have permission to do this"})
+	}
+}
+
+// RequireAuthExcept applies RequireAuth to every /api/v1 route except the public ones. A
+// public route is matched against the registered route path; one ending in "/" covers every
+// route below it. Unknown paths are left to the router so they still answer 404.
+func RequireAuthExcept(public ...string) gin.HandlerFunc {
+	requireAuth := RequireAuth()
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" || !strings.HasPrefix(route, "/api/v1/") {
+			c.Next()
+			return
+		}
+		for _, p := range public {
+			if route == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(route, p)) {
+				c.Next()
+				return
+			}
+		}
+		requireAuth(c)
+	}
+}
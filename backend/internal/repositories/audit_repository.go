@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(log *domain.AuditLog) error
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(log *domain.AuditLog) error {
+	return r.db.Create(log).Error
+}
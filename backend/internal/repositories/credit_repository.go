@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CreditRepository interface {
+	CreateEvent(event *domain.CreditEvent) error
+	FindEvents(customerID uint, limit int) ([]domain.CreditEvent, error)
+
+	CreateOverride(override *domain.CreditOverride) error
+	UpdateOverride(override *domain.CreditOverride) error
+	FindOverrideByID(id uint) (*domain.CreditOverride, error)
+	FindActiveOverrides(customerID uint, at time.Time) ([]domain.CreditOverride, error)
+
+	FindOpenInvoices(customerID uint) ([]domain.SalesOrder, error)
+	FindBlockedOrders(customerID uint) ([]domain.SalesOrder, error)
+	FindCustomersOnHold() ([]domain.Customer, error)
+}
+
+type creditRepository struct {
+	db *gorm.DB
+}
+
+func NewCreditRepository(db *gorm.DB) CreditRepository {
+	return &creditRepository{db: db}
+}
+
+func (r *creditRepository) CreateEvent(event *domain.CreditEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *creditRepository) FindEvents(customerID uint, limit int) ([]domain.CreditEvent, error) {
+	var events []domain.CreditEvent
+	err := r.db.Where("customer_id = ?", customerID).Order("created_at DESC, id DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// Override Methods
+func (r *creditRepository) CreateOverride(override *domain.CreditOverride) error {
+	return r.db.Create(override).Error
+}
+
+func (r *creditRepository) UpdateOverride(override *domain.CreditOverride) error {
+	return r.db.Save(override).Error
+}
+
+func (r *creditRepository) FindOverrideByID(id uint) (*domain.CreditOverride, error) {
+	var override domain.CreditOverride
+	err := r.db.First(&override, id).Error
+	return &override, err
+}
+
+func (r *creditRepository) FindActiveOverrides(customerID uint, at time.Time) ([]domain.CreditOverride, error) {
+	var overrides []domain.CreditOverride
+	err := r.db.Where("customer_id = ? AND revoked_at IS NULL AND expires_at > ?", customerID, at).
+		Order("expires_at ASC").Find(&overrides).Error
+	return overrides, err
+}
+
+// FindOpenInvoices returns booked orders with an outstanding amount, for all customers when customerID is zero
+func (r *creditRepository) FindOpenInvoices(customerID uint) ([]domain.SalesOrder, error) {
+	var orders []domain.SalesOrder
+	query := r.db.Preload("Customer").
+		Where("status NOT IN ?", []string{domain.OrderStatusPendingApproval, domain.OrderStatusCreditHold, domain.OrderStatusCancelled}).
+		Where("net_amount > paid_amount AND deleted_at IS NULL")
+	if customerID > 0 {
+		query = query.Where("customer_id = ?", customerID)
+	}
+	err := query.Order("order_date ASC").Find(&orders).Error
+	return orders, err
+}
+
+// FindBlockedOrders returns orders waiting in the credit hold queue, oldest first
+func (r *creditRepository) FindBlockedOrders(customerID uint) ([]domain.SalesOrder, error) {
+	var orders []domain.SalesOrder
+	query := r.db.Preload("Customer").Where("status = ? AND deleted_at IS NULL", domain.OrderStatusCreditHold)
+	if customerID > 0 {
+		query = query.Where("customer_id = ?", customerID)
+	}
+	err := query.Order("created_at ASC, id ASC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *creditRepository) FindCustomersOnHold() ([]domain.Customer, error) {
+	var customers []domain.Customer
+	err := r.db.Where("credit_hold = ? AND deleted_at IS NULL", true).Find(&customers).Error
+	return customers, err
+}
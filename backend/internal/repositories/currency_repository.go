@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CurrencyRepository interface {
+	SetRate(rate *domain.ExchangeRate) error
+	DeleteRate(id uint) error
+	FindRateAt(currency string, date time.Time) (*domain.ExchangeRate, error)
+	FindRates(currency string) ([]domain.ExchangeRate, error)
+	FindLatestRates() ([]domain.ExchangeRate, error)
+}
+
+type currencyRepository struct {
+	db *gorm.DB
+}
+
+func NewCurrencyRepository(db *gorm.DB) CurrencyRepository {
+	return &currencyRepository{db: db}
+}
+
+// SetRate creates the rate or replaces the existing one for the same currency and date
+func (r *currencyRepository) SetRate(rate *domain.ExchangeRate) error {
+	var existing domain.ExchangeRate
+	err := r.db.Where("currency = ? AND effective_date = ?", rate.Currency, rate.EffectiveDate).First(&existing).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return r.db.Create(rate).Error
+		}
+		return err
+	}
+
+	rate.ID = existing.ID
+	rate.CreatedAt = existing.CreatedAt
+	return r.db.Save(rate).Error
+}
+
+func (r *currencyRepository) DeleteRate(id uint) error {
+	return r.db.Delete(&domain.ExchangeRate{}, id).Error
+}
+
+// FindRateAt returns the latest rate effective on or before the date
+func (r *currencyRepository) FindRateAt(currency string, date time.Time) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := r.db.Where("currency = ? AND effective_date <= ?", currency, date).
+		Order("effective_date DESC").First(&rate).Error
+	return &rate, err
+}
+
+func (r *currencyRepository) FindRates(currency string) ([]domain.ExchangeRate, error) {
+	var rates []domain.ExchangeRate
+	query := r.db.Model(&domain.ExchangeRate{})
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+	err := query.Order("effective_date DESC, currency").Find(&rates).Error
+	return rates, err
+}
+
+// FindLatestRates returns the most recent rate of every currency
+func (r *currencyRepository) FindLatestRates() ([]domain.ExchangeRate, error) {
+	var rates []domain.ExchangeRate
+	err := r.db.Where("effective_date = (SELECT MAX(er.effective_date) FROM exchange_rates er WHERE er.currency = exchange_rates.currency)").
+		Order("currency").Find(&rates).Error
+	return rates, err
+}
@@ -2,16 +2,29 @@ package repositories
 
 import (
 	"erp-system/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CustomerActivityRepository interface {
 	Create(activity *domain.CustomerActivity) error
 	FindByCustomerID(customerID uint) ([]domain.CustomerActivity, error)
 	FindByID(id uint) (*domain.CustomerActivity, error)
+	FindAll(filter domain.ActivityFilter, page, limit int) ([]domain.CustomerActivity, int64, error)
+	FindFollowUps(previousID uint) ([]domain.CustomerActivity, error)
+	FindByExternalID(externalID string) (*domain.CustomerActivity, error)
 	Update(activity *domain.CustomerActivity) error
 	Delete(id uint) error
+
+	CreateCadence(cadence *domain.FollowUpCadence) error
+	UpdateCadence(cadence *domain.FollowUpCadence) error
+	DeleteCadence(id uint) error
+	FindCadenceByID(id uint) (*domain.FollowUpCadence, error)
+	FindCadenceByName(name string) (*domain.FollowUpCadence, error)
+	FindCadences() ([]domain.FollowUpCadence, error)
+	FindActiveCadences(triggerType string) ([]domain.FollowUpCadence, error)
 }
 
 type customerActivityRepository struct {
@@ -28,20 +41,105 @@ func (r *customerActivityRepository) Create(activity *domain.CustomerActivity) e
 
 func (r *customerActivityRepository) FindByCustomerID(customerID uint) ([]domain.CustomerActivity, error) {
 	var activities []domain.CustomerActivity
-	err := r.db.Where("customer_id = ?", customerID).Order("created_at DESC").Find(&activities).Error
+	err := r.db.Preload("Assignee").Where("customer_id = ?", customerID).Order("created_at DESC").Find(&activities).Error
 	return activities, err
 }
 
 func (r *customerActivityRepository) FindByID(id uint) (*domain.CustomerActivity, error) {
 	var activity domain.CustomerActivity
-	err := r.db.First(&activity, id).Error
+	err := r.db.Preload("Assignee").First(&activity, id).Error
 	return &activity, err
 }
 
+// FindAll lists activities across customers; open ones are ordered by reminder date with
+// undated ones last, the rest newest first
+func (r *customerActivityRepository) FindAll(filter domain.ActivityFilter, page, limit int) ([]domain.CustomerActivity, int64, error) {
+	var activities []domain.CustomerActivity
+	var total int64
+
+	query := r.db.Model(&domain.CustomerActivity{}).
+		Where("customer_id IN (SELECT id FROM customers WHERE deleted_at IS NULL)")
+	if filter.AssignedTo != nil {
+		query = query.Where("assigned_to = ?", *filter.AssignedTo)
+	}
+	if filter.CustomerID > 0 {
+		query = query.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	order := "created_at DESC, id DESC"
+	switch filter.Status {
+	case domain.ActivityStatusOpen:
+		query = query.Where("is_completed = ?", false)
+		order = "reminder_date IS NULL, reminder_date ASC, id ASC"
+	case domain.ActivityStatusOverdue:
+		query = query.Where("is_completed = ? AND reminder_date < ?", false, time.Now())
+		order = "reminder_date ASC, id ASC"
+	case domain.ActivityStatusCompleted:
+		query = query.Where("is_completed = ?", true)
+		order = "completed_at DESC, id DESC"
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Customer").Preload("Assignee").Order(order).Offset(offset).Limit(limit).Find(&activities).Error
+	return activities, total, err
+}
+
 func (r *customerActivityRepository) Update(activity *domain.CustomerActivity) error {
-	return r.db.Save(activity).Error
+	return r.db.Omit(clause.Associations).Save(activity).Error
 }
 
 func (r *customerActivityRepository) Delete(id uint) error {
 	return r.db.Delete(&domain.CustomerActivity{}, id).Error
 }
+
+func (r *customerActivityRepository) FindFollowUps(previousID uint) ([]domain.CustomerActivity, error) {
+	var activities []domain.CustomerActivity
+	err := r.db.Where("previous_id = ?", previousID).Order("id").Find(&activities).Error
+	return activities, err
+}
+
+func (r *customerActivityRepository) FindByExternalID(externalID string) (*domain.CustomerActivity, error) {
+	var activity domain.CustomerActivity
+	err := r.db.Where("external_id = ?", externalID).First(&activity).Error
+	return &activity, err
+}
+
+func (r *customerActivityRepository) CreateCadence(cadence *domain.FollowUpCadence) error {
+	return r.db.Create(cadence).Error
+}
+
+func (r *customerActivityRepository) UpdateCadence(cadence *domain.FollowUpCadence) error {
+	return r.db.Save(cadence).Error
+}
+
+func (r *customerActivityRepository) DeleteCadence(id uint) error {
+	return r.db.Delete(&domain.FollowUpCadence{}, id).Error
+}
+
+func (r *customerActivityRepository) FindCadenceByID(id uint) (*domain.FollowUpCadence, error) {
+	var cadence domain.FollowUpCadence
+	err := r.db.First(&cadence, id).Error
+	return &cadence, err
+}
+
+func (r *customerActivityRepository) FindCadenceByName(name string) (*domain.FollowUpCadence, error) {
+	var cadence domain.FollowUpCadence
+	err := r.db.Where("name = ?", name).First(&cadence).Error
+	return &cadence, err
+}
+
+func (r *customerActivityRepository) FindCadences() ([]domain.FollowUpCadence, error) {
+	var cadences []domain.FollowUpCadence
+	err := r.db.Order("name").Find(&cadences).Error
+	return cadences, err
+}
+
+// FindActiveCadences lists the active cadences started by completing an activity of the type
+func (r *customerActivityRepository) FindActiveCadences(triggerType string) ([]domain.FollowUpCadence, error) {
+	var cadences []domain.FollowUpCadence
+	err := r.db.Where("trigger_type = ? AND active = ?", triggerType, true).Order("id").Find(&cadences).Error
+	return cadences, err
+}
@@ -2,14 +2,22 @@ package repositories
 
 import (
 	"erp-system/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type CustomerDocumentRepository interface {
 	Create(doc *domain.CustomerDocument) error
-	FindByCustomerID(customerID uint) ([]domain.CustomerDocument, error)
-	Delete(id uint) error
+	Update(doc *domain.CustomerDocument) error
+	FindByID(id uint) (*domain.CustomerDocument, error)
+	FindByCustomerID(customerID uint, filter domain.DocumentFilter) ([]domain.CustomerDocument, error)
+	FindVersions(groupID uint) ([]domain.CustomerDocument, error)
+	FindByChecksum(checksum string) ([]domain.CustomerDocument, error)
+	CountByPath(path string) (int64, error)
+	AddVersion(current, next *domain.CustomerDocument) error
+	SetDeleted(groupID uint, deletedAt *time.Time, deletedBy *uint) error
+	DeleteGroup(groupID uint) error
 }
 
 type customerDocumentRepository struct {
@@ -20,16 +28,90 @@ func NewCustomerDocumentRepository(db *gorm.DB) CustomerDocumentRepository {
 	return &customerDocumentRepository{db: db}
 }
 
+// Create saves a first version, which starts its own group
 func (r *customerDocumentRepository) Create(doc *domain.CustomerDocument) error {
-	return r.db.Create(doc).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(doc).Error; err != nil {
+			return err
+		}
+		if doc.GroupID == 0 {
+			doc.GroupID = doc.ID
+			return tx.Model(doc).Update("group_id", doc.ID).Error
+		}
+		return nil
+	})
 }
 
-func (r *customerDocumentRepository) FindByCustomerID(customerID uint) ([]domain.CustomerDocument, error) {
+func (r *customerDocumentRepository) Update(doc *domain.CustomerDocument) error {
+	return r.db.Save(doc).Error
+}
+
+func (r *customerDocumentRepository) FindByID(id uint) (*domain.CustomerDocument, error) {
+	var doc domain.CustomerDocument
+	err := r.db.First(&doc, id).Error
+	return &doc, err
+}
+
+// FindByCustomerID returns the latest version of each of a customer's documents
+func (r *customerDocumentRepository) FindByCustomerID(customerID uint, filter domain.DocumentFilter) ([]domain.CustomerDocument, error) {
+	var docs []domain.CustomerDocument
+	query := r.db.Where("customer_id = ? AND replaced_at IS NULL", customerID)
+	if filter.Deleted {
+		query = query.Where("deleted_at IS NOT NULL")
+	} else {
+		query = query.Where("deleted_at IS NULL")
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	err := query.Order("uploaded_at DESC").Order("id DESC").Find(&docs).Error
+	return docs, err
+}
+
+// FindVersions returns every version of a document, newest first
+func (r *customerDocumentRepository) FindVersions(groupID uint) ([]domain.CustomerDocument, error) {
+	var docs []domain.CustomerDocument
+	err := r.db.Where("group_id = ?", groupID).Order("version DESC").Find(&docs).Error
+	return docs, err
+}
+
+// FindByChecksum returns the documents with the given content checksum, oldest first
+func (r *customerDocumentRepository) FindByChecksum(checksum string) ([]domain.CustomerDocument, error) {
 	var docs []domain.CustomerDocument
-	err := r.db.Where("customer_id = ?", customerID).Order("uploaded_at DESC").Find(&docs).Error
+	err := r.db.Where("checksum = ?", checksum).Order("id").Find(&docs).Error
 	return docs, err
 }
 
-func (r *customerDocumentRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.CustomerDocument{}, id).Error
+// CountByPath counts the documents that use a stored file, as content or as thumbnail
+func (r *customerDocumentRepository) CountByPath(path string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.CustomerDocument{}).Where("file_path = ? OR thumbnail_key = ?", path, path).Count(&count).Error
+	return count, err
+}
+
+// AddVersion marks current as replaced and saves next as the newest version of its group
+func (r *customerDocumentRepository) AddVersion(current, next *domain.CustomerDocument) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&domain.CustomerDocument{}).Where("id = ? AND replaced_at IS NULL", current.ID).Update("replaced_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		current.ReplacedAt = &now
+		return tx.Create(next).Error
+	})
+}
+
+// SetDeleted moves every version of a document to the trash, or restores them when deletedAt is nil
+func (r *customerDocumentRepository) SetDeleted(groupID uint, deletedAt *time.Time, deletedBy *uint) error {
+	return r.db.Model(&domain.CustomerDocument{}).Where("group_id = ?", groupID).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "deleted_by": deletedBy}).Error
+}
+
+// DeleteGroup permanently removes every version of a document
+func (r *customerDocumentRepository) DeleteGroup(groupID uint) error {
+	return r.db.Where("group_id = ?", groupID).Delete(&domain.CustomerDocument{}).Error
 }
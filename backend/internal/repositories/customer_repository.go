@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"encoding/json"
 	"erp-system/internal/domain"
 	"erp-system/pkg/pagination"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,9 +15,16 @@ type CustomerRepository interface {
 	Update(customer *domain.Customer) error
 	Delete(id uint) error
 	FindByID(id uint) (*domain.Customer, error)
+	FindByCode(code string) (*domain.Customer, error)
 	FindAll(page, limit int, search string) ([]domain.Customer, int64, error)
+	FindAllFiltered(page, limit int, filter domain.CustomerFilter) ([]domain.Customer, int64, error)
 	FindAllPaginated(params *pagination.PaginationParams, search string) *pagination.PaginatedResponse
 	GenerateCode() (string, error)
+
+	FindAllActive() ([]domain.Customer, error)
+	FindByPhoneSuffix(suffix string) ([]domain.Customer, error)
+	Merge(survivor, merged *domain.Customer, record *domain.CustomerMerge) error
+	FindMerges(customerID uint) ([]domain.CustomerMerge, error)
 }
 
 type customerRepository struct {
@@ -45,16 +54,24 @@ func (r *customerRepository) FindByID(id uint) (*domain.Customer, error) {
 	return &customer, err
 }
 
+func (r *customerRepository) FindByCode(code string) (*domain.Customer, error) {
+	var customer domain.Customer
+	err := r.db.Where("code = ?", code).First(&customer).Error
+	return &customer, err
+}
+
 func (r *customerRepository) FindAll(page, limit int, search string) ([]domain.Customer, int64, error) {
+	return r.FindAllFiltered(page, limit, domain.CustomerFilter{Search: search})
+}
+
+// FindAllFiltered lists customers by search text, tag and segment, with their tags
+func (r *customerRepository) FindAllFiltered(page, limit int, filter domain.CustomerFilter) ([]domain.Customer, int64, error) {
 	var customers []domain.Customer
 	var total int64
 
-	query := r.db.Model(&domain.Customer{})
-
-	// Search filter
-	if search != "" {
-		query = query.Where("name LIKE ? OR email LIKE ? OR phone LIKE ? OR code LIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%", "%"+search+"%")
+	query, err := filterCustomers(r.db, r.db.Model(&domain.Customer{}), filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Get total count
@@ -62,7 +79,7 @@ func (r *customerRepository) FindAll(page, limit int, search string) ([]domain.C
 
 	// Get paginated results
 	offset := (page - 1) * limit
-	err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&customers).Error
+	err = query.Preload("Tags").Offset(offset).Limit(limit).Order("customers.created_at DESC").Find(&customers).Error
 
 	return customers, total, err
 }
@@ -93,6 +110,80 @@ func (r *customerRepository) GenerateCode() (string, error) {
 	return code, nil
 }
 
+// FindAllActive returns every customer that is not deleted or merged
+func (r *customerRepository) FindAllActive() ([]domain.Customer, error) {
+	var customers []domain.Customer
+	err := r.db.Where("deleted_at IS NULL").Order("id ASC").Find(&customers).Error
+	return customers, err
+}
+
+// FindByPhoneSuffix returns active customers whose phone or mobile ends with suffix; callers
+// compare the normalised numbers, since stored numbers may contain spaces or dashes
+func (r *customerRepository) FindByPhoneSuffix(suffix string) ([]domain.Customer, error) {
+	var customers []domain.Customer
+	err := r.db.Where("deleted_at IS NULL AND (mobile LIKE ? OR phone LIKE ?)", "%"+suffix, "%"+suffix).
+		Order("id ASC").Find(&customers).Error
+	return customers, err
+}
+
+// customerTables lists the tables whose rows follow a customer into a merge
+var customerTables = []string{"sales_orders", "customer_activities", "customer_documents", "credit_events", "credit_overrides", "customer_tags",
+	"outbound_messages"}
+
+// Merge moves the merged customer's orders, activities, documents, tags, messages and credit history onto the
+// survivor, saves both customers, soft-deletes the merged one and writes the merge record and
+// an audit log entry, all in one transaction. The move counts are filled in on the record.
+func (r *customerRepository) Merge(survivor, merged *domain.Customer, record *domain.CustomerMerge) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Tags both customers carry would collide on the join table's key
+		err := tx.Exec("DELETE FROM customer_tags WHERE customer_id = ? AND tag_id IN (SELECT tag_id FROM customer_tags WHERE customer_id = ?)",
+			merged.ID, survivor.ID).Error
+		if err != nil {
+			return err
+		}
+		for _, table := range customerTables {
+			res := tx.Table(table).Where("customer_id = ?", merged.ID).Update("customer_id", survivor.ID)
+			if res.Error != nil {
+				return res.Error
+			}
+			switch table {
+			case "sales_orders":
+				record.OrdersMoved = res.RowsAffected
+			case "customer_activities":
+				record.ActivitiesMoved = res.RowsAffected
+			case "customer_documents":
+				record.DocumentsMoved = res.RowsAffected
+			}
+		}
+
+		now := time.Now()
+		merged.DeletedAt = &now
+		if err := tx.Omit("Activities", "Documents", "Branch", "Tags").Save(survivor).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Activities", "Documents", "Branch", "Tags").Save(merged).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+
+		details, _ := json.Marshal(record)
+		return tx.Create(&domain.AuditLog{
+			UserID:   record.MergedBy,
+			Action:   "MERGE",
+			Resource: "customers",
+			Details:  string(details),
+		}).Error
+	})
+}
+
+func (r *customerRepository) FindMerges(customerID uint) ([]domain.CustomerMerge, error) {
+	var merges []domain.CustomerMerge
+	err := r.db.Where("survivor_id = ? OR merged_id = ?", customerID, customerID).Order("created_at DESC").Find(&merges).Error
+	return merges, err
+}
+
 func padLeft(n, width int) string {
 	s := ""
 	for i := 0; i < width; i++ {
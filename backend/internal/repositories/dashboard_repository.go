@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"erp-system/internal/domain"
+	"erp-system/pkg/money"
 
 	"gorm.io/gorm"
 )
@@ -21,7 +22,7 @@ func NewDashboardRepository(db *gorm.DB) DashboardRepository {
 }
 
 func (r *dashboardRepository) GetDashboardStats(filters *domain.DashboardFilters) (*domain.DashboardStats, error) {
-	stats := &domain.DashboardStats{}
+	stats := &domain.DashboardStats{Currency: money.DefaultCurrency}
 	now := time.Now()
 
 	// Customer Stats
@@ -114,37 +115,37 @@ func (r *dashboardRepository) getSalesStats(stats *domain.DashboardStats, now ti
 	// Total sales today
 	r.db.Model(&domain.SalesOrder{}).
 		Where("deleted_at IS NULL AND order_date >= ? AND status != ?", todayStart, "cancelled").
-		Select("COALESCE(SUM(net_amount), 0)").
+		Select("COALESCE(SUM(base_net_amount), 0)").
 		Scan(&stats.TotalSalesToday)
 
 	// Total sales this week
 	r.db.Model(&domain.SalesOrder{}).
 		Where("deleted_at IS NULL AND order_date >= ? AND status != ?", weekStart, "cancelled").
-		Select("COALESCE(SUM(net_amount), 0)").
+		Select("COALESCE(SUM(base_net_amount), 0)").
 		Scan(&stats.TotalSalesThisWeek)
 
 	// Total sales this month
 	r.db.Model(&domain.SalesOrder{}).
 		Where("deleted_at IS NULL AND order_date >= ? AND status != ?", monthStart, "cancelled").
-		Select("COALESCE(SUM(net_amount), 0)").
+		Select("COALESCE(SUM(base_net_amount), 0)").
 		Scan(&stats.TotalSalesThisMonth)
 
 	// Total sales this year
 	r.db.Model(&domain.SalesOrder{}).
 		Where("deleted_at IS NULL AND order_date >= ? AND status != ?", yearStart, "cancelled").
-		Select("COALESCE(SUM(net_amount), 0)").
+		Select("COALESCE(SUM(base_net_amount), 0)").
 		Scan(&stats.TotalSalesThisYear)
 
 	// Sales growth rate (this month vs last month)
 	lastMonthStart := monthStart.AddDate(0, -1, 0)
-	var lastMonthSales float64
+	var lastMonthSales money.Money
 	r.db.Model(&domain.SalesOrder{}).
 		Where("deleted_at IS NULL AND order_date >= ? AND order_date < ? AND status != ?", lastMonthStart, monthStart, "cancelled").
-		Select("COALESCE(SUM(net_amount), 0)").
+		Select("COALESCE(SUM(base_net_amount), 0)").
 		Scan(&lastMonthSales)
 
-	if lastMonthSales > 0 {
-		stats.SalesGrowthRate = (stats.TotalSalesThisMonth - lastMonthSales) / lastMonthSales * 100
+	if lastMonthSales.IsPositive() {
+		stats.SalesGrowthRate = stats.TotalSalesThisMonth.Sub(lastMonthSales).Float64() / lastMonthSales.Float64() * 100
 	}
 
 	return nil
@@ -171,7 +172,7 @@ func (r *dashboardRepository) getOrderStats(stats *domain.DashboardStats, now ti
 
 	// Average order value
 	if stats.TotalOrdersThisMonth > 0 {
-		stats.AverageOrderValue = stats.TotalSalesThisMonth / float64(stats.TotalOrdersThisMonth)
+		stats.AverageOrderValue = stats.TotalSalesThisMonth.Div(stats.TotalOrdersThisMonth)
 	}
 
 	return nil
@@ -233,7 +234,7 @@ func (r *dashboardRepository) getTopSellingProducts(stats *domain.DashboardStats
 		ProductName  string
 		SKU          string
 		QuantitySold int64
-		Revenue      float64
+		Revenue      money.Money
 	}
 
 	var results []Result
@@ -243,7 +244,7 @@ func (r *dashboardRepository) getTopSellingProducts(stats *domain.DashboardStats
 			p.name as product_name,
 			p.sku,
 			SUM(soi.quantity) as quantity_sold,
-			SUM(soi.total) as revenue
+			SUM(soi.total * so.exchange_rate) as revenue
 		`).
 		Joins("JOIN products p ON soi.product_id = p.id").
 		Joins("JOIN sales_orders so ON soi.order_id = so.id").
@@ -274,7 +275,7 @@ func (r *dashboardRepository) getTopSellingProducts(stats *domain.DashboardStats
 func (r *dashboardRepository) getRevenueTrend(stats *domain.DashboardStats, days int) error {
 	type Result struct {
 		Date    string
-		Revenue float64
+		Revenue money.Money
 		Orders  int64
 	}
 
@@ -285,7 +286,7 @@ func (r *dashboardRepository) getRevenueTrend(stats *domain.DashboardStats, days
 	err := r.db.Table("sales_orders").
 		Select(`
 			DATE(order_date) as date,
-			COALESCE(SUM(net_amount), 0) as revenue,
+			COALESCE(SUM(base_net_amount), 0) as revenue,
 			COUNT(*) as orders
 		`).
 		Where("deleted_at IS NULL AND order_date >= ? AND status != ?", startDate, "cancelled").
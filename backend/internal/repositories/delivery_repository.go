@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInsufficientStock is returned when a warehouse cannot cover a shipped quantity
+var ErrInsufficientStock = errors.New("insufficient stock in warehouse")
+
+// ErrOverShipped is returned when a note would ship more of an order line than is still
+// outstanding, e.g. because another note for the order was shipped at the same time
+var ErrOverShipped = errors.New("shipment exceeds the quantity outstanding on the order")
+
+// quantityTolerance absorbs float noise when comparing fractional quantities
+const quantityTolerance = 1e-9
+
+type DeliveryRepository interface {
+	Ship(note *domain.DeliveryNote, orderStatus string, events ...domain.Event) error
+	ConfirmDelivery(note *domain.DeliveryNote, orderStatus string, events ...domain.Event) error
+	FindByID(id uint) (*domain.DeliveryNote, error)
+	FindAll(orderID uint, status string) ([]domain.DeliveryNote, error)
+	GenerateNoteNumber() (string, error)
+}
+
+type deliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewDeliveryRepository(db *gorm.DB) DeliveryRepository {
+	return &deliveryRepository{db: db}
+}
+
+// Ship saves the note, deducts its quantities from the warehouse and the product totals,
+// adds them to the order lines' shipped quantities and sets the order status, all in one
+// transaction with the events the shipment raises
+func (r *deliveryRepository) Ship(note *domain.DeliveryNote, orderStatus string, events ...domain.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+
+		for _, item := range note.Items {
+			// Checked against the line as it is now, not as it was when the note was prepared
+			res := tx.Model(&domain.SalesOrderItem{}).
+				Where("id = ? AND quantity - shipped_quantity >= ?", item.OrderItemID, item.Quantity-quantityTolerance).
+				Update("shipped_quantity", gorm.Expr("shipped_quantity + ?", item.Quantity))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("%w for item %d", ErrOverShipped, item.OrderItemID)
+			}
+
+			res = tx.Model(&domain.WarehouseStock{}).
+				Where("warehouse_id = ? AND product_id = ? AND quantity >= ?", note.WarehouseID, item.ProductID, item.Quantity).
+				Update("quantity", gorm.Expr("quantity - ?", item.Quantity))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("%w for product %d", ErrInsufficientStock, item.ProductID)
+			}
+
+			if err := tx.Model(&domain.Product{}).Where("id = ?", item.ProductID).
+				Update("stock_quantity", gorm.Expr("stock_quantity - ?", int(math.Round(item.Quantity)))).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&domain.SalesOrder{}).Where("id = ?", note.OrderID).Update("status", orderStatus).Error; err != nil {
+			return err
+		}
+		return appendEvents(tx, events)
+	})
+}
+
+// ConfirmDelivery marks the note delivered, adds its quantities to the order lines'
+// delivered quantities and sets the order status
+func (r *deliveryRepository) ConfirmDelivery(note *domain.DeliveryNote, orderStatus string, events ...domain.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(note).Updates(map[string]interface{}{
+			"status":       note.Status,
+			"delivered_at": note.DeliveredAt,
+			"received_by":  note.ReceivedBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		for _, item := range note.Items {
+			if err := tx.Model(&domain.SalesOrderItem{}).Where("id = ?", item.OrderItemID).
+				Update("delivered_quantity", gorm.Expr("delivered_quantity + ?", item.Quantity)).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&domain.SalesOrder{}).Where("id = ?", note.OrderID).Update("status", orderStatus).Error; err != nil {
+			return err
+		}
+		return appendEvents(tx, events)
+	})
+}
+
+func (r *deliveryRepository) FindByID(id uint) (*domain.DeliveryNote, error) {
+	var note domain.DeliveryNote
+	err := r.db.Preload("Warehouse").Preload("Items").Preload("Items.Product").Preload("Items.OrderItem").First(&note, id).Error
+	return &note, err
+}
+
+func (r *deliveryRepository) FindAll(orderID uint, status string) ([]domain.DeliveryNote, error) {
+	var notes []domain.DeliveryNote
+
+	query := r.db.Preload("Warehouse").Preload("Items")
+	if orderID > 0 {
+		query = query.Where("order_id = ?", orderID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Order("delivery_date DESC, id DESC").Find(&notes).Error
+	return notes, err
+}
+
+func (r *deliveryRepository) GenerateNoteNumber() (string, error) {
+	var count int64
+	r.db.Model(&domain.DeliveryNote{}).Count(&count)
+	year := time.Now().Format("2006")
+	return fmt.Sprintf("DN-%s-%05d", year, count+1), nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"encoding/json"
+	"erp-system/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventRepository interface {
+	FindByID(id uint) (*domain.DomainEvent, error)
+	FindAll(filter domain.DomainEventFilter, page, limit int) ([]domain.DomainEvent, int64, error)
+	CountByStatus() (map[string]int64, error)
+	FindDue(now time.Time, limit int) ([]domain.DomainEvent, error)
+	Update(event *domain.DomainEvent) error
+}
+
+type eventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{db: db}
+}
+
+// appendEvents stores events in the transaction of the write that caused them, so they are
+// only published if it commits
+func appendEvents(tx *gorm.DB, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]domain.DomainEvent, 0, len(events))
+	for _, e := range events {
+		if build, ok := e.(domain.EventFunc); ok {
+			e = build()
+		}
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, domain.DomainEvent{
+			Type:          e.EventType(),
+			Payload:       string(payload),
+			ActorID:       e.EventActor(),
+			Status:        domain.EventStatusPending,
+			NextAttemptAt: now,
+		})
+	}
+	return tx.Create(&rows).Error
+}
+
+// inTransaction runs write and stores events in one transaction. Without events it runs
+// write on its own, as the repositories did before they raised any.
+func inTransaction(db *gorm.DB, events []domain.Event, write func(tx *gorm.DB) error) error {
+	if len(events) == 0 {
+		return write(db)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		return appendEvents(tx, events)
+	})
+}
+
+func (r *eventRepository) FindByID(id uint) (*domain.DomainEvent, error) {
+	var event domain.DomainEvent
+	err := r.db.First(&event, id).Error
+	return &event, err
+}
+
+func (r *eventRepository) FindAll(filter domain.DomainEventFilter, page, limit int) ([]domain.DomainEvent, int64, error) {
+	var events []domain.DomainEvent
+	var total int64
+
+	query := r.db.Model(&domain.DomainEvent{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&events).Error
+	return events, total, err
+}
+
+func (r *eventRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.Model(&domain.DomainEvent{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindDue returns pending events whose next attempt is due, oldest first
+func (r *eventRepository) FindDue(now time.Time, limit int) ([]domain.DomainEvent, error) {
+	var events []domain.DomainEvent
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", domain.EventStatusPending, now).
+		Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *eventRepository) Update(event *domain.DomainEvent) error {
+	return r.db.Save(event).Error
+}
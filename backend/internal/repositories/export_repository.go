@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many rows are loaded at a time while exporting
+const exportBatchSize = 500
+
+// ExportRepository walks list queries row by row for exports, applying the list filters
+type ExportRepository interface {
+	EachCustomer(filter domain.ExportFilter, limit int, fn func(*domain.Customer) error) error
+	EachSalesOrder(filter domain.ExportFilter, limit int, fn func(*domain.SalesOrder) error) error
+	EachProduct(filter domain.ExportFilter, limit int, fn func(*domain.Product) error) error
+	EachProductionOrder(filter domain.ExportFilter, limit int, fn func(*domain.ProductionOrder) error) error
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// eachInBatches loads at most limit rows in batches, so an export never holds the whole table
+func eachInBatches[T any](query *gorm.DB, limit int, fn func(*T) error) error {
+	var batch []T
+	var fnErr error
+	err := query.Limit(limit).FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if fnErr = fn(&batch[i]); fnErr != nil {
+				return fnErr
+			}
+		}
+		return nil
+	}).Error
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
+func (r *exportRepository) EachCustomer(filter domain.ExportFilter, limit int, fn func(*domain.Customer) error) error {
+	query, err := filterCustomers(r.db, r.db.Model(&domain.Customer{}), domain.CustomerFilter{
+		Search:    filter.Search,
+		Tag:       filter.Tag,
+		SegmentID: filter.SegmentID,
+	})
+	if err != nil {
+		return err
+	}
+	return eachInBatches(query, limit, fn)
+}
+
+func (r *exportRepository) EachSalesOrder(filter domain.ExportFilter, limit int, fn func(*domain.SalesOrder) error) error {
+	query := r.db.Model(&domain.SalesOrder{}).Preload("Customer")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CustomerID > 0 {
+		query = query.Where("customer_id = ?", filter.CustomerID)
+	}
+	return eachInBatches(query, limit, fn)
+}
+
+func (r *exportRepository) EachProduct(filter domain.ExportFilter, limit int, fn func(*domain.Product) error) error {
+	query := r.db.Model(&domain.Product{}).Preload("Category")
+	if filter.Search != "" {
+		query = query.Where("name LIKE ? OR sku LIKE ?", "%"+filter.Search+"%", "%"+filter.Search+"%")
+	}
+	if filter.CategoryID > 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	return eachInBatches(query, limit, fn)
+}
+
+func (r *exportRepository) EachProductionOrder(filter domain.ExportFilter, limit int, fn func(*domain.ProductionOrder) error) error {
+	query := r.db.Model(&domain.ProductionOrder{}).Preload("Product")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return eachInBatches(query, limit, fn)
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ImportRepository stores bulk import jobs
+type ImportRepository interface {
+	Create(job *domain.ImportJob) error
+	Update(job *domain.ImportJob) error
+	FindByID(id uint) (*domain.ImportJob, error)
+	FindAll(importType string, limit int) ([]domain.ImportJob, error)
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+// NewImportRepository creates a new import repository
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) Create(job *domain.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *importRepository) Update(job *domain.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+func (r *importRepository) FindByID(id uint) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	err := r.db.First(&job, id).Error
+	return &job, err
+}
+
+// FindAll lists recent jobs without their row results
+func (r *importRepository) FindAll(importType string, limit int) ([]domain.ImportJob, error) {
+	var jobs []domain.ImportJob
+	query := r.db.Omit("results_json").Order("id DESC").Limit(limit)
+	if importType != "" {
+		query = query.Where("type = ?", importType)
+	}
+	err := query.Find(&jobs).Error
+	return jobs, err
+}
@@ -3,20 +3,29 @@ package repositories
 import (
 	"erp-system/internal/domain"
 	"erp-system/pkg/pagination"
+	"math"
 
 	"gorm.io/gorm"
 )
 
 type InventoryRepository interface {
 	CreateProduct(product *domain.Product) error
-	UpdateProduct(product *domain.Product) error
+	UpdateProduct(product *domain.Product, events ...domain.Event) error
 	DeleteProduct(id uint) error
 	FindProductByID(id uint) (*domain.Product, error)
+	FindProductBySKU(sku string) (*domain.Product, error)
 	FindAllProducts(page, limit int, search string, categoryID uint) ([]domain.Product, int64, error)
 	FindAllProductsPaginated(params *pagination.PaginationParams, search string, categoryID uint) *pagination.PaginatedResponse
 
 	CreateCategory(category *domain.Category) error
 	FindAllCategories() ([]domain.Category, error)
+
+	CreateWarehouse(warehouse *domain.Warehouse) error
+	FindWarehouseByID(id uint) (*domain.Warehouse, error)
+	FindAllWarehouses() ([]domain.Warehouse, error)
+	FindWarehouseStock(warehouseID uint) ([]domain.WarehouseStock, error)
+	FindStockLevel(warehouseID, productID uint) (float64, error)
+	SetWarehouseStock(warehouseID, productID uint, quantity float64, events ...domain.Event) (*domain.WarehouseStock, error)
 }
 
 type inventoryRepository struct {
@@ -32,8 +41,10 @@ func (r *inventoryRepository) CreateProduct(product *domain.Product) error {
 	return r.db.Create(product).Error
 }
 
-func (r *inventoryRepository) UpdateProduct(product *domain.Product) error {
-	return r.db.Save(product).Error
+func (r *inventoryRepository) UpdateProduct(product *domain.Product, events ...domain.Event) error {
+	return inTransaction(r.db, events, func(tx *gorm.DB) error {
+		return tx.Save(product).Error
+	})
 }
 
 func (r *inventoryRepository) DeleteProduct(id uint) error {
@@ -42,7 +53,13 @@ func (r *inventoryRepository) DeleteProduct(id uint) error {
 
 func (r *inventoryRepository) FindProductByID(id uint) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.Preload("Category").First(&product, id).Error
+	err := r.db.Preload("Category").Preload("TaxCode").First(&product, id).Error
+	return &product, err
+}
+
+func (r *inventoryRepository) FindProductBySKU(sku string) (*domain.Product, error) {
+	var product domain.Product
+	err := r.db.Where("sku = ?", sku).First(&product).Error
 	return &product, err
 }
 
@@ -96,3 +113,57 @@ func (r *inventoryRepository) FindAllCategories() ([]domain.Category, error) {
 	err := r.db.Find(&categories).Error
 	return categories, err
 }
+
+// Warehouse Methods
+func (r *inventoryRepository) CreateWarehouse(warehouse *domain.Warehouse) error {
+	return r.db.Create(warehouse).Error
+}
+
+func (r *inventoryRepository) FindWarehouseByID(id uint) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	err := r.db.First(&warehouse, id).Error
+	return &warehouse, err
+}
+
+func (r *inventoryRepository) FindAllWarehouses() ([]domain.Warehouse, error) {
+	var warehouses []domain.Warehouse
+	err := r.db.Order("code ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (r *inventoryRepository) FindWarehouseStock(warehouseID uint) ([]domain.WarehouseStock, error) {
+	var stock []domain.WarehouseStock
+	err := r.db.Preload("Product").Where("warehouse_id = ?", warehouseID).Order("product_id ASC").Find(&stock).Error
+	return stock, err
+}
+
+// FindStockLevel returns the on-hand quantity, zero when the product was never stocked there
+func (r *inventoryRepository) FindStockLevel(warehouseID, productID uint) (float64, error) {
+	var stock domain.WarehouseStock
+	err := r.db.Where("warehouse_id = ? AND product_id = ?", warehouseID, productID).First(&stock).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	}
+	return stock.Quantity, err
+}
+
+// SetWarehouseStock records a counted quantity and moves the product's total stock by the difference
+func (r *inventoryRepository) SetWarehouseStock(warehouseID, productID uint, quantity float64, events ...domain.Event) (*domain.WarehouseStock, error) {
+	stock := domain.WarehouseStock{WarehouseID: warehouseID, ProductID: productID}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(&stock).FirstOrCreate(&stock).Error; err != nil {
+			return err
+		}
+		delta := int(math.Round(quantity - stock.Quantity))
+		stock.Quantity = quantity
+		if err := tx.Save(&stock).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Product{}).Where("id = ?", productID).
+			Update("stock_quantity", gorm.Expr("stock_quantity + ?", delta)).Error; err != nil {
+			return err
+		}
+		return appendEvents(tx, events)
+	})
+	return &stock, err
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type MessageTemplateRepository interface {
+	FindAll() ([]domain.MessageTemplate, error)
+	FindByKey(key string) (*domain.MessageTemplate, error)
+	Save(template *domain.MessageTemplate) error
+	DeleteByKey(key string) error
+}
+
+type messageTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageTemplateRepository(db *gorm.DB) MessageTemplateRepository {
+	return &messageTemplateRepository{db: db}
+}
+
+func (r *messageTemplateRepository) FindAll() ([]domain.MessageTemplate, error) {
+	var templates []domain.MessageTemplate
+	err := r.db.Order("key").Find(&templates).Error
+	return templates, err
+}
+
+func (r *messageTemplateRepository) FindByKey(key string) (*domain.MessageTemplate, error) {
+	var template domain.MessageTemplate
+	err := r.db.Where("key = ?", key).First(&template).Error
+	return &template, err
+}
+
+// Save creates the template for its key or replaces the stored one
+func (r *messageTemplateRepository) Save(template *domain.MessageTemplate) error {
+	var existing domain.MessageTemplate
+	if err := r.db.Where("key = ?", template.Key).First(&existing).Error; err == nil {
+		template.ID = existing.ID
+	}
+	return r.db.Save(template).Error
+}
+
+func (r *messageTemplateRepository) DeleteByKey(key string) error {
+	return r.db.Where("key = ?", key).Delete(&domain.MessageTemplate{}).Error
+}
@@ -9,7 +9,11 @@ import (
 type NotificationRepository interface {
 	Create(notification *domain.Notification) error
 	GetUnreadByUserID(userID uint) ([]domain.Notification, error)
-	MarkAsRead(id uint) error
+	FindByUserID(userID uint, filter domain.NotificationFilter, page, limit int) ([]domain.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkAsRead(id, userID uint) error
+	MarkAllAsRead(userID uint) (int64, error)
+	Delete(id, userID uint) error
 }
 
 type notificationRepository struct {
@@ -32,6 +36,51 @@ func (r *notificationRepository) GetUnreadByUserID(userID uint) ([]domain.Notifi
 	return notifications, err
 }
 
-func (r *notificationRepository) MarkAsRead(id uint) error {
-	return r.db.Model(&domain.Notification{}).Where("id = ?", id).Update("is_read", true).Error
+// FindByUserID pages through a user's notifications, newest first
+func (r *notificationRepository) FindByUserID(userID uint, filter domain.NotificationFilter, page, limit int) ([]domain.Notification, int64, error) {
+	var notifications []domain.Notification
+	var total int64
+
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if filter.IsRead != nil {
+		query = query.Where("is_read = ?", *filter.IsRead)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at desc, id desc").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Count(&count).Error
+	return count, err
+}
+
+// MarkAsRead marks one of a user's notifications read; other users' notifications are not found
+func (r *notificationRepository) MarkAsRead(id, userID uint) error {
+	result := r.db.Model(&domain.Notification{}).Where("id = ? AND user_id = ?", id, userID).Update("is_read", true)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *notificationRepository) MarkAllAsRead(userID uint) (int64, error) {
+	result := r.db.Model(&domain.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Update("is_read", true)
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) Delete(id, userID uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&domain.Notification{})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
 }
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboundMessageRepository interface {
+	Create(msg *domain.OutboundMessage) error
+	FindByID(id uint) (*domain.OutboundMessage, error)
+	FindByIdempotencyKey(key string) (*domain.OutboundMessage, error)
+	FindAll(filter domain.OutboundMessageFilter, page, limit int) ([]domain.OutboundMessage, int64, error)
+	CountByStatus() (map[string]int64, error)
+	ClaimDue(now, leaseUntil time.Time, limit int) ([]domain.OutboundMessage, error)
+	SaveAttempt(msg *domain.OutboundMessage, delivery *domain.MessageDelivery) error
+}
+
+type outboundMessageRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboundMessageRepository(db *gorm.DB) OutboundMessageRepository {
+	return &outboundMessageRepository{db: db}
+}
+
+func (r *outboundMessageRepository) Create(msg *domain.OutboundMessage) error {
+	return r.db.Omit("Customer", "Deliveries").Create(msg).Error
+}
+
+func (r *outboundMessageRepository) FindByID(id uint) (*domain.OutboundMessage, error) {
+	var msg domain.OutboundMessage
+	err := r.db.Preload("Customer").
+		Preload("Deliveries", func(db *gorm.DB) *gorm.DB { return db.Order("attempt") }).
+		First(&msg, id).Error
+	return &msg, err
+}
+
+func (r *outboundMessageRepository) FindByIdempotencyKey(key string) (*domain.OutboundMessage, error) {
+	var msg domain.OutboundMessage
+	err := r.db.Where("idempotency_key = ?", key).First(&msg).Error
+	return &msg, err
+}
+
+func (r *outboundMessageRepository) FindAll(filter domain.OutboundMessageFilter, page, limit int) ([]domain.OutboundMessage, int64, error) {
+	var messages []domain.OutboundMessage
+	var total int64
+
+	query := r.db.Model(&domain.OutboundMessage{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.CustomerID != 0 {
+		query = query.Where("customer_id = ?", filter.CustomerID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Customer").Order("id DESC").Offset(offset).Limit(limit).Find(&messages).Error
+	return messages, total, err
+}
+
+func (r *outboundMessageRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&domain.OutboundMessage{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, err
+}
+
+// ClaimDue takes up to limit messages that are due for an attempt and holds them until
+// leaseUntil, so no other worker picks them up meanwhile. A message whose attempt never
+// finishes becomes due again when the lease runs out.
+func (r *outboundMessageRepository) ClaimDue(now, leaseUntil time.Time, limit int) ([]domain.OutboundMessage, error) {
+	due := []string{domain.MessageStatusQueued, domain.MessageStatusFailed}
+	var candidates []domain.OutboundMessage
+	err := r.db.Where("status IN ? AND next_attempt_at <= ?", due, now).
+		Order("next_attempt_at").Order("id").Limit(limit).Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := candidates[:0]
+	for _, msg := range candidates {
+		res := r.db.Model(&domain.OutboundMessage{}).
+			Where("id = ? AND status IN ? AND next_attempt_at = ?", msg.ID, due, msg.NextAttemptAt).
+			Update("next_attempt_at", leaseUntil)
+		if res.Error != nil {
+			return claimed, res.Error
+		}
+		if res.RowsAffected == 1 {
+			msg.NextAttemptAt = leaseUntil
+			claimed = append(claimed, msg)
+		}
+	}
+	return claimed, nil
+}
+
+// SaveAttempt stores the outcome of a delivery attempt together with its log entry
+func (r *outboundMessageRepository) SaveAttempt(msg *domain.OutboundMessage, delivery *domain.MessageDelivery) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Customer", "Deliveries").Save(msg).Error; err != nil {
+			return err
+		}
+		delivery.MessageID = msg.ID
+		return tx.Create(delivery).Error
+	})
+}
@@ -14,7 +14,7 @@ type ProductionRepository interface {
 	FindAllOrders(page, limit int, status string) ([]domain.ProductionOrder, int64, error)
 	FindAllOrdersPaginated(params *pagination.PaginationParams, status string) *pagination.PaginatedResponse
 	FindOrderByID(id uint) (*domain.ProductionOrder, error)
-	UpdateOrderStatus(id uint, status string) error
+	UpdateOrderStatus(id uint, status string, events ...domain.Event) error
 	GenerateOrderNumber() (string, error)
 
 	CreateBOM(bom *domain.BillOfMaterials) error
@@ -72,8 +72,10 @@ func (r *productionRepository) FindOrderByID(id uint) (*domain.ProductionOrder,
 	return &order, err
 }
 
-func (r *productionRepository) UpdateOrderStatus(id uint, status string) error {
-	return r.db.Model(&domain.ProductionOrder{}).Where("id = ?", id).Update("status", status).Error
+func (r *productionRepository) UpdateOrderStatus(id uint, status string, events ...domain.Event) error {
+	return inTransaction(r.db, events, func(tx *gorm.DB) error {
+		return tx.Model(&domain.ProductionOrder{}).Where("id = ?", id).Update("status", status).Error
+	})
 }
 
 func (r *productionRepository) GenerateOrderNumber() (string, error) {
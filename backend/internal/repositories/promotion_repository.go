@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type PromotionRepository interface {
+	Create(promotion *domain.Promotion) error
+	Update(promotion *domain.Promotion) error
+	Delete(id uint) error
+	FindByID(id uint) (*domain.Promotion, error)
+	FindByCode(code string) (*domain.Promotion, error)
+	FindAll(activeOnly bool) ([]domain.Promotion, error)
+	FindAutomatic() ([]domain.Promotion, error)
+
+	SetPolicy(policy *domain.DiscountPolicy) error
+	FindPolicyByRoleID(roleID uint) (*domain.DiscountPolicy, error)
+	FindAllPolicies() ([]domain.DiscountPolicy, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+// Promotion Methods
+func (r *promotionRepository) Create(promotion *domain.Promotion) error {
+	return r.db.Create(promotion).Error
+}
+
+func (r *promotionRepository) Update(promotion *domain.Promotion) error {
+	return r.db.Save(promotion).Error
+}
+
+func (r *promotionRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.Promotion{}, id).Error
+}
+
+func (r *promotionRepository) FindByID(id uint) (*domain.Promotion, error) {
+	var promotion domain.Promotion
+	err := r.db.Where("deleted_at IS NULL").First(&promotion, id).Error
+	return &promotion, err
+}
+
+func (r *promotionRepository) FindByCode(code string) (*domain.Promotion, error) {
+	var promotion domain.Promotion
+	err := r.db.Where("code = ? AND deleted_at IS NULL", code).First(&promotion).Error
+	return &promotion, err
+}
+
+func (r *promotionRepository) FindAll(activeOnly bool) ([]domain.Promotion, error) {
+	var promotions []domain.Promotion
+	query := r.db.Where("deleted_at IS NULL")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("created_at DESC").Find(&promotions).Error
+	return promotions, err
+}
+
+// FindAutomatic returns active promotions that apply without a coupon code
+func (r *promotionRepository) FindAutomatic() ([]domain.Promotion, error) {
+	var promotions []domain.Promotion
+	err := r.db.Where("code IS NULL AND is_active = ? AND deleted_at IS NULL", true).Find(&promotions).Error
+	return promotions, err
+}
+
+// Discount Policy Methods
+func (r *promotionRepository) SetPolicy(policy *domain.DiscountPolicy) error {
+	var existing domain.DiscountPolicy
+	err := r.db.Where("role_id = ?", policy.RoleID).First(&existing).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return r.db.Create(policy).Error
+		}
+		return err
+	}
+
+	policy.ID = existing.ID
+	policy.CreatedAt = existing.CreatedAt
+	return r.db.Save(policy).Error
+}
+
+func (r *promotionRepository) FindPolicyByRoleID(roleID uint) (*domain.DiscountPolicy, error) {
+	var policy domain.DiscountPolicy
+	err := r.db.Where("role_id = ?", roleID).First(&policy).Error
+	return &policy, err
+}
+
+func (r *promotionRepository) FindAllPolicies() ([]domain.DiscountPolicy, error) {
+	var policies []domain.DiscountPolicy
+	err := r.db.Preload("Role").Find(&policies).Error
+	return policies, err
+}
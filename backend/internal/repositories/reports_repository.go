@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"erp-system/pkg/money"
+
 	"gorm.io/gorm"
 )
 
@@ -18,11 +20,11 @@ func NewReportsRepository(db *gorm.DB) ReportsRepository {
 }
 
 func (r *reportsRepository) GetSalesStats(startDate, endDate string) (map[string]interface{}, error) {
-	var totalSales float64
+	var totalSales money.Money
 	var totalOrders int64
 
 	// Total Sales
-	r.db.Table("sales_orders").Where("created_at BETWEEN ? AND ?", startDate, endDate).Select("COALESCE(SUM(total_amount), 0)").Scan(&totalSales)
+	r.db.Table("sales_orders").Where("created_at BETWEEN ? AND ?", startDate, endDate).Select("COALESCE(SUM(total_amount * exchange_rate), 0)").Scan(&totalSales)
 
 	// Total Orders
 	r.db.Table("sales_orders").Where("created_at BETWEEN ? AND ?", startDate, endDate).Count(&totalOrders)
@@ -36,6 +38,7 @@ func (r *reportsRepository) GetSalesStats(startDate, endDate string) (map[string
 
 	return map[string]interface{}{
 		"total_sales":     totalSales,
+		"currency":        money.DefaultCurrency,
 		"total_orders":    totalOrders,
 		"sales_by_status": salesByStatus,
 	}, nil
@@ -44,7 +47,7 @@ func (r *reportsRepository) GetSalesStats(startDate, endDate string) (map[string
 func (r *reportsRepository) GetInventoryStats() (map[string]interface{}, error) {
 	var totalProducts int64
 	var lowStockProducts int64
-	var totalValue float64
+	var totalValue money.Money
 
 	// Total Products
 	r.db.Table("products").Count(&totalProducts)
@@ -7,15 +7,24 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SalesRepository interface {
-	Create(order *domain.SalesOrder) error
+	Create(order *domain.SalesOrder, events ...domain.Event) error
 	FindAll(page, limit int, status string, customerID uint) ([]domain.SalesOrder, int64, error)
 	FindAllPaginated(params *pagination.PaginationParams, status string, customerID uint) *pagination.PaginatedResponse
 	FindByID(id uint) (*domain.SalesOrder, error)
+	Update(order *domain.SalesOrder, events ...domain.Event) error
 	UpdateStatus(id uint, status string) error
 	GenerateOrderNumber() (string, error)
+
+	CreatePayment(payment *domain.SalesPayment, order *domain.SalesOrder, events ...domain.Event) error
+	FindPaymentsByOrderID(orderID uint) ([]domain.SalesPayment, error)
+
+	UpdateWithItems(order *domain.SalesOrder, revision *domain.SalesOrderRevision, events ...domain.Event) error
+	FindRevisions(orderID uint) ([]domain.SalesOrderRevision, error)
+	FindRevision(orderID uint, revision int) (*domain.SalesOrderRevision, error)
 }
 
 type salesRepository struct {
@@ -26,8 +35,13 @@ func NewSalesRepository(db *gorm.DB) SalesRepository {
 	return &salesRepository{db: db}
 }
 
-func (r *salesRepository) Create(order *domain.SalesOrder) error {
-	return r.db.Create(order).Error
+func (r *salesRepository) Create(order *domain.SalesOrder, events ...domain.Event) error {
+	return inTransaction(r.db, events, func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		return applyBalanceChanges(tx, events)
+	})
 }
 
 func (r *salesRepository) FindAll(page, limit int, status string, customerID uint) ([]domain.SalesOrder, int64, error) {
@@ -70,10 +84,20 @@ func (r *salesRepository) FindAllPaginated(params *pagination.PaginationParams,
 
 func (r *salesRepository) FindByID(id uint) (*domain.SalesOrder, error) {
 	var order domain.SalesOrder
-	err := r.db.Preload("Customer").Preload("Items").First(&order, id).Error
+	err := r.db.Preload("Customer").Preload("Items").Preload("Items.Product").Preload("TaxSummary").Preload("Payments").First(&order, id).Error
 	return &order, err
 }
 
+// Update saves order header fields without touching items or customer
+func (r *salesRepository) Update(order *domain.SalesOrder, events ...domain.Event) error {
+	return inTransaction(r.db, events, func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(order).Error; err != nil {
+			return err
+		}
+		return applyBalanceChanges(tx, events)
+	})
+}
+
 func (r *salesRepository) UpdateStatus(id uint, status string) error {
 	return r.db.Model(&domain.SalesOrder{}).Where("id = ?", id).Update("status", status).Error
 }
@@ -84,3 +108,105 @@ func (r *salesRepository) GenerateOrderNumber() (string, error) {
 	year := time.Now().Format("2006")
 	return fmt.Sprintf("SO-%s-%05d", year, count+1), nil
 }
+
+// Payment Methods
+
+// CreatePayment stores the payment together with the order's new paid amount and events
+func (r *salesRepository) CreatePayment(payment *domain.SalesPayment, order *domain.SalesOrder, events ...domain.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(order).Error; err != nil {
+			return err
+		}
+		if err := applyBalanceChanges(tx, events); err != nil {
+			return err
+		}
+		return appendEvents(tx, events)
+	})
+}
+
+// applyBalanceChanges moves customer balances by the CustomerBalanceChanged events among
+// events. The update is relative, so concurrent orders for a customer don't overwrite each other.
+func applyBalanceChanges(tx *gorm.DB, events []domain.Event) error {
+	for _, e := range events {
+		if build, ok := e.(domain.EventFunc); ok {
+			e = build()
+		}
+		change, ok := e.(domain.CustomerBalanceChanged)
+		if !ok || change.Amount.IsZero() {
+			continue
+		}
+		if err := tx.Model(&domain.Customer{}).Where("id = ?", change.CustomerID).
+			Update("balance", gorm.Expr("balance + ?", change.Amount)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *salesRepository) FindPaymentsByOrderID(orderID uint) ([]domain.SalesPayment, error) {
+	var payments []domain.SalesPayment
+	err := r.db.Where("order_id = ?", orderID).Order("payment_date ASC, id ASC").Find(&payments).Error
+	return payments, err
+}
+
+// Revision Methods
+
+// UpdateWithItems saves the order header, its lines and tax summary in one transaction.
+// Lines no longer on the order are deleted. A non-nil revision and any events are stored alongside.
+func (r *salesRepository) UpdateWithItems(order *domain.SalesOrder, revision *domain.SalesOrderRevision, events ...domain.Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if revision != nil {
+			if err := tx.Create(revision).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(order).Error; err != nil {
+			return err
+		}
+
+		var kept []uint
+		for i := range order.Items {
+			order.Items[i].OrderID = order.ID
+			if err := tx.Omit(clause.Associations).Save(&order.Items[i]).Error; err != nil {
+				return err
+			}
+			kept = append(kept, order.Items[i].ID)
+		}
+		if err := tx.Where("order_id = ? AND id NOT IN ?", order.ID, kept).Delete(&domain.SalesOrderItem{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("order_id = ?", order.ID).Delete(&domain.SalesOrderTax{}).Error; err != nil {
+			return err
+		}
+		for i := range order.TaxSummary {
+			order.TaxSummary[i].ID = 0
+			order.TaxSummary[i].OrderID = order.ID
+		}
+		if len(order.TaxSummary) > 0 {
+			if err := tx.Create(&order.TaxSummary).Error; err != nil {
+				return err
+			}
+		}
+		if err := applyBalanceChanges(tx, events); err != nil {
+			return err
+		}
+		return appendEvents(tx, events)
+	})
+}
+
+func (r *salesRepository) FindRevisions(orderID uint) ([]domain.SalesOrderRevision, error) {
+	var revisions []domain.SalesOrderRevision
+	err := r.db.Where("order_id = ?", orderID).Order("revision ASC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *salesRepository) FindRevision(orderID uint, revision int) (*domain.SalesOrderRevision, error) {
+	var rev domain.SalesOrderRevision
+	err := r.db.Where("order_id = ? AND revision = ?", orderID, revision).First(&rev).Error
+	return &rev, err
+}
@@ -0,0 +1,286 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/pkg/money"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// purchaseStatuses are the order statuses that count as purchases in segments
+var purchaseStatuses = []string{domain.OrderStatusConfirmed, domain.OrderStatusShipped, domain.OrderStatusDelivered}
+
+const (
+	totalPurchasesSQL = "(SELECT COALESCE(SUM(so.base_net_amount), 0) FROM sales_orders so WHERE so.customer_id = customers.id AND so.status IN ? AND so.deleted_at IS NULL)"
+	lastOrderDateSQL  = "(SELECT MAX(so.order_date) FROM sales_orders so WHERE so.customer_id = customers.id AND so.status IN ? AND so.deleted_at IS NULL)"
+	customerTagSQL    = "customers.id IN (SELECT ct.customer_id FROM customer_tags ct JOIN tags t ON t.id = ct.tag_id WHERE t.name = ?)"
+)
+
+var comparisonSQL = map[string]string{"eq": "=", "neq": "<>", "gt": ">", "gte": ">=", "lt": "<", "lte": "<="}
+
+// segmentCondition translates a validated rule into SQL on the customers table
+func segmentCondition(rule domain.SegmentRule, now time.Time) (string, []interface{}) {
+	value := strings.TrimSpace(rule.Value)
+	switch rule.Field {
+	case domain.SegmentFieldTag:
+		if rule.Operator == "not_has" {
+			return "NOT " + customerTagSQL, []interface{}{value}
+		}
+		return customerTagSQL, []interface{}{value}
+
+	case domain.SegmentFieldBalance, domain.SegmentFieldCreditLimit, domain.SegmentFieldTotalPurchases:
+		amount, _ := money.Parse(value)
+		if rule.Field == domain.SegmentFieldTotalPurchases {
+			return totalPurchasesSQL + " " + comparisonSQL[rule.Operator] + " ?", []interface{}{purchaseStatuses, amount.Minor}
+		}
+		return "customers." + rule.Field + " " + comparisonSQL[rule.Operator] + " ?", []interface{}{amount.Minor}
+
+	case domain.SegmentFieldLastOrderDate:
+		switch rule.Operator {
+		case "never":
+			return lastOrderDateSQL + " IS NULL", []interface{}{purchaseStatuses}
+		case "before", "after":
+			day, _ := time.ParseInLocation("2006-01-02", value, now.Location())
+			if rule.Operator == "before" {
+				return lastOrderDateSQL + " < ?", []interface{}{purchaseStatuses, day}
+			}
+			return lastOrderDateSQL + " >= ?", []interface{}{purchaseStatuses, day.AddDate(0, 0, 1)}
+		}
+		days, _ := strconv.Atoi(value)
+		since := now.AddDate(0, 0, -days)
+		if rule.Operator == "within_days" {
+			return lastOrderDateSQL + " >= ?", []interface{}{purchaseStatuses, since}
+		}
+		// Customers who never ordered have not ordered recently either
+		return lastOrderDateSQL + " IS NULL OR " + lastOrderDateSQL + " < ?", []interface{}{purchaseStatuses, purchaseStatuses, since}
+	}
+
+	column := "customers." + rule.Field
+	switch rule.Operator {
+	case "in":
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return column + " IN ?", []interface{}{values}
+	case "contains":
+		return column + " LIKE ?", []interface{}{"%" + value + "%"}
+	}
+	return column + " " + comparisonSQL[rule.Operator] + " ?", []interface{}{value}
+}
+
+// segmentScope restricts a customers query to a segment's members
+func segmentScope(segment *domain.Segment) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(segment.Rules) == 0 {
+			return db
+		}
+		join := " AND "
+		if segment.Match == domain.SegmentMatchAny {
+			join = " OR "
+		}
+		now := time.Now()
+		parts := make([]string, 0, len(segment.Rules))
+		var args []interface{}
+		for _, rule := range segment.Rules {
+			sql, ruleArgs := segmentCondition(rule, now)
+			parts = append(parts, "("+sql+")")
+			args = append(args, ruleArgs...)
+		}
+		return db.Where("("+strings.Join(parts, join)+")", args...)
+	}
+}
+
+// filterCustomers applies the list filters shared by customer lists and exports
+func filterCustomers(db, query *gorm.DB, filter domain.CustomerFilter) (*gorm.DB, error) {
+	query = query.Where("customers.deleted_at IS NULL")
+	if filter.Search != "" {
+		query = query.Where("customers.name LIKE ? OR customers.email LIKE ? OR customers.phone LIKE ? OR customers.code LIKE ?",
+			"%"+filter.Search+"%", "%"+filter.Search+"%", "%"+filter.Search+"%", "%"+filter.Search+"%")
+	}
+	if filter.Tag != "" {
+		query = query.Where(customerTagSQL, filter.Tag)
+	}
+	if filter.SegmentID > 0 {
+		var segment domain.Segment
+		if err := db.First(&segment, filter.SegmentID).Error; err != nil {
+			return nil, err
+		}
+		query = query.Scopes(segmentScope(&segment))
+	}
+	return query, nil
+}
+
+// SegmentRepository stores tags, segments and campaigns and evaluates segment membership
+type SegmentRepository interface {
+	CreateTag(tag *domain.Tag) error
+	FindTagByID(id uint) (*domain.Tag, error)
+	FindTagByName(name string) (*domain.Tag, error)
+	FindAllTags() ([]domain.Tag, error)
+	DeleteTag(id uint) error
+	AddCustomerTag(customerID, tagID uint) error
+	RemoveCustomerTag(customerID, tagID uint) error
+	FindCustomerTags(customerID uint) ([]domain.Tag, error)
+
+	CreateSegment(segment *domain.Segment) error
+	UpdateSegment(segment *domain.Segment) error
+	DeleteSegment(id uint) error
+	FindSegmentByID(id uint) (*domain.Segment, error)
+	FindAllSegments() ([]domain.Segment, error)
+	CountMembers(segment *domain.Segment) (int64, error)
+	FindMembers(segment *domain.Segment, page, limit int) ([]domain.Customer, int64, error)
+	EachMember(segment *domain.Segment, fn func(*domain.Customer) error) error
+
+	CreateCampaign(campaign *domain.WhatsAppCampaign) error
+	UpdateCampaign(campaign *domain.WhatsAppCampaign) error
+	FindCampaigns(segmentID uint) ([]domain.WhatsAppCampaign, error)
+}
+
+type segmentRepository struct {
+	db *gorm.DB
+}
+
+// NewSegmentRepository creates a new segment repository
+func NewSegmentRepository(db *gorm.DB) SegmentRepository {
+	return &segmentRepository{db: db}
+}
+
+func (r *segmentRepository) CreateTag(tag *domain.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *segmentRepository) FindTagByID(id uint) (*domain.Tag, error) {
+	var tag domain.Tag
+	err := r.db.First(&tag, id).Error
+	return &tag, err
+}
+
+func (r *segmentRepository) FindTagByName(name string) (*domain.Tag, error) {
+	var tag domain.Tag
+	err := r.db.Where("name = ?", name).First(&tag).Error
+	return &tag, err
+}
+
+// FindAllTags lists tags with how many active customers carry each
+func (r *segmentRepository) FindAllTags() ([]domain.Tag, error) {
+	var tags []domain.Tag
+	if err := r.db.Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		TagID uint
+		Total int64
+	}
+	err := r.db.Table("customer_tags ct").
+		Select("ct.tag_id, COUNT(*) AS total").
+		Joins("JOIN customers c ON c.id = ct.customer_id AND c.deleted_at IS NULL").
+		Group("ct.tag_id").Scan(&counts).Error
+	for _, c := range counts {
+		for i := range tags {
+			if tags[i].ID == c.TagID {
+				tags[i].CustomerCount = c.Total
+			}
+		}
+	}
+	return tags, err
+}
+
+func (r *segmentRepository) DeleteTag(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM customer_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Tag{}, id).Error
+	})
+}
+
+func (r *segmentRepository) AddCustomerTag(customerID, tagID uint) error {
+	return r.db.Table("customer_tags").Clauses(clause.OnConflict{DoNothing: true}).
+		Create(map[string]interface{}{"customer_id": customerID, "tag_id": tagID}).Error
+}
+
+func (r *segmentRepository) RemoveCustomerTag(customerID, tagID uint) error {
+	return r.db.Exec("DELETE FROM customer_tags WHERE customer_id = ? AND tag_id = ?", customerID, tagID).Error
+}
+
+func (r *segmentRepository) FindCustomerTags(customerID uint) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	err := r.db.Joins("JOIN customer_tags ct ON ct.tag_id = tags.id").
+		Where("ct.customer_id = ?", customerID).Order("tags.name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *segmentRepository) CreateSegment(segment *domain.Segment) error {
+	return r.db.Create(segment).Error
+}
+
+func (r *segmentRepository) UpdateSegment(segment *domain.Segment) error {
+	return r.db.Save(segment).Error
+}
+
+func (r *segmentRepository) DeleteSegment(id uint) error {
+	return r.db.Delete(&domain.Segment{}, id).Error
+}
+
+func (r *segmentRepository) FindSegmentByID(id uint) (*domain.Segment, error) {
+	var segment domain.Segment
+	err := r.db.First(&segment, id).Error
+	return &segment, err
+}
+
+func (r *segmentRepository) FindAllSegments() ([]domain.Segment, error) {
+	var segments []domain.Segment
+	err := r.db.Order("name").Find(&segments).Error
+	return segments, err
+}
+
+func (r *segmentRepository) members(segment *domain.Segment) *gorm.DB {
+	return r.db.Model(&domain.Customer{}).Where("customers.deleted_at IS NULL").Scopes(segmentScope(segment))
+}
+
+func (r *segmentRepository) CountMembers(segment *domain.Segment) (int64, error) {
+	var total int64
+	err := r.members(segment).Count(&total).Error
+	return total, err
+}
+
+func (r *segmentRepository) FindMembers(segment *domain.Segment, page, limit int) ([]domain.Customer, int64, error) {
+	var customers []domain.Customer
+	var total int64
+
+	query := r.members(segment)
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	err := query.Preload("Tags").Offset(offset).Limit(limit).Order("customers.name").Find(&customers).Error
+	return customers, total, err
+}
+
+func (r *segmentRepository) EachMember(segment *domain.Segment, fn func(*domain.Customer) error) error {
+	return eachInBatches(r.members(segment), -1, fn)
+}
+
+func (r *segmentRepository) CreateCampaign(campaign *domain.WhatsAppCampaign) error {
+	return r.db.Create(campaign).Error
+}
+
+func (r *segmentRepository) UpdateCampaign(campaign *domain.WhatsAppCampaign) error {
+	return r.db.Save(campaign).Error
+}
+
+func (r *segmentRepository) FindCampaigns(segmentID uint) ([]domain.WhatsAppCampaign, error) {
+	var campaigns []domain.WhatsAppCampaign
+	query := r.db.Order("id DESC").Limit(100)
+	if segmentID > 0 {
+		query = query.Where("segment_id = ?", segmentID)
+	}
+	err := query.Find(&campaigns).Error
+	return campaigns, err
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionRepository interface {
+	FindByUser(userID uint) ([]domain.NotificationSubscription, error)
+	FindByEvent(event string) ([]domain.NotificationSubscription, error)
+	SaveAll(subscriptions []domain.NotificationSubscription) error
+	FindPreference(userID uint) (*domain.NotificationPreference, error)
+	SavePreference(pref *domain.NotificationPreference) error
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (r *subscriptionRepository) FindByUser(userID uint) ([]domain.NotificationSubscription, error) {
+	var subscriptions []domain.NotificationSubscription
+	err := r.db.Where("user_id = ?", userID).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *subscriptionRepository) FindByEvent(event string) ([]domain.NotificationSubscription, error) {
+	var subscriptions []domain.NotificationSubscription
+	err := r.db.Where("event = ?", event).Order("user_id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// SaveAll inserts or replaces subscriptions by user and event
+func (r *subscriptionRepository) SaveAll(subscriptions []domain.NotificationSubscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "channels", "updated_at"}),
+	}).Create(&subscriptions).Error
+}
+
+func (r *subscriptionRepository) FindPreference(userID uint) (*domain.NotificationPreference, error) {
+	var pref domain.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).First(&pref).Error
+	return &pref, err
+}
+
+func (r *subscriptionRepository) SavePreference(pref *domain.NotificationPreference) error {
+	return r.db.Save(pref).Error
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	Create(code *domain.TaxCode) error
+	Update(code *domain.TaxCode) error
+	Delete(id uint) error
+	FindByID(id uint) (*domain.TaxCode, error)
+	FindAll() ([]domain.TaxCode, error)
+	FindDefault() (*domain.TaxCode, error)
+	ClearDefault(exceptID uint) error
+}
+
+type taxRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRepository(db *gorm.DB) TaxRepository {
+	return &taxRepository{db: db}
+}
+
+func (r *taxRepository) Create(code *domain.TaxCode) error {
+	return r.db.Create(code).Error
+}
+
+func (r *taxRepository) Update(code *domain.TaxCode) error {
+	return r.db.Save(code).Error
+}
+
+func (r *taxRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.TaxCode{}, id).Error
+}
+
+func (r *taxRepository) FindByID(id uint) (*domain.TaxCode, error) {
+	var code domain.TaxCode
+	err := r.db.First(&code, id).Error
+	return &code, err
+}
+
+func (r *taxRepository) FindAll() ([]domain.TaxCode, error) {
+	var codes []domain.TaxCode
+	err := r.db.Where("deleted_at IS NULL").Order("code").Find(&codes).Error
+	return codes, err
+}
+
+func (r *taxRepository) FindDefault() (*domain.TaxCode, error) {
+	var code domain.TaxCode
+	err := r.db.Where("is_default = ? AND is_active = ?", true, true).First(&code).Error
+	return &code, err
+}
+
+// ClearDefault unsets the default flag on every code except the given one
+func (r *taxRepository) ClearDefault(exceptID uint) error {
+	return r.db.Model(&domain.TaxCode{}).Where("id <> ? AND is_default = ?", exceptID, true).Update("is_default", false).Error
+}
@@ -0,0 +1,377 @@
+package repositories
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/pkg/money"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// TimelineRepository reads a customer's history from every table that records it
+type TimelineRepository interface {
+	// FindEntries returns up to limit entries of each requested type that come after
+	// the cursor in timeline order; the caller merges and trims them
+	FindEntries(customerID uint, types []string, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error)
+	GetSummary(customerID uint) (*domain.CustomerSummary, error)
+}
+
+type timelineRepository struct {
+	db *gorm.DB
+}
+
+// NewTimelineRepository creates a new timeline repository
+func NewTimelineRepository(db *gorm.DB) TimelineRepository {
+	return &timelineRepository{db: db}
+}
+
+// Timeline sources; entries at the same instant are ordered by source
+const (
+	timelineSourceActivity     = "activity"
+	timelineSourceDocument     = "document"
+	timelineSourceNotification = "notification"
+	timelineSourceOrder        = "order"
+	timelineSourcePayment      = "payment"
+	timelineSourceApproved     = "status.approved"
+	timelineSourceCredit       = "status.credit"
+	timelineSourceDelivered    = "status.delivered"
+	timelineSourceMerged       = "status.merged"
+	timelineSourceRevised      = "status.revised"
+	timelineSourceShipped      = "status.shipped"
+)
+
+// afterCursor restricts a source query to rows that come after the cursor in timeline order
+func afterCursor(query *gorm.DB, timeColumn, idColumn, source string, cursor *domain.TimelineCursor) *gorm.DB {
+	if cursor == nil {
+		return query
+	}
+	switch {
+	case source < cursor.Source:
+		return query.Where(timeColumn+" < ?", cursor.At)
+	case source > cursor.Source:
+		return query.Where(timeColumn+" <= ?", cursor.At)
+	}
+	return query.Where("("+timeColumn+" < ? OR ("+timeColumn+" = ? AND "+idColumn+" < ?))", cursor.At, cursor.At, cursor.ID)
+}
+
+// timelinePage orders a source query newest first and caps it
+func timelinePage(query *gorm.DB, timeColumn, idColumn string, limit int) *gorm.DB {
+	return query.Order(timeColumn + " DESC").Order(idColumn + " DESC").Limit(limit)
+}
+
+func (r *timelineRepository) FindEntries(customerID uint, types []string, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	sources := map[string][]func(uint, *domain.TimelineCursor, int) ([]domain.TimelineEntry, error){
+		domain.TimelineActivity:     {r.activities},
+		domain.TimelineDocument:     {r.documents},
+		domain.TimelineOrder:        {r.orders},
+		domain.TimelineStatus:       {r.approvals, r.creditEvents, r.shipments, r.deliveries, r.revisions, r.merges},
+		domain.TimelinePayment:      {r.payments},
+		domain.TimelineNotification: {r.notifications},
+	}
+
+	var entries []domain.TimelineEntry
+	for _, t := range types {
+		for _, fetch := range sources[t] {
+			found, err := fetch(customerID, cursor, limit)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, found...)
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) activities(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.CustomerActivity
+	query := r.db.Where("customer_id = ?", customerID)
+	query = afterCursor(query, "created_at", "id", timelineSourceActivity, cursor)
+	if err := timelinePage(query, "created_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, a := range rows {
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineActivity, Subtype: a.Type, Source: timelineSourceActivity, RefID: a.ID,
+			OccurredAt: a.CreatedAt, Title: a.Type, Detail: a.Description,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) documents(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.CustomerDocument
+	query := r.db.Where("customer_id = ? AND deleted_at IS NULL", customerID)
+	query = afterCursor(query, "uploaded_at", "id", timelineSourceDocument, cursor)
+	if err := timelinePage(query, "uploaded_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, d := range rows {
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineDocument, Subtype: d.FileType, Source: timelineSourceDocument, RefID: d.ID,
+			OccurredAt: d.UploadedAt, Title: d.Title, Detail: d.OriginalName,
+		}
+	}
+	return entries, nil
+}
+
+// customerOrders selects sales orders of the customer, for joins on an order_id column
+func (r *timelineRepository) customerOrders(customerID uint) *gorm.DB {
+	return r.db.Model(&domain.SalesOrder{}).Select("id").Where("customer_id = ? AND deleted_at IS NULL", customerID)
+}
+
+func (r *timelineRepository) orders(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.SalesOrder
+	query := r.db.Where("customer_id = ? AND deleted_at IS NULL", customerID)
+	query = afterCursor(query, "created_at", "id", timelineSourceOrder, cursor)
+	if err := timelinePage(query, "created_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, o := range rows {
+		amount, orderID := o.NetAmount, o.ID
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineOrder, Subtype: o.Status, Source: timelineSourceOrder, RefID: o.ID,
+			OccurredAt: o.CreatedAt, Title: o.OrderNumber, Detail: o.Notes,
+			Amount: &amount, Currency: o.Currency, OrderID: &orderID,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) payments(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.SalesPayment
+	query := r.db.Where("order_id IN (?)", r.customerOrders(customerID))
+	query = afterCursor(query, "payment_date", "id", timelineSourcePayment, cursor)
+	if err := timelinePage(query, "payment_date", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, p := range rows {
+		amount, orderID := p.Amount, p.OrderID
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelinePayment, Subtype: p.Method, Source: timelineSourcePayment, RefID: p.ID,
+			OccurredAt: p.PaymentDate, Title: p.Reference,
+			Amount: &amount, Currency: p.Currency, OrderID: &orderID,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) approvals(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.SalesOrder
+	query := r.db.Where("customer_id = ? AND deleted_at IS NULL AND approved_at IS NOT NULL", customerID)
+	query = afterCursor(query, "approved_at", "id", timelineSourceApproved, cursor)
+	if err := timelinePage(query, "approved_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, o := range rows {
+		orderID := o.ID
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineStatus, Subtype: "approved", Source: timelineSourceApproved, RefID: o.ID,
+			OccurredAt: *o.ApprovedAt, Title: o.OrderNumber, Detail: o.ApprovalNote, OrderID: &orderID,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) creditEvents(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.CreditEvent
+	query := r.db.Where("customer_id = ?", customerID)
+	query = afterCursor(query, "created_at", "id", timelineSourceCredit, cursor)
+	if err := timelinePage(query, "created_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, e := range rows {
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineStatus, Subtype: e.Type, Source: timelineSourceCredit, RefID: e.ID,
+			OccurredAt: e.CreatedAt, Title: e.Type, Detail: e.Reason, OrderID: e.OrderID,
+		}
+		if !e.Amount.IsZero() {
+			amount := e.Amount
+			entries[i].Amount = &amount
+		}
+	}
+	return entries, nil
+}
+
+// deliveryNotes lists the customer's delivery notes at the given time column
+func (r *timelineRepository) deliveryNotes(customerID uint, timeColumn, source string, cursor *domain.TimelineCursor, limit int) ([]domain.DeliveryNote, error) {
+	var rows []domain.DeliveryNote
+	query := r.db.Where("order_id IN (?) AND "+timeColumn+" IS NOT NULL", r.customerOrders(customerID))
+	query = afterCursor(query, timeColumn, "id", source, cursor)
+	err := timelinePage(query, timeColumn, "id", limit).Find(&rows).Error
+	return rows, err
+}
+
+func (r *timelineRepository) shipments(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	rows, err := r.deliveryNotes(customerID, "created_at", timelineSourceShipped, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, n := range rows {
+		orderID := n.OrderID
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineStatus, Subtype: "shipped", Source: timelineSourceShipped, RefID: n.ID,
+			OccurredAt: n.CreatedAt, Title: n.NoteNumber, Detail: n.DriverName, OrderID: &orderID,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) deliveries(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	rows, err := r.deliveryNotes(customerID, "delivered_at", timelineSourceDelivered, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, n := range rows {
+		orderID := n.OrderID
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineStatus, Subtype: "delivered", Source: timelineSourceDelivered, RefID: n.ID,
+			OccurredAt: *n.DeliveredAt, Title: n.NoteNumber, Detail: n.ReceivedBy, OrderID: &orderID,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) revisions(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.SalesOrderRevision
+	query := r.db.Where("order_id IN (?)", r.customerOrders(customerID))
+	query = afterCursor(query, "created_at", "id", timelineSourceRevised, cursor)
+	if err := timelinePage(query, "created_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, rev := range rows {
+		orderID := rev.OrderID
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineStatus, Subtype: "revised", Source: timelineSourceRevised, RefID: rev.ID,
+			OccurredAt: rev.CreatedAt, Title: fmt.Sprintf("revision %d", rev.Revision), Detail: rev.Reason, OrderID: &orderID,
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) merges(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var rows []domain.CustomerMerge
+	query := r.db.Where("survivor_id = ?", customerID)
+	query = afterCursor(query, "created_at", "id", timelineSourceMerged, cursor)
+	if err := timelinePage(query, "created_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, m := range rows {
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineStatus, Subtype: "merged", Source: timelineSourceMerged, RefID: m.ID,
+			OccurredAt: m.CreatedAt, Title: m.MergedName, Detail: m.Reason,
+		}
+	}
+	return entries, nil
+}
+
+// notifications finds notifications linking to the customer or one of its orders. A
+// notification sent to several users is stored once per user in consecutive rows, so only
+// the first copy is returned.
+func (r *timelineRepository) notifications(customerID uint, cursor *domain.TimelineCursor, limit int) ([]domain.TimelineEntry, error) {
+	var orderIDs []uint
+	if err := r.customerOrders(customerID).Pluck("id", &orderIDs).Error; err != nil {
+		return nil, err
+	}
+	customerLink := fmt.Sprintf("/customers/%d", customerID)
+	links := []string{customerLink}
+	for _, id := range orderIDs {
+		links = append(links, fmt.Sprintf("/sales/%d", id))
+	}
+
+	var rows []domain.Notification
+	query := r.db.Where("(link IN ? OR link LIKE ?)", links, customerLink+"/%").
+		Where("NOT EXISTS (SELECT 1 FROM notifications prev WHERE prev.id = notifications.id - 1 " +
+			"AND prev.title = notifications.title AND prev.message = notifications.message AND prev.link = notifications.link)")
+	query = afterCursor(query, "created_at", "id", timelineSourceNotification, cursor)
+	if err := timelinePage(query, "created_at", "id", limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TimelineEntry, len(rows))
+	for i, n := range rows {
+		entries[i] = domain.TimelineEntry{
+			Type: domain.TimelineNotification, Subtype: n.Type, Source: timelineSourceNotification, RefID: n.ID,
+			OccurredAt: n.CreatedAt, Title: n.Title, Detail: n.Message,
+		}
+		var orderID uint
+		if _, err := fmt.Sscanf(n.Link, "/sales/%d", &orderID); err == nil {
+			entries[i].OrderID = &orderID
+		}
+	}
+	return entries, nil
+}
+
+func (r *timelineRepository) GetSummary(customerID uint) (*domain.CustomerSummary, error) {
+	summary := &domain.CustomerSummary{}
+
+	purchases := func() *gorm.DB {
+		return r.db.Model(&domain.SalesOrder{}).
+			Where("customer_id = ? AND status IN ? AND deleted_at IS NULL", customerID, purchaseStatuses)
+	}
+
+	var totals struct {
+		Total int64
+		Count int64
+	}
+	if err := purchases().Select("COALESCE(SUM(base_net_amount), 0) AS total, COUNT(*) AS count").Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	summary.LifetimeValue = money.FromMinor(totals.Total)
+	summary.OrderCount = totals.Count
+	if totals.Count > 0 {
+		summary.AverageOrderValue = summary.LifetimeValue.Div(totals.Count)
+	}
+
+	var first, last domain.SalesOrder
+	if err := purchases().Order("order_date ASC").Limit(1).Find(&first).Error; err != nil {
+		return nil, err
+	}
+	if first.ID != 0 {
+		summary.FirstOrderAt = &first.OrderDate
+	}
+	if err := purchases().Order("order_date DESC").Limit(1).Find(&last).Error; err != nil {
+		return nil, err
+	}
+	if last.ID != 0 {
+		summary.LastOrderAt = &last.OrderDate
+	}
+
+	var paid int64
+	if err := r.db.Model(&domain.SalesPayment{}).Select("COALESCE(SUM(base_amount), 0)").
+		Where("order_id IN (?)", r.customerOrders(customerID)).Scan(&paid).Error; err != nil {
+		return nil, err
+	}
+	summary.TotalPaid = money.FromMinor(paid)
+
+	var contact domain.CustomerActivity
+	if err := r.db.Where("customer_id = ? AND type IN ?", customerID, domain.ContactActivityTypes).
+		Order("created_at DESC").Limit(1).Find(&contact).Error; err != nil {
+		return nil, err
+	}
+	if contact.ID != 0 {
+		at := contact.CreatedAt
+		summary.LastContactAt = &at
+		summary.LastContactType = contact.Type
+	}
+	return summary, nil
+}
@@ -11,6 +11,7 @@ type UserRepository interface {
 	FindByEmail(email string) (*domain.User, error)
 	FindByID(id uint) (*domain.User, error)
 	FindAll(offset, limit int) ([]domain.User, int64, error)
+	FindByRoleID(roleID uint) ([]domain.User, error)
 	Create(user *domain.User) error
 	Update(user *domain.User) error
 	Delete(id uint) error
@@ -52,6 +53,12 @@ func (r *userRepository) FindAll(offset, limit int) ([]domain.User, int64, error
 	return users, total, err
 }
 
+func (r *userRepository) FindByRoleID(roleID uint) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Where("role_id = ? AND is_active = ? AND deleted_at IS NULL", roleID, true).Find(&users).Error
+	return users, err
+}
+
 func (r *userRepository) Create(user *domain.User) error {
 	return r.db.Create(user).Error
 }
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Credit control defaults used when the settings are missing
+const (
+	defaultCreditWarningPercent = 80
+	defaultCreditApproverRole   = 2 // Manager
+)
+
+type CreditService struct {
+	creditRepo   repositories.CreditRepository
+	settingsRepo repositories.SettingsRepository
+	userRepo     repositories.UserRepository
+	dispatcher   *NotificationDispatcher
+}
+
+func NewCreditService(cr repositories.CreditRepository, sr repositories.SettingsRepository, ur repositories.UserRepository, dispatcher *NotificationDispatcher) *CreditService {
+	return &CreditService{creditRepo: cr, settingsRepo: sr, userRepo: ur, dispatcher: dispatcher}
+}
+
+// CreditDecision is the outcome of a credit check. Override is set when an active
+// manager override let an otherwise blocked order through.
+type CreditDecision struct {
+	Blocked  bool
+	Reason   string
+	Override *domain.CreditOverride
+}
+
+func (s *CreditService) settingFloat(key string, fallback float64) float64 {
+	if setting, err := s.settingsRepo.Get(key); err == nil {
+		if v, err := strconv.ParseFloat(setting.Value, 64); err == nil {
+			return v
+		}
+	}
+	return fallback
+}
+
+// WarningPercent is the utilisation of the credit limit at which approvers are warned
+func (s *CreditService) WarningPercent() float64 {
+	return s.settingFloat(domain.SettingCreditWarningPercent, defaultCreditWarningPercent)
+}
+
+// GraceDays is how long past the payment terms an invoice may stay unpaid before it counts as overdue
+func (s *CreditService) GraceDays() int {
+	return int(s.settingFloat(domain.SettingCreditGraceDays, 0))
+}
+
+// DueDate returns when an order must be paid under the customer's payment terms
+func DueDate(order *domain.SalesOrder, customer *domain.Customer) time.Time {
+	return order.OrderDate.AddDate(0, 0, customer.PaymentTermsDays)
+}
+
+// Overdue returns the customer's unpaid orders past their due date and grace period,
+// and their outstanding total in the base currency
+func (s *CreditService) Overdue(customer *domain.Customer, at time.Time) ([]domain.SalesOrder, money.Money, error) {
+	invoices, err := s.creditRepo.FindOpenInvoices(customer.ID)
+	if err != nil {
+		return nil, money.Money{}, err
+	}
+
+	grace := s.GraceDays()
+	var overdue []domain.SalesOrder
+	var total money.Money
+	for _, order := range invoices {
+		if at.After(DueDate(&order, customer).AddDate(0, 0, grace)) {
+			overdue = append(overdue, order)
+			total = total.Add(ToBase(order.NetAmount.Sub(order.PaidAmount), order.ExchangeRate))
+		}
+	}
+	return overdue, total, nil
+}
+
+// Check decides whether the customer may take on the additional base amount. Holds and
+// overdue invoices block any order; otherwise the credit limit applies. An active override
+// lets the order through, up to its maximum above the limit. orderID is zero for new orders,
+// which cannot use overrides granted for one specific order.
+func (s *CreditService) Check(customer *domain.Customer, amount money.Money, orderID uint) CreditDecision {
+	now := time.Now()
+
+	var reason string
+	if customer.CreditHold {
+		reason = "customer is on credit hold: " + customer.CreditHoldReason
+	} else if overdue, total, err := s.Overdue(customer, now); err == nil && len(overdue) > 0 {
+		reason = fmt.Sprintf("customer has %d overdue invoices totalling %s", len(overdue), total)
+	}
+
+	exposure := customer.Balance.Add(amount)
+	overLimit := customer.CreditLimit.IsPositive() && exposure.GreaterThan(customer.CreditLimit)
+	if reason == "" && !overLimit {
+		return CreditDecision{}
+	}
+	if reason == "" {
+		reason = fmt.Sprintf("credit limit of %s exceeded: balance %s plus order %s", customer.CreditLimit, customer.Balance, amount)
+	}
+
+	overrides, _ := s.creditRepo.FindActiveOverrides(customer.ID, now)
+	for i := range overrides {
+		o := &overrides[i]
+		if o.OrderID != nil && *o.OrderID != orderID {
+			continue
+		}
+		if overLimit && o.MaxAmount.IsPositive() && exposure.GreaterThan(customer.CreditLimit.Add(o.MaxAmount)) {
+			continue
+		}
+		return CreditDecision{Reason: reason, Override: o}
+	}
+	return CreditDecision{Blocked: true, Reason: reason}
+}
+
+// Record appends an event to the customer's credit history with their current position.
+// A zero user marks the event as automatic.
+func (s *CreditService) Record(customer *domain.Customer, event *domain.CreditEvent) {
+	if event.UserID != nil && *event.UserID == 0 {
+		event.UserID = nil
+	}
+	event.CustomerID = customer.ID
+	event.Balance = customer.Balance
+	event.CreditLimit = customer.CreditLimit
+	_ = s.creditRepo.CreateEvent(event)
+}
+
+// OrderBlocked logs an order placed in the credit hold queue and tells the approvers
+func (s *CreditService) OrderBlocked(customer *domain.Customer, order *domain.SalesOrder) {
+	s.Record(customer, &domain.CreditEvent{
+		Type:    domain.CreditEventOrderBlocked,
+		OrderID: &order.ID,
+		Amount:  order.BaseNetAmount,
+		Reason:  order.CreditNote,
+	})
+	s.NotifyApprovers("طلب موقوف ائتمانياً: "+order.OrderNumber, customer.Name+": "+order.CreditNote, "warning", fmt.Sprintf("/sales/%d", order.ID))
+}
+
+// Booked is called after an amount was added to the customer balance. It logs the use
+// of an override and warns when the balance crosses the warning threshold.
+func (s *CreditService) Booked(customer *domain.Customer, previousBalance money.Money, order *domain.SalesOrder, decision CreditDecision, userID uint) {
+	if decision.Override != nil {
+		s.Record(customer, &domain.CreditEvent{
+			Type:       domain.CreditEventOverrideUsed,
+			OrderID:    &order.ID,
+			OverrideID: &decision.Override.ID,
+			Amount:     customer.Balance.Sub(previousBalance),
+			Reason:     decision.Reason,
+			UserID:     &userID,
+		})
+	}
+
+	if !customer.CreditLimit.IsPositive() {
+		return
+	}
+	threshold := s.WarningPercent()
+	before := Utilisation(previousBalance, customer.CreditLimit)
+	after := Utilisation(customer.Balance, customer.CreditLimit)
+	if before >= threshold || after < threshold {
+		return
+	}
+
+	reason := fmt.Sprintf("credit utilisation reached %.1f%% of %s", after, customer.CreditLimit)
+	s.Record(customer, &domain.CreditEvent{Type: domain.CreditEventWarning, OrderID: &order.ID, Reason: reason})
+	s.NotifyApprovers("تحذير حد ائتماني: "+customer.Name, reason, "warning", fmt.Sprintf("/customers/%d", customer.ID))
+}
+
+// Utilisation returns the balance as a percentage of the limit
+func Utilisation(balance, limit money.Money) float64 {
+	if !limit.IsPositive() {
+		return 0
+	}
+	return float64(balance.Minor) * 100 / float64(limit.Minor)
+}
+
+// NotifyApprovers dispatches a credit breach event to the users of the credit approver role
+// and anyone else subscribed to credit breaches
+func (s *CreditService) NotifyApprovers(title, message, notifType, link string) {
+	if s.dispatcher == nil || s.userRepo == nil {
+		return
+	}
+
+	approvers, err := s.userRepo.FindByRoleID(uint(s.settingFloat(domain.SettingCreditApproverRole, defaultCreditApproverRole)))
+	if err != nil {
+		return
+	}
+	event := domain.NotificationEvent{
+		Type:      domain.EventCreditBreach,
+		Title:     title,
+		Message:   message,
+		Link:      link,
+		NotifType: notifType,
+	}
+	for _, approver := range approvers {
+		event.Audience = append(event.Audience, approver.ID)
+	}
+	s.dispatcher.Dispatch(event)
+}
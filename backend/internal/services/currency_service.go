@@ -0,0 +1,43 @@
+package services
+
+import (
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+	"fmt"
+	"time"
+)
+
+type CurrencyService struct {
+	currencyRepo repositories.CurrencyRepository
+}
+
+func NewCurrencyService(cr repositories.CurrencyRepository) *CurrencyService {
+	return &CurrencyService{currencyRepo: cr}
+}
+
+// BaseCurrency returns the company base currency used by the dashboard and reports
+func (s *CurrencyService) BaseCurrency() string {
+	return money.DefaultCurrency
+}
+
+// RateAt returns the base currency value of one unit of the currency on the date
+func (s *CurrencyService) RateAt(currency string, date time.Time) (float64, error) {
+	if currency == "" || currency == s.BaseCurrency() {
+		return 1, nil
+	}
+	rate, err := s.currencyRepo.FindRateAt(currency, date)
+	if err != nil {
+		return 0, fmt.Errorf("no exchange rate for %s on %s", currency, date.Format("2006-01-02"))
+	}
+	return rate.Rate, nil
+}
+
+// ToBase converts an amount at the given rate into the base currency
+func ToBase(amount money.Money, rate float64) money.Money {
+	return amount.Ratio(rate, 1)
+}
+
+// FromBase converts a base currency amount into a currency quoted at the given rate
+func FromBase(amount money.Money, rate float64) money.Money {
+	return amount.Ratio(1, rate)
+}
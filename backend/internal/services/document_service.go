@@ -0,0 +1,583 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+	"erp-system/pkg/pdf"
+)
+
+// Font files tried when no font is configured in settings
+var defaultFontPaths = []string{
+	"assets/fonts/DejaVuSans.ttf",
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/TTF/DejaVuSans.ttf",
+	"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+	"/Library/Fonts/Arial Unicode.ttf",
+	"C:\\Windows\\Fonts\\arial.ttf",
+}
+
+const (
+	pageMargin   = 40.0
+	footerHeight = 40.0
+)
+
+// DocumentService renders printable PDF documents
+type DocumentService struct {
+	settingsRepo repositories.SettingsRepository
+	storage      *StorageService
+}
+
+func NewDocumentService(sr repositories.SettingsRepository, storage *StorageService) *DocumentService {
+	return &DocumentService{settingsRepo: sr, storage: storage}
+}
+
+// documentTitles holds the bilingual title of each sales document kind
+var documentTitles = map[string][2]string{
+	domain.SalesDocumentQuote:        {"عرض سعر", "Quotation"},
+	domain.SalesDocumentOrder:        {"أمر بيع", "Sales Order"},
+	domain.SalesDocumentInvoice:      {"فاتورة ضريبية", "Tax Invoice"},
+	domain.SalesDocumentDeliveryNote: {"إذن تسليم", "Delivery Note"},
+}
+
+// docColumn describes one table column; Arabic and English labels are stacked in the header
+type docColumn struct {
+	ar, en string
+	width  float64
+	right  bool // Right-align values (numbers)
+}
+
+// docLayout is the layout state while a document is being drawn
+type docLayout struct {
+	doc     *pdf.Document
+	y       float64
+	bold    string
+	columns []docColumn
+	header  func()
+}
+
+func (s *DocumentService) setting(key string) string {
+	if setting, err := s.settingsRepo.Get(key); err == nil {
+		return setting.Value
+	}
+	return ""
+}
+
+// newDocument creates a document with the configured fonts registered as "regular" and "bold"
+func (s *DocumentService) newDocument() (*pdf.Document, string, error) {
+	regularPaths := append([]string{s.setting(domain.SettingPDFFont)}, defaultFontPaths...)
+	var regular *pdf.TrueTypeFont
+	for _, path := range regularPaths {
+		if path == "" {
+			continue
+		}
+		if font, err := pdf.LoadTrueTypeFont(fontName(path), path); err == nil {
+			regular = font
+			break
+		}
+	}
+	if regular == nil {
+		return nil, "", errors.New("no PDF font available: set " + domain.SettingPDFFont + " to a TrueType font with Arabic glyphs")
+	}
+
+	doc := pdf.New()
+	doc.AddFont("regular", regular)
+
+	bold := "regular"
+	boldPaths := []string{s.setting(domain.SettingPDFFontBold)}
+	for _, path := range regularPaths {
+		if path != "" {
+			boldPaths = append(boldPaths, strings.Replace(path, ".ttf", "-Bold.ttf", 1))
+		}
+	}
+	for _, path := range boldPaths {
+		if path == "" {
+			continue
+		}
+		if font, err := pdf.LoadTrueTypeFont(fontName(path), path); err == nil {
+			doc.AddFont("bold", font)
+			bold = "bold"
+			break
+		}
+	}
+	return doc, bold, nil
+}
+
+func fontName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// RenderSalesDocument renders a sales order as a quotation, order, invoice or delivery note
+func (s *DocumentService) RenderSalesDocument(order *domain.SalesOrder, kind string) ([]byte, error) {
+	title, ok := documentTitles[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown document type %s", kind)
+	}
+
+	doc, bold, err := s.newDocument()
+	if err != nil {
+		return nil, err
+	}
+	p := &docLayout{doc: doc, bold: bold}
+
+	doc.AddPage()
+	s.drawHeader(p, title)
+	drawOrderInfo(p, order, kind)
+
+	if kind == domain.SalesDocumentDeliveryNote {
+		rows := make([]deliveryRow, 0, len(order.Items))
+		for _, item := range order.Items {
+			rows = append(rows, deliveryRow{item.Product, itemName(item), item.Quantity})
+		}
+		drawDeliveryItems(p, rows)
+		drawSignatures(p)
+	} else {
+		drawPricedItems(p, order)
+		drawTotals(p, order, kind)
+	}
+
+	drawNotes(p, order.Notes)
+
+	drawFooters(doc, order.OrderNumber)
+	return doc.Bytes()
+}
+
+// RenderDeliveryNote renders a delivery note with only the quantities it ships
+func (s *DocumentService) RenderDeliveryNote(note *domain.DeliveryNote, order *domain.SalesOrder) ([]byte, error) {
+	doc, bold, err := s.newDocument()
+	if err != nil {
+		return nil, err
+	}
+	p := &docLayout{doc: doc, bold: bold}
+
+	doc.AddPage()
+	s.drawHeader(p, documentTitles[domain.SalesDocumentDeliveryNote])
+
+	left := []infoLine{
+		{"الرقم", "Number", note.NoteNumber},
+		{"التاريخ", "Date", note.DeliveryDate.Format("2006-01-02")},
+		{"أمر البيع", "Order", order.OrderNumber},
+	}
+	if note.Warehouse != nil {
+		left = append(left, infoLine{"المخزن", "Warehouse", note.Warehouse.Name})
+	}
+	if note.DriverName != "" {
+		left = append(left, infoLine{"السائق", "Driver", strings.TrimSpace(note.DriverName + " " + note.DriverPhone)})
+	}
+	if note.VehicleNumber != "" {
+		left = append(left, infoLine{"المركبة", "Vehicle", note.VehicleNumber})
+	}
+	drawPartyInfo(p, left, order.Customer)
+
+	lines := make(map[uint]domain.SalesOrderItem, len(order.Items))
+	for _, item := range order.Items {
+		lines[item.ID] = item
+	}
+	rows := make([]deliveryRow, 0, len(note.Items))
+	for _, item := range note.Items {
+		line, ok := lines[item.OrderItemID]
+		if !ok {
+			line = domain.SalesOrderItem{ProductID: item.ProductID, Product: item.Product}
+		}
+		rows = append(rows, deliveryRow{line.Product, itemName(line), item.Quantity})
+	}
+	drawDeliveryItems(p, rows)
+	drawSignatures(p)
+	drawNotes(p, note.Notes)
+
+	drawFooters(doc, note.NoteNumber)
+	return doc.Bytes()
+}
+
+func drawNotes(p *docLayout, notes string) {
+	if notes == "" {
+		return
+	}
+	doc := p.doc
+	p.ensureSpace(40)
+	p.y += 20
+	p.font(p.bold, 9)
+	doc.Text(pageMargin, p.y, "ملاحظات / Notes")
+	p.font("regular", 9)
+	for _, line := range doc.WrapText(notes, doc.Width()-2*pageMargin) {
+		p.ensureSpace(12)
+		p.y += 12
+		doc.Text(pageMargin, p.y, line)
+	}
+}
+
+// drawHeader draws the logo, company details and the bilingual title on the current page
+func (s *DocumentService) drawHeader(p *docLayout, title [2]string) {
+	doc := p.doc
+	right := doc.Width() - pageMargin
+
+	if logo := s.setting(domain.SettingCompanyLogo); logo != "" && s.storage != nil && !strings.HasSuffix(logo, ".svg") {
+		if data, err := s.storage.Read(logo); err == nil {
+			_ = doc.Image(data, pageMargin, 30, 0, 55)
+		}
+	}
+
+	name := s.setting(domain.SettingCompanyName)
+	if name == "" {
+		name = "ERP System"
+	}
+	p.font(p.bold, 16)
+	doc.TextRight(right, 48, name)
+
+	p.font("regular", 9)
+	y := 62.0
+	for _, line := range []string{
+		s.setting(domain.SettingCompanyAddress),
+		s.setting(domain.SettingCompanyPhone),
+		labelled("الرقم الضريبي", "Tax No.", s.setting(domain.SettingCompanyTaxNumber)),
+	} {
+		if line != "" {
+			doc.TextRight(right, y, line)
+			y += 12
+		}
+	}
+
+	doc.SetStrokeColor(180, 180, 180)
+	doc.SetLineWidth(0.8)
+	doc.Line(pageMargin, 100, right, 100)
+
+	p.font(p.bold, 16)
+	doc.TextCenter(doc.Width()/2, 126, title[0])
+	p.font("regular", 11)
+	doc.TextCenter(doc.Width()/2, 141, title[1])
+	p.y = 160
+}
+
+type infoLine struct{ ar, en, value string }
+
+type totalLine struct {
+	ar, en string
+	value  money.Money
+}
+
+func drawOrderInfo(p *docLayout, order *domain.SalesOrder, kind string) {
+	left := []infoLine{
+		{"الرقم", "Number", order.OrderNumber},
+		{"التاريخ", "Date", order.OrderDate.Format("2006-01-02")},
+	}
+	if order.DeliveryDate != nil {
+		left = append(left, infoLine{"تاريخ التسليم", "Delivery", order.DeliveryDate.Format("2006-01-02")})
+	}
+	if kind != domain.SalesDocumentDeliveryNote {
+		left = append(left, infoLine{"العملة", "Currency", currencyOf(order)})
+	}
+	drawPartyInfo(p, left, order.Customer)
+}
+
+// drawPartyInfo draws the document details on the left and the customer block on the right
+func drawPartyInfo(p *docLayout, left []infoLine, customer domain.Customer) {
+	doc := p.doc
+	right := []string{customer.Name, customer.Code, strings.TrimSpace(customer.Address + " " + customer.City), customer.Phone}
+	if customer.TaxNumber != "" {
+		right = append(right, labelled("الرقم الضريبي", "Tax No.", customer.TaxNumber))
+	}
+
+	top := p.y
+	for i, row := range left {
+		y := top + float64(i+1)*14
+		p.font(p.bold, 9)
+		doc.Text(pageMargin, y, row.ar+" / "+row.en)
+		p.font("regular", 9)
+		doc.Text(pageMargin+110, y, row.value)
+	}
+
+	edge := doc.Width() - pageMargin
+	p.font(p.bold, 9)
+	doc.TextRight(edge, top+14, "العميل / Customer")
+	p.font("regular", 9)
+	lines := 1
+	for _, line := range right {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines++
+		doc.TextRight(edge, top+float64(lines)*14, line)
+	}
+
+	rows := len(left)
+	if lines > rows {
+		rows = lines
+	}
+	p.y = top + float64(rows)*14 + 16
+}
+
+func drawPricedItems(p *docLayout, order *domain.SalesOrder) {
+	p.columns = []docColumn{
+		{"م", "#", 22, false},
+		{"الصنف", "Item", 178, false},
+		{"الكمية", "Qty", 45, true},
+		{"سعر الوحدة", "Unit Price", 65, true},
+		{"الخصم", "Discount", 60, true},
+		{"الضريبة", "Tax", 60, true},
+		{"الإجمالي", "Total", 85, true},
+	}
+	p.header = p.tableHeader
+	p.tableHeader()
+
+	for i, item := range order.Items {
+		gross := item.UnitPrice.MulFloat(item.Quantity)
+		discount := gross.Percent(item.Discount).Add(item.PromotionDiscount)
+		tax := formatAmount(item.TaxAmount)
+		if item.TaxRate > 0 {
+			tax = fmt.Sprintf("%s (%g%%)", tax, item.TaxRate)
+		}
+		p.tableRow([]string{
+			fmt.Sprint(i + 1),
+			itemName(item),
+			formatQuantity(item.Quantity),
+			formatAmount(item.UnitPrice),
+			formatAmount(discount),
+			tax,
+			formatAmount(item.Total),
+		})
+	}
+}
+
+type deliveryRow struct {
+	product  *domain.Product
+	name     string
+	quantity float64
+}
+
+func drawDeliveryItems(p *docLayout, rows []deliveryRow) {
+	p.columns = []docColumn{
+		{"م", "#", 25, false},
+		{"الكود", "SKU", 90, false},
+		{"الصنف", "Item", 250, false},
+		{"الكمية", "Qty", 75, true},
+		{"المستلم", "Received", 75, true},
+	}
+	p.header = p.tableHeader
+	p.tableHeader()
+
+	for i, row := range rows {
+		sku := ""
+		if row.product != nil {
+			sku = row.product.SKU
+		}
+		p.tableRow([]string{fmt.Sprint(i + 1), sku, row.name, formatQuantity(row.quantity), ""})
+	}
+}
+
+// drawTotals draws the tax breakdown on the left and the order totals on the right
+func drawTotals(p *docLayout, order *domain.SalesOrder, kind string) {
+	doc := p.doc
+	p.header = nil
+
+	totals := []totalLine{
+		{"المجموع", "Subtotal", order.TotalAmount},
+		{"الخصم", "Discount", order.DiscountAmount},
+		{"الضريبة", "Tax", order.TaxAmount},
+		{"الصافي", "Net Total", order.NetAmount},
+	}
+	if kind == domain.SalesDocumentInvoice && order.PaidAmount.IsPositive() {
+		totals = append(totals,
+			totalLine{"المدفوع", "Paid", order.PaidAmount},
+			totalLine{"المستحق", "Balance Due", order.NetAmount.Sub(order.PaidAmount)})
+	}
+
+	rows := len(totals)
+	if len(order.TaxSummary)+1 > rows {
+		rows = len(order.TaxSummary) + 1
+	}
+	p.ensureSpace(float64(rows)*16 + 40)
+	top := p.y + 10
+
+	// Tax breakdown
+	if len(order.TaxSummary) > 0 {
+		p.font(p.bold, 8)
+		x := pageMargin
+		for _, h := range []struct {
+			label string
+			width float64
+		}{{"الضريبة / Tax", 110}, {"النسبة / Rate", 50}, {"الخاضع / Taxable", 70}, {"القيمة / Amount", 70}} {
+			doc.Text(x+2, top+12, h.label)
+			x += h.width
+		}
+		p.font("regular", 8)
+		for i, t := range order.TaxSummary {
+			y := top + 12 + float64(i+1)*14
+			name := t.Code
+			if t.Name != "" {
+				name = t.Name
+			}
+			doc.Text(pageMargin+2, y, name)
+			doc.TextRight(pageMargin+158, y, fmt.Sprintf("%g%%", t.Rate))
+			doc.TextRight(pageMargin+228, y, formatAmount(t.TaxableAmount))
+			doc.TextRight(pageMargin+298, y, formatAmount(t.TaxAmount))
+		}
+	}
+
+	// Totals box
+	right := doc.Width() - pageMargin
+	boxLeft := right - 190
+	currency := currencyOf(order)
+	for i, t := range totals {
+		y := top + float64(i+1)*16
+		name := "regular"
+		if t.en == "Net Total" || t.en == "Balance Due" {
+			name = p.bold
+			doc.SetFillColor(240, 240, 240)
+			doc.Rect(boxLeft, y-11, 190, 15, true)
+			doc.SetFillColor(0, 0, 0)
+		}
+		p.font(name, 9)
+		doc.Text(boxLeft+4, y, t.ar+" / "+t.en)
+		doc.TextRight(right-4, y, formatAmount(t.value)+" "+currency)
+	}
+
+	y := top + float64(len(totals)+1)*16
+	if currency != money.DefaultCurrency && order.ExchangeRate > 0 {
+		p.font("regular", 8)
+		doc.TextRight(right-4, y, fmt.Sprintf("%s %s @ %g  المعادل / Equivalent", formatAmount(order.BaseNetAmount), money.DefaultCurrency, order.ExchangeRate))
+		y += 14
+	}
+	p.y = y
+}
+
+func drawSignatures(p *docLayout) {
+	doc := p.doc
+	p.ensureSpace(80)
+	y := p.y + 50
+	doc.SetStrokeColor(0, 0, 0)
+	doc.SetLineWidth(0.5)
+	doc.Line(pageMargin, y, pageMargin+180, y)
+	doc.Line(doc.Width()-pageMargin-180, y, doc.Width()-pageMargin, y)
+	p.font("regular", 9)
+	doc.TextCenter(pageMargin+90, y+14, "سلمه / Delivered by")
+	doc.TextCenter(doc.Width()-pageMargin-90, y+14, "استلمه / Received by")
+	p.y = y + 20
+}
+
+// drawFooters writes the page numbers once the page count is known
+func drawFooters(doc *pdf.Document, reference string) {
+	total := doc.PageCount()
+	for i := 1; i <= total; i++ {
+		doc.SetPage(i)
+		if err := doc.SetFont("regular", 8); err != nil {
+			return
+		}
+		doc.SetFillColor(120, 120, 120)
+		doc.TextCenter(doc.Width()/2, doc.Height()-20, fmt.Sprintf("%s  -  %d / %d", reference, i, total))
+	}
+}
+
+func (p *docLayout) font(name string, size float64) {
+	_ = p.doc.SetFont(name, size)
+}
+
+// ensureSpace starts a new page when the next block would run into the footer
+func (p *docLayout) ensureSpace(h float64) {
+	if p.y+h <= p.doc.Height()-pageMargin-footerHeight {
+		return
+	}
+	p.doc.AddPage()
+	p.y = pageMargin
+	if p.header != nil {
+		p.header()
+	}
+}
+
+func (p *docLayout) tableHeader() {
+	doc := p.doc
+	doc.SetFillColor(45, 62, 80)
+	doc.Rect(pageMargin, p.y, doc.Width()-2*pageMargin, 28, true)
+	doc.SetFillColor(255, 255, 255)
+
+	x := pageMargin
+	for _, c := range p.columns {
+		p.font(p.bold, 8)
+		cellText(doc, c, x, p.y+12, c.ar)
+		p.font("regular", 7)
+		cellText(doc, c, x, p.y+23, c.en)
+		x += c.width
+	}
+	doc.SetFillColor(0, 0, 0)
+	p.y += 28
+}
+
+func (p *docLayout) tableRow(values []string) {
+	doc := p.doc
+	p.font("regular", 8.5)
+
+	cells := make([][]string, len(values))
+	lines := 1
+	for i, v := range values {
+		cells[i] = doc.WrapText(v, p.columns[i].width-8)
+		if len(cells[i]) > lines {
+			lines = len(cells[i])
+		}
+	}
+	height := float64(lines)*11 + 6
+
+	p.ensureSpace(height)
+	x := pageMargin
+	for i, c := range p.columns {
+		for j, line := range cells[i] {
+			cellText(doc, c, x, p.y+13+float64(j)*11, line)
+		}
+		x += c.width
+	}
+	doc.SetStrokeColor(220, 220, 220)
+	doc.SetLineWidth(0.5)
+	doc.Line(pageMargin, p.y+height, doc.Width()-pageMargin, p.y+height)
+	p.y += height
+}
+
+// cellText draws a value inside a column, right-aligning numbers and Arabic text
+func cellText(doc *pdf.Document, c docColumn, x, y float64, s string) {
+	if c.right || pdf.ContainsRTL(s) {
+		doc.TextRight(x+c.width-4, y, s)
+		return
+	}
+	doc.Text(x+4, y, s)
+}
+
+func labelled(ar, en, value string) string {
+	if value == "" {
+		return ""
+	}
+	return ar + " / " + en + ": " + value
+}
+
+func itemName(item domain.SalesOrderItem) string {
+	if item.Product != nil {
+		return item.Product.Name
+	}
+	return fmt.Sprintf("#%d", item.ProductID)
+}
+
+func currencyOf(order *domain.SalesOrder) string {
+	if order.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return order.Currency
+}
+
+// formatAmount formats money with thousands separators, e.g. 12,345.50
+func formatAmount(m money.Money) string {
+	s := m.String()
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s[:len(s)-3], s[len(s)-3:]
+	for i := len(intPart) - 3; i > 0; i -= 3 {
+		intPart = intPart[:i] + "," + intPart[i:]
+	}
+	return sign + intPart + frac
+}
+
+func formatQuantity(q float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", q), "0"), ".")
+}
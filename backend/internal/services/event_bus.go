@@ -0,0 +1,171 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+)
+
+// maxEventAttempts is how often a subscriber may fail an event before it is given up on
+const maxEventAttempts = 6
+
+// EventHandlerFunc handles a stored event; an error has the event retried for this
+// subscriber later
+type EventHandlerFunc func(event *domain.DomainEvent) error
+
+type eventSubscriber struct {
+	name      string
+	eventType string // Empty for every event
+	handle    EventHandlerFunc
+}
+
+// EventBus hands the events stored by repositories to in-process subscribers once the
+// writes that raised them have committed. Each subscriber sees an event at least once, so
+// handlers should tolerate repeats.
+type EventBus struct {
+	repo        repositories.EventRepository
+	mu          sync.RWMutex
+	subscribers []eventSubscriber
+}
+
+func NewEventBus(repo repositories.EventRepository) *EventBus {
+	return &EventBus{repo: repo}
+}
+
+// SubscribeAll registers a handler for every event. Names identify subscribers in retries
+// and must be unique.
+func (b *EventBus) SubscribeAll(name string, handle EventHandlerFunc) {
+	b.subscribe(eventSubscriber{name: name, handle: handle})
+}
+
+// On registers a handler for one type of event, decoding its payload
+func On[E domain.Event](b *EventBus, name string, handle func(event *domain.DomainEvent, payload E) error) {
+	var zero E
+	b.subscribe(eventSubscriber{
+		name:      name,
+		eventType: zero.EventType(),
+		handle: func(event *domain.DomainEvent) error {
+			var payload E
+			if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+				return fmt.Errorf("decode %s: %w", event.Type, err)
+			}
+			return handle(event, payload)
+		},
+	})
+}
+
+func (b *EventBus) subscribe(s eventSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, existing := range b.subscribers {
+		if existing.name == s.name {
+			panic("event subscriber registered twice: " + s.name)
+		}
+	}
+	b.subscribers = append(b.subscribers, s)
+}
+
+// ProcessDue delivers up to limit events that are due and returns how many were handled
+func (b *EventBus) ProcessDue(limit int) (int, error) {
+	events, err := b.repo.FindDue(time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	for i := range events {
+		if err := b.Deliver(&events[i]); err != nil {
+			log.Printf("⚠️ Failed to record handling of event #%d: %v", events[i].ID, err)
+		}
+	}
+	return len(events), nil
+}
+
+// Deliver hands an event to its subscribers, or after a failure to those that failed, and
+// records the outcome. Failing subscribers are retried with exponential backoff until the
+// event runs out of attempts; the returned error is only about saving the outcome.
+func (b *EventBus) Deliver(event *domain.DomainEvent) error {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	retrying := make(map[string]bool, len(event.Pending))
+	for _, name := range event.Pending {
+		retrying[name] = true
+	}
+
+	var failed, errs []string
+	for _, s := range subscribers {
+		if s.eventType != "" && s.eventType != event.Type {
+			continue
+		}
+		if event.Attempts > 0 && !retrying[s.name] {
+			continue
+		}
+		if err := safeHandle(s.handle, event); err != nil {
+			failed = append(failed, s.name)
+			errs = append(errs, s.name+": "+err.Error())
+		}
+	}
+
+	now := time.Now()
+	event.Attempts++
+	event.Pending = failed
+	event.LastError = strings.Join(errs, "; ")
+	switch {
+	case len(failed) == 0:
+		event.Status = domain.EventStatusProcessed
+		event.ProcessedAt = &now
+	case event.Attempts >= maxEventAttempts:
+		event.Status = domain.EventStatusFailed
+	default:
+		event.Status = domain.EventStatusPending
+		event.NextAttemptAt = now.Add(eventRetryDelay(event.Attempts))
+	}
+	return b.repo.Update(event)
+}
+
+// Retry schedules the subscribers that gave up on an event for one more attempt
+func (b *EventBus) Retry(id uint) (*domain.DomainEvent, error) {
+	event, err := b.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	if event.Status != domain.EventStatusFailed {
+		return nil, errors.New("only failed events can be retried")
+	}
+	event.Status = domain.EventStatusPending
+	event.NextAttemptAt = time.Now()
+	event.Attempts = maxEventAttempts - 1
+	if err := b.repo.Update(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// safeHandle keeps a panicking subscriber from taking the worker down
+func safeHandle(handle EventHandlerFunc, event *domain.DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handle(event)
+}
+
+// eventRetryDelay waits 30s after the first failure, doubling up to an hour
+func eventRetryDelay(attempt int) time.Duration {
+	delay := 30 * time.Second
+	for i := 1; i < attempt && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/messaging"
+)
+
+// SubscribeNotifications tells users about the events they subscribed to, through the
+// dispatcher. The event ID keys the email and WhatsApp copies, so redelivered events
+// don't queue them twice.
+func SubscribeNotifications(bus *EventBus, dispatcher *NotificationDispatcher) {
+	On(bus, "notifications:order_created", func(event *domain.DomainEvent, e domain.OrderCreated) error {
+		dispatcher.Dispatch(domain.NotificationEvent{
+			Type:     domain.EventOrderCreated,
+			Title:    "طلب مبيعات جديد: " + e.OrderNumber,
+			Message:  fmt.Sprintf("%s: %s %s", e.CustomerName, e.NetAmount, e.Currency),
+			Link:     fmt.Sprintf("/sales/%d", e.OrderID),
+			BranchID: e.BranchID,
+			Actor:    e.CreatedBy,
+			Key:      fmt.Sprint(event.ID),
+		})
+		return nil
+	})
+	On(bus, "notifications:low_stock", func(event *domain.DomainEvent, e domain.StockBelowReorder) error {
+		dispatcher.Dispatch(domain.NotificationEvent{
+			Type:      domain.EventLowStock,
+			Title:     "انخفاض المخزون: " + e.Name,
+			Message:   fmt.Sprintf("%s: %d left, reorder level %d", e.SKU, e.StockQuantity, e.ReorderLevel),
+			Link:      "/inventory",
+			NotifType: "warning",
+			Key:       fmt.Sprint(event.ID),
+		})
+		return nil
+	})
+	On(bus, "notifications:production_completed", func(event *domain.DomainEvent, e domain.ProductionCompleted) error {
+		dispatcher.Dispatch(domain.NotificationEvent{
+			Type:      domain.EventProductionCompleted,
+			Title:     "اكتمل أمر الإنتاج: " + e.OrderNumber,
+			Message:   fmt.Sprintf("%s × %g", e.ProductName, e.Quantity),
+			Link:      "/production",
+			NotifType: "success",
+			Audience:  []uint{e.CreatedBy},
+			Key:       fmt.Sprint(event.ID),
+		})
+		return nil
+	})
+}
+
+// SubscribeAudit records every event in the audit log, next to the requests that caused them
+func SubscribeAudit(bus *EventBus, auditRepo repositories.AuditRepository) {
+	bus.SubscribeAll("audit", func(event *domain.DomainEvent) error {
+		return auditRepo.Create(&domain.AuditLog{
+			UserID:    event.ActorID,
+			Action:    "EVENT",
+			Resource:  event.Type,
+			Method:    "EVENT",
+			RequestID: fmt.Sprintf("event-%d", event.ID),
+			Details:   event.Payload,
+			CreatedAt: event.CreatedAt,
+		})
+	})
+}
+
+// EventWebhook posts every event to the URL in the event webhook setting, signed like
+// inbound WhatsApp webhooks when a secret is set. Nothing is sent while the URL is empty.
+// Only http(s) URLs are posted to, and unless the server allows it, only public addresses,
+// so the setting can't be used to reach services inside the network.
+type EventWebhook struct {
+	settingsRepo repositories.SettingsRepository
+	client       *http.Client
+}
+
+// NewEventWebhook returns the webhook subscriber; allowPrivate lets it post to loopback and
+// private network addresses, for webhooks served next to the ERP
+func NewEventWebhook(sr repositories.SettingsRepository, allowPrivate bool) *EventWebhook {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = publicAddressOnly
+	}
+	transport := &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second}
+	return &EventWebhook{settingsRepo: sr, client: &http.Client{Timeout: 10 * time.Second, Transport: transport}}
+}
+
+// Subscribe registers the webhook on the bus
+func (w *EventWebhook) Subscribe(bus *EventBus) {
+	bus.SubscribeAll("webhook", w.Post)
+}
+
+// Post sends one event; any response other than 2xx is an error so the event is retried
+func (w *EventWebhook) Post(event *domain.DomainEvent) error {
+	raw := w.setting(domain.SettingEventWebhookURL)
+	if raw == "" {
+		return nil
+	}
+	target, err := checkWebhookURL(raw)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         event.ID,
+		"type":       event.Type,
+		"actor_id":   event.ActorID,
+		"created_at": event.CreatedAt,
+		"data":       json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", fmt.Sprint(event.ID))
+	req.Header.Set("X-Event-Type", event.Type)
+	if secret := w.setting(domain.SettingEventWebhookSecret); secret != "" {
+		req.Header.Set(messaging.SignatureHeader, messaging.Sign(secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *EventWebhook) setting(key string) string {
+	setting, err := w.settingsRepo.Get(key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(setting.Value)
+}
+
+// checkWebhookURL accepts absolute http(s) URLs with a host and no credentials
+func checkWebhookURL(raw string) (*url.URL, error) {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("webhook URL must use http or https, not %q", target.Scheme)
+	}
+	if target.Hostname() == "" || target.User != nil {
+		return nil, fmt.Errorf("webhook URL %q needs a host and no credentials", raw)
+	}
+	return target, nil
+}
+
+// publicAddressOnly refuses connections to loopback, private, link-local and other
+// non-public addresses. It runs on the resolved address of every connection, redirects
+// included, so host names can't be pointed inside the network either.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+)
+
+// MessageQueue queues email and WhatsApp messages for delivery; the outbox implements it
+type MessageQueue interface {
+	Queue(msg *domain.OutboundMessage) (*domain.OutboundMessage, error)
+}
+
+// NotificationDispatcher fans events out to the users subscribed to them, on the channels
+// each user chose. A nil dispatcher drops events, so it is optional wherever it is used.
+type NotificationDispatcher struct {
+	subRepo   repositories.SubscriptionRepository
+	userRepo  repositories.UserRepository
+	notifRepo repositories.NotificationRepository
+	queue     MessageQueue
+}
+
+// NewNotificationDispatcher creates a dispatcher. Without a queue, email and WhatsApp
+// deliveries are skipped.
+func NewNotificationDispatcher(sr repositories.SubscriptionRepository, ur repositories.UserRepository, nr repositories.NotificationRepository, queue MessageQueue) *NotificationDispatcher {
+	return &NotificationDispatcher{subRepo: sr, userRepo: ur, notifRepo: nr, queue: queue}
+}
+
+// Dispatch delivers an event to its audience, unless they turned the event off, and to
+// every other user who subscribed to it. It returns how many deliveries were made; failures
+// are logged since the event itself has already happened.
+func (d *NotificationDispatcher) Dispatch(event domain.NotificationEvent) int {
+	if d == nil {
+		return 0
+	}
+	eventType, ok := domain.FindNotificationEventType(event.Type)
+	if !ok {
+		log.Printf("⚠️ Unknown notification event %q", event.Type)
+		return 0
+	}
+
+	subscriptions, err := d.subRepo.FindByEvent(event.Type)
+	if err != nil {
+		log.Printf("⚠️ Failed to load subscriptions for %s: %v", event.Type, err)
+		return 0
+	}
+	byUser := make(map[uint]domain.NotificationSubscription, len(subscriptions))
+	for _, s := range subscriptions {
+		byUser[s.UserID] = s
+	}
+
+	// The audience first, then subscribers, each user once
+	type recipient struct {
+		userID   uint
+		audience bool
+	}
+	var recipients []recipient
+	seen := make(map[uint]bool)
+	for _, id := range event.Audience {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			recipients = append(recipients, recipient{id, true})
+		}
+	}
+	if !eventType.AudienceOnly {
+		for _, s := range subscriptions {
+			if s.Enabled && !seen[s.UserID] {
+				seen[s.UserID] = true
+				recipients = append(recipients, recipient{s.UserID, false})
+			}
+		}
+	}
+
+	delivered := 0
+	for _, r := range recipients {
+		if r.userID == event.Actor {
+			continue
+		}
+		channels := []string{domain.DeliveryInApp}
+		if s, ok := byUser[r.userID]; ok {
+			if !s.Enabled {
+				continue
+			}
+			if len(s.Channels) > 0 {
+				channels = s.Channels
+			}
+		}
+
+		user, err := d.userRepo.FindByID(r.userID)
+		if err != nil || !user.IsActive || user.DeletedAt != nil {
+			continue
+		}
+		if !r.audience && event.BranchID != nil && user.BranchID != nil && *user.BranchID != *event.BranchID {
+			continue
+		}
+		pref, _ := d.subRepo.FindPreference(user.ID)
+		for _, channel := range channels {
+			if err := d.deliver(event, user, pref, channel); err != nil {
+				log.Printf("⚠️ Failed to deliver %s to user #%d by %s: %v", event.Type, user.ID, channel, err)
+				continue
+			}
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// deliver sends an event to a user on one channel; email and WhatsApp are held until the
+// user's quiet hours end
+func (d *NotificationDispatcher) deliver(event domain.NotificationEvent, user *domain.User, pref *domain.NotificationPreference, channel string) error {
+	notifType := event.NotifType
+	if notifType == "" {
+		notifType = "info"
+	}
+	if channel == domain.DeliveryInApp {
+		return d.notifRepo.Create(&domain.Notification{
+			UserID:  user.ID,
+			Title:   event.Title,
+			Message: event.Message,
+			Type:    notifType,
+			Link:    event.Link,
+		})
+	}
+
+	if d.queue == nil {
+		return fmt.Errorf("no message queue for %s", channel)
+	}
+	to := user.Email
+	if channel == domain.ChannelWhatsApp {
+		if pref == nil || pref.WhatsAppNumber == "" {
+			return fmt.Errorf("no WhatsApp number")
+		}
+		to = pref.WhatsAppNumber
+	}
+	msg := &domain.OutboundMessage{
+		Channel:       channel,
+		Recipient:     to,
+		Subject:       event.Title,
+		Body:          event.Message,
+		Source:        domain.MessageSourceEvent,
+		NextAttemptAt: pref.QuietUntil(time.Now()),
+	}
+	if channel == domain.ChannelWhatsApp && event.Message != "" {
+		msg.Body = event.Title + "\n" + event.Message
+	}
+	if event.Key != "" {
+		key := fmt.Sprintf("event:%s:%s:%d:%s", event.Type, event.Key, user.ID, channel)
+		msg.IdempotencyKey = &key
+	}
+	_, err := d.queue.Queue(msg)
+	return err
+}
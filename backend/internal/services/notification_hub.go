@@ -0,0 +1,86 @@
+package services
+
+import (
+	"sync"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+)
+
+// subscriberBuffer is how many notifications a slow subscriber can fall behind before new
+// ones are dropped for it; clients catch up from the unread list when they reconnect
+const subscriberBuffer = 16
+
+// NotificationHub fans new notifications out to the streams of the users they are for
+type NotificationHub struct {
+	mu          sync.Mutex
+	subscribers map[uint]map[chan domain.Notification]struct{}
+}
+
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{subscribers: make(map[uint]map[chan domain.Notification]struct{})}
+}
+
+// Subscribe returns a channel of the user's new notifications and a function that
+// unsubscribes and closes it
+func (h *NotificationHub) Subscribe(userID uint) (<-chan domain.Notification, func()) {
+	ch := make(chan domain.Notification, subscriberBuffer)
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan domain.Notification]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends a notification to every stream its user has open without blocking
+func (h *NotificationHub) Publish(n domain.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
+
+// Subscribers counts the open streams of a user
+func (h *NotificationHub) Subscribers(userID uint) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers[userID])
+}
+
+// Publishing wraps a notification repository so every notification it creates is
+// published once the insert has committed. The repository writes outside any transaction,
+// so a notification is never pushed for work that is later rolled back.
+func (h *NotificationHub) Publishing(repo repositories.NotificationRepository) repositories.NotificationRepository {
+	return &publishingNotificationRepository{NotificationRepository: repo, hub: h}
+}
+
+type publishingNotificationRepository struct {
+	repositories.NotificationRepository
+	hub *NotificationHub
+}
+
+func (r *publishingNotificationRepository) Create(notification *domain.Notification) error {
+	if err := r.NotificationRepository.Create(notification); err != nil {
+		return err
+	}
+	r.hub.Publish(*notification)
+	return nil
+}
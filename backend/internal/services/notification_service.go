@@ -1,16 +1,24 @@
 package services
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
-	"net/http"
-	"time"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"erp-system/internal/domain"
 	"erp-system/internal/repositories"
+	"erp-system/pkg/messaging"
 )
 
+// ErrNoChannel is returned when none of the configured channels can reach a customer
+var ErrNoChannel = errors.New("no messaging channel available for this customer")
+
+// defaultChannelOrder is tried when a customer has no preference, or can't be reached on it
+var defaultChannelOrder = []string{domain.ChannelWhatsApp, domain.ChannelSMS, domain.ChannelEmail}
+
+// NotificationService sends messages to customers over the channels configured in settings.
+// Channels are built from settings on every send, so changes apply without a restart.
 type NotificationService struct {
 	settingsRepo repositories.SettingsRepository
 }
@@ -19,53 +27,134 @@ func NewNotificationService(sr repositories.SettingsRepository) *NotificationSer
 	return &NotificationService{settingsRepo: sr}
 }
 
-// SendWhatsApp sends a message using the configured API
-func (s *NotificationService) SendWhatsApp(to string, message string) error {
-	// 1. Get Settings
-	urlSetting, err := s.settingsRepo.Get(domain.SettingWhatsAppURL)
+func (s *NotificationService) setting(key string) string {
+	setting, err := s.settingsRepo.Get(key)
 	if err != nil {
-		return errors.New("WhatsApp API URL not configured")
-	}
-	tokenSetting, err := s.settingsRepo.Get(domain.SettingWhatsAppToken)
-	if err != nil {
-		return errors.New("WhatsApp API Token not configured")
+		return ""
 	}
+	return strings.TrimSpace(setting.Value)
+}
 
-	apiURL := urlSetting.Value
-	apiToken := tokenSetting.Value
+// Channel returns the named channel, or nil when it isn't configured
+func (s *NotificationService) Channel(name string) messaging.Channel {
+	switch name {
+	case domain.ChannelWhatsApp:
+		token := s.setting(domain.SettingWhatsAppToken)
+		if s.setting(domain.SettingWhatsAppProvider) == "cloud" {
+			phoneNumberID := s.setting(domain.SettingWhatsAppPhoneNumberID)
+			if phoneNumberID == "" || token == "" {
+				return nil
+			}
+			return &messaging.WhatsAppCloud{
+				Endpoint:      s.setting(domain.SettingWhatsAppCloudURL),
+				PhoneNumberID: phoneNumberID,
+				Token:         token,
+			}
+		}
+		url := s.setting(domain.SettingWhatsAppURL)
+		if url == "" || token == "" {
+			return nil
+		}
+		return &messaging.Webhook{ChannelName: domain.ChannelWhatsApp, URL: url, Token: token}
 
-	if apiURL == "" || apiToken == "" {
-		return errors.New("WhatsApp configuration incomplete")
-	}
+	case domain.ChannelSMS:
+		url := s.setting(domain.SettingSMSURL)
+		if url == "" {
+			return nil
+		}
+		return &messaging.SMS{URL: url, Token: s.setting(domain.SettingSMSToken), Sender: s.setting(domain.SettingSMSSender)}
 
-	// 2. Prepare Payload (Adjust structure based on Provider, using a generic one for now)
-	// Most providers use: { "phone": "...", "message": "..." } or similar
-	payload := map[string]string{
-		"phone":   to,
-		"message": message,
+	case domain.ChannelEmail:
+		host, from := s.setting(domain.SettingSMTPHost), s.setting(domain.SettingSMTPFrom)
+		if host == "" || from == "" {
+			return nil
+		}
+		port, _ := strconv.Atoi(s.setting(domain.SettingSMTPPort))
+		return &messaging.SMTP{
+			Host:     host,
+			Port:     port,
+			Username: s.setting(domain.SettingSMTPUsername),
+			Password: s.setting(domain.SettingSMTPPassword),
+			From:     from,
+		}
 	}
-	jsonData, _ := json.Marshal(payload)
+	return nil
+}
 
-	// 3. Create Request
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
+// Send delivers a message over the named channel
+func (s *NotificationService) Send(channel string, msg messaging.Message) error {
+	ch := s.Channel(channel)
+	if ch == nil {
+		return fmt.Errorf("%s channel not configured", channel)
 	}
+	return ch.Send(msg)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiToken)
+// SendWhatsApp sends a message using the configured WhatsApp provider
+func (s *NotificationService) SendWhatsApp(to string, message string) error {
+	return s.Send(domain.ChannelWhatsApp, messaging.Message{To: to, Body: message})
+}
+
+// CustomerChannel picks the channel a customer is messaged on: their preferred channel, or
+// the first channel in the configured order that is set up and has an address for them
+func (s *NotificationService) CustomerChannel(customer *domain.Customer) (channel, to string, err error) {
+	for _, name := range s.channelOrder(customer.PreferredChannel) {
+		if to := CustomerAddress(customer, name); to != "" && s.Channel(name) != nil {
+			return name, to, nil
+		}
+	}
+	return "", "", ErrNoChannel
+}
 
-	// 4. Send
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+// SendToCustomer sends a message on the channel CustomerChannel picks and returns that
+// channel; a failed send is not retried on another channel
+func (s *NotificationService) SendToCustomer(customer *domain.Customer, subject, body string) (string, error) {
+	channel, to, err := s.CustomerChannel(customer)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer resp.Body.Close()
+	return channel, s.Send(channel, messaging.Message{To: to, Subject: subject, Body: body})
+}
 
-	if resp.StatusCode >= 400 {
-		return errors.New("WhatsApp API returned error status: " + resp.Status)
+// channelOrder lists the channels to try, the preferred one first
+func (s *NotificationService) channelOrder(preferred string) []string {
+	order := defaultChannelOrder
+	if value := s.setting(domain.SettingChannelOrder); value != "" {
+		order = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				order = append(order, name)
+			}
+		}
+	}
+	if preferred == "" {
+		return order
 	}
+	result := []string{preferred}
+	for _, name := range order {
+		if name != preferred {
+			result = append(result, name)
+		}
+	}
+	return result
+}
 
-	return nil
+// CustomerAddress returns where a customer is reached on a channel, or "" when they can't be
+func CustomerAddress(customer *domain.Customer, channel string) string {
+	phone := customer.Mobile
+	if phone == "" {
+		phone = customer.Phone
+	}
+	switch channel {
+	case domain.ChannelWhatsApp:
+		if !customer.IsWhatsAppEnabled {
+			return ""
+		}
+		return phone
+	case domain.ChannelSMS:
+		return phone
+	case domain.ChannelEmail:
+		return customer.Email
+	}
+	return ""
 }
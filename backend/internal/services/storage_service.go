@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/storage"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDocumentMaxSizeMB = 10
+	maxLogoSize              = 2 << 20
+
+	// LocalFilesPrefix is the URL path that serves signed links to files on the local disk
+	LocalFilesPrefix = "/api/v1/files"
+)
+
+// Upload rejections
+var (
+	ErrFileEmpty    = errors.New("file is empty")
+	ErrFileTooLarge = errors.New("file is too large")
+	ErrFileType     = errors.New("file type is not allowed")
+)
+
+// sniffedExtensions lists the extensions accepted for each sniffed content type, so a file
+// can't claim to be something its content is not
+var sniffedExtensions = map[string][]string{
+	"application/pdf": {".pdf"},
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"text/plain":      {".txt", ".csv", ".svg"},
+	"text/xml":        {".svg"},
+	"application/zip": {".zip", ".docx", ".xlsx", ".pptx"},
+}
+
+// extensionTypes refines a sniffed type by extension: Office files are zip archives and CSV
+// is plain text, SVG is text or XML
+var extensionTypes = map[string]string{
+	".svg":  "image/svg+xml",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".csv":  "text/csv",
+}
+
+var fileKinds = map[string]string{
+	"application/pdf": "pdf",
+	"image/jpeg":      "image",
+	"image/png":       "image",
+	"image/gif":       "image",
+	"image/webp":      "image",
+	"image/svg+xml":   "image",
+	"text/plain":      "text",
+	"text/csv":        "spreadsheet",
+	"application/zip": "archive",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   "document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         "spreadsheet",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "document",
+}
+
+// defaultDocumentTypes are accepted when the allowed types setting is empty
+var defaultDocumentTypes = []string{
+	"application/pdf", "image/jpeg", "image/png", "image/gif", "image/webp", "text/plain", "text/csv",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// logoTypes are the image types accepted for the company logo
+var logoTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "image/svg+xml"}
+
+// FileInfo describes an upload that passed the checks
+type FileInfo struct {
+	ContentType string
+	Extension   string
+	Kind        string
+	Size        int64
+	Checksum    string // SHA-256, hex
+}
+
+// StorageService checks uploaded files and keeps them in the storage backend under random
+// names. Files are handed out through signed, expiring links.
+type StorageService struct {
+	settingsRepo repositories.SettingsRepository
+	backend      storage.Backend
+}
+
+func NewStorageService(sr repositories.SettingsRepository, backend storage.Backend) *StorageService {
+	return &StorageService{settingsRepo: sr, backend: backend}
+}
+
+// OpenStorageBackend builds the backend selected by the storage settings: the local disk
+// under localRoot (the default) or an S3-compatible bucket. Settings are read once, so a
+// change takes effect on restart. The key that signs local links is generated on first use.
+func OpenStorageBackend(sr repositories.SettingsRepository, localRoot string) (storage.Backend, error) {
+	setting := func(key string) string {
+		if s, err := sr.Get(key); err == nil {
+			return strings.TrimSpace(s.Value)
+		}
+		return ""
+	}
+
+	switch setting(domain.SettingStorageBackend) {
+	case "", "local":
+		secret := setting(domain.SettingStorageSigningKey)
+		if secret == "" {
+			random := make([]byte, 32)
+			if _, err := rand.Read(random); err != nil {
+				return nil, err
+			}
+			secret = hex.EncodeToString(random)
+			if err := sr.Set(domain.SettingStorageSigningKey, secret, "storage"); err != nil {
+				return nil, err
+			}
+		}
+		return storage.NewLocal(localRoot, LocalFilesPrefix, []byte(secret)), nil
+	case "s3":
+		pathStyle, _ := strconv.ParseBool(setting(domain.SettingS3PathStyle))
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  setting(domain.SettingS3Endpoint),
+			Region:    setting(domain.SettingS3Region),
+			Bucket:    setting(domain.SettingS3Bucket),
+			AccessKey: setting(domain.SettingS3AccessKey),
+			SecretKey: setting(domain.SettingS3SecretKey),
+			PathStyle: pathStyle,
+			Client:    &http.Client{Timeout: 5 * time.Minute},
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q, use local or s3", setting(domain.SettingStorageBackend))
+	}
+}
+
+// MaxSize is the largest accepted document upload in bytes
+func (s *StorageService) MaxSize() int64 {
+	mb := defaultDocumentMaxSizeMB
+	if s.settingsRepo != nil {
+		if setting, err := s.settingsRepo.Get(domain.SettingDocumentMaxSizeMB); err == nil {
+			if n, err := strconv.Atoi(strings.TrimSpace(setting.Value)); err == nil && n > 0 {
+				mb = n
+			}
+		}
+	}
+	return int64(mb) << 20
+}
+
+func (s *StorageService) allowedTypes() []string {
+	if s.settingsRepo != nil {
+		if setting, err := s.settingsRepo.Get(domain.SettingDocumentAllowedTypes); err == nil && strings.TrimSpace(setting.Value) != "" {
+			var types []string
+			for _, t := range strings.Split(setting.Value, ",") {
+				if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+					types = append(types, t)
+				}
+			}
+			return types
+		}
+	}
+	return defaultDocumentTypes
+}
+
+// Check validates a document upload's size and type. The type is sniffed from the content
+// and must agree with the file extension.
+func (s *StorageService) Check(filename string, data []byte) (*FileInfo, error) {
+	return check(filename, data, s.MaxSize(), s.allowedTypes())
+}
+
+// CheckLogo validates a company logo upload
+func (s *StorageService) CheckLogo(filename string, data []byte) (*FileInfo, error) {
+	return check(filename, data, maxLogoSize, logoTypes)
+}
+
+func check(filename string, data []byte, maxSize int64, allowedTypes []string) (*FileInfo, error) {
+	if len(data) == 0 {
+		return nil, ErrFileEmpty
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: the limit is %d MB", ErrFileTooLarge, maxSize>>20)
+	}
+
+	sniffed, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	ext := strings.ToLower(filepath.Ext(filename))
+	matches := false
+	for _, e := range sniffedExtensions[sniffed] {
+		matches = matches || e == ext
+	}
+	if ext == ".svg" && !bytes.Contains(data, []byte("<svg")) {
+		matches = false
+	}
+	if !matches {
+		return nil, fmt.Errorf("%w: the content of %q is not a supported %s file", ErrFileType, filename, strings.TrimPrefix(ext, "."))
+	}
+
+	contentType := sniffed
+	if t, ok := extensionTypes[ext]; ok {
+		contentType = t
+	}
+	allowed := false
+	for _, t := range allowedTypes {
+		allowed = allowed || t == contentType
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: %s", ErrFileType, contentType)
+	}
+
+	sum := sha256.Sum256(data)
+	return &FileInfo{
+		ContentType: contentType,
+		Extension:   ext,
+		Kind:        fileKinds[contentType],
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Save stores a checked file under a random name in dir and returns its storage key
+func (s *StorageService) Save(dir string, info *FileInfo, data []byte) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(random)
+	// Shard by the first characters to keep directories small
+	key := path.Join(dir, name[:2], name+info.Extension)
+	if err := s.backend.Put(key, bytes.NewReader(data), int64(len(data)), info.ContentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Open opens a stored file for reading; the caller closes it
+func (s *StorageService) Open(key string) (io.ReadCloser, *storage.Object, error) {
+	return s.backend.Get(key)
+}
+
+// Read returns the content of a stored file
+func (s *StorageService) Read(key string) ([]byte, error) {
+	r, _, err := s.backend.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Delete removes a stored file; a file that is already gone is not an error
+func (s *StorageService) Delete(key string) error {
+	return s.backend.Delete(key)
+}
+
+// URL returns a link that downloads a stored file without authentication until it expires
+func (s *StorageService) URL(key string, expiry time.Duration, opts storage.URLOptions) (string, error) {
+	return s.backend.SignedURL(key, expiry, opts)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+)
+
+// TaxLine is a priced line to be taxed, with amounts expressed in the order's pricing mode
+type TaxLine struct {
+	TaxCode  *domain.TaxCode // nil when the manual rate applies
+	Rate     float64         // Used only when TaxCode is nil
+	Gross    money.Money     // Quantity * UnitPrice
+	Discount money.Money     // Total line discount
+}
+
+// TaxLineResult holds the tax-exclusive amounts of a line
+type TaxLineResult struct {
+	Gross    money.Money
+	Discount money.Money
+	Taxable  money.Money
+	Tax      money.Money
+}
+
+// TaxResult is the outcome of taxing a whole document
+type TaxResult struct {
+	Lines    []TaxLineResult
+	Summary  []domain.SalesOrderTax
+	TotalTax money.Money
+}
+
+type TaxService struct {
+	taxRepo      repositories.TaxRepository
+	settingsRepo repositories.SettingsRepository
+}
+
+func NewTaxService(tr repositories.TaxRepository, sr repositories.SettingsRepository) *TaxService {
+	return &TaxService{taxRepo: tr, settingsRepo: sr}
+}
+
+// PricingMode returns the configured pricing mode, exclusive unless set otherwise
+func (s *TaxService) PricingMode() string {
+	if setting, err := s.settingsRepo.Get(domain.SettingTaxPricingMode); err == nil && setting.Value == domain.TaxPricingInclusive {
+		return domain.TaxPricingInclusive
+	}
+	return domain.TaxPricingExclusive
+}
+
+// RoundingRule returns the configured rounding rule, per line unless set otherwise
+func (s *TaxService) RoundingRule() string {
+	if setting, err := s.settingsRepo.Get(domain.SettingTaxRounding); err == nil && setting.Value == domain.TaxRoundingDocument {
+		return domain.TaxRoundingDocument
+	}
+	return domain.TaxRoundingLine
+}
+
+// ResolveTaxCode picks the tax code for a line: explicit line code, then customer, then
+// product. Without one, a manual rate on the line wins over the default code, so lines
+// priced with a tax_rate keep it; nil means the manual rate applies.
+func (s *TaxService) ResolveTaxCode(lineCodeID *uint, manualRate float64, customer *domain.Customer, product *domain.Product) *domain.TaxCode {
+	candidates := []*uint{lineCodeID}
+	if customer != nil {
+		candidates = append(candidates, customer.TaxCodeID)
+	}
+	if product != nil {
+		candidates = append(candidates, product.TaxCodeID)
+	}
+
+	for _, id := range candidates {
+		if id == nil {
+			continue
+		}
+		if code, err := s.taxRepo.FindByID(*id); err == nil && code.IsActive {
+			return code
+		}
+	}
+
+	if manualRate != 0 {
+		return nil
+	}
+	if code, err := s.taxRepo.FindDefault(); err == nil {
+		return code
+	}
+	return nil
+}
+
+// CalculateTaxes taxes a set of lines using the given pricing mode and rounding rule.
+// With line rounding every line's tax is rounded and the summary adds them up; with
+// document rounding the tax is computed once per tax code on the summed amounts.
+func CalculateTaxes(lines []TaxLine, pricingMode, rounding string) *TaxResult {
+	result := &TaxResult{Lines: make([]TaxLineResult, len(lines))}
+
+	var groups []*domain.SalesOrderTax
+	index := map[string]int{}
+	amounts := map[string]money.Money{} // Sum of line amounts in the pricing mode
+
+	for i, line := range lines {
+		rate, key := lineRate(line)
+		result.Lines[i] = taxLine(line.Gross, line.Discount, rate, pricingMode)
+
+		if _, ok := index[key]; !ok {
+			g := &domain.SalesOrderTax{Code: key, Rate: rate}
+			if line.TaxCode != nil {
+				g.TaxCodeID = &line.TaxCode.ID
+				g.Name = line.TaxCode.Name
+				g.Type = line.TaxCode.Type
+			}
+			index[key] = len(groups)
+			groups = append(groups, g)
+		}
+		g := groups[index[key]]
+		g.TaxableAmount = g.TaxableAmount.Add(result.Lines[i].Taxable)
+		g.TaxAmount = g.TaxAmount.Add(result.Lines[i].Tax)
+		amounts[key] = amounts[key].Add(line.Gross.Sub(line.Discount))
+	}
+
+	for _, g := range groups {
+		if rounding == domain.TaxRoundingDocument {
+			doc := taxLine(amounts[g.Code], money.Zero, g.Rate, pricingMode)
+			g.TaxableAmount, g.TaxAmount = doc.Taxable, doc.Tax
+		}
+		result.Summary = append(result.Summary, *g)
+		result.TotalTax = result.TotalTax.Add(g.TaxAmount)
+	}
+
+	return result
+}
+
+func lineRate(line TaxLine) (float64, string) {
+	if line.TaxCode != nil {
+		return line.TaxCode.EffectiveRate(), line.TaxCode.Code
+	}
+	return line.Rate, "MANUAL"
+}
+
+// taxLine computes the tax-exclusive amounts of one line, rounding the tax to the minor unit
+func taxLine(gross, discount money.Money, rate float64, pricingMode string) TaxLineResult {
+	taxable := gross.Sub(discount)
+	if pricingMode != domain.TaxPricingInclusive {
+		return TaxLineResult{Gross: gross, Discount: discount, Taxable: taxable, Tax: taxable.Percent(rate)}
+	}
+
+	// Back the tax out of the inclusive amount so the line still sums to what the customer pays
+	net := taxable.Ratio(100, 100+rate)
+	exclGross := gross.Ratio(100, 100+rate)
+	return TaxLineResult{
+		Gross:    exclGross,
+		Discount: exclGross.Sub(net),
+		Taxable:  net,
+		Tax:      taxable.Sub(net),
+	}
+}
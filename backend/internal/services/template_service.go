@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+)
+
+// ErrUnknownTemplate is returned for template keys the system doesn't send
+var ErrUnknownTemplate = errors.New("unknown message template")
+
+// defaultTemplates are used until a template is customised
+var defaultTemplates = map[string]domain.MessageTemplate{
+	domain.TemplateActivityReminder: {
+		Name:      "تذكير بنشاط",
+		SubjectAr: "تذكير",
+		BodyAr:    "مرحباً {customer_name}،\nتذكير: {description}",
+		SubjectEn: "Reminder",
+		BodyEn:    "Hello {customer_name},\nReminder: {description}",
+	},
+	domain.TemplateActivityAlert: {
+		Name:      "تنبيه للعميل",
+		SubjectAr: "تنبيه",
+		BodyAr:    "مرحباً {customer_name}،\n{description}",
+		SubjectEn: "Notice",
+		BodyEn:    "Hello {customer_name},\n{description}",
+	},
+	domain.TemplateOrderStatus: {
+		Name:      "تغير حالة الطلب",
+		SubjectAr: "تحديث الطلب {order_number}",
+		BodyAr:    "مرحباً {customer_name}،\nحالة طلبكم رقم {order_number} أصبحت: {order_status}.\nموعد التركيب: {installation_date}",
+		SubjectEn: "Order {order_number} update",
+		BodyEn:    "Hello {customer_name},\nYour order {order_number} is now {order_status}.\nInstallation date: {installation_date}",
+	},
+	domain.TemplatePaymentReceipt: {
+		Name:      "إيصال دفع",
+		SubjectAr: "إيصال دفع - {order_number}",
+		BodyAr:    "شكراً {customer_name}،\nتم استلام مبلغ {amount_paid} للطلب رقم {order_number}.\nالمبلغ المتبقي: {amount_due}",
+		SubjectEn: "Payment receipt - {order_number}",
+		BodyEn:    "Thank you {customer_name},\nWe received {amount_paid} for order {order_number}.\nAmount due: {amount_due}",
+	},
+}
+
+// orderStatusLabels name order statuses in messages to customers
+var orderStatusLabels = map[string]map[string]string{
+	domain.LanguageArabic: {
+		domain.OrderStatusDraft:     "قيد التجهيز",
+		domain.OrderStatusConfirmed: "مؤكد",
+		domain.OrderStatusShipped:   "تم الشحن",
+		domain.OrderStatusDelivered: "تم التسليم",
+		domain.OrderStatusCancelled: "ملغي",
+	},
+	domain.LanguageEnglish: {
+		domain.OrderStatusDraft:     "in preparation",
+		domain.OrderStatusConfirmed: "confirmed",
+		domain.OrderStatusShipped:   "shipped",
+		domain.OrderStatusDelivered: "delivered",
+		domain.OrderStatusCancelled: "cancelled",
+	},
+}
+
+// TemplateService renders customer messages from stored templates in the customer's language
+type TemplateService struct {
+	repo         repositories.MessageTemplateRepository
+	settingsRepo repositories.SettingsRepository
+}
+
+func NewTemplateService(repo repositories.MessageTemplateRepository, sr repositories.SettingsRepository) *TemplateService {
+	return &TemplateService{repo: repo, settingsRepo: sr}
+}
+
+// Get returns the template for a key, customised or default
+func (s *TemplateService) Get(key string) (*domain.MessageTemplate, error) {
+	def, ok := defaultTemplates[key]
+	if !ok {
+		return nil, ErrUnknownTemplate
+	}
+	if stored, err := s.repo.FindByKey(key); err == nil {
+		return stored, nil
+	}
+	def.Key = key
+	def.IsDefault = true
+	return &def, nil
+}
+
+// All returns every template the system sends, customised or default, by key
+func (s *TemplateService) All() ([]domain.MessageTemplate, error) {
+	keys := make([]string, 0, len(defaultTemplates))
+	for key := range defaultTemplates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	templates := make([]domain.MessageTemplate, 0, len(keys))
+	for _, key := range keys {
+		template, err := s.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+// Save customises a template
+func (s *TemplateService) Save(template *domain.MessageTemplate) error {
+	if _, ok := defaultTemplates[template.Key]; !ok {
+		return ErrUnknownTemplate
+	}
+	return s.repo.Save(template)
+}
+
+// Reset returns a template to its default text
+func (s *TemplateService) Reset(key string) error {
+	if _, ok := defaultTemplates[key]; !ok {
+		return ErrUnknownTemplate
+	}
+	return s.repo.DeleteByKey(key)
+}
+
+// RenderFor fills in a template for a customer in their language. The customer and company
+// names and, when order is set, the order's details are added to vars.
+func (s *TemplateService) RenderFor(key string, customer *domain.Customer, order *domain.SalesOrder, vars map[string]string) (domain.RenderedMessage, error) {
+	template, err := s.Get(key)
+	if err != nil {
+		return domain.RenderedMessage{}, err
+	}
+	return s.Render(template, CustomerLanguage(customer), customer, order, vars), nil
+}
+
+// Render fills in a template's variant for a language; a missing English variant falls back
+// to Arabic. Values in vars override those taken from the customer, order and settings.
+func (s *TemplateService) Render(template *domain.MessageTemplate, language string, customer *domain.Customer, order *domain.SalesOrder, vars map[string]string) domain.RenderedMessage {
+	subject, body := template.SubjectAr, template.BodyAr
+	if language == domain.LanguageEnglish && strings.TrimSpace(template.BodyEn) != "" {
+		subject, body = template.SubjectEn, template.BodyEn
+	} else {
+		language = domain.LanguageArabic
+	}
+
+	values := map[string]string{}
+	if s.settingsRepo != nil {
+		if setting, err := s.settingsRepo.Get(domain.SettingCompanyName); err == nil {
+			values["company_name"] = setting.Value
+		}
+	}
+	if customer != nil {
+		values["customer_name"] = customer.Name
+	}
+	if order != nil {
+		for k, v := range OrderVars(order, language) {
+			values[k] = v
+		}
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+
+	pairs := make([]string, 0, len(values)*2)
+	for k, v := range values {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	return domain.RenderedMessage{
+		Language: language,
+		Subject:  replacer.Replace(subject),
+		Body:     replacer.Replace(body),
+	}
+}
+
+// CustomerLanguage is the language messages to a customer are written in
+func CustomerLanguage(customer *domain.Customer) string {
+	if customer != nil && customer.Language == domain.LanguageEnglish {
+		return domain.LanguageEnglish
+	}
+	return domain.LanguageArabic
+}
+
+// OrderVars are the template values describing an order
+func OrderVars(order *domain.SalesOrder, language string) map[string]string {
+	status := order.Status
+	if label, ok := orderStatusLabels[language][order.Status]; ok {
+		status = label
+	}
+	installation := "-"
+	if order.DeliveryDate != nil {
+		installation = order.DeliveryDate.Format("2006-01-02")
+	}
+	return map[string]string{
+		"order_number":      order.OrderNumber,
+		"order_status":      status,
+		"installation_date": installation,
+		"order_total":       order.NetAmount.String() + " " + order.Currency,
+		"amount_due":        order.NetAmount.Sub(order.PaidAmount).String() + " " + order.Currency,
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	thumbnailSize        = 320 // Longest side in pixels
+	thumbnailQuality     = 80
+	maxThumbnailPixels   = 40_000_000 // Larger images are not decoded
+	pdfPreviewTimeout    = 20 * time.Second
+	defaultPDFPreviewCmd = "pdftoppm"
+)
+
+// ErrNoPreview is returned for files a preview can't be made of
+var ErrNoPreview = errors.New("no preview available")
+
+// ThumbnailService makes small JPEG previews of images and of the first page of PDFs.
+// PDF pages are rendered by pdftoppm (poppler-utils), or the pdftoppm-compatible command
+// the server was started with. It is never taken from settings, which users can change.
+type ThumbnailService struct {
+	pdfCommand string
+}
+
+func NewThumbnailService(pdfCommand string) *ThumbnailService {
+	pdfCommand = strings.TrimSpace(pdfCommand)
+	if pdfCommand == "" {
+		pdfCommand = defaultPDFPreviewCmd
+	}
+	return &ThumbnailService{pdfCommand: pdfCommand}
+}
+
+// Generate returns a JPEG thumbnail of a file of the given content type
+func (s *ThumbnailService) Generate(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif":
+	case "application/pdf":
+		page, err := s.renderPDF(data)
+		if err != nil {
+			return nil, err
+		}
+		data = page
+	default:
+		return nil, ErrNoPreview
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 || cfg.Width*cfg.Height > maxThumbnailPixels {
+		return nil, ErrNoPreview
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNoPreview
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scaleDown(img, thumbnailSize), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// renderPDF renders the first page of a PDF to PNG
+func (s *ThumbnailService) renderPDF(data []byte) ([]byte, error) {
+	bin, err := exec.LookPath(s.pdfCommand)
+	if err != nil {
+		return nil, ErrNoPreview
+	}
+
+	dir, err := os.MkdirTemp("", "preview-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	input := filepath.Join(dir, "input.pdf")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pdfPreviewTimeout)
+	defer cancel()
+	output := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, bin, "-png", "-f", "1", "-l", "1", "-singlefile", "-scale-to", strconv.Itoa(thumbnailSize*2), input, output)
+	if err := cmd.Run(); err != nil {
+		return nil, ErrNoPreview
+	}
+	page, err := os.ReadFile(output + ".png")
+	if err != nil {
+		return nil, ErrNoPreview
+	}
+	return page, nil
+}
+
+// scaleDown fits an image within max×max pixels by averaging the source pixels under each
+// target pixel, over a white background so transparent images stay readable
+func scaleDown(src image.Image, max int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > max || h > max {
+		if w >= h {
+			w, h = max, h*max/w
+		} else {
+			w, h = w*max/h, max
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	flat := image.NewRGBA(b)
+	draw.Draw(flat, b, image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, b, src, b.Min, draw.Over)
+	if w == b.Dx() && h == b.Dy() {
+		return flat
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			if x1 == x0 {
+				x1++
+			}
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					p := flat.RGBAAt(sx, sy)
+					r += uint32(p.R)
+					g += uint32(p.G)
+					bl += uint32(p.B)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 255})
+		}
+	}
+	return dst
+}
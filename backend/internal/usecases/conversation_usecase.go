@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/messaging"
+	"erp-system/pkg/textmatch"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned for inbound webhooks that aren't signed with the app secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// defaultOptOutKeywords stop WhatsApp messages when a customer replies with one of them
+var defaultOptOutKeywords = []string{"stop", "unsubscribe", "إيقاف", "ايقاف", "إلغاء", "الغاء"}
+
+// conversationLimit caps how many sent messages a conversation shows
+const conversationLimit = 200
+
+type ConversationUseCase struct {
+	customerRepo repositories.CustomerRepository
+	activityRepo repositories.CustomerActivityRepository
+	notifRepo    repositories.NotificationRepository
+	outboxRepo   repositories.OutboundMessageRepository
+	settingsRepo repositories.SettingsRepository
+}
+
+func NewConversationUseCase(cr repositories.CustomerRepository, car repositories.CustomerActivityRepository, nr repositories.NotificationRepository, or repositories.OutboundMessageRepository, sr repositories.SettingsRepository) *ConversationUseCase {
+	return &ConversationUseCase{
+		customerRepo: cr,
+		activityRepo: car,
+		notifRepo:    nr,
+		outboxRepo:   or,
+		settingsRepo: sr,
+	}
+}
+
+func (uc *ConversationUseCase) setting(key string) string {
+	setting, err := uc.settingsRepo.Get(key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(setting.Value)
+}
+
+// VerifySubscription answers the Cloud API's webhook subscription check, returning the
+// challenge when the verify token matches the configured one
+func (uc *ConversationUseCase) VerifySubscription(mode, token, challenge string) (string, error) {
+	expected := uc.setting(domain.SettingWhatsAppVerifyToken)
+	if mode != "subscribe" || expected == "" || token != expected {
+		return "", errors.New("verify token mismatch")
+	}
+	return challenge, nil
+}
+
+// Receive verifies and processes an inbound webhook. The payload is read as a Cloud API
+// webhook when the Cloud provider is configured, and as {"phone", "message", "id"} otherwise.
+func (uc *ConversationUseCase) Receive(body []byte, signature string) (*domain.InboundResult, error) {
+	if !messaging.VerifySignature(uc.setting(domain.SettingWhatsAppAppSecret), body, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	parse := messaging.ParseWebhook
+	if uc.setting(domain.SettingWhatsAppProvider) == "cloud" {
+		parse = messaging.ParseWhatsAppCloud
+	}
+	messages, err := parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.InboundResult{Received: len(messages)}
+	for _, msg := range messages {
+		if err := uc.receiveMessage(msg, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// receiveMessage logs one message on the sender's customer record, applies opt-out keywords
+// and notifies the salesperson
+func (uc *ConversationUseCase) receiveMessage(msg messaging.InboundMessage, result *domain.InboundResult) error {
+	if msg.ID != "" {
+		if _, err := uc.activityRepo.FindByExternalID(msg.ID); err == nil {
+			result.Duplicate++
+			return nil
+		}
+	}
+
+	customer := uc.FindCustomerByPhone(msg.From)
+	if customer == nil {
+		log.Printf("⚠️ WhatsApp message from unknown number %s ignored", msg.From)
+		result.Unmatched++
+		return nil
+	}
+
+	salesperson := uc.salesperson(customer)
+	now := time.Now()
+	activity := &domain.CustomerActivity{
+		CustomerID:  customer.ID,
+		Type:        domain.ActivityTypeWhatsApp,
+		Direction:   domain.DirectionIn,
+		ExternalID:  msg.ID,
+		Description: msg.Body,
+		AssignedTo:  &salesperson,
+		IsCompleted: true,
+		CompletedAt: &now,
+		CreatedAt:   msg.ReceivedAt,
+	}
+	if err := uc.activityRepo.Create(activity); err != nil {
+		return err
+	}
+	result.Logged++
+
+	notif := &domain.Notification{
+		UserID:  salesperson,
+		Title:   "رسالة واتساب من " + customer.Name,
+		Message: msg.Body,
+		Type:    "info",
+		Link:    fmt.Sprintf("/customers/%d", customer.ID),
+	}
+	if uc.isOptOut(msg.Body) && customer.IsWhatsAppEnabled {
+		customer.IsWhatsAppEnabled = false
+		if err := uc.customerRepo.Update(customer); err != nil {
+			return err
+		}
+		result.OptedOut++
+		notif.Title = "إلغاء اشتراك واتساب: " + customer.Name
+		notif.Message = "طلب العميل إيقاف رسائل واتساب"
+		notif.Type = "warning"
+	}
+	if err := uc.notifRepo.Create(notif); err != nil {
+		log.Printf("⚠️ Failed to notify user #%d of a WhatsApp message: %v", salesperson, err)
+	}
+	return nil
+}
+
+// FindCustomerByPhone returns the customer a phone number belongs to, comparing normalised
+// numbers so country codes and formatting don't matter. When several customers share the
+// number the oldest wins.
+func (uc *ConversationUseCase) FindCustomerByPhone(phone string) *domain.Customer {
+	normalized := textmatch.NormalizePhone(phone)
+	if normalized == "" {
+		return nil
+	}
+	candidates, err := uc.customerRepo.FindByPhoneSuffix(normalized[len(normalized)-4:])
+	if err != nil {
+		return nil
+	}
+	for i := range candidates {
+		c := &candidates[i]
+		if textmatch.NormalizePhone(c.Mobile) == normalized || textmatch.NormalizePhone(c.Phone) == normalized {
+			return c
+		}
+	}
+	return nil
+}
+
+// salesperson picks who hears about a customer's messages: the assignee of their latest
+// assigned activity, else whoever created the customer, else the admin
+func (uc *ConversationUseCase) salesperson(customer *domain.Customer) uint {
+	activities, _ := uc.activityRepo.FindByCustomerID(customer.ID)
+	for _, a := range activities {
+		if a.AssignedTo != nil && *a.AssignedTo != 0 {
+			return *a.AssignedTo
+		}
+	}
+	if customer.CreatedBy != 0 {
+		return customer.CreatedBy
+	}
+	return 1
+}
+
+// isOptOut reports whether a message is one of the opt-out keywords, ignoring case and
+// surrounding punctuation
+func (uc *ConversationUseCase) isOptOut(body string) bool {
+	keywords := defaultOptOutKeywords
+	if value := uc.setting(domain.SettingWhatsAppOptOut); value != "" {
+		keywords = strings.Split(value, ",")
+	}
+	text := strings.ToLower(strings.Trim(strings.TrimSpace(body), ".!؟?، "))
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && text == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConversation merges a customer's received WhatsApp messages with those sent to them,
+// oldest first
+func (uc *ConversationUseCase) GetConversation(customerID uint) ([]domain.ConversationEntry, error) {
+	if _, err := uc.customerRepo.FindByID(customerID); err != nil {
+		return nil, errors.New("customer not found")
+	}
+
+	var entries []domain.ConversationEntry
+	activities, err := uc.activityRepo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range activities {
+		if a.Type == domain.ActivityTypeWhatsApp && a.Direction == domain.DirectionIn {
+			entries = append(entries, domain.ConversationEntry{Direction: domain.DirectionIn, Body: a.Description, ActivityID: a.ID, At: a.CreatedAt})
+		}
+	}
+
+	sent, _, err := uc.outboxRepo.FindAll(domain.OutboundMessageFilter{Channel: domain.ChannelWhatsApp, CustomerID: customerID}, 1, conversationLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range sent {
+		at := m.CreatedAt
+		if m.SentAt != nil {
+			at = *m.SentAt
+		}
+		entries = append(entries, domain.ConversationEntry{Direction: domain.DirectionOut, Body: m.Body, Status: m.Status, MessageID: m.ID, At: at})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+	return entries, nil
+}
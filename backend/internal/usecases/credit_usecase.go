@@ -0,0 +1,366 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxOverrideHours caps how long a manager override stays valid
+const maxOverrideHours = 7 * 24
+
+type CreditUseCase struct {
+	creditRepo    repositories.CreditRepository
+	customerRepo  repositories.CustomerRepository
+	salesRepo     repositories.SalesRepository
+	creditService *services.CreditService
+}
+
+func NewCreditUseCase(repo repositories.CreditRepository, custRepo repositories.CustomerRepository, salesRepo repositories.SalesRepository, creditService *services.CreditService) *CreditUseCase {
+	return &CreditUseCase{
+		creditRepo:    repo,
+		customerRepo:  custRepo,
+		salesRepo:     salesRepo,
+		creditService: creditService,
+	}
+}
+
+// CreditEvaluation reports what an overdue scan changed
+type CreditEvaluation struct {
+	HoldsPlaced    int `json:"holds_placed"`
+	HoldsReleased  int `json:"holds_released"`
+	OrdersReleased int `json:"orders_released"`
+}
+
+func (uc *CreditUseCase) GetCreditStatus(customerID uint) (*domain.CreditStatus, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+
+	overdue, overdueAmount, err := uc.creditService.Overdue(customer, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := uc.creditRepo.FindBlockedOrders(customerID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := uc.creditRepo.FindActiveOverrides(customerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.CreditStatus{
+		CustomerID:        customer.ID,
+		CreditLimit:       customer.CreditLimit,
+		Balance:           customer.Balance,
+		Utilisation:       services.Utilisation(customer.Balance, customer.CreditLimit),
+		OnHold:            customer.CreditHold,
+		HoldReason:        customer.CreditHoldReason,
+		HoldAt:            customer.CreditHoldAt,
+		OverdueCount:      len(overdue),
+		OverdueAmount:     overdueAmount,
+		BlockedOrders:     len(blocked),
+		ActiveOverrides:   overrides,
+		PaymentTermsDays:  customer.PaymentTermsDays,
+		WarningPercentage: uc.creditService.WarningPercent(),
+	}
+	if customer.CreditLimit.IsPositive() {
+		status.Available = customer.CreditLimit.Sub(customer.Balance)
+	}
+	return status, nil
+}
+
+func (uc *CreditUseCase) GetCreditHistory(customerID uint, limit int) ([]domain.CreditEvent, error) {
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+	return uc.creditRepo.FindEvents(customerID, limit)
+}
+
+// PlaceHold stops every new order of the customer until the hold is released
+func (uc *CreditUseCase) PlaceHold(customerID uint, reason string, userID uint) (*domain.Customer, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+	if customer.CreditHold {
+		return nil, errors.New("customer is already on credit hold")
+	}
+
+	uc.placeHold(customer, strings.TrimSpace(reason), &userID)
+	return customer, nil
+}
+
+// ReleaseHold lifts a manual or automatic hold and retries the customer's blocked orders
+func (uc *CreditUseCase) ReleaseHold(customerID uint, reason string, userID uint) (*domain.Customer, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+	if !customer.CreditHold {
+		return nil, errors.New("customer is not on credit hold")
+	}
+
+	uc.releaseHold(customer, strings.TrimSpace(reason), &userID)
+	uc.retryBlockedOrders(customer.ID, userID)
+	return uc.customerRepo.FindByID(customerID)
+}
+
+func (uc *CreditUseCase) placeHold(customer *domain.Customer, reason string, userID *uint) {
+	now := time.Now()
+	customer.CreditHold = true
+	customer.CreditHoldReason = reason
+	customer.CreditHoldAt = &now
+	customer.CreditHoldBy = userID
+	_ = uc.customerRepo.Update(customer)
+
+	uc.creditService.Record(customer, &domain.CreditEvent{Type: domain.CreditEventHoldPlaced, Reason: reason, UserID: userID})
+	uc.creditService.NotifyApprovers("إيقاف ائتماني: "+customer.Name, reason, "warning", fmt.Sprintf("/customers/%d", customer.ID))
+}
+
+func (uc *CreditUseCase) releaseHold(customer *domain.Customer, reason string, userID *uint) {
+	customer.CreditHold = false
+	customer.CreditHoldReason = ""
+	customer.CreditHoldAt = nil
+	customer.CreditHoldBy = nil
+	_ = uc.customerRepo.Update(customer)
+
+	uc.creditService.Record(customer, &domain.CreditEvent{Type: domain.CreditEventHoldReleased, Reason: reason, UserID: userID})
+}
+
+// GrantOverride lets the customer's orders through credit control for a limited time
+// and releases the blocked orders it now covers
+func (uc *CreditUseCase) GrantOverride(customerID uint, req *domain.GrantCreditOverrideRequest, userID uint) (*domain.CreditOverride, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("a reason is required for a credit override")
+	}
+	if req.MaxAmount.IsNegative() {
+		return nil, errors.New("override amount cannot be negative")
+	}
+
+	hours := req.Hours
+	if hours == 0 {
+		hours = 24
+	}
+	if hours > maxOverrideHours {
+		return nil, fmt.Errorf("overrides are limited to %d hours", maxOverrideHours)
+	}
+
+	if req.OrderID != nil {
+		order, err := uc.salesRepo.FindByID(*req.OrderID)
+		if err != nil || order.CustomerID != customerID {
+			return nil, errors.New("order not found for this customer")
+		}
+	}
+
+	override := &domain.CreditOverride{
+		CustomerID: customerID,
+		OrderID:    req.OrderID,
+		MaxAmount:  req.MaxAmount,
+		Reason:     strings.TrimSpace(req.Reason),
+		ExpiresAt:  time.Now().Add(time.Duration(hours) * time.Hour),
+		GrantedBy:  userID,
+	}
+	if err := uc.creditRepo.CreateOverride(override); err != nil {
+		return nil, err
+	}
+	uc.creditService.Record(customer, &domain.CreditEvent{
+		Type:       domain.CreditEventOverrideGranted,
+		OrderID:    req.OrderID,
+		OverrideID: &override.ID,
+		Amount:     req.MaxAmount,
+		Reason:     override.Reason,
+		UserID:     &userID,
+	})
+
+	uc.retryBlockedOrders(customerID, userID)
+	return override, nil
+}
+
+func (uc *CreditUseCase) RevokeOverride(id uint, userID uint) (*domain.CreditOverride, error) {
+	override, err := uc.creditRepo.FindOverrideByID(id)
+	if err != nil {
+		return nil, errors.New("override not found")
+	}
+	if override.RevokedAt != nil {
+		return nil, errors.New("override is already revoked")
+	}
+
+	now := time.Now()
+	override.RevokedAt = &now
+	if err := uc.creditRepo.UpdateOverride(override); err != nil {
+		return nil, err
+	}
+	if customer, err := uc.customerRepo.FindByID(override.CustomerID); err == nil {
+		uc.creditService.Record(customer, &domain.CreditEvent{Type: domain.CreditEventOverrideRevoked, OverrideID: &override.ID, UserID: &userID})
+	}
+	return override, nil
+}
+
+func (uc *CreditUseCase) GetBlockedOrders(customerID uint) ([]domain.SalesOrder, error) {
+	return uc.creditRepo.FindBlockedOrders(customerID)
+}
+
+// ReleaseOrder books a blocked order once it passes credit control, e.g. after a payment or an override
+func (uc *CreditUseCase) ReleaseOrder(orderID uint, reason string, userID uint) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != domain.OrderStatusCreditHold {
+		return nil, errors.New("order is not on credit hold")
+	}
+	customer, err := uc.customerRepo.FindByID(order.CustomerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+
+	if err := uc.releaseOrder(order, customer, reason, userID); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (uc *CreditUseCase) releaseOrder(order *domain.SalesOrder, customer *domain.Customer, reason string, userID uint) error {
+	decision := uc.creditService.Check(customer, order.BaseNetAmount, order.ID)
+	if decision.Blocked {
+		return errors.New(decision.Reason)
+	}
+
+	if reason = strings.TrimSpace(reason); reason == "" {
+		reason = "credit check passed"
+		if decision.Override != nil {
+			reason = "override: " + decision.Override.Reason
+		}
+	}
+	order.Status = domain.OrderStatusDraft
+	order.CreditNote = reason
+	if err := uc.salesRepo.Update(order,
+		statusChanged(order, domain.OrderStatusCreditHold, userID),
+		balanceChanged(order, order.BaseNetAmount, userID),
+	); err != nil {
+		return err
+	}
+
+	previousBalance := customer.Balance
+	customer.Balance = customer.Balance.Add(order.BaseNetAmount)
+
+	uc.creditService.Record(customer, &domain.CreditEvent{
+		Type:    domain.CreditEventOrderReleased,
+		OrderID: &order.ID,
+		Amount:  order.BaseNetAmount,
+		Reason:  reason,
+		UserID:  &userID,
+	})
+	uc.creditService.Booked(customer, previousBalance, order, decision, userID)
+	return nil
+}
+
+// RejectOrder cancels a blocked order without touching the customer balance
+func (uc *CreditUseCase) RejectOrder(orderID uint, reason string, userID uint) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != domain.OrderStatusCreditHold {
+		return nil, errors.New("order is not on credit hold")
+	}
+
+	order.Status = domain.OrderStatusCancelled
+	order.CreditNote = strings.TrimSpace(reason)
+	if err := uc.salesRepo.Update(order, statusChanged(order, domain.OrderStatusCreditHold, userID)); err != nil {
+		return nil, err
+	}
+
+	if customer, err := uc.customerRepo.FindByID(order.CustomerID); err == nil {
+		uc.creditService.Record(customer, &domain.CreditEvent{
+			Type:    domain.CreditEventOrderRejected,
+			OrderID: &order.ID,
+			Amount:  order.BaseNetAmount,
+			Reason:  order.CreditNote,
+			UserID:  &userID,
+		})
+	}
+	return order, nil
+}
+
+// retryBlockedOrders releases the customer's queued orders, oldest first, while they pass
+func (uc *CreditUseCase) retryBlockedOrders(customerID uint, userID uint) int {
+	orders, err := uc.creditRepo.FindBlockedOrders(customerID)
+	if err != nil {
+		return 0
+	}
+
+	released := 0
+	for i := range orders {
+		customer, err := uc.customerRepo.FindByID(orders[i].CustomerID)
+		if err != nil {
+			continue
+		}
+		if uc.releaseOrder(&orders[i], customer, "", userID) == nil {
+			released++
+		}
+	}
+	return released
+}
+
+// EvaluateCredit places automatic holds on customers with overdue invoices, lifts automatic
+// holds that no longer apply and retries the blocked orders queue
+func (uc *CreditUseCase) EvaluateCredit() (*CreditEvaluation, error) {
+	now := time.Now()
+	result := &CreditEvaluation{}
+
+	invoices, err := uc.creditRepo.FindOpenInvoices(0)
+	if err != nil {
+		return nil, err
+	}
+	overdueCustomers := make(map[uint]bool)
+	for _, order := range invoices {
+		if overdueCustomers[order.CustomerID] {
+			continue
+		}
+		if now.After(services.DueDate(&order, &order.Customer).AddDate(0, 0, uc.creditService.GraceDays())) {
+			overdueCustomers[order.CustomerID] = true
+		}
+	}
+
+	for customerID := range overdueCustomers {
+		customer, err := uc.customerRepo.FindByID(customerID)
+		if err != nil || customer.CreditHold {
+			continue
+		}
+		overdue, total, err := uc.creditService.Overdue(customer, now)
+		if err != nil || len(overdue) == 0 {
+			continue
+		}
+		uc.placeHold(customer, fmt.Sprintf("%d overdue invoices totalling %s", len(overdue), total), nil)
+		result.HoldsPlaced++
+	}
+
+	held, err := uc.creditRepo.FindCustomersOnHold()
+	if err != nil {
+		return nil, err
+	}
+	for i := range held {
+		// Manual holds stay until someone releases them
+		if held[i].CreditHoldBy != nil || overdueCustomers[held[i].ID] {
+			continue
+		}
+		uc.releaseHold(&held[i], "overdue invoices settled", nil)
+		result.HoldsReleased++
+	}
+
+	// User zero records the releases as automatic
+	result.OrdersReleased = uc.retryBlockedOrders(0, 0)
+	return result, nil
+}
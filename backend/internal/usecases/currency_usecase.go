@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+	"errors"
+	"time"
+)
+
+type CurrencyUseCase struct {
+	currencyRepo repositories.CurrencyRepository
+}
+
+func NewCurrencyUseCase(repo repositories.CurrencyRepository) *CurrencyUseCase {
+	return &CurrencyUseCase{currencyRepo: repo}
+}
+
+// CurrencyInfo describes a supported currency and its latest rate against the base currency
+type CurrencyInfo struct {
+	Code          string     `json:"code"`
+	IsBase        bool       `json:"is_base"`
+	Rate          float64    `json:"rate"`
+	EffectiveDate *time.Time `json:"effective_date"`
+}
+
+func (uc *CurrencyUseCase) GetCurrencies() ([]CurrencyInfo, error) {
+	latest, err := uc.currencyRepo.FindLatestRates()
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[string]domain.ExchangeRate, len(latest))
+	for _, r := range latest {
+		rates[r.Currency] = r
+	}
+
+	currencies := make([]CurrencyInfo, 0, len(domain.SupportedCurrencies))
+	for _, code := range domain.SupportedCurrencies {
+		info := CurrencyInfo{Code: code, IsBase: code == money.DefaultCurrency}
+		if info.IsBase {
+			info.Rate = 1
+		} else if r, ok := rates[code]; ok {
+			info.Rate = r.Rate
+			info.EffectiveDate = &r.EffectiveDate
+		}
+		currencies = append(currencies, info)
+	}
+	return currencies, nil
+}
+
+// SetExchangeRate records the rate of a currency from the start of the effective date
+func (uc *CurrencyUseCase) SetExchangeRate(req *domain.SetExchangeRateRequest, userID uint) (*domain.ExchangeRate, error) {
+	currency, err := domain.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == money.DefaultCurrency {
+		return nil, errors.New("the base currency always has a rate of 1")
+	}
+
+	d := req.EffectiveDate.UTC()
+	rate := &domain.ExchangeRate{
+		Currency:      currency,
+		Rate:          req.Rate,
+		EffectiveDate: time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC),
+		CreatedBy:     userID,
+	}
+	if err := uc.currencyRepo.SetRate(rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (uc *CurrencyUseCase) GetExchangeRates(currency string) ([]domain.ExchangeRate, error) {
+	return uc.currencyRepo.FindRates(currency)
+}
+
+func (uc *CurrencyUseCase) DeleteExchangeRate(id uint) error {
+	return uc.currencyRepo.DeleteRate(id)
+}
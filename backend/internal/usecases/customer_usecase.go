@@ -1,30 +1,64 @@
 package usecases
 
 import (
+	"encoding/json"
 	"erp-system/internal/domain"
 	"erp-system/internal/repositories"
 	"erp-system/internal/services"
+	"erp-system/pkg/money"
+	"erp-system/pkg/storage"
+	"erp-system/pkg/textmatch"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
 type CustomerUseCase struct {
 	customerRepo repositories.CustomerRepository
 	activityRepo repositories.CustomerActivityRepository
 	docRepo      repositories.CustomerDocumentRepository
-	notifService *services.NotificationService
+	userRepo     repositories.UserRepository
+	storage      *services.StorageService
+	thumbnails   *services.ThumbnailService
+	outbox       *OutboxUseCase
 }
 
+// documentURLExpiry is how long a document link stays valid
+const documentURLExpiry = 15 * time.Minute
+
+// ErrDocumentAccess is returned when a user may not see a customer's documents
+var ErrDocumentAccess = errors.New("you do not have access to this customer's documents")
+
 // NewCustomerUseCase creates a new customer use case
-func NewCustomerUseCase(cr repositories.CustomerRepository, car repositories.CustomerActivityRepository, cdr repositories.CustomerDocumentRepository, ns *services.NotificationService) *CustomerUseCase {
+func NewCustomerUseCase(cr repositories.CustomerRepository, car repositories.CustomerActivityRepository, cdr repositories.CustomerDocumentRepository, ur repositories.UserRepository, storage *services.StorageService, thumbnails *services.ThumbnailService, outbox *OutboxUseCase) *CustomerUseCase {
 	return &CustomerUseCase{
 		customerRepo: cr,
 		activityRepo: car,
 		docRepo:      cdr,
-		notifService: ns,
+		userRepo:     ur,
+		storage:      storage,
+		thumbnails:   thumbnails,
+		outbox:       outbox,
 	}
 }
 
 // CreateCustomer creates a new customer
 func (uc *CustomerUseCase) CreateCustomer(req domain.CreateCustomerRequest, userID uint) (*domain.Customer, error) {
+	if req.CreditLimit.IsNegative() {
+		return nil, errors.New("credit limit cannot be negative")
+	}
+	currency, err := domain.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate code
 	code, _ := uc.customerRepo.GenerateCode()
 
@@ -40,16 +74,24 @@ func (uc *CustomerUseCase) CreateCustomer(req domain.CreateCustomerRequest, user
 		Country:           req.Country,
 		PostalCode:        req.PostalCode,
 		TaxNumber:         req.TaxNumber,
+		TaxCodeID:         req.TaxCodeID,
+		Currency:          currency,
 		CreditLimit:       req.CreditLimit,
 		Type:              req.Type,
 		Status:            "active",
 		IsWhatsAppEnabled: req.IsWhatsAppEnabled, // New Field
+		PreferredChannel:  req.PreferredChannel,
+		Language:          req.Language,
 		CreatedBy:         userID,
 	}
 
 	if customer.Type == "" {
 		customer.Type = "regular"
 	}
+	customer.PaymentTermsDays = req.PaymentTermsDays
+	if customer.PaymentTermsDays == 0 {
+		customer.PaymentTermsDays = 30
+	}
 
 	if err := uc.customerRepo.Create(customer); err != nil {
 		return nil, err
@@ -68,6 +110,11 @@ func (uc *CustomerUseCase) GetAllCustomers(page, limit int, search string) ([]do
 	return uc.customerRepo.FindAll(page, limit, search)
 }
 
+// GetCustomersFiltered lists customers by search text, tag and segment
+func (uc *CustomerUseCase) GetCustomersFiltered(page, limit int, filter domain.CustomerFilter) ([]domain.Customer, int64, error) {
+	return uc.customerRepo.FindAllFiltered(page, limit, filter)
+}
+
 // GetCustomers is an alias for GetAllCustomers for backward compatibility
 func (uc *CustomerUseCase) GetCustomers(page, limit int, search string) ([]domain.Customer, int64, error) {
 	return uc.GetAllCustomers(page, limit, search)
@@ -75,6 +122,10 @@ func (uc *CustomerUseCase) GetCustomers(page, limit int, search string) ([]domai
 
 // UpdateCustomer updates an existing customer
 func (uc *CustomerUseCase) UpdateCustomer(id uint, req domain.UpdateCustomerRequest) error {
+	if req.CreditLimit.IsNegative() {
+		return errors.New("credit limit cannot be negative")
+	}
+
 	existing, err := uc.customerRepo.FindByID(id)
 	if err != nil {
 		return err
@@ -90,10 +141,25 @@ func (uc *CustomerUseCase) UpdateCustomer(id uint, req domain.UpdateCustomerRequ
 	existing.Country = req.Country
 	existing.PostalCode = req.PostalCode
 	existing.TaxNumber = req.TaxNumber
+	existing.TaxCodeID = req.TaxCodeID
+	if req.Currency != "" {
+		currency, err := domain.NormalizeCurrency(req.Currency)
+		if err != nil {
+			return err
+		}
+		existing.Currency = currency
+	}
 	existing.CreditLimit = req.CreditLimit
+	if req.PaymentTermsDays != nil {
+		existing.PaymentTermsDays = *req.PaymentTermsDays
+	}
 	existing.Type = req.Type
 	existing.Status = req.Status
 	existing.IsWhatsAppEnabled = req.IsWhatsAppEnabled // New Field
+	existing.PreferredChannel = req.PreferredChannel
+	if req.Language != "" {
+		existing.Language = req.Language
+	}
 
 	// Update Balance if needed (business logic for balance shouldn't be here usually)
 	// But let's assume balance is managed via transactions
@@ -106,54 +172,603 @@ func (uc *CustomerUseCase) DeleteCustomer(id uint) error {
 	return uc.customerRepo.Delete(id)
 }
 
-// AddActivity adds a note, call, or meeting log
-func (uc *CustomerUseCase) AddActivity(customerID uint, activityType, description string, userID uint) error {
+// AddActivity adds a note, call, or meeting log, assigned to its creator unless another
+// user is given
+func (uc *CustomerUseCase) AddActivity(customerID uint, req *domain.SaveActivityRequest, userID uint) (*domain.CustomerActivity, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil || customer.DeletedAt != nil {
+		return nil, errors.New("customer not found")
+	}
+
 	activity := &domain.CustomerActivity{
-		CustomerID:  customerID,
-		Type:        activityType,
-		Description: description,
-		CreatedBy:   userID,
+		CustomerID: customerID,
+		CreatedBy:  userID,
+		AssignedTo: &userID,
+	}
+	if err := uc.applyActivity(activity, req); err != nil {
+		return nil, err
+	}
+	if err := uc.createActivity(activity); err != nil {
+		return nil, err
+	}
+
+	// Trigger Notification if type is 'alert'
+	if activity.Type == "alert" && uc.outbox != nil {
+		// Queued on the customer's preferred channel; the outbox worker delivers it
+		uc.outbox.NotifyCustomer(customer, domain.TemplateActivityAlert, nil, map[string]string{"description": activity.Description},
+			domain.MessageSourceActivity, fmt.Sprintf("activity:%d", activity.ID), userID)
 	}
 
+	return uc.activityRepo.FindByID(activity.ID)
+}
+
+// createActivity saves a new activity. The notification column defaults to enabled, so a
+// disabled flag is only kept by an update.
+func (uc *CustomerUseCase) createActivity(activity *domain.CustomerActivity) error {
+	enabled := activity.NotificationEnabled
 	if err := uc.activityRepo.Create(activity); err != nil {
 		return err
 	}
+	if !enabled {
+		activity.NotificationEnabled = false
+		return uc.activityRepo.Update(activity)
+	}
+	return nil
+}
 
-	// Trigger Notification if type is 'alert'
-	if activityType == "alert" {
-		// 1. Get Customer to get phone & preferences
-		customer, err := uc.customerRepo.FindByID(customerID)
-		if err == nil && customer.Phone != "" && customer.IsWhatsAppEnabled {
-			// 2. Send WhatsApp (Async to not block)
-			go func() {
-				// TODO: Check System Global Setting here too ideally, but for now PER CUSTOMER control is implemented
-				_ = uc.notifService.SendWhatsApp(customer.Phone, description) // Raw message for now, will template later
-			}()
+// applyActivity copies a request onto an activity, checking the assignee and recurrence
+func (uc *CustomerUseCase) applyActivity(activity *domain.CustomerActivity, req *domain.SaveActivityRequest) error {
+	activityType := strings.TrimSpace(req.Type)
+	if activityType == "" || strings.TrimSpace(req.Description) == "" {
+		return errors.New("type and description are required")
+	}
+	if req.AssignedTo != nil {
+		assignee, err := uc.userRepo.FindByID(*req.AssignedTo)
+		if err != nil || !assignee.IsActive || assignee.DeletedAt != nil {
+			return errors.New("assignee not found")
+		}
+		activity.AssignedTo = req.AssignedTo
+	}
+	if req.Recurrence != nil {
+		recurrence := *req.Recurrence
+		recurrence.Occurrence = 1
+		if activity.Recurrence != nil {
+			recurrence.Occurrence = activity.Recurrence.Occurrence
+		}
+		if err := recurrence.Validate(); err != nil {
+			return err
 		}
+		activity.Recurrence = &recurrence
+	} else {
+		activity.Recurrence = nil
 	}
 
+	// A new reminder date is a new reminder
+	if !sameTime(activity.ReminderDate, req.ReminderDate) {
+		activity.NotifiedAt = nil
+	}
+	activity.Type = activityType
+	activity.Description = req.Description
+	activity.ReminderDate = req.ReminderDate
+	activity.NotificationEnabled = req.NotificationEnabled == nil || *req.NotificationEnabled
+	activity.Assignee = nil
 	return nil
 }
 
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 // GetActivities retrieves all activities for a customer
 func (uc *CustomerUseCase) GetActivities(customerID uint) ([]domain.CustomerActivity, error) {
 	return uc.activityRepo.FindByCustomerID(customerID)
 }
 
-// AddDocument saves a document record
-func (uc *CustomerUseCase) AddDocument(customerID uint, title, path, fileType string) error {
-	doc := &domain.CustomerDocument{
-		CustomerID: customerID,
-		Title:      title,
-		FilePath:   path,
-		FileType:   fileType,
+func (uc *CustomerUseCase) GetActivity(id uint) (*domain.CustomerActivity, error) {
+	activity, err := uc.activityRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("activity not found")
+	}
+	return activity, nil
+}
+
+// ListActivities lists activities across customers
+func (uc *CustomerUseCase) ListActivities(filter domain.ActivityFilter, page, limit int) ([]domain.CustomerActivity, int64, error) {
+	return uc.activityRepo.FindAll(filter, page, limit)
+}
+
+// UpdateActivity replaces an activity's details. Leaving assigned_to out keeps the
+// current assignee.
+func (uc *CustomerUseCase) UpdateActivity(id uint, req *domain.SaveActivityRequest) (*domain.CustomerActivity, error) {
+	activity, err := uc.activityRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("activity not found")
+	}
+	if err := uc.applyActivity(activity, req); err != nil {
+		return nil, err
+	}
+	if err := uc.activityRepo.Update(activity); err != nil {
+		return nil, err
 	}
-	return uc.docRepo.Create(doc)
+	return uc.activityRepo.FindByID(id)
 }
 
-// GetDocuments retrieves all documents for a customer
-func (uc *CustomerUseCase) GetDocuments(customerID uint) ([]domain.CustomerDocument, error) {
-	return uc.docRepo.FindByCustomerID(customerID)
+func (uc *CustomerUseCase) DeleteActivity(id uint) error {
+	if _, err := uc.activityRepo.FindByID(id); err != nil {
+		return errors.New("activity not found")
+	}
+	return uc.activityRepo.Delete(id)
+}
+
+// CompleteActivity marks an activity done, which also stops its reminder. The next
+// occurrence of a recurring activity and the steps of cadences triggered by its type are
+// scheduled and returned as follow-ups.
+func (uc *CustomerUseCase) CompleteActivity(id uint, req *domain.CompleteActivityRequest, userID uint) (*domain.CustomerActivity, error) {
+	activity, err := uc.activityRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("activity not found")
+	}
+	if activity.IsCompleted {
+		return nil, errors.New("activity is already completed")
+	}
+	now := time.Now()
+	activity.IsCompleted = true
+	activity.CompletedAt = &now
+	activity.CompletedBy = &userID
+	activity.Outcome = req.Outcome
+	if err := uc.activityRepo.Update(activity); err != nil {
+		return nil, err
+	}
+
+	if activity.FollowUps, err = uc.scheduleFollowUps(activity, userID); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// scheduleFollowUps creates the activities that follow a completed one. An activity that
+// was reopened and completed again already has them, so nothing more is created.
+func (uc *CustomerUseCase) scheduleFollowUps(activity *domain.CustomerActivity, userID uint) ([]domain.CustomerActivity, error) {
+	existing, err := uc.activityRepo.FindFollowUps(activity.ID)
+	if err != nil || len(existing) > 0 {
+		return existing, err
+	}
+
+	now := *activity.CompletedAt
+	followUp := func(activityType, description string, at time.Time) domain.CustomerActivity {
+		reminder := at
+		return domain.CustomerActivity{
+			CustomerID:          activity.CustomerID,
+			Type:                activityType,
+			Description:         description,
+			ReminderDate:        &reminder,
+			NotificationEnabled: activity.NotificationEnabled,
+			AssignedTo:          activity.AssignedTo,
+			PreviousID:          &activity.ID,
+			CreatedBy:           userID,
+		}
+	}
+
+	var followUps []domain.CustomerActivity
+	if activity.Recurrence != nil {
+		from := now
+		if activity.ReminderDate != nil {
+			from = *activity.ReminderDate
+		}
+		if next, at, ok := activity.Recurrence.Next(from, now); ok {
+			f := followUp(activity.Type, activity.Description, at)
+			f.Recurrence = &next
+			followUps = append(followUps, f)
+		}
+	}
+
+	cadences, err := uc.activityRepo.FindActiveCadences(activity.Type)
+	if err != nil {
+		return nil, err
+	}
+	for _, cadence := range cadences {
+		for _, step := range cadence.Steps {
+			f := followUp(step.Type, step.Description, now.AddDate(0, 0, step.AfterDays))
+			f.CadenceID = &cadence.ID
+			followUps = append(followUps, f)
+		}
+	}
+
+	for i := range followUps {
+		if err := uc.createActivity(&followUps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return followUps, nil
+}
+
+// ReopenActivity undoes a completion
+func (uc *CustomerUseCase) ReopenActivity(id uint) (*domain.CustomerActivity, error) {
+	activity, err := uc.activityRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("activity not found")
+	}
+	if !activity.IsCompleted {
+		return nil, errors.New("activity is not completed")
+	}
+	activity.IsCompleted = false
+	activity.CompletedAt = nil
+	activity.CompletedBy = nil
+	if err := uc.activityRepo.Update(activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// SnoozeActivity moves an open activity's reminder later; it will be delivered again
+func (uc *CustomerUseCase) SnoozeActivity(id uint, req *domain.SnoozeActivityRequest) (*domain.CustomerActivity, error) {
+	activity, err := uc.activityRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("activity not found")
+	}
+	if activity.IsCompleted {
+		return nil, errors.New("completed activities cannot be snoozed")
+	}
+
+	var until time.Time
+	switch {
+	case req.Until != nil:
+		until = *req.Until
+	case req.Minutes > 0:
+		until = time.Now().Add(time.Duration(req.Minutes) * time.Minute)
+	default:
+		return nil, errors.New("until or minutes is required")
+	}
+	if !until.After(time.Now()) {
+		return nil, errors.New("snooze time must be in the future")
+	}
+
+	activity.ReminderDate = &until
+	activity.NotifiedAt = nil
+	if err := uc.activityRepo.Update(activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// MaxDocumentSize is the largest document upload accepted, in bytes
+func (uc *CustomerUseCase) MaxDocumentSize() int64 {
+	return uc.storage.MaxSize()
+}
+
+// documentCategory validates a category; an empty one means other
+func documentCategory(category string) (string, error) {
+	category = strings.TrimSpace(category)
+	if category == "" {
+		return domain.DocumentCategoryOther, nil
+	}
+	for _, c := range domain.DocumentCategories {
+		if c == category {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unknown document category %q, use %s", category, strings.Join(domain.DocumentCategories, ", "))
+}
+
+// markThumbnails fills HasThumbnail for the client
+func markThumbnails(docs []domain.CustomerDocument) []domain.CustomerDocument {
+	for i := range docs {
+		docs[i].HasThumbnail = docs[i].ThumbnailKey != ""
+	}
+	return docs
+}
+
+// storeFile checks an upload and fills in the document's file fields. Content already stored
+// for any document is shared rather than stored again, thumbnail included. It reports whether
+// new files were written, so they can be removed if the record isn't saved.
+func (uc *CustomerUseCase) storeFile(doc *domain.CustomerDocument, info *services.FileInfo, data []byte, existing []domain.CustomerDocument) (bool, error) {
+	doc.FileType = info.Kind
+	doc.ContentType = info.ContentType
+	doc.Size = info.Size
+	doc.Checksum = info.Checksum
+	doc.HasThumbnail = false
+
+	if len(existing) > 0 {
+		doc.FilePath = existing[0].FilePath
+		doc.ThumbnailKey = existing[0].ThumbnailKey
+		doc.HasThumbnail = doc.ThumbnailKey != ""
+		return false, nil
+	}
+
+	var err error
+	if doc.FilePath, err = uc.storage.Save("documents", info, data); err != nil {
+		return false, err
+	}
+	doc.ThumbnailKey = ""
+	if uc.thumbnails != nil {
+		// A missing preview never fails the upload
+		if thumb, err := uc.thumbnails.Generate(info.ContentType, data); err == nil {
+			if key, err := uc.storage.Save("thumbnails", &services.FileInfo{Extension: ".jpg", ContentType: "image/jpeg"}, thumb); err == nil {
+				doc.ThumbnailKey = key
+				doc.HasThumbnail = true
+			}
+		}
+	}
+	return true, nil
+}
+
+// removeFiles deletes stored files no document uses any more
+func (uc *CustomerUseCase) removeFiles(keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if remaining, err := uc.docRepo.CountByPath(key); err == nil && remaining == 0 {
+			uc.storage.Delete(key)
+		}
+	}
+}
+
+// UploadDocument checks and stores a customer document. Uploading content the customer
+// already has as a live document returns that document with duplicate set; content stored
+// for any other document gets its own record but shares the stored file.
+func (uc *CustomerUseCase) UploadDocument(customerID uint, title, category, filename string, data []byte, userID uint) (doc *domain.CustomerDocument, duplicate bool, err error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil || customer.DeletedAt != nil {
+		return nil, false, errors.New("customer not found")
+	}
+	if category, err = documentCategory(category); err != nil {
+		return nil, false, err
+	}
+	info, err := uc.storage.Check(filename, data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := uc.docRepo.FindByChecksum(info.Checksum)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range existing {
+		if e := existing[i]; e.CustomerID == customerID && e.DeletedAt == nil && e.ReplacedAt == nil {
+			return &markThumbnails(existing[i : i+1])[0], true, nil
+		}
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = filename
+	}
+	doc = &domain.CustomerDocument{
+		CustomerID:   customerID,
+		Title:        title,
+		Category:     category,
+		OriginalName: filepath.Base(filename),
+		Version:      1,
+		UploadedBy:   userID,
+	}
+	saved, err := uc.storeFile(doc, info, data, existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := uc.docRepo.Create(doc); err != nil {
+		if saved {
+			uc.removeFiles(doc.FilePath, doc.ThumbnailKey)
+		}
+		return nil, false, err
+	}
+	return doc, false, nil
+}
+
+// ReplaceDocument uploads a new version of a document. Earlier versions stay available in
+// its history. Uploading the content of the current version again returns it unchanged.
+func (uc *CustomerUseCase) ReplaceDocument(customerID, docID uint, filename string, data []byte, userID uint) (doc *domain.CustomerDocument, duplicate bool, err error) {
+	current, err := uc.docRepo.FindByID(docID)
+	if err != nil || current.CustomerID != customerID {
+		return nil, false, errors.New("document not found")
+	}
+	if current.DeletedAt != nil {
+		return nil, false, errors.New("restore the document before uploading a new version")
+	}
+	if current.ReplacedAt != nil {
+		return nil, false, errors.New("only the latest version can be replaced")
+	}
+	info, err := uc.storage.Check(filename, data)
+	if err != nil {
+		return nil, false, err
+	}
+	if info.Checksum == current.Checksum {
+		return &markThumbnails([]domain.CustomerDocument{*current})[0], true, nil
+	}
+	existing, err := uc.docRepo.FindByChecksum(info.Checksum)
+	if err != nil {
+		return nil, false, err
+	}
+
+	doc = &domain.CustomerDocument{
+		CustomerID:   customerID,
+		Title:        current.Title,
+		Category:     current.Category,
+		OriginalName: filepath.Base(filename),
+		GroupID:      current.GroupID,
+		Version:      current.Version + 1,
+		UploadedBy:   userID,
+	}
+	if doc.GroupID == 0 {
+		doc.GroupID = current.ID
+	}
+	saved, err := uc.storeFile(doc, info, data, existing)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := uc.docRepo.AddVersion(current, doc); err != nil {
+		if saved {
+			uc.removeFiles(doc.FilePath, doc.ThumbnailKey)
+		}
+		return nil, false, errors.New("the document was changed meanwhile, reload and try again")
+	}
+	return doc, false, nil
+}
+
+// GetDocuments retrieves the latest version of a customer's documents, or of those in the trash
+func (uc *CustomerUseCase) GetDocuments(customerID uint, filter domain.DocumentFilter) ([]domain.CustomerDocument, error) {
+	if filter.Category != "" {
+		if _, err := documentCategory(filter.Category); err != nil {
+			return nil, err
+		}
+	}
+	docs, err := uc.docRepo.FindByCustomerID(customerID, filter)
+	return markThumbnails(docs), err
+}
+
+// GetDocumentVersions returns the history of a document, newest first
+func (uc *CustomerUseCase) GetDocumentVersions(customerID, docID uint) ([]domain.CustomerDocument, error) {
+	doc, err := uc.docRepo.FindByID(docID)
+	if err != nil || doc.CustomerID != customerID {
+		return nil, errors.New("document not found")
+	}
+	groupID := doc.GroupID
+	if groupID == 0 {
+		groupID = doc.ID
+	}
+	versions, err := uc.docRepo.FindVersions(groupID)
+	return markThumbnails(versions), err
+}
+
+// UpdateDocument renames or recategorises the latest version of a document
+func (uc *CustomerUseCase) UpdateDocument(customerID, docID uint, req *domain.UpdateDocumentRequest) (*domain.CustomerDocument, error) {
+	doc, err := uc.docRepo.FindByID(docID)
+	if err != nil || doc.CustomerID != customerID {
+		return nil, errors.New("document not found")
+	}
+	if doc.ReplacedAt != nil {
+		return nil, errors.New("only the latest version can be edited")
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		doc.Title = title
+	}
+	if req.Category != "" {
+		if doc.Category, err = documentCategory(req.Category); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.docRepo.Update(doc); err != nil {
+		return nil, err
+	}
+	return &markThumbnails([]domain.CustomerDocument{*doc})[0], nil
+}
+
+// getDocument loads a customer's document, checking the user may see the customer
+func (uc *CustomerUseCase) getDocument(customerID, docID, userID uint) (*domain.CustomerDocument, error) {
+	doc, err := uc.docRepo.FindByID(docID)
+	if err != nil || doc.CustomerID != customerID {
+		return nil, errors.New("document not found")
+	}
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, errors.New("document not found")
+	}
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil || !user.CanSeeCustomer(customer) {
+		return nil, ErrDocumentAccess
+	}
+	return doc, nil
+}
+
+// OpenDocument opens a document's content for a user allowed to see the customer. The
+// caller closes the returned reader.
+func (uc *CustomerUseCase) OpenDocument(customerID, docID, userID uint) (*domain.CustomerDocument, io.ReadCloser, *storage.Object, error) {
+	doc, err := uc.getDocument(customerID, docID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r, obj, err := uc.storage.Open(doc.FilePath)
+	if err != nil {
+		return nil, nil, nil, errors.New("document file is missing")
+	}
+	return doc, r, obj, nil
+}
+
+// OpenThumbnail opens a document's preview image for a user allowed to see the customer
+func (uc *CustomerUseCase) OpenThumbnail(customerID, docID, userID uint) (io.ReadCloser, *storage.Object, error) {
+	doc, err := uc.getDocument(customerID, docID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if doc.ThumbnailKey == "" {
+		return nil, nil, errors.New("document has no preview")
+	}
+	r, obj, err := uc.storage.Open(doc.ThumbnailKey)
+	if err != nil {
+		return nil, nil, errors.New("document has no preview")
+	}
+	return r, obj, nil
+}
+
+// DocumentURL returns a short-lived link to a document for a user allowed to see the customer
+func (uc *CustomerUseCase) DocumentURL(customerID, docID, userID uint, inline bool) (string, time.Time, error) {
+	doc, err := uc.getDocument(customerID, docID, userID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	name := doc.OriginalName
+	if name == "" {
+		name = path.Base(doc.FilePath)
+	}
+	url, err := uc.storage.URL(doc.FilePath, documentURLExpiry, storage.URLOptions{Filename: name, Inline: inline, ContentType: doc.ContentType})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return url, time.Now().Add(documentURLExpiry), nil
+}
+
+// DeleteDocument moves a document with all its versions to the trash. Deleting a document
+// that is already in the trash removes it for good, with its stored files once no other
+// document uses them.
+func (uc *CustomerUseCase) DeleteDocument(customerID, docID, userID uint) (purged bool, err error) {
+	doc, err := uc.getDocument(customerID, docID, userID)
+	if err != nil {
+		return false, err
+	}
+	groupID := doc.GroupID
+	if groupID == 0 {
+		groupID = doc.ID
+	}
+
+	if doc.DeletedAt == nil {
+		now := time.Now()
+		return false, uc.docRepo.SetDeleted(groupID, &now, &userID)
+	}
+
+	versions, err := uc.docRepo.FindVersions(groupID)
+	if err != nil {
+		return false, err
+	}
+	if err := uc.docRepo.DeleteGroup(groupID); err != nil {
+		return false, err
+	}
+	for _, v := range versions {
+		uc.removeFiles(v.FilePath, v.ThumbnailKey)
+	}
+	return true, nil
+}
+
+// RestoreDocument takes a document with all its versions out of the trash
+func (uc *CustomerUseCase) RestoreDocument(customerID, docID, userID uint) (*domain.CustomerDocument, error) {
+	doc, err := uc.getDocument(customerID, docID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.DeletedAt == nil {
+		return nil, errors.New("document is not in the trash")
+	}
+	groupID := doc.GroupID
+	if groupID == 0 {
+		groupID = doc.ID
+	}
+	if err := uc.docRepo.SetDeleted(groupID, nil, nil); err != nil {
+		return nil, err
+	}
+	doc.DeletedAt, doc.DeletedBy = nil, nil
+	return &markThumbnails([]domain.CustomerDocument{*doc})[0], nil
 }
 
 // ToggleActivityNotification enables/disables notification for an activity
@@ -166,3 +781,318 @@ func (uc *CustomerUseCase) ToggleActivityNotification(activityID uint, enabled b
 	activity.NotificationEnabled = enabled
 	return uc.activityRepo.Update(activity)
 }
+
+// buildCadence validates a request into a cadence
+func buildCadence(cadence *domain.FollowUpCadence, req *domain.SaveCadenceRequest) error {
+	name := strings.TrimSpace(req.Name)
+	trigger := strings.TrimSpace(req.TriggerType)
+	if name == "" || trigger == "" {
+		return errors.New("name and trigger type are required")
+	}
+	if len(req.Steps) == 0 {
+		return errors.New("a cadence needs at least one step")
+	}
+	for _, step := range req.Steps {
+		if step.AfterDays < 0 {
+			return errors.New("step days cannot be negative")
+		}
+		if strings.TrimSpace(step.Type) == "" || strings.TrimSpace(step.Description) == "" {
+			return errors.New("each step needs a type and description")
+		}
+	}
+
+	cadence.Name = name
+	cadence.TriggerType = trigger
+	cadence.Active = req.Active == nil || *req.Active
+	cadence.Steps = req.Steps
+	return nil
+}
+
+func (uc *CustomerUseCase) CreateCadence(req *domain.SaveCadenceRequest, userID uint) (*domain.FollowUpCadence, error) {
+	cadence := &domain.FollowUpCadence{CreatedBy: userID}
+	if err := buildCadence(cadence, req); err != nil {
+		return nil, err
+	}
+	if _, err := uc.activityRepo.FindCadenceByName(cadence.Name); err == nil {
+		return nil, errors.New("cadence already exists")
+	}
+	if err := uc.activityRepo.CreateCadence(cadence); err != nil {
+		return nil, err
+	}
+	return cadence, nil
+}
+
+func (uc *CustomerUseCase) UpdateCadence(id uint, req *domain.SaveCadenceRequest) (*domain.FollowUpCadence, error) {
+	cadence, err := uc.activityRepo.FindCadenceByID(id)
+	if err != nil {
+		return nil, errors.New("cadence not found")
+	}
+	if err := buildCadence(cadence, req); err != nil {
+		return nil, err
+	}
+	if other, err := uc.activityRepo.FindCadenceByName(cadence.Name); err == nil && other.ID != id {
+		return nil, errors.New("cadence already exists")
+	}
+	if err := uc.activityRepo.UpdateCadence(cadence); err != nil {
+		return nil, err
+	}
+	return cadence, nil
+}
+
+func (uc *CustomerUseCase) DeleteCadence(id uint) error {
+	if _, err := uc.activityRepo.FindCadenceByID(id); err != nil {
+		return errors.New("cadence not found")
+	}
+	return uc.activityRepo.DeleteCadence(id)
+}
+
+func (uc *CustomerUseCase) GetCadence(id uint) (*domain.FollowUpCadence, error) {
+	cadence, err := uc.activityRepo.FindCadenceByID(id)
+	if err != nil {
+		return nil, errors.New("cadence not found")
+	}
+	return cadence, nil
+}
+
+func (uc *CustomerUseCase) GetCadences() ([]domain.FollowUpCadence, error) {
+	return uc.activityRepo.FindCadences()
+}
+
+// defaultDuplicateThreshold is the name similarity above which customers are reported
+const defaultDuplicateThreshold = 0.85
+
+// customerKeys holds the normalised values a customer is matched on
+type customerKeys struct {
+	name   string
+	phones []string
+	email  string
+}
+
+func keysOf(c *domain.Customer) customerKeys {
+	keys := customerKeys{name: textmatch.NormalizeName(c.Name), email: textmatch.NormalizeEmail(c.Email)}
+	for _, p := range []string{c.Phone, c.Mobile} {
+		if n := textmatch.NormalizePhone(p); n != "" {
+			keys.phones = append(keys.phones, n)
+		}
+	}
+	return keys
+}
+
+// matchCustomers scores a pair on shared phones and emails and on name similarity.
+// Each extra matching signal adds to the strongest one.
+func matchCustomers(a, b customerKeys, threshold float64) (float64, []string) {
+	var reasons []string
+	var scores []float64
+
+	phoneMatch := false
+	for _, pa := range a.phones {
+		for _, pb := range b.phones {
+			if pa == pb {
+				phoneMatch = true
+			}
+		}
+	}
+	if phoneMatch {
+		reasons = append(reasons, domain.DuplicateMatchPhone)
+		scores = append(scores, 0.95)
+	}
+	if a.email != "" && a.email == b.email {
+		reasons = append(reasons, domain.DuplicateMatchEmail)
+		scores = append(scores, 0.95)
+	}
+	if a.name != "" && a.name == b.name {
+		reasons = append(reasons, domain.DuplicateMatchName)
+		scores = append(scores, 0.9)
+	} else if sim := textmatch.Similarity(a.name, b.name); sim >= threshold {
+		reasons = append(reasons, domain.DuplicateMatchSimilarName)
+		scores = append(scores, sim*0.9)
+	}
+
+	if len(scores) == 0 {
+		return 0, nil
+	}
+	sort.Float64s(scores)
+	score := scores[len(scores)-1] + 0.05*float64(len(scores)-1)
+	return math.Min(math.Round(score*1000)/1000, 1), reasons
+}
+
+// duplicateBlockLimit caps how many customers a shared name word may group. Words that
+// common, like "شركة", say little about a match and would bring back the pairwise scan.
+const duplicateBlockLimit = 200
+
+// blocks returns the keys a customer shares with its likely duplicates: each phone, the
+// email, the whole name, and every name word with its one-letter deletions, so words one
+// edit apart share a key. Name word keys are prefixed "w:" so they can be capped.
+func (k customerKeys) blocks() []string {
+	var keys []string
+	for _, p := range k.phones {
+		keys = append(keys, "p:"+p)
+	}
+	if k.email != "" {
+		keys = append(keys, "e:"+k.email)
+	}
+	if k.name != "" {
+		keys = append(keys, "n:"+k.name)
+	}
+	for _, word := range strings.Fields(k.name) {
+		keys = append(keys, "w:"+word)
+		if runes := []rune(word); len(runes) > 3 {
+			for i := range runes {
+				keys = append(keys, "w:"+string(runes[:i])+string(runes[i+1:]))
+			}
+		}
+	}
+	return keys
+}
+
+// FindDuplicates lists likely duplicate pairs among active customers, best matches first.
+// A non-zero customerID restricts the search to that customer's duplicates. Only customers
+// sharing a phone, an email or a name word (up to one edit apart) are compared, so names
+// alike only through many small edits spread over long words are not reported.
+func (uc *CustomerUseCase) FindDuplicates(customerID uint, threshold float64, limit int) ([]domain.DuplicateCandidate, error) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = defaultDuplicateThreshold
+	}
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	customers, err := uc.customerRepo.FindAllActive()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]customerKeys, len(customers))
+	blocks := make(map[string][]int)
+	for i := range customers {
+		keys[i] = keysOf(&customers[i])
+		for _, key := range keys[i].blocks() {
+			if members := blocks[key]; len(members) == 0 || members[len(members)-1] != i {
+				blocks[key] = append(members, i)
+			}
+		}
+	}
+
+	candidates := []domain.DuplicateCandidate{}
+	seen := make(map[[2]int]bool)
+	compare := func(i, j int) {
+		if i > j {
+			i, j = j, i
+		}
+		if seen[[2]int{i, j}] {
+			return
+		}
+		seen[[2]int{i, j}] = true
+		score, reasons := matchCustomers(keys[i], keys[j], threshold)
+		if score == 0 {
+			return
+		}
+		first, second := customers[i], customers[j]
+		if customerID != 0 && second.ID == customerID {
+			first, second = second, first
+		}
+		candidates = append(candidates, domain.DuplicateCandidate{Customer: first, Duplicate: second, Score: score, Reasons: reasons})
+	}
+	for key, members := range blocks {
+		if strings.HasPrefix(key, "w:") && len(members) > duplicateBlockLimit {
+			continue
+		}
+		if customerID != 0 {
+			// Only the block's pairs with the customer itself
+			target := slices.IndexFunc(members, func(i int) bool { return customers[i].ID == customerID })
+			if target < 0 {
+				continue
+			}
+			for _, m := range members {
+				if m != members[target] {
+					compare(members[target], m)
+				}
+			}
+			continue
+		}
+		for a := range members {
+			for b := a + 1; b < len(members); b++ {
+				compare(members[a], members[b])
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].Score != candidates[b].Score {
+			return candidates[a].Score > candidates[b].Score
+		}
+		if candidates[a].Customer.ID != candidates[b].Customer.ID {
+			return candidates[a].Customer.ID < candidates[b].Customer.ID
+		}
+		return candidates[a].Duplicate.ID < candidates[b].Duplicate.ID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// MergeCustomers folds a duplicate into the surviving customer: orders, activities, documents
+// and credit history move over, the balance is added, blank contact fields are filled from the
+// duplicate, a credit hold carries over, and the duplicate is soft-deleted.
+func (uc *CustomerUseCase) MergeCustomers(survivorID uint, req *domain.MergeCustomersRequest, userID uint) (*domain.CustomerMerge, error) {
+	if survivorID == req.MergeID {
+		return nil, errors.New("cannot merge a customer into itself")
+	}
+	survivor, err := uc.customerRepo.FindByID(survivorID)
+	if err != nil || survivor.DeletedAt != nil {
+		return nil, errors.New("customer not found")
+	}
+	merged, err := uc.customerRepo.FindByID(req.MergeID)
+	if err != nil || merged.DeletedAt != nil {
+		return nil, errors.New("customer to merge not found")
+	}
+
+	snapshot, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	record := &domain.CustomerMerge{
+		SurvivorID:   survivor.ID,
+		MergedID:     merged.ID,
+		MergedCode:   merged.Code,
+		MergedName:   merged.Name,
+		Snapshot:     string(snapshot),
+		BalanceMoved: merged.Balance,
+		Reason:       strings.TrimSpace(req.Reason),
+		MergedBy:     userID,
+	}
+
+	for _, f := range []struct{ into, from *string }{
+		{&survivor.Phone, &merged.Phone},
+		{&survivor.Mobile, &merged.Mobile},
+		{&survivor.Address, &merged.Address},
+		{&survivor.City, &merged.City},
+		{&survivor.Governorate, &merged.Governorate},
+		{&survivor.PostalCode, &merged.PostalCode},
+		{&survivor.TaxNumber, &merged.TaxNumber},
+	} {
+		if strings.TrimSpace(*f.into) == "" {
+			*f.into = *f.from
+		}
+	}
+	if merged.CreditHold && !survivor.CreditHold {
+		survivor.CreditHold = true
+		survivor.CreditHoldReason = merged.CreditHoldReason
+		survivor.CreditHoldAt = merged.CreditHoldAt
+		survivor.CreditHoldBy = merged.CreditHoldBy
+	}
+	survivor.Balance = survivor.Balance.Add(merged.Balance)
+
+	merged.Balance = money.Zero
+	merged.Status = "inactive"
+	merged.MergedIntoID = &survivor.ID
+
+	if err := uc.customerRepo.Merge(survivor, merged, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (uc *CustomerUseCase) GetMerges(customerID uint) ([]domain.CustomerMerge, error) {
+	return uc.customerRepo.FindMerges(customerID)
+}
@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// quantityTolerance absorbs float noise when comparing fractional quantities
+const quantityTolerance = 1e-9
+
+type DeliveryUseCase struct {
+	deliveryRepo  repositories.DeliveryRepository
+	salesRepo     repositories.SalesRepository
+	inventoryRepo repositories.InventoryRepository
+}
+
+func NewDeliveryUseCase(repo repositories.DeliveryRepository, salesRepo repositories.SalesRepository, invRepo repositories.InventoryRepository) *DeliveryUseCase {
+	return &DeliveryUseCase{
+		deliveryRepo:  repo,
+		salesRepo:     salesRepo,
+		inventoryRepo: invRepo,
+	}
+}
+
+// CreateDeliveryNote ships a subset of the order's outstanding quantities from one warehouse.
+// The order becomes shipped once every line has been fully shipped.
+func (uc *DeliveryUseCase) CreateDeliveryNote(req *domain.CreateDeliveryNoteRequest, userID uint) (*domain.DeliveryNote, error) {
+	order, err := uc.salesRepo.FindByID(req.OrderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != domain.OrderStatusDraft && order.Status != domain.OrderStatusConfirmed {
+		return nil, fmt.Errorf("cannot ship an order that is %s", order.Status)
+	}
+
+	warehouse, err := uc.inventoryRepo.FindWarehouseByID(req.WarehouseID)
+	if err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	if !warehouse.IsActive {
+		return nil, fmt.Errorf("warehouse %s is inactive", warehouse.Code)
+	}
+
+	lines := make(map[uint]bool, len(order.Items))
+	for _, line := range order.Items {
+		lines[line.ID] = true
+	}
+
+	// Requested quantities per order line; none means everything still outstanding
+	requested := make(map[uint]float64)
+	if len(req.Items) == 0 {
+		for _, line := range order.Items {
+			if remaining := line.Quantity - line.ShippedQuantity; remaining > quantityTolerance {
+				requested[line.ID] = remaining
+			}
+		}
+		if len(requested) == 0 {
+			return nil, errors.New("order has nothing left to ship")
+		}
+	}
+	for _, item := range req.Items {
+		if !lines[item.OrderItemID] {
+			return nil, fmt.Errorf("item %d does not belong to order %s", item.OrderItemID, order.OrderNumber)
+		}
+		requested[item.OrderItemID] += item.Quantity
+	}
+
+	note := &domain.DeliveryNote{
+		OrderID:       order.ID,
+		WarehouseID:   warehouse.ID,
+		Status:        domain.DeliveryStatusShipped,
+		DriverName:    req.DriverName,
+		DriverPhone:   req.DriverPhone,
+		VehicleNumber: req.VehicleNumber,
+		DeliveryDate:  time.Now(),
+		Notes:         req.Notes,
+		CreatedBy:     userID,
+	}
+	if req.DeliveryDate != nil {
+		note.DeliveryDate = *req.DeliveryDate
+	}
+
+	perProduct := make(map[uint]float64)
+	for i := range order.Items {
+		line := &order.Items[i]
+		qty, ok := requested[line.ID]
+		if !ok {
+			continue
+		}
+		if remaining := line.Quantity - line.ShippedQuantity; qty > remaining+quantityTolerance {
+			return nil, fmt.Errorf("cannot ship %s of %s, only %s outstanding", formatQty(qty), lineName(*line), formatQty(remaining))
+		}
+		// Product stock is counted in whole units, so a fractional shipment could not be taken
+		// from it without rounding away the difference
+		if math.Abs(qty-math.Round(qty)) > quantityTolerance {
+			return nil, fmt.Errorf("cannot ship %s of %s, stock is counted in whole units", formatQty(qty), lineName(*line))
+		}
+		line.ShippedQuantity += qty
+		perProduct[line.ProductID] += qty
+		note.Items = append(note.Items, domain.DeliveryNoteItem{
+			OrderItemID: line.ID,
+			ProductID:   line.ProductID,
+			Quantity:    qty,
+		})
+	}
+
+	for _, line := range order.Items {
+		qty, ok := perProduct[line.ProductID]
+		if !ok {
+			continue
+		}
+		delete(perProduct, line.ProductID)
+		available, err := uc.inventoryRepo.FindStockLevel(warehouse.ID, line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if available+quantityTolerance < qty {
+			return nil, fmt.Errorf("insufficient stock for %s in warehouse %s: available %s, requested %s",
+				lineName(line), warehouse.Code, formatQty(available), formatQty(qty))
+		}
+	}
+
+	status := domain.OrderStatusShipped
+	for _, line := range order.Items {
+		if line.Quantity-line.ShippedQuantity > quantityTolerance {
+			status = domain.OrderStatusConfirmed
+			break
+		}
+	}
+
+	number, err := uc.deliveryRepo.GenerateNoteNumber()
+	if err != nil {
+		return nil, err
+	}
+	note.NoteNumber = number
+
+	var events []domain.Event
+	if from := order.Status; status != from {
+		order.Status = status
+		events = append(events, statusChanged(order, from, userID))
+	}
+
+	// Every shipped quantity is whole, so product stock moves by exactly what the warehouse loses
+	shipped := make(map[uint]int)
+	for _, item := range note.Items {
+		shipped[item.ProductID] += int(math.Round(item.Quantity))
+	}
+	for productID, qty := range shipped {
+		if product, err := uc.inventoryRepo.FindProductByID(productID); err == nil {
+			events = append(events, lowStockEvents(product, product.StockQuantity, product.StockQuantity-qty, userID)...)
+		}
+	}
+
+	if err := uc.deliveryRepo.Ship(note, status, events...); err != nil {
+		return nil, err
+	}
+	return uc.deliveryRepo.FindByID(note.ID)
+}
+
+// ConfirmDelivery records the customer's receipt of a shipped note.
+// The order becomes delivered once every line has been fully delivered.
+func (uc *DeliveryUseCase) ConfirmDelivery(id uint, req *domain.ConfirmDeliveryRequest) (*domain.DeliveryNote, error) {
+	note, err := uc.deliveryRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("delivery note not found")
+	}
+	if note.Status != domain.DeliveryStatusShipped {
+		return nil, fmt.Errorf("delivery note is already %s", note.Status)
+	}
+
+	order, err := uc.salesRepo.FindByID(note.OrderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+
+	delivered := make(map[uint]float64, len(note.Items))
+	for _, item := range note.Items {
+		delivered[item.OrderItemID] += item.Quantity
+	}
+
+	status := domain.OrderStatusDelivered
+	for _, line := range order.Items {
+		if line.Quantity-(line.DeliveredQuantity+delivered[line.ID]) > quantityTolerance {
+			status = order.Status
+			break
+		}
+	}
+
+	now := time.Now()
+	note.Status = domain.DeliveryStatusDelivered
+	note.DeliveredAt = &now
+	if req.DeliveredAt != nil {
+		note.DeliveredAt = req.DeliveredAt
+	}
+	note.ReceivedBy = req.ReceivedBy
+
+	var events []domain.Event
+	if from := order.Status; status != from {
+		order.Status = status
+		events = append(events, statusChanged(order, from, note.CreatedBy))
+	}
+
+	if err := uc.deliveryRepo.ConfirmDelivery(note, status, events...); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (uc *DeliveryUseCase) GetDeliveryNote(id uint) (*domain.DeliveryNote, error) {
+	return uc.deliveryRepo.FindByID(id)
+}
+
+func (uc *DeliveryUseCase) GetDeliveryNotes(orderID uint, status string) ([]domain.DeliveryNote, error) {
+	return uc.deliveryRepo.FindAll(orderID, status)
+}
+
+func lineName(line domain.SalesOrderItem) string {
+	if line.Product != nil && line.Product.Name != "" {
+		return line.Product.Name
+	}
+	return fmt.Sprintf("product %d", line.ProductID)
+}
+
+func formatQty(q float64) string {
+	return fmt.Sprintf("%g", q)
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type DocumentUseCase struct {
+	salesRepo       repositories.SalesRepository
+	deliveryRepo    repositories.DeliveryRepository
+	documentService *services.DocumentService
+}
+
+func NewDocumentUseCase(salesRepo repositories.SalesRepository, deliveryRepo repositories.DeliveryRepository, ds *services.DocumentService) *DocumentUseCase {
+	return &DocumentUseCase{salesRepo: salesRepo, deliveryRepo: deliveryRepo, documentService: ds}
+}
+
+// SalesDocument renders a sales order as a PDF and returns it with a download file name
+func (uc *DocumentUseCase) SalesDocument(orderID uint, kind string) ([]byte, string, error) {
+	order, err := uc.salesRepo.FindByID(orderID)
+	if err != nil {
+		return nil, "", errors.New("order not found")
+	}
+
+	// Only approved, live orders can be invoiced or delivered
+	if kind == domain.SalesDocumentInvoice || kind == domain.SalesDocumentDeliveryNote {
+		if order.Status == domain.OrderStatusPendingApproval || order.Status == domain.OrderStatusCreditHold || order.Status == domain.OrderStatusCancelled {
+			return nil, "", fmt.Errorf("cannot issue a %s for an order that is %s", strings.ReplaceAll(kind, "_", " "), order.Status)
+		}
+	}
+
+	data, err := uc.documentService.RenderSalesDocument(order, kind)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, fmt.Sprintf("%s-%s.pdf", order.OrderNumber, strings.ReplaceAll(kind, "_", "-")), nil
+}
+
+// DeliveryNoteDocument renders a delivery note as a PDF and returns it with a download file name
+func (uc *DocumentUseCase) DeliveryNoteDocument(id uint) ([]byte, string, error) {
+	note, err := uc.deliveryRepo.FindByID(id)
+	if err != nil {
+		return nil, "", errors.New("delivery note not found")
+	}
+	order, err := uc.salesRepo.FindByID(note.OrderID)
+	if err != nil {
+		return nil, "", errors.New("order not found")
+	}
+
+	data, err := uc.documentService.RenderDeliveryNote(note, order)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, note.NoteNumber + ".pdf", nil
+}
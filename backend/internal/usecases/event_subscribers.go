@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"errors"
+	"fmt"
+)
+
+// customerStatuses are the order statuses customers are told about
+var customerStatuses = map[string]bool{
+	domain.OrderStatusConfirmed: true,
+	domain.OrderStatusShipped:   true,
+	domain.OrderStatusDelivered: true,
+}
+
+// SubscribeCustomerMessages sends customers their order status updates and payment
+// receipts on their preferred channel. The idempotency keys keep redelivered events from
+// queuing a message twice.
+func SubscribeCustomerMessages(bus *services.EventBus, outbox *OutboxUseCase, salesRepo repositories.SalesRepository) {
+	services.On(bus, "customer_messages:order_status", func(event *domain.DomainEvent, e domain.OrderStatusChanged) error {
+		if !customerStatuses[e.To] {
+			return nil
+		}
+		order, err := salesRepo.FindByID(e.OrderID)
+		if err != nil {
+			return err
+		}
+		order.Status = e.To
+		return queueCustomerMessage(outbox, order, domain.TemplateOrderStatus, nil, domain.MessageSourceOrder,
+			fmt.Sprintf("order:%d:%s", order.ID, e.To), e.ChangedBy)
+	})
+	services.On(bus, "customer_messages:payment_receipt", func(event *domain.DomainEvent, e domain.PaymentReceived) error {
+		order, err := salesRepo.FindByID(e.OrderID)
+		if err != nil {
+			return err
+		}
+		vars := map[string]string{"amount_paid": e.Amount.String() + " " + e.Currency}
+		return queueCustomerMessage(outbox, order, domain.TemplatePaymentReceipt, vars, domain.MessageSourcePayment,
+			fmt.Sprintf("payment:%d", e.PaymentID), e.ReceivedBy)
+	})
+}
+
+// queueCustomerMessage queues a template message to the order's customer; customers no
+// channel reaches are skipped
+func queueCustomerMessage(outbox *OutboxUseCase, order *domain.SalesOrder, key string, vars map[string]string, source, idempotencyKey string, userID uint) error {
+	_, err := outbox.QueueTemplate(&order.Customer, key, order, vars, source, idempotencyKey, userID)
+	if errors.Is(err, services.ErrNoChannel) {
+		return nil
+	}
+	return err
+}
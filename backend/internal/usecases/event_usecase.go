@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"errors"
+)
+
+type EventUseCase struct {
+	repo repositories.EventRepository
+	bus  *services.EventBus
+}
+
+func NewEventUseCase(repo repositories.EventRepository, bus *services.EventBus) *EventUseCase {
+	return &EventUseCase{repo: repo, bus: bus}
+}
+
+// GetEvents lists domain events, newest first, with counts per status
+func (uc *EventUseCase) GetEvents(filter domain.DomainEventFilter, page, limit int) ([]domain.DomainEvent, int64, map[string]int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	events, total, err := uc.repo.FindAll(filter, page, limit)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	counts, err := uc.repo.CountByStatus()
+	return events, total, counts, err
+}
+
+func (uc *EventUseCase) GetEvent(id uint) (*domain.DomainEvent, error) {
+	event, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	return event, nil
+}
+
+// RetryEvent gives the subscribers that failed an event one more attempt
+func (uc *EventUseCase) RetryEvent(id uint) (*domain.DomainEvent, error) {
+	return uc.bus.Retry(id)
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+	"erp-system/pkg/spreadsheet"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const defaultExportMaxRows = 50000
+
+// exportColumn is one column of an export with its English and Arabic titles
+type exportColumn[T any] struct {
+	title   string
+	titleAr string
+	value   func(*T) any
+}
+
+// exportAmount exports money as a number so spreadsheets can sum it
+func exportAmount(m money.Money) any {
+	return m.Float64()
+}
+
+var customerExportColumns = []exportColumn[domain.Customer]{
+	{"Code", "الكود", func(c *domain.Customer) any { return c.Code }},
+	{"Name", "الاسم", func(c *domain.Customer) any { return c.Name }},
+	{"Type", "النوع", func(c *domain.Customer) any { return c.Type }},
+	{"Status", "الحالة", func(c *domain.Customer) any { return c.Status }},
+	{"Email", "البريد الإلكتروني", func(c *domain.Customer) any { return c.Email }},
+	{"Phone", "الهاتف", func(c *domain.Customer) any { return c.Phone }},
+	{"Mobile", "المحمول", func(c *domain.Customer) any { return c.Mobile }},
+	{"Address", "العنوان", func(c *domain.Customer) any { return c.Address }},
+	{"City", "المدينة", func(c *domain.Customer) any { return c.City }},
+	{"Governorate", "المحافظة", func(c *domain.Customer) any { return c.Governorate }},
+	{"Tax Number", "الرقم الضريبي", func(c *domain.Customer) any { return c.TaxNumber }},
+	{"Currency", "العملة", func(c *domain.Customer) any { return c.Currency }},
+	{"Credit Limit", "حد الائتمان", func(c *domain.Customer) any { return exportAmount(c.CreditLimit) }},
+	{"Balance", "الرصيد", func(c *domain.Customer) any { return exportAmount(c.Balance) }},
+	{"Payment Terms Days", "أيام السداد", func(c *domain.Customer) any { return c.PaymentTermsDays }},
+	{"Created At", "تاريخ الإنشاء", func(c *domain.Customer) any { return c.CreatedAt }},
+}
+
+var salesOrderExportColumns = []exportColumn[domain.SalesOrder]{
+	{"Order Number", "رقم الأمر", func(o *domain.SalesOrder) any { return o.OrderNumber }},
+	{"Order Date", "تاريخ الأمر", func(o *domain.SalesOrder) any { return o.OrderDate }},
+	{"Customer Code", "كود العميل", func(o *domain.SalesOrder) any { return o.Customer.Code }},
+	{"Customer", "العميل", func(o *domain.SalesOrder) any { return o.Customer.Name }},
+	{"Status", "الحالة", func(o *domain.SalesOrder) any { return o.Status }},
+	{"Currency", "العملة", func(o *domain.SalesOrder) any { return o.Currency }},
+	{"Total", "الإجمالي", func(o *domain.SalesOrder) any { return exportAmount(o.TotalAmount) }},
+	{"Discount", "الخصم", func(o *domain.SalesOrder) any { return exportAmount(o.DiscountAmount) }},
+	{"Tax", "الضريبة", func(o *domain.SalesOrder) any { return exportAmount(o.TaxAmount) }},
+	{"Net", "الصافي", func(o *domain.SalesOrder) any { return exportAmount(o.NetAmount) }},
+	{"Paid", "المدفوع", func(o *domain.SalesOrder) any { return exportAmount(o.PaidAmount) }},
+	{"Delivery Date", "تاريخ التسليم", func(o *domain.SalesOrder) any { return o.DeliveryDate }},
+	{"Notes", "ملاحظات", func(o *domain.SalesOrder) any { return o.Notes }},
+}
+
+var productExportColumns = []exportColumn[domain.Product]{
+	{"SKU", "الكود", func(p *domain.Product) any { return p.SKU }},
+	{"Name", "الاسم", func(p *domain.Product) any { return p.Name }},
+	{"Category", "الفئة", func(p *domain.Product) any { return p.Category.Name }},
+	{"Cost Price", "سعر التكلفة", func(p *domain.Product) any { return exportAmount(p.CostPrice) }},
+	{"Selling Price", "سعر البيع", func(p *domain.Product) any { return exportAmount(p.SellingPrice) }},
+	{"Stock Quantity", "الكمية بالمخزن", func(p *domain.Product) any { return p.StockQuantity }},
+	{"Reorder Level", "حد إعادة الطلب", func(p *domain.Product) any { return p.ReorderLevel }},
+	{"Active", "نشط", func(p *domain.Product) any { return p.IsActive }},
+}
+
+var productionOrderExportColumns = []exportColumn[domain.ProductionOrder]{
+	{"Order Number", "رقم الأمر", func(o *domain.ProductionOrder) any { return o.OrderNumber }},
+	{"Product SKU", "كود المنتج", func(o *domain.ProductionOrder) any { return o.Product.SKU }},
+	{"Product", "المنتج", func(o *domain.ProductionOrder) any { return o.Product.Name }},
+	{"Quantity", "الكمية", func(o *domain.ProductionOrder) any { return o.Quantity }},
+	{"Actual Quantity", "الكمية الفعلية", func(o *domain.ProductionOrder) any { return o.ActualQuantity }},
+	{"Status", "الحالة", func(o *domain.ProductionOrder) any { return o.Status }},
+	{"Start Date", "تاريخ البدء", func(o *domain.ProductionOrder) any { return o.StartDate }},
+	{"End Date", "تاريخ الانتهاء", func(o *domain.ProductionOrder) any { return o.EndDate }},
+}
+
+type ExportUseCase struct {
+	exportRepo   repositories.ExportRepository
+	settingsRepo repositories.SettingsRepository
+}
+
+func NewExportUseCase(exportRepo repositories.ExportRepository, settingsRepo repositories.SettingsRepository) *ExportUseCase {
+	return &ExportUseCase{exportRepo: exportRepo, settingsRepo: settingsRepo}
+}
+
+// CheckExport validates the list and format before any output is written
+func (uc *ExportUseCase) CheckExport(list, format string) error {
+	switch list {
+	case domain.ExportCustomers, domain.ExportSalesOrders, domain.ExportProducts, domain.ExportProductionOrders:
+	default:
+		return fmt.Errorf("unknown export %q", list)
+	}
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		return errors.New("format must be csv or xlsx")
+	}
+	return nil
+}
+
+// FileName suggests a download name such as customers-2024-05-01.xlsx
+func (uc *ExportUseCase) FileName(list, format string) string {
+	return fmt.Sprintf("%s-%s.%s", list, time.Now().Format("2006-01-02"), format)
+}
+
+// MaxRows is the row cap from settings
+func (uc *ExportUseCase) MaxRows() int {
+	if setting, err := uc.settingsRepo.Get(domain.SettingExportMaxRows); err == nil {
+		if n, err := strconv.Atoi(setting.Value); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultExportMaxRows
+}
+
+// Export streams a list to w as CSV or XLSX, with Arabic headers and a right-to-left
+// sheet when arabic is set, stopping at the configured row cap
+func (uc *ExportUseCase) Export(list, format string, filter domain.ExportFilter, arabic bool, w io.Writer) error {
+	if err := uc.CheckExport(list, format); err != nil {
+		return err
+	}
+	out, err := spreadsheet.NewWriter(format, w, spreadsheet.Options{SheetName: list, RightToLeft: arabic})
+	if err != nil {
+		return err
+	}
+
+	limit := uc.MaxRows()
+	switch list {
+	case domain.ExportCustomers:
+		err = streamExport(out, customerExportColumns, arabic, func(fn func(*domain.Customer) error) error {
+			return uc.exportRepo.EachCustomer(filter, limit, fn)
+		})
+	case domain.ExportSalesOrders:
+		err = streamExport(out, salesOrderExportColumns, arabic, func(fn func(*domain.SalesOrder) error) error {
+			return uc.exportRepo.EachSalesOrder(filter, limit, fn)
+		})
+	case domain.ExportProducts:
+		err = streamExport(out, productExportColumns, arabic, func(fn func(*domain.Product) error) error {
+			return uc.exportRepo.EachProduct(filter, limit, fn)
+		})
+	case domain.ExportProductionOrders:
+		err = streamExport(out, productionOrderExportColumns, arabic, func(fn func(*domain.ProductionOrder) error) error {
+			return uc.exportRepo.EachProductionOrder(filter, limit, fn)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func streamExport[T any](out spreadsheet.Writer, columns []exportColumn[T], arabic bool, each func(func(*T) error) error) error {
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.title
+		if arabic {
+			titles[i] = col.titleAr
+		}
+	}
+	if err := out.WriteHeader(titles); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	return each(func(row *T) error {
+		for i, col := range columns {
+			values[i] = col.value(row)
+		}
+		return out.WriteRow(values)
+	})
+}
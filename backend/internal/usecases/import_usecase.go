@@ -0,0 +1,536 @@
+package usecases
+
+import (
+	"encoding/json"
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/pkg/money"
+	"erp-system/pkg/spreadsheet"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	maxImportRows = 10000
+	// importProgressEvery is how many rows are processed between progress saves
+	importProgressEvery = 50
+)
+
+// importValidator checks rows against the same `binding` rules the JSON endpoints use,
+// naming fields by their JSON (and so column) names
+var importValidator = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return strings.Split(f.Tag.Get("json"), ",")[0]
+	})
+	return v
+}()
+
+type ImportUseCase struct {
+	importRepo    repositories.ImportRepository
+	customerRepo  repositories.CustomerRepository
+	inventoryRepo repositories.InventoryRepository
+}
+
+func NewImportUseCase(importRepo repositories.ImportRepository, customerRepo repositories.CustomerRepository, inventoryRepo repositories.InventoryRepository) *ImportUseCase {
+	return &ImportUseCase{
+		importRepo:    importRepo,
+		customerRepo:  customerRepo,
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+// importRow is one data row keyed by normalised column header
+type importRow struct {
+	number int
+	cells  map[string]string
+}
+
+// has reports whether the row has a non-empty value for the column
+func (r importRow) has(column string) bool {
+	return r.cells[column] != ""
+}
+
+// StartImport parses the upload, records a job and processes it in the background.
+// File-level problems such as an unreadable file or missing columns fail immediately.
+func (uc *ImportUseCase) StartImport(importType, fileName string, data []byte, dryRun bool, userID uint) (*domain.ImportJob, error) {
+	var required []string
+	switch importType {
+	case domain.ImportTypeCustomers:
+		required = []string{"name"}
+	case domain.ImportTypeProducts:
+		required = []string{"sku", "name"}
+	default:
+		return nil, errors.New("import type must be customers or products")
+	}
+
+	// The header row comes on top of the data rows
+	table, err := spreadsheet.Read(fileName, data, maxImportRows+1)
+	if errors.Is(err, spreadsheet.ErrTooManyRows) {
+		return nil, fmt.Errorf("file has more than %d rows, the limit is %d", maxImportRows, maxImportRows)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(table) < 2 {
+		return nil, errors.New("file has no data rows")
+	}
+
+	header := make([]string, len(table[0]))
+	present := map[string]bool{}
+	for i, h := range table[0] {
+		header[i] = importColumn(h)
+		present[header[i]] = true
+	}
+	for _, col := range required {
+		if !present[col] {
+			return nil, fmt.Errorf("missing required column %q", col)
+		}
+	}
+
+	rows := make([]importRow, 0, len(table)-1)
+	for i, cells := range table[1:] {
+		row := importRow{number: i + 2, cells: map[string]string{}}
+		blank := true
+		for j, cell := range cells {
+			if j < len(header) && header[j] != "" && cell != "" {
+				row.cells[header[j]] = cell
+				blank = false
+			}
+		}
+		if !blank {
+			rows = append(rows, row)
+		}
+	}
+
+	job := &domain.ImportJob{
+		Type:      importType,
+		FileName:  fileName,
+		DryRun:    dryRun,
+		Status:    domain.ImportStatusPending,
+		TotalRows: len(rows),
+		CreatedBy: userID,
+	}
+	if err := uc.importRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	go uc.run(*job, rows)
+	return job, nil
+}
+
+// importColumn normalises a header such as "Credit Limit" to the JSON field name credit_limit
+func importColumn(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(header)
+}
+
+func (uc *ImportUseCase) run(job domain.ImportJob, rows []importRow) {
+	now := time.Now()
+	job.Status = domain.ImportStatusRunning
+	job.StartedAt = &now
+	uc.saveJob(&job)
+
+	defer func() {
+		if r := recover(); r != nil {
+			job.Status = domain.ImportStatusFailed
+			job.Error = fmt.Sprint(r)
+			finished := time.Now()
+			job.FinishedAt = &finished
+			uc.saveJob(&job)
+		}
+	}()
+
+	results := make([]domain.ImportRowResult, 0, len(rows))
+	seen := map[string]int{}
+	for i, row := range rows {
+		var result domain.ImportRowResult
+		if job.Type == domain.ImportTypeCustomers {
+			result = uc.importCustomer(row, job.DryRun, job.CreatedBy, seen)
+		} else {
+			result = uc.importProduct(row, job.DryRun, seen)
+		}
+		results = append(results, result)
+
+		job.ProcessedRows++
+		switch result.Action {
+		case domain.ImportActionCreate:
+			job.CreatedCount++
+		case domain.ImportActionUpdate:
+			job.UpdatedCount++
+		default:
+			job.FailedCount++
+		}
+		if (i+1)%importProgressEvery == 0 {
+			uc.saveJob(&job)
+		}
+	}
+
+	resultsJSON, _ := json.Marshal(results)
+	job.ResultsJSON = string(resultsJSON)
+	job.Status = domain.ImportStatusCompleted
+	finished := time.Now()
+	job.FinishedAt = &finished
+	uc.saveJob(&job)
+}
+
+func (uc *ImportUseCase) saveJob(job *domain.ImportJob) {
+	if err := uc.importRepo.Update(job); err != nil {
+		log.Printf("⚠️ Failed to save import job #%d: %v", job.ID, err)
+	}
+}
+
+// claimKey rejects a code or SKU that appeared on an earlier row of the same file
+func claimKey(seen map[string]int, key string, row int) error {
+	if first, ok := seen[key]; ok {
+		return fmt.Errorf("duplicate of row %d", first)
+	}
+	seen[key] = row
+	return nil
+}
+
+func (uc *ImportUseCase) importCustomer(row importRow, dryRun bool, userID uint, seen map[string]int) domain.ImportRowResult {
+	code := row.cells["code"]
+	result := domain.ImportRowResult{Row: row.number, Key: code, Action: domain.ImportActionError}
+	p := &cellParser{row: row}
+
+	req := domain.CreateCustomerRequest{
+		Name:              row.cells["name"],
+		Email:             row.cells["email"],
+		Phone:             row.cells["phone"],
+		Mobile:            row.cells["mobile"],
+		Address:           row.cells["address"],
+		City:              row.cells["city"],
+		Governorate:       row.cells["governorate"],
+		Country:           row.cells["country"],
+		PostalCode:        row.cells["postal_code"],
+		TaxNumber:         row.cells["tax_number"],
+		TaxCodeID:         p.uintPtr("tax_code_id"),
+		Currency:          row.cells["currency"],
+		CreditLimit:       p.money("credit_limit"),
+		PaymentTermsDays:  p.int("payment_terms_days"),
+		Type:              row.cells["type"],
+		IsWhatsAppEnabled: p.bool("is_whatsapp_enabled"),
+		PreferredChannel:  row.cells["preferred_channel"],
+		Language:          row.cells["language"],
+	}
+	errs := append(p.errs, validationErrors(&req)...)
+	if req.CreditLimit.IsNegative() {
+		errs = append(errs, "credit_limit cannot be negative")
+	}
+	currency, err := domain.NormalizeCurrency(req.Currency)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	var existing *domain.Customer
+	if code != "" {
+		if err := claimKey(seen, code, row.number); err != nil {
+			errs = append(errs, err.Error())
+		} else if c, err := uc.customerRepo.FindByCode(code); err == nil {
+			if c.DeletedAt != nil {
+				errs = append(errs, fmt.Sprintf("customer %s was merged or deleted", code))
+			}
+			existing = c
+		}
+	}
+	if len(errs) > 0 {
+		result.Errors = errs
+		return result
+	}
+
+	if existing == nil {
+		result.Action = domain.ImportActionCreate
+		if dryRun {
+			return result
+		}
+		if code == "" {
+			code, _ = uc.customerRepo.GenerateCode()
+			result.Key = code
+		}
+		customer := &domain.Customer{
+			Code:              code,
+			Name:              req.Name,
+			Email:             req.Email,
+			Phone:             req.Phone,
+			Mobile:            req.Mobile,
+			Address:           req.Address,
+			City:              req.City,
+			Governorate:       req.Governorate,
+			Country:           req.Country,
+			PostalCode:        req.PostalCode,
+			TaxNumber:         req.TaxNumber,
+			TaxCodeID:         req.TaxCodeID,
+			Currency:          currency,
+			CreditLimit:       req.CreditLimit,
+			PaymentTermsDays:  req.PaymentTermsDays,
+			Type:              req.Type,
+			Status:            "active",
+			IsWhatsAppEnabled: req.IsWhatsAppEnabled,
+			PreferredChannel:  req.PreferredChannel,
+			Language:          req.Language,
+			CreatedBy:         userID,
+		}
+		if customer.Type == "" {
+			customer.Type = "regular"
+		}
+		if customer.PaymentTermsDays == 0 {
+			customer.PaymentTermsDays = 30
+		}
+		if err := uc.customerRepo.Create(customer); err != nil {
+			result.Action = domain.ImportActionError
+			result.Errors = []string{err.Error()}
+			return result
+		}
+		result.ID = customer.ID
+		return result
+	}
+
+	// Existing customers only take the columns that have a value
+	result.Action = domain.ImportActionUpdate
+	result.ID = existing.ID
+	if dryRun {
+		return result
+	}
+	for col, field := range map[string]*string{
+		"name": &existing.Name, "email": &existing.Email, "phone": &existing.Phone, "mobile": &existing.Mobile,
+		"address": &existing.Address, "city": &existing.City, "governorate": &existing.Governorate,
+		"country": &existing.Country, "postal_code": &existing.PostalCode, "tax_number": &existing.TaxNumber,
+		"type": &existing.Type, "preferred_channel": &existing.PreferredChannel,
+	} {
+		if row.has(col) {
+			*field = row.cells[col]
+		}
+	}
+	if row.has("tax_code_id") {
+		existing.TaxCodeID = req.TaxCodeID
+	}
+	if row.has("currency") {
+		existing.Currency = currency
+	}
+	if row.has("credit_limit") {
+		existing.CreditLimit = req.CreditLimit
+	}
+	if row.has("payment_terms_days") {
+		existing.PaymentTermsDays = req.PaymentTermsDays
+	}
+	if row.has("language") && req.Language != "" {
+		existing.Language = req.Language
+	}
+	if row.has("is_whatsapp_enabled") {
+		existing.IsWhatsAppEnabled = req.IsWhatsAppEnabled
+	}
+	if err := uc.customerRepo.Update(existing); err != nil {
+		result.Action = domain.ImportActionError
+		result.Errors = []string{err.Error()}
+	}
+	return result
+}
+
+func (uc *ImportUseCase) importProduct(row importRow, dryRun bool, seen map[string]int) domain.ImportRowResult {
+	sku := row.cells["sku"]
+	result := domain.ImportRowResult{Row: row.number, Key: sku, Action: domain.ImportActionError}
+	p := &cellParser{row: row}
+
+	req := domain.CreateProductRequest{
+		SKU:           sku,
+		Name:          row.cells["name"],
+		Description:   row.cells["description"],
+		CategoryID:    p.uint("category_id"),
+		TaxCodeID:     p.uintPtr("tax_code_id"),
+		CostPrice:     p.money("cost_price"),
+		SellingPrice:  p.money("selling_price"),
+		ReorderLevel:  p.int("reorder_level"),
+		MaxStockLevel: p.int("max_stock_level"),
+		StockQuantity: p.int("stock_quantity"),
+	}
+	errs := append(p.errs, validationErrors(&req)...)
+	if req.CostPrice.IsNegative() || req.SellingPrice.IsNegative() {
+		errs = append(errs, "prices cannot be negative")
+	}
+
+	var existing *domain.Product
+	if sku != "" {
+		if err := claimKey(seen, sku, row.number); err != nil {
+			errs = append(errs, err.Error())
+		} else if product, err := uc.inventoryRepo.FindProductBySKU(sku); err == nil {
+			existing = product
+		}
+	}
+	if len(errs) > 0 {
+		result.Errors = errs
+		return result
+	}
+
+	if existing == nil {
+		result.Action = domain.ImportActionCreate
+		if dryRun {
+			return result
+		}
+		product := &domain.Product{
+			SKU:           req.SKU,
+			Name:          req.Name,
+			Description:   req.Description,
+			CategoryID:    req.CategoryID,
+			TaxCodeID:     req.TaxCodeID,
+			CostPrice:     req.CostPrice,
+			SellingPrice:  req.SellingPrice,
+			ReorderLevel:  req.ReorderLevel,
+			MaxStockLevel: req.MaxStockLevel,
+			StockQuantity: req.StockQuantity,
+			IsActive:      true,
+		}
+		if !row.has("reorder_level") {
+			product.ReorderLevel = 10
+		}
+		if err := uc.inventoryRepo.CreateProduct(product); err != nil {
+			result.Action = domain.ImportActionError
+			result.Errors = []string{err.Error()}
+			return result
+		}
+		result.ID = product.ID
+		return result
+	}
+
+	result.Action = domain.ImportActionUpdate
+	result.ID = existing.ID
+	if dryRun {
+		return result
+	}
+	existing.Name = req.Name
+	if row.has("description") {
+		existing.Description = req.Description
+	}
+	if row.has("category_id") {
+		existing.CategoryID = req.CategoryID
+	}
+	if row.has("tax_code_id") {
+		existing.TaxCodeID = req.TaxCodeID
+	}
+	if row.has("cost_price") {
+		existing.CostPrice = req.CostPrice
+	}
+	if row.has("selling_price") {
+		existing.SellingPrice = req.SellingPrice
+	}
+	if row.has("reorder_level") {
+		existing.ReorderLevel = req.ReorderLevel
+	}
+	if row.has("max_stock_level") {
+		existing.MaxStockLevel = req.MaxStockLevel
+	}
+	if row.has("stock_quantity") {
+		existing.StockQuantity = req.StockQuantity
+	}
+	if err := uc.inventoryRepo.UpdateProduct(existing); err != nil {
+		result.Action = domain.ImportActionError
+		result.Errors = []string{err.Error()}
+	}
+	return result
+}
+
+// validationErrors runs the request's binding rules and phrases failures per column
+func validationErrors(req any) []string {
+	err := importValidator.Struct(req)
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return nil
+	}
+	msgs := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		switch fe.Tag() {
+		case "required":
+			msgs = append(msgs, fe.Field()+" is required")
+		case "email":
+			msgs = append(msgs, fe.Field()+" must be a valid email")
+		case "gte", "min":
+			msgs = append(msgs, fe.Field()+" must be at least "+fe.Param())
+		default:
+			msgs = append(msgs, fmt.Sprintf("%s failed %s", fe.Field(), fe.Tag()))
+		}
+	}
+	return msgs
+}
+
+// cellParser converts typed cells, collecting an error per unreadable cell
+type cellParser struct {
+	row  importRow
+	errs []string
+}
+
+func (p *cellParser) fail(column, kind string) {
+	p.errs = append(p.errs, fmt.Sprintf("%s must be %s, got %q", column, kind, p.row.cells[column]))
+}
+
+func (p *cellParser) money(column string) money.Money {
+	m, err := money.Parse(strings.ReplaceAll(p.row.cells[column], ",", ""))
+	if err != nil {
+		p.fail(column, "an amount")
+	}
+	return m
+}
+
+func (p *cellParser) int(column string) int {
+	if !p.row.has(column) {
+		return 0
+	}
+	v, err := strconv.ParseFloat(p.row.cells[column], 64)
+	if err != nil || v != float64(int(v)) {
+		p.fail(column, "a whole number")
+	}
+	return int(v)
+}
+
+func (p *cellParser) uint(column string) uint {
+	v := p.int(column)
+	if v < 0 {
+		p.fail(column, "a positive ID")
+		return 0
+	}
+	return uint(v)
+}
+
+func (p *cellParser) uintPtr(column string) *uint {
+	if !p.row.has(column) {
+		return nil
+	}
+	v := p.uint(column)
+	return &v
+}
+
+func (p *cellParser) bool(column string) bool {
+	switch strings.ToLower(p.row.cells[column]) {
+	case "", "0", "false", "no", "n", "لا":
+		return false
+	case "1", "true", "yes", "y", "نعم":
+		return true
+	}
+	p.fail(column, "yes or no")
+	return false
+}
+
+// GetJob returns a job with its row results
+func (uc *ImportUseCase) GetJob(id uint) (*domain.ImportJob, error) {
+	job, err := uc.importRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("import job not found")
+	}
+	if job.ResultsJSON != "" {
+		if err := json.Unmarshal([]byte(job.ResultsJSON), &job.Results); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+func (uc *ImportUseCase) GetJobs(importType string) ([]domain.ImportJob, error) {
+	return uc.importRepo.FindAll(importType, 50)
+}
@@ -4,6 +4,7 @@ import (
 	"erp-system/internal/domain"
 	"erp-system/internal/repositories"
 	"errors"
+	"math"
 )
 
 type InventoryUseCase struct {
@@ -14,13 +15,34 @@ func NewInventoryUseCase(repo repositories.InventoryRepository) *InventoryUseCas
 	return &InventoryUseCase{inventoryRepo: repo}
 }
 
+// lowStockEvents returns a StockBelowReorder event when a product's stock falls from before
+// to after across its reorder level; stock that was already low raises nothing
+func lowStockEvents(product *domain.Product, before, after int, userID uint) []domain.Event {
+	if before <= product.ReorderLevel || after > product.ReorderLevel {
+		return nil
+	}
+	return []domain.Event{domain.StockBelowReorder{
+		ProductID:     product.ID,
+		SKU:           product.SKU,
+		Name:          product.Name,
+		StockQuantity: after,
+		ReorderLevel:  product.ReorderLevel,
+		ChangedBy:     userID,
+	}}
+}
+
 // Product Logic
 func (uc *InventoryUseCase) CreateProduct(req *domain.CreateProductRequest) (*domain.Product, error) {
+	if req.CostPrice.IsNegative() || req.SellingPrice.IsNegative() {
+		return nil, errors.New("prices cannot be negative")
+	}
+
 	product := &domain.Product{
 		SKU:           req.SKU,
 		Name:          req.Name,
 		Description:   req.Description,
 		CategoryID:    req.CategoryID,
+		TaxCodeID:     req.TaxCodeID,
 		CostPrice:     req.CostPrice,
 		SellingPrice:  req.SellingPrice,
 		ReorderLevel:  req.ReorderLevel,
@@ -52,10 +74,13 @@ func (uc *InventoryUseCase) UpdateProduct(id uint, req *domain.UpdateProductRequ
 	if req.CategoryID > 0 {
 		product.CategoryID = req.CategoryID
 	}
-	if req.CostPrice > 0 {
+	if req.TaxCodeID != nil {
+		product.TaxCodeID = req.TaxCodeID
+	}
+	if req.CostPrice.IsPositive() {
 		product.CostPrice = req.CostPrice
 	}
-	if req.SellingPrice > 0 {
+	if req.SellingPrice.IsPositive() {
 		product.SellingPrice = req.SellingPrice
 	}
 	if req.ReorderLevel > 0 {
@@ -64,6 +89,7 @@ func (uc *InventoryUseCase) UpdateProduct(id uint, req *domain.UpdateProductRequ
 	if req.MaxStockLevel > 0 {
 		product.MaxStockLevel = req.MaxStockLevel
 	}
+	before := product.StockQuantity
 	if req.StockQuantity != nil {
 		product.StockQuantity = *req.StockQuantity
 	}
@@ -71,7 +97,7 @@ func (uc *InventoryUseCase) UpdateProduct(id uint, req *domain.UpdateProductRequ
 		product.IsActive = *req.IsActive
 	}
 
-	err = uc.inventoryRepo.UpdateProduct(product)
+	err = uc.inventoryRepo.UpdateProduct(product, lowStockEvents(product, before, product.StockQuantity, 0)...)
 	if err != nil {
 		return nil, err
 	}
@@ -104,3 +130,44 @@ func (uc *InventoryUseCase) CreateCategory(name, description string) (*domain.Ca
 func (uc *InventoryUseCase) GetCategories() ([]domain.Category, error) {
 	return uc.inventoryRepo.FindAllCategories()
 }
+
+// Warehouse Logic
+func (uc *InventoryUseCase) CreateWarehouse(req *domain.CreateWarehouseRequest) (*domain.Warehouse, error) {
+	warehouse := &domain.Warehouse{
+		Code:      req.Code,
+		Name:      req.Name,
+		Address:   req.Address,
+		ManagerID: req.ManagerID,
+		IsActive:  true,
+	}
+	err := uc.inventoryRepo.CreateWarehouse(warehouse)
+	return warehouse, err
+}
+
+func (uc *InventoryUseCase) GetWarehouses() ([]domain.Warehouse, error) {
+	return uc.inventoryRepo.FindAllWarehouses()
+}
+
+func (uc *InventoryUseCase) GetWarehouseStock(warehouseID uint) ([]domain.WarehouseStock, error) {
+	if _, err := uc.inventoryRepo.FindWarehouseByID(warehouseID); err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	return uc.inventoryRepo.FindWarehouseStock(warehouseID)
+}
+
+func (uc *InventoryUseCase) SetWarehouseStock(warehouseID uint, req *domain.SetWarehouseStockRequest) (*domain.WarehouseStock, error) {
+	if _, err := uc.inventoryRepo.FindWarehouseByID(warehouseID); err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	product, err := uc.inventoryRepo.FindProductByID(req.ProductID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	// The product total moves by the change in this warehouse, in whole units
+	counted, err := uc.inventoryRepo.FindStockLevel(warehouseID, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	after := product.StockQuantity + int(math.Round(req.Quantity-counted))
+	return uc.inventoryRepo.SetWarehouseStock(warehouseID, req.ProductID, req.Quantity, lowStockEvents(product, product.StockQuantity, after, 0)...)
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"errors"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+)
+
+// previewCustomer fills in templates previewed without a customer
+var previewCustomer = domain.Customer{Name: "أحمد محمد", Language: domain.LanguageArabic}
+
+type MessageTemplateUseCase struct {
+	templates    *services.TemplateService
+	customerRepo repositories.CustomerRepository
+	salesRepo    repositories.SalesRepository
+}
+
+func NewMessageTemplateUseCase(templates *services.TemplateService, cr repositories.CustomerRepository, sr repositories.SalesRepository) *MessageTemplateUseCase {
+	return &MessageTemplateUseCase{templates: templates, customerRepo: cr, salesRepo: sr}
+}
+
+// GetTemplates returns every template the system sends, customised or default
+func (uc *MessageTemplateUseCase) GetTemplates() ([]domain.MessageTemplate, error) {
+	return uc.templates.All()
+}
+
+func (uc *MessageTemplateUseCase) GetTemplate(key string) (*domain.MessageTemplate, error) {
+	return uc.templates.Get(key)
+}
+
+// UpdateTemplate customises a template
+func (uc *MessageTemplateUseCase) UpdateTemplate(key string, req *domain.SaveMessageTemplateRequest, userID uint) (*domain.MessageTemplate, error) {
+	current, err := uc.templates.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	template := &domain.MessageTemplate{
+		Key:       key,
+		Name:      req.Name,
+		SubjectAr: req.SubjectAr,
+		BodyAr:    req.BodyAr,
+		SubjectEn: req.SubjectEn,
+		BodyEn:    req.BodyEn,
+		UpdatedBy: userID,
+	}
+	if template.Name == "" {
+		template.Name = current.Name
+	}
+	if err := uc.templates.Save(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// ResetTemplate returns a template to its default text
+func (uc *MessageTemplateUseCase) ResetTemplate(key string) (*domain.MessageTemplate, error) {
+	if err := uc.templates.Reset(key); err != nil {
+		return nil, err
+	}
+	return uc.templates.Get(key)
+}
+
+// Preview renders a template, or draft template text, for a customer and order. The
+// language defaults to the customer's.
+func (uc *MessageTemplateUseCase) Preview(req *domain.PreviewTemplateRequest) (*domain.RenderedMessage, error) {
+	var template *domain.MessageTemplate
+	if req.Template != nil {
+		template = &domain.MessageTemplate{
+			SubjectAr: req.Template.SubjectAr,
+			BodyAr:    req.Template.BodyAr,
+			SubjectEn: req.Template.SubjectEn,
+			BodyEn:    req.Template.BodyEn,
+		}
+	} else {
+		stored, err := uc.templates.Get(req.Key)
+		if err != nil {
+			return nil, err
+		}
+		template = stored
+	}
+
+	customer := &previewCustomer
+	var order *domain.SalesOrder
+	if req.OrderID != 0 {
+		found, err := uc.salesRepo.FindByID(req.OrderID)
+		if err != nil {
+			return nil, errors.New("order not found")
+		}
+		order = found
+		customer = &found.Customer
+	}
+	if req.CustomerID != 0 {
+		found, err := uc.customerRepo.FindByID(req.CustomerID)
+		if err != nil {
+			return nil, errors.New("customer not found")
+		}
+		customer = found
+	}
+
+	language := req.Language
+	if language == "" {
+		language = services.CustomerLanguage(customer)
+	}
+	rendered := uc.templates.Render(template, language, customer, order, req.Variables)
+	return &rendered, nil
+}
@@ -3,14 +3,21 @@ package usecases
 import (
 	"erp-system/internal/domain"
 	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 type NotificationUseCase struct {
-	repo repositories.NotificationRepository
+	repo    repositories.NotificationRepository
+	subRepo repositories.SubscriptionRepository
+	hub     *services.NotificationHub
 }
 
-func NewNotificationUseCase(repo repositories.NotificationRepository) *NotificationUseCase {
-	return &NotificationUseCase{repo: repo}
+func NewNotificationUseCase(repo repositories.NotificationRepository, subRepo repositories.SubscriptionRepository, hub *services.NotificationHub) *NotificationUseCase {
+	return &NotificationUseCase{repo: repo, subRepo: subRepo, hub: hub}
 }
 
 func (uc *NotificationUseCase) CreateNotification(userID uint, title, message, notifType string) error {
@@ -28,6 +35,138 @@ func (uc *NotificationUseCase) GetUnread(userID uint) ([]domain.Notification, er
 	return uc.repo.GetUnreadByUserID(userID)
 }
 
-func (uc *NotificationUseCase) MarkAsRead(id uint) error {
-	return uc.repo.MarkAsRead(id)
+// GetHistory pages through a user's read and unread notifications
+func (uc *NotificationUseCase) GetHistory(userID uint, filter domain.NotificationFilter, page, limit int) ([]domain.Notification, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return uc.repo.FindByUserID(userID, filter, page, limit)
+}
+
+func (uc *NotificationUseCase) CountUnread(userID uint) (int64, error) {
+	return uc.repo.CountUnread(userID)
+}
+
+func (uc *NotificationUseCase) MarkAsRead(id, userID uint) error {
+	if err := uc.repo.MarkAsRead(id, userID); err != nil {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// MarkAllAsRead marks every unread notification of a user read and returns how many there were
+func (uc *NotificationUseCase) MarkAllAsRead(userID uint) (int64, error) {
+	return uc.repo.MarkAllAsRead(userID)
+}
+
+func (uc *NotificationUseCase) DeleteNotification(id, userID uint) error {
+	if err := uc.repo.Delete(id, userID); err != nil {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// Subscribe streams a user's new notifications until the returned function is called
+func (uc *NotificationUseCase) Subscribe(userID uint) (<-chan domain.Notification, func()) {
+	return uc.hub.Subscribe(userID)
+}
+
+// GetPreferences returns a user's delivery settings and their effective choice for every
+// event. Events without a saved choice reach the user in the app when they are about them:
+// reminders are on by default, other events only once the user subscribes.
+func (uc *NotificationUseCase) GetPreferences(userID uint) (*domain.NotificationPreferences, error) {
+	subscriptions, err := uc.subRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	byEvent := make(map[string]domain.NotificationSubscription, len(subscriptions))
+	for _, s := range subscriptions {
+		byEvent[s.Event] = s
+	}
+
+	prefs := &domain.NotificationPreferences{NotificationPreference: domain.NotificationPreference{UserID: userID}}
+	if pref, err := uc.subRepo.FindPreference(userID); err == nil {
+		prefs.NotificationPreference = *pref
+	}
+	for _, t := range domain.NotificationEventTypes {
+		setting := domain.SubscriptionSetting{
+			NotificationEventType: t,
+			Enabled:               t.AudienceOnly,
+			Channels:              []string{domain.DeliveryInApp},
+		}
+		if s, ok := byEvent[t.Key]; ok {
+			setting.Enabled = s.Enabled
+			setting.Custom = true
+			if len(s.Channels) > 0 {
+				setting.Channels = s.Channels
+			}
+		}
+		prefs.Subscriptions = append(prefs.Subscriptions, setting)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences saves a user's delivery settings and the subscriptions in the request;
+// events left out keep their current choice
+func (uc *NotificationUseCase) UpdatePreferences(userID uint, req *domain.UpdatePreferencesRequest) (*domain.NotificationPreferences, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var subscriptions []domain.NotificationSubscription
+	wantsWhatsApp := false
+	for _, s := range req.Subscriptions {
+		if _, ok := domain.FindNotificationEventType(s.Event); !ok {
+			return nil, fmt.Errorf("unknown notification event %q", s.Event)
+		}
+		channels := []string{}
+		seen := make(map[string]bool)
+		for _, c := range s.Channels {
+			switch c {
+			case domain.DeliveryInApp, domain.ChannelEmail, domain.ChannelWhatsApp:
+			default:
+				return nil, fmt.Errorf("unknown notification channel %q", c)
+			}
+			if !seen[c] {
+				seen[c] = true
+				channels = append(channels, c)
+			}
+		}
+		if len(channels) == 0 {
+			channels = []string{domain.DeliveryInApp}
+		}
+		if s.Enabled && seen[domain.ChannelWhatsApp] {
+			wantsWhatsApp = true
+		}
+		subscriptions = append(subscriptions, domain.NotificationSubscription{
+			UserID:    userID,
+			Event:     s.Event,
+			Enabled:   s.Enabled,
+			Channels:  channels,
+			UpdatedAt: now,
+		})
+	}
+
+	number := strings.TrimSpace(req.WhatsAppNumber)
+	if wantsWhatsApp && number == "" {
+		return nil, errors.New("a WhatsApp number is needed for WhatsApp notifications")
+	}
+
+	if err := uc.subRepo.SaveAll(subscriptions); err != nil {
+		return nil, err
+	}
+	pref := &domain.NotificationPreference{
+		UserID:          userID,
+		QuietHoursStart: req.QuietHoursStart,
+		QuietHoursEnd:   req.QuietHoursEnd,
+		WhatsAppNumber:  number,
+	}
+	if err := uc.subRepo.SavePreference(pref); err != nil {
+		return nil, err
+	}
+	return uc.GetPreferences(userID)
 }
@@ -0,0 +1,198 @@
+package usecases
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/pkg/messaging"
+)
+
+const (
+	defaultMaxAttempts = 8
+	retryBaseDelay     = time.Minute // Doubles after every failed attempt
+	retryMaxDelay      = 2 * time.Hour
+	deliveryLease      = 5 * time.Minute // How long a claimed message is held by a worker
+)
+
+// OutboxUseCase queues customer messages and delivers them with retries, keeping a log of
+// every attempt
+type OutboxUseCase struct {
+	repo         repositories.OutboundMessageRepository
+	notifService *services.NotificationService
+	templates    *services.TemplateService
+}
+
+func NewOutboxUseCase(repo repositories.OutboundMessageRepository, ns *services.NotificationService, templates *services.TemplateService) *OutboxUseCase {
+	return &OutboxUseCase{repo: repo, notifService: ns, templates: templates}
+}
+
+// Queue adds a message to the outbox. A message with the idempotency key of one already
+// queued is not added again; the existing message is returned instead.
+func (uc *OutboxUseCase) Queue(msg *domain.OutboundMessage) (*domain.OutboundMessage, error) {
+	if msg.IdempotencyKey != nil {
+		if existing, err := uc.repo.FindByIdempotencyKey(*msg.IdempotencyKey); err == nil {
+			return existing, nil
+		}
+	}
+	msg.Status = domain.MessageStatusQueued
+	msg.Attempts = 0
+	if msg.MaxAttempts <= 0 {
+		msg.MaxAttempts = defaultMaxAttempts
+	}
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = time.Now()
+	}
+	if err := uc.repo.Create(msg); err != nil {
+		// Lost a race with another caller queuing the same key
+		if msg.IdempotencyKey != nil {
+			if existing, findErr := uc.repo.FindByIdempotencyKey(*msg.IdempotencyKey); findErr == nil {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// QueueForCustomer queues a message on the customer's channel. It returns
+// services.ErrNoChannel when no configured channel can reach the customer.
+func (uc *OutboxUseCase) QueueForCustomer(customer *domain.Customer, subject, body, source, idempotencyKey string, userID uint) (*domain.OutboundMessage, error) {
+	return uc.queueForCustomer(customer, "", subject, body, source, idempotencyKey, userID)
+}
+
+// QueueTemplate writes a message from a template in the customer's language, filled in with
+// the customer's, the order's and the given values, and queues it on the customer's channel
+func (uc *OutboxUseCase) QueueTemplate(customer *domain.Customer, key string, order *domain.SalesOrder, vars map[string]string, source, idempotencyKey string, userID uint) (*domain.OutboundMessage, error) {
+	rendered, err := uc.templates.RenderFor(key, customer, order, vars)
+	if err != nil {
+		return nil, err
+	}
+	return uc.queueForCustomer(customer, key, rendered.Subject, rendered.Body, source, idempotencyKey, userID)
+}
+
+func (uc *OutboxUseCase) queueForCustomer(customer *domain.Customer, template, subject, body, source, idempotencyKey string, userID uint) (*domain.OutboundMessage, error) {
+	channel, to, err := uc.notifService.CustomerChannel(customer)
+	if err != nil {
+		return nil, err
+	}
+	msg := &domain.OutboundMessage{
+		Channel:    channel,
+		Recipient:  to,
+		Subject:    subject,
+		Body:       body,
+		CustomerID: &customer.ID,
+		Source:     source,
+		Template:   template,
+		CreatedBy:  userID,
+	}
+	if idempotencyKey != "" {
+		msg.IdempotencyKey = &idempotencyKey
+	}
+	return uc.Queue(msg)
+}
+
+// NotifyCustomer queues a template message for a change that has already been saved, so
+// failures are logged rather than returned. Customers no channel reaches are skipped.
+func (uc *OutboxUseCase) NotifyCustomer(customer *domain.Customer, key string, order *domain.SalesOrder, vars map[string]string, source, idempotencyKey string, userID uint) {
+	if _, err := uc.QueueTemplate(customer, key, order, vars, source, idempotencyKey, userID); err != nil && !errors.Is(err, services.ErrNoChannel) {
+		log.Printf("⚠️ Failed to queue %s message for customer #%d: %v", key, customer.ID, err)
+	}
+}
+
+// ClaimDue takes up to limit messages that are due for delivery
+func (uc *OutboxUseCase) ClaimDue(limit int) ([]domain.OutboundMessage, error) {
+	now := time.Now()
+	return uc.repo.ClaimDue(now, now.Add(deliveryLease), limit)
+}
+
+// Deliver makes one attempt to send a claimed message and records the outcome. Failed
+// messages are retried with exponential backoff until they run out of attempts; the
+// returned error is only about saving the outcome.
+func (uc *OutboxUseCase) Deliver(msg *domain.OutboundMessage) error {
+	start := time.Now()
+	sendErr := uc.notifService.Send(msg.Channel, messaging.Message{To: msg.Recipient, Subject: msg.Subject, Body: msg.Body})
+	now := time.Now()
+
+	msg.Attempts++
+	delivery := &domain.MessageDelivery{
+		Attempt:    msg.Attempts,
+		Success:    sendErr == nil,
+		DurationMS: now.Sub(start).Milliseconds(),
+	}
+	switch {
+	case sendErr == nil:
+		msg.Status = domain.MessageStatusSent
+		msg.SentAt = &now
+		msg.LastError = ""
+	case msg.Attempts >= msg.MaxAttempts || errors.Is(sendErr, messaging.ErrNoRecipient):
+		delivery.Error = sendErr.Error()
+		msg.Status = domain.MessageStatusDead
+		msg.LastError = sendErr.Error()
+	default:
+		delivery.Error = sendErr.Error()
+		msg.Status = domain.MessageStatusFailed
+		msg.LastError = sendErr.Error()
+		msg.NextAttemptAt = now.Add(RetryDelay(msg.Attempts))
+	}
+	return uc.repo.SaveAttempt(msg, delivery)
+}
+
+// RetryDelay is the wait before the attempt after the given number of failed attempts
+func RetryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// GetMessages lists outbox messages, newest first, with the number of messages in each status
+func (uc *OutboxUseCase) GetMessages(filter domain.OutboundMessageFilter, page, limit int) ([]domain.OutboundMessage, int64, map[string]int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	messages, total, err := uc.repo.FindAll(filter, page, limit)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	counts, err := uc.repo.CountByStatus()
+	return messages, total, counts, err
+}
+
+// GetMessage returns a message with its delivery log
+func (uc *OutboxUseCase) GetMessage(id uint) (*domain.OutboundMessage, error) {
+	msg, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("message not found")
+	}
+	return msg, nil
+}
+
+// Resend queues a copy of a message for immediate delivery, whatever its status
+func (uc *OutboxUseCase) Resend(id, userID uint) (*domain.OutboundMessage, error) {
+	original, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("message not found")
+	}
+	return uc.Queue(&domain.OutboundMessage{
+		Channel:    original.Channel,
+		Recipient:  original.Recipient,
+		Subject:    original.Subject,
+		Body:       original.Body,
+		CustomerID: original.CustomerID,
+		Source:     domain.MessageSourceResend,
+		Template:   original.Template,
+		ResendOf:   &original.ID,
+		CreatedBy:  userID,
+	})
+}
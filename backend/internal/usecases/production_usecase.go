@@ -45,8 +45,25 @@ func (uc *ProductionUseCase) GetOrder(id uint) (*domain.ProductionOrder, error)
 	return uc.productionRepo.FindOrderByID(id)
 }
 
-func (uc *ProductionUseCase) UpdateOrderStatus(id uint, status string) error {
-	return uc.productionRepo.UpdateOrderStatus(id, status)
+func (uc *ProductionUseCase) UpdateOrderStatus(id uint, status string, userID uint) error {
+	order, err := uc.productionRepo.FindOrderByID(id)
+	if err != nil {
+		return err
+	}
+
+	var events []domain.Event
+	if status == "completed" && order.Status != "completed" {
+		events = append(events, domain.ProductionCompleted{
+			OrderID:     order.ID,
+			OrderNumber: order.OrderNumber,
+			ProductID:   order.ProductID,
+			ProductName: order.Product.Name,
+			Quantity:    order.Quantity,
+			CreatedBy:   order.CreatedBy,
+			CompletedBy: userID,
+		})
+	}
+	return uc.productionRepo.UpdateOrderStatus(id, status, events...)
 }
 
 func (uc *ProductionUseCase) GetBOM(productID uint) ([]domain.BillOfMaterials, error) {
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"errors"
+	"strings"
+)
+
+type PromotionUseCase struct {
+	promotionRepo repositories.PromotionRepository
+}
+
+func NewPromotionUseCase(repo repositories.PromotionRepository) *PromotionUseCase {
+	return &PromotionUseCase{promotionRepo: repo}
+}
+
+func (uc *PromotionUseCase) CreatePromotion(req *domain.CreatePromotionRequest, userID uint) (*domain.Promotion, error) {
+	if req.Type == domain.PromotionTypePercent && req.Value > 100 {
+		return nil, errors.New("percentage promotion cannot exceed 100")
+	}
+	if req.StartDate != nil && req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("end date must be after start date")
+	}
+
+	promotion := &domain.Promotion{
+		Name:          req.Name,
+		Type:          req.Type,
+		Value:         req.Value,
+		ProductID:     req.ProductID,
+		CategoryID:    req.CategoryID,
+		MinOrderValue: req.MinOrderValue,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		IsActive:      true,
+		CreatedBy:     userID,
+	}
+	if code := strings.ToUpper(strings.TrimSpace(req.Code)); code != "" {
+		promotion.Code = &code
+	}
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+
+	if err := uc.promotionRepo.Create(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (uc *PromotionUseCase) UpdatePromotion(id uint, req *domain.UpdatePromotionRequest) (*domain.Promotion, error) {
+	promotion, err := uc.promotionRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("promotion not found")
+	}
+
+	if req.Name != "" {
+		promotion.Name = req.Name
+	}
+	if req.Type != "" {
+		promotion.Type = req.Type
+	}
+	if req.Value > 0 {
+		promotion.Value = req.Value
+	}
+	if req.ProductID != nil {
+		promotion.ProductID = req.ProductID
+	}
+	if req.CategoryID != nil {
+		promotion.CategoryID = req.CategoryID
+	}
+	if req.MinOrderValue != nil {
+		promotion.MinOrderValue = *req.MinOrderValue
+	}
+	if req.StartDate != nil {
+		promotion.StartDate = req.StartDate
+	}
+	if req.EndDate != nil {
+		promotion.EndDate = req.EndDate
+	}
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+
+	if promotion.Type == domain.PromotionTypePercent && promotion.Value > 100 {
+		return nil, errors.New("percentage promotion cannot exceed 100")
+	}
+
+	if err := uc.promotionRepo.Update(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (uc *PromotionUseCase) DeletePromotion(id uint) error {
+	return uc.promotionRepo.Delete(id)
+}
+
+func (uc *PromotionUseCase) GetPromotion(id uint) (*domain.Promotion, error) {
+	return uc.promotionRepo.FindByID(id)
+}
+
+func (uc *PromotionUseCase) GetPromotions(activeOnly bool) ([]domain.Promotion, error) {
+	return uc.promotionRepo.FindAll(activeOnly)
+}
+
+// Discount Policy Logic
+func (uc *PromotionUseCase) SetDiscountPolicy(req *domain.SetDiscountPolicyRequest) (*domain.DiscountPolicy, error) {
+	policy := &domain.DiscountPolicy{
+		RoleID:             req.RoleID,
+		MaxDiscountPercent: req.MaxDiscountPercent,
+		ApproverRoleID:     req.ApproverRoleID,
+	}
+	if policy.ApproverRoleID == 0 {
+		policy.ApproverRoleID = domain.RoleManager
+	}
+
+	if err := uc.promotionRepo.SetPolicy(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (uc *PromotionUseCase) GetDiscountPolicies() ([]domain.DiscountPolicy, error) {
+	return uc.promotionRepo.FindAllPolicies()
+}
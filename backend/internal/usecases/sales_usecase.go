@@ -1,24 +1,47 @@
 package usecases
 
 import (
+	"encoding/json"
 	"erp-system/internal/domain"
 	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/pkg/money"
 	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 )
 
 type SalesUseCase struct {
-	salesRepo    repositories.SalesRepository
-	customerRepo repositories.CustomerRepository
+	salesRepo       repositories.SalesRepository
+	customerRepo    repositories.CustomerRepository
+	inventoryRepo   repositories.InventoryRepository
+	promotionRepo   repositories.PromotionRepository
+	userRepo        repositories.UserRepository
+	notifRepo       repositories.NotificationRepository
+	taxService      *services.TaxService
+	currencyService *services.CurrencyService
+	creditService   *services.CreditService
 }
 
-func NewSalesUseCase(repo repositories.SalesRepository, custRepo repositories.CustomerRepository) *SalesUseCase {
+func NewSalesUseCase(repo repositories.SalesRepository, custRepo repositories.CustomerRepository, invRepo repositories.InventoryRepository, promoRepo repositories.PromotionRepository, userRepo repositories.UserRepository, notifRepo repositories.NotificationRepository, taxService *services.TaxService, currencyService *services.CurrencyService, creditService *services.CreditService) *SalesUseCase {
 	return &SalesUseCase{
-		salesRepo:    repo,
-		customerRepo: custRepo,
+		salesRepo:       repo,
+		customerRepo:    custRepo,
+		inventoryRepo:   invRepo,
+		promotionRepo:   promoRepo,
+		userRepo:        userRepo,
+		notifRepo:       notifRepo,
+		taxService:      taxService,
+		currencyService: currencyService,
+		creditService:   creditService,
 	}
 }
 
 func (uc *SalesUseCase) CreateOrder(req *domain.CreateOrderRequest, userID uint) (*domain.SalesOrder, error) {
+	req.CouponCode = strings.ToUpper(strings.TrimSpace(req.CouponCode))
+
 	orderNumber, err := uc.salesRepo.GenerateOrderNumber()
 	if err != nil {
 		return nil, err
@@ -29,64 +52,804 @@ func (uc *SalesUseCase) CreateOrder(req *domain.CreateOrderRequest, userID uint)
 		CustomerID:   req.CustomerID,
 		OrderDate:    req.OrderDate,
 		DeliveryDate: req.DeliveryDate,
-		Status:       "draft",
+		Status:       domain.OrderStatusDraft,
+		CouponCode:   req.CouponCode,
 		Notes:        req.Notes,
 		CreatedBy:    userID,
+		Revision:     1,
+	}
+
+	// Customer is needed up front for its tax code and the credit check
+	customer, err := uc.customerRepo.FindByID(req.CustomerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+
+	// Prices on the request are in the order currency
+	currency := req.Currency
+	if currency == "" {
+		currency = customer.Currency
+	}
+	order.Currency, err = domain.NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	order.ExchangeRate, err = uc.rateAt(order.Currency, req.OrderDate)
+	if err != nil {
+		return nil, err
+	}
+
+	maxManualDiscount, err := uc.priceOrder(order, customer, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// === Credit Control ===
+	// Orders that fail it wait in the credit hold queue instead of being refused
+	credit := uc.checkCredit(customer, order.BaseNetAmount, 0)
+
+	// === Discount Policy Check ===
+	policy := uc.discountPolicyFor(userID)
+	needsApproval := policy != nil && maxManualDiscount > policy.MaxDiscountPercent
+	if needsApproval {
+		order.Status = domain.OrderStatusPendingApproval
+	} else if credit.Blocked {
+		order.Status = domain.OrderStatusCreditHold
+		order.CreditNote = credit.Reason
+	}
+
+	// Create Order
+	created := domain.EventFunc(func() domain.Event {
+		return domain.OrderCreated{
+			OrderID:      order.ID,
+			OrderNumber:  order.OrderNumber,
+			CustomerID:   customer.ID,
+			CustomerName: customer.Name,
+			BranchID:     customer.BranchID,
+			Status:       order.Status,
+			NetAmount:    order.NetAmount,
+			Currency:     order.Currency,
+			CreatedBy:    userID,
+		}
+	})
+	// Orders waiting for approval or credit release are booked to the balance later
+	booked := !needsApproval && !credit.Blocked
+	events := []domain.Event{created}
+	if booked {
+		events = append(events, domain.EventFunc(func() domain.Event {
+			return balanceChanged(order, order.BaseNetAmount, userID)
+		}))
+	}
+	err = uc.salesRepo.Create(order, events...)
+	if err != nil {
+		return nil, err
+	}
+
+	if needsApproval {
+		uc.notifyApprovers(order, policy, maxManualDiscount)
+		return order, nil
+	}
+	if credit.Blocked {
+		if uc.creditService != nil {
+			uc.creditService.OrderBlocked(customer, order)
+		}
+		return order, nil
 	}
 
-	var totalAmount, taxAmount, discountAmount float64
+	previousBalance := customer.Balance
+	customer.Balance = customer.Balance.Add(order.BaseNetAmount)
+	uc.creditBooked(customer, previousBalance, order, credit, userID)
+
+	return order, nil
+}
+
+// checkCredit runs credit control, or only the plain credit limit when it is not configured
+func (uc *SalesUseCase) checkCredit(customer *domain.Customer, amount money.Money, orderID uint) services.CreditDecision {
+	if uc.creditService != nil {
+		return uc.creditService.Check(customer, amount, orderID)
+	}
+	if customer.CreditLimit.IsPositive() && customer.Balance.Add(amount).GreaterThan(customer.CreditLimit) {
+		return services.CreditDecision{Blocked: true, Reason: "credit limit exceeded for this customer"}
+	}
+	return services.CreditDecision{}
+}
+
+func (uc *SalesUseCase) creditBooked(customer *domain.Customer, previousBalance money.Money, order *domain.SalesOrder, credit services.CreditDecision, userID uint) {
+	if uc.creditService != nil {
+		uc.creditService.Booked(customer, previousBalance, order, credit, userID)
+	}
+}
+
+// priceOrder builds the order lines from the requested items and computes promotions, taxes
+// and totals in the order currency. It returns the largest manual discount for the policy check.
+func (uc *SalesUseCase) priceOrder(order *domain.SalesOrder, customer *domain.Customer, itemReqs []domain.CreateOrderItemRequest) (float64, error) {
+	promotions, err := uc.eligiblePromotions(order, itemReqs)
+	if err != nil {
+		return 0, err
+	}
+
+	// New orders take the configured tax rules; edits keep the rules the order was created with
+	if order.TaxPricingMode == "" {
+		order.TaxPricingMode, order.TaxRounding = domain.TaxPricingExclusive, domain.TaxRoundingLine
+		if uc.taxService != nil {
+			order.TaxPricingMode, order.TaxRounding = uc.taxService.PricingMode(), uc.taxService.RoundingRule()
+		}
+	}
+	pricingMode, rounding := order.TaxPricingMode, order.TaxRounding
+
+	var maxManualDiscount float64
 
-	// Let's rewrite the loop properly
 	var items []domain.SalesOrderItem
-	for _, itemReq := range req.Items {
-		total := itemReq.Quantity * itemReq.UnitPrice
-		itemDiscount := total * (itemReq.Discount / 100)
-		itemTax := (total - itemDiscount) * (itemReq.TaxRate / 100)
-		itemTotal := total - itemDiscount + itemTax
-
-		items = append(items, domain.SalesOrderItem{
-			ProductID: itemReq.ProductID,
-			Quantity:  itemReq.Quantity,
-			UnitPrice: itemReq.UnitPrice,
-			Discount:  itemReq.Discount,
-			TaxRate:   itemReq.TaxRate,
-			Total:     itemTotal,
-		})
-
-		totalAmount += total
-		discountAmount += itemDiscount
-		taxAmount += itemTax
+	var taxLines []services.TaxLine
+	for _, itemReq := range itemReqs {
+		if itemReq.UnitPrice.IsNegative() {
+			return 0, errors.New("unit price cannot be negative")
+		}
+		total := itemReq.UnitPrice.MulFloat(itemReq.Quantity)
+		itemDiscount := total.Percent(itemReq.Discount)
+
+		var product *domain.Product
+		if p, err := uc.inventoryRepo.FindProductByID(itemReq.ProductID); err == nil {
+			product = p
+		}
+
+		// Best promotion for this line, on top of the manual discount
+		var promotionID *uint
+		var promotionDiscount money.Money
+		if len(promotions) > 0 {
+			var categoryID uint
+			if product != nil {
+				categoryID = product.CategoryID
+			}
+			for i := range promotions {
+				if !promotions[i].AppliesTo(itemReq.ProductID, categoryID) {
+					continue
+				}
+				if d := promotions[i].LineDiscount(itemReq.Quantity, itemReq.UnitPrice, order.ExchangeRate); d.GreaterThan(promotionDiscount) {
+					promotionDiscount = d
+					promotionID = &promotions[i].ID
+				}
+			}
+		}
+		promotionDiscount = money.Min(promotionDiscount, total.Sub(itemDiscount))
+		itemDiscount = itemDiscount.Add(promotionDiscount)
+
+		taxLine := services.TaxLine{Rate: itemReq.TaxRate, Gross: total, Discount: itemDiscount}
+		if uc.taxService != nil {
+			taxLine.TaxCode = uc.taxService.ResolveTaxCode(itemReq.TaxCodeID, itemReq.TaxRate, customer, product)
+		}
+		taxLines = append(taxLines, taxLine)
+
+		item := domain.SalesOrderItem{
+			ProductID:         itemReq.ProductID,
+			Quantity:          itemReq.Quantity,
+			UnitPrice:         itemReq.UnitPrice,
+			Discount:          itemReq.Discount,
+			PromotionID:       promotionID,
+			PromotionDiscount: promotionDiscount,
+			TaxRate:           itemReq.TaxRate,
+		}
+		if taxLine.TaxCode != nil {
+			item.TaxCodeID = &taxLine.TaxCode.ID
+			item.TaxRate = taxLine.TaxCode.EffectiveRate()
+		}
+		items = append(items, item)
+
+		if itemReq.Discount > maxManualDiscount {
+			maxManualDiscount = itemReq.Discount
+		}
 	}
 
+	// === Tax Calculation ===
+	var totalAmount, taxableAmount money.Money
+	taxes := services.CalculateTaxes(taxLines, pricingMode, rounding)
+	for i, line := range taxes.Lines {
+		items[i].TaxAmount = line.Tax
+		items[i].Total = line.Taxable.Add(line.Tax)
+		totalAmount = totalAmount.Add(line.Gross)
+	}
+	for _, tax := range taxes.Summary {
+		taxableAmount = taxableAmount.Add(tax.TaxableAmount)
+	}
+
+	// Totals come from the tax summary so the order reconciles with its printed breakdown
 	order.Items = items
+	order.TaxSummary = taxes.Summary
 	order.TotalAmount = totalAmount
-	order.DiscountAmount = discountAmount
-	order.TaxAmount = taxAmount
-	order.NetAmount = totalAmount - discountAmount + taxAmount
+	order.DiscountAmount = totalAmount.Sub(taxableAmount)
+	order.TaxAmount = taxes.TotalTax
+	order.NetAmount = taxableAmount.Add(taxes.TotalTax)
+	order.BaseNetAmount = services.ToBase(order.NetAmount, order.ExchangeRate)
 
-	// === Credit Limit Check ===
-	customer, err := uc.customerRepo.FindByID(req.CustomerID)
+	return maxManualDiscount, nil
+}
+
+// UpdateOrder edits a draft order in place; confirmed orders go through ReviseOrder
+func (uc *SalesUseCase) UpdateOrder(id uint, req *domain.UpdateOrderRequest, userID uint) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status == domain.OrderStatusConfirmed {
+		return nil, errors.New("confirmed orders must be changed through a revision")
+	}
+	if order.Status != domain.OrderStatusDraft {
+		return nil, fmt.Errorf("cannot edit an order that is %s", order.Status)
+	}
+	return uc.applyOrderEdit(order, req, userID, nil)
+}
+
+// ReviseOrder changes a confirmed order after keeping a snapshot of its current version
+func (uc *SalesUseCase) ReviseOrder(id uint, req *domain.UpdateOrderRequest, userID uint) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status == domain.OrderStatusDraft {
+		return nil, errors.New("draft orders are edited directly")
+	}
+	if order.Status != domain.OrderStatusConfirmed {
+		return nil, fmt.Errorf("cannot revise an order that is %s", order.Status)
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, errors.New("a reason is required to revise a confirmed order")
+	}
+
+	snapshot, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+	revision := &domain.SalesOrderRevision{
+		OrderID:   order.ID,
+		Revision:  order.Revision,
+		Snapshot:  string(snapshot),
+		NetAmount: order.NetAmount,
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedBy: userID,
+	}
+	order.Revision++
+
+	return uc.applyOrderEdit(order, req, userID, revision)
+}
+
+// applyOrderEdit reprices the order with the requested lines, keeping shipped quantities
+// of existing lines, and moves the customer balance by the change in the booked amount
+func (uc *SalesUseCase) applyOrderEdit(order *domain.SalesOrder, req *domain.UpdateOrderRequest, userID uint, revision *domain.SalesOrderRevision) (*domain.SalesOrder, error) {
+	customer, err := uc.customerRepo.FindByID(order.CustomerID)
 	if err != nil {
 		return nil, errors.New("customer not found")
 	}
 
-	if customer.CreditLimit > 0 && (customer.Balance+order.NetAmount) > customer.CreditLimit {
-		return nil, errors.New("credit limit exceeded for this customer")
+	existing := make(map[uint]domain.SalesOrderItem, len(order.Items))
+	for _, line := range order.Items {
+		existing[line.ID] = line
 	}
 
-	// Create Order
-	err = uc.salesRepo.Create(order)
+	// Only new or raised manual discounts are checked against the user's policy
+	var maxManualDiscount float64
+	kept := make(map[uint]bool, len(req.Items))
+	itemReqs := make([]domain.CreateOrderItemRequest, len(req.Items))
+	for i, itemReq := range req.Items {
+		itemReqs[i] = itemReq.CreateOrderItemRequest
+		if itemReq.ID == 0 {
+			maxManualDiscount = math.Max(maxManualDiscount, itemReq.Discount)
+			continue
+		}
+
+		line, ok := existing[itemReq.ID]
+		if !ok {
+			return nil, fmt.Errorf("item %d does not belong to order %s", itemReq.ID, order.OrderNumber)
+		}
+		if kept[itemReq.ID] {
+			return nil, fmt.Errorf("item %d appears more than once", itemReq.ID)
+		}
+		kept[itemReq.ID] = true
+		if itemReq.ProductID != line.ProductID {
+			return nil, fmt.Errorf("cannot change the product of item %d, remove the line and add a new one", itemReq.ID)
+		}
+		if itemReq.Quantity < line.ShippedQuantity {
+			return nil, fmt.Errorf("cannot reduce item %d below its shipped quantity of %g", itemReq.ID, line.ShippedQuantity)
+		}
+		if itemReq.Discount > line.Discount {
+			maxManualDiscount = math.Max(maxManualDiscount, itemReq.Discount)
+		}
+	}
+	for _, line := range order.Items {
+		if !kept[line.ID] && line.ShippedQuantity > 0 {
+			return nil, fmt.Errorf("cannot remove item %d, it has already been shipped", line.ID)
+		}
+	}
+
+	if policy := uc.discountPolicyFor(userID); policy != nil && maxManualDiscount > policy.MaxDiscountPercent {
+		return nil, fmt.Errorf("discount of %.2f%% exceeds the allowed %.2f%%", maxManualDiscount, policy.MaxDiscountPercent)
+	}
+
+	if req.DeliveryDate != nil {
+		order.DeliveryDate = req.DeliveryDate
+	}
+	if req.Notes != nil {
+		order.Notes = *req.Notes
+	}
+	if req.CouponCode != nil {
+		order.CouponCode = strings.ToUpper(strings.TrimSpace(*req.CouponCode))
+	}
+
+	previousBase := order.BaseNetAmount
+	if _, err := uc.priceOrder(order, customer, itemReqs); err != nil {
+		return nil, err
+	}
+
+	// Priced lines are new values; existing lines keep their identity and fulfilment
+	fullyShipped := true
+	for i, itemReq := range req.Items {
+		if line, ok := existing[itemReq.ID]; ok {
+			order.Items[i].ID = line.ID
+			order.Items[i].ShippedQuantity = line.ShippedQuantity
+			order.Items[i].DeliveredQuantity = line.DeliveredQuantity
+			order.Items[i].CreatedAt = line.CreatedAt
+		}
+		if order.Items[i].ShippedQuantity < order.Items[i].Quantity {
+			fullyShipped = false
+		}
+	}
+	var events []domain.Event
+	if fullyShipped && order.Status == domain.OrderStatusConfirmed {
+		order.Status = domain.OrderStatusShipped
+		events = append(events, statusChanged(order, domain.OrderStatusConfirmed, userID))
+	}
+
+	if order.NetAmount.LessThan(order.PaidAmount) {
+		return nil, errors.New("order total cannot fall below the amount already paid")
+	}
+
+	delta := order.BaseNetAmount.Sub(previousBase)
+	var credit services.CreditDecision
+	if delta.IsPositive() {
+		if credit = uc.checkCredit(customer, delta, order.ID); credit.Blocked {
+			return nil, errors.New(credit.Reason)
+		}
+	}
+
+	if !delta.IsZero() {
+		events = append(events, balanceChanged(order, delta, userID))
+	}
+	if err := uc.salesRepo.UpdateWithItems(order, revision, events...); err != nil {
+		return nil, err
+	}
+
+	if !delta.IsZero() {
+		previousBalance := customer.Balance
+		customer.Balance = customer.Balance.Add(delta)
+		uc.creditBooked(customer, previousBalance, order, credit, userID)
+	}
+
+	return uc.salesRepo.FindByID(order.ID)
+}
+
+func (uc *SalesUseCase) GetRevisions(orderID uint) ([]domain.SalesOrderRevision, error) {
+	return uc.salesRepo.FindRevisions(orderID)
+}
+
+// GetRevision returns the order as it was at the revision; the current revision is the live order
+func (uc *SalesUseCase) GetRevision(orderID uint, revision int) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if revision == order.Revision {
+		return order, nil
+	}
+
+	rev, err := uc.salesRepo.FindRevision(orderID, revision)
+	if err != nil {
+		return nil, fmt.Errorf("revision %d not found", revision)
+	}
+	var snapshot domain.SalesOrder
+	if err := json.Unmarshal([]byte(rev.Snapshot), &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// DiffRevisions compares two revisions of an order. Zero values default to the current
+// revision and the one before it.
+func (uc *SalesUseCase) DiffRevisions(orderID uint, from, to int) (*domain.SalesOrderDiff, error) {
+	order, err := uc.salesRepo.FindByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if to == 0 {
+		to = order.Revision
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 || to > order.Revision || from >= to {
+		return nil, fmt.Errorf("invalid revision range %d..%d, the order is at revision %d", from, to, order.Revision)
+	}
+
+	before, err := uc.GetRevision(orderID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := uc.GetRevision(orderID, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffOrders(before, after, from, to), nil
+}
+
+func diffOrders(before, after *domain.SalesOrder, from, to int) *domain.SalesOrderDiff {
+	diff := &domain.SalesOrderDiff{OrderID: after.ID, From: from, To: to, Fields: []domain.FieldChange{}, Items: []domain.OrderItemChange{}}
+
+	date := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+	diff.Fields = appendChanges(diff.Fields,
+		"status", before.Status, after.Status,
+		"delivery_date", date(before.DeliveryDate), date(after.DeliveryDate),
+		"coupon_code", before.CouponCode, after.CouponCode,
+		"notes", before.Notes, after.Notes,
+		"total_amount", before.TotalAmount.String(), after.TotalAmount.String(),
+		"discount_amount", before.DiscountAmount.String(), after.DiscountAmount.String(),
+		"tax_amount", before.TaxAmount.String(), after.TaxAmount.String(),
+		"net_amount", before.NetAmount.String(), after.NetAmount.String(),
+	)
+
+	remaining := make(map[uint]domain.SalesOrderItem, len(before.Items))
+	for _, line := range before.Items {
+		remaining[line.ID] = line
+	}
+	for _, line := range after.Items {
+		old, ok := remaining[line.ID]
+		if !ok {
+			diff.Items = append(diff.Items, domain.OrderItemChange{ItemID: line.ID, ProductID: line.ProductID, Change: domain.ItemChangeAdded})
+			continue
+		}
+		delete(remaining, line.ID)
+
+		fields := appendChanges(nil,
+			"quantity", formatQty(old.Quantity), formatQty(line.Quantity),
+			"unit_price", old.UnitPrice.String(), line.UnitPrice.String(),
+			"discount", formatQty(old.Discount), formatQty(line.Discount),
+			"promotion_discount", old.PromotionDiscount.String(), line.PromotionDiscount.String(),
+			"tax_rate", formatQty(old.TaxRate), formatQty(line.TaxRate),
+			"total", old.Total.String(), line.Total.String(),
+		)
+		if len(fields) > 0 {
+			diff.Items = append(diff.Items, domain.OrderItemChange{ItemID: line.ID, ProductID: line.ProductID, Change: domain.ItemChangeChanged, Fields: fields})
+		}
+	}
+	for _, line := range before.Items {
+		if _, ok := remaining[line.ID]; ok {
+			diff.Items = append(diff.Items, domain.OrderItemChange{ItemID: line.ID, ProductID: line.ProductID, Change: domain.ItemChangeRemoved})
+		}
+	}
+	return diff
+}
+
+// appendChanges takes (field, from, to) triples and appends those whose values differ
+func appendChanges(changes []domain.FieldChange, triples ...string) []domain.FieldChange {
+	for i := 0; i+2 < len(triples); i += 3 {
+		if triples[i+1] != triples[i+2] {
+			changes = append(changes, domain.FieldChange{Field: triples[i], From: triples[i+1], To: triples[i+2]})
+		}
+	}
+	return changes
+}
+
+// rateAt returns the exchange rate of the currency on the date, 1 for the base currency
+func (uc *SalesUseCase) rateAt(currency string, date time.Time) (float64, error) {
+	if uc.currencyService == nil {
+		if currency != money.DefaultCurrency {
+			return 0, errors.New("multi-currency is not configured")
+		}
+		return 1, nil
+	}
+	return uc.currencyService.RateAt(currency, date)
+}
+
+// eligiblePromotions returns the automatic promotions plus the coupon promotion valid for this order
+func (uc *SalesUseCase) eligiblePromotions(order *domain.SalesOrder, itemReqs []domain.CreateOrderItemRequest) ([]domain.Promotion, error) {
+	if uc.promotionRepo == nil {
+		return nil, nil
+	}
+
+	promotions, err := uc.promotionRepo.FindAutomatic()
+	if err != nil {
+		return nil, err
+	}
+
+	if order.CouponCode != "" {
+		coupon, err := uc.promotionRepo.FindByCode(order.CouponCode)
+		if err != nil {
+			return nil, errors.New("invalid coupon code")
+		}
+		if !coupon.IsValidAt(order.OrderDate) {
+			return nil, errors.New("coupon code is expired or inactive")
+		}
+		promotions = append(promotions, *coupon)
+	}
+
+	// Minimum order values are in the base currency
+	var gross money.Money
+	for _, item := range itemReqs {
+		gross = gross.Add(item.UnitPrice.MulFloat(item.Quantity))
+	}
+	gross = services.ToBase(gross, order.ExchangeRate)
+
+	var eligible []domain.Promotion
+	for _, p := range promotions {
+		if !p.IsValidAt(order.OrderDate) || gross.LessThan(p.MinOrderValue) {
+			if p.Code != nil && *p.Code == order.CouponCode {
+				return nil, fmt.Errorf("coupon requires a minimum order value of %s", p.MinOrderValue)
+			}
+			continue
+		}
+		eligible = append(eligible, p)
+	}
+
+	return eligible, nil
+}
+
+// discountPolicyFor returns the discount policy of the user's role, or nil when unrestricted
+func (uc *SalesUseCase) discountPolicyFor(userID uint) *domain.DiscountPolicy {
+	if uc.promotionRepo == nil || uc.userRepo == nil {
+		return nil
+	}
+
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil {
+		return nil
+	}
+
+	policy, err := uc.promotionRepo.FindPolicyByRoleID(user.RoleID)
+	if err != nil {
+		return nil
+	}
+	return policy
+}
+
+func (uc *SalesUseCase) notifyApprovers(order *domain.SalesOrder, policy *domain.DiscountPolicy, discount float64) {
+	if uc.notifRepo == nil {
+		return
+	}
+
+	approvers, err := uc.userRepo.FindByRoleID(policy.ApproverRoleID)
+	if err != nil {
+		return
+	}
+
+	for _, approver := range approvers {
+		notif := &domain.Notification{
+			UserID:  approver.ID,
+			Title:   "طلب موافقة على خصم: " + order.OrderNumber,
+			Message: fmt.Sprintf("Discount of %.2f%% exceeds the allowed %.2f%%", discount, policy.MaxDiscountPercent),
+			Type:    "warning",
+			Link:    fmt.Sprintf("/sales/%d", order.ID),
+		}
+		_ = uc.notifRepo.Create(notif)
+	}
+}
+
+// ErrNotApprover is returned when someone other than the approver role of an order's
+// discount policy decides on it, or its creator tries to
+var ErrNotApprover = errors.New("you are not allowed to approve this order")
+
+// checkApprover allows users with the approver role of the creator's discount policy,
+// other than the creator
+func (uc *SalesUseCase) checkApprover(order *domain.SalesOrder, approverID uint) error {
+	if approverID == 0 || approverID == order.CreatedBy {
+		return ErrNotApprover
+	}
+	approver, err := uc.userRepo.FindByID(approverID)
+	if err != nil || !approver.IsActive || approver.DeletedAt != nil {
+		return ErrNotApprover
+	}
+	roleID := uint(domain.RoleManager)
+	if policy := uc.discountPolicyFor(order.CreatedBy); policy != nil && policy.ApproverRoleID != 0 {
+		roleID = policy.ApproverRoleID
+	}
+	if approver.RoleID != roleID {
+		return ErrNotApprover
+	}
+	return nil
+}
+
+// ApproveOrder accepts the discounts of a pending order and books it to the customer.
+// An order that then fails credit control moves to the credit hold queue.
+func (uc *SalesUseCase) ApproveOrder(id uint, approverID uint, note string) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != domain.OrderStatusPendingApproval {
+		return nil, errors.New("order is not pending approval")
+	}
+	if err := uc.checkApprover(order, approverID); err != nil {
+		return nil, err
+	}
+
+	customer, err := uc.customerRepo.FindByID(order.CustomerID)
+	if err != nil {
+		return nil, errors.New("customer not found")
+	}
+	credit := uc.checkCredit(customer, order.BaseNetAmount, order.ID)
+
+	now := time.Now()
+	order.Status = domain.OrderStatusDraft
+	order.ApprovedBy = &approverID
+	order.ApprovedAt = &now
+	order.ApprovalNote = note
+	if credit.Blocked {
+		order.Status = domain.OrderStatusCreditHold
+		order.CreditNote = credit.Reason
+	}
+	events := []domain.Event{statusChanged(order, domain.OrderStatusPendingApproval, approverID)}
+	if !credit.Blocked {
+		events = append(events, balanceChanged(order, order.BaseNetAmount, approverID))
+	}
+	if err := uc.salesRepo.Update(order, events...); err != nil {
+		return nil, err
+	}
+
+	if credit.Blocked {
+		if uc.creditService != nil {
+			uc.creditService.OrderBlocked(customer, order)
+		}
+		uc.notifyCreator(order, "الطلب موقوف ائتمانياً: "+order.OrderNumber, "warning")
+		return order, nil
+	}
+
+	previousBalance := customer.Balance
+	customer.Balance = customer.Balance.Add(order.BaseNetAmount)
+	uc.creditBooked(customer, previousBalance, order, credit, approverID)
+
+	uc.notifyCreator(order, "تمت الموافقة على الطلب: "+order.OrderNumber, "success")
+	return order, nil
+}
+
+// RejectOrder cancels a pending order without affecting the customer balance
+func (uc *SalesUseCase) RejectOrder(id uint, approverID uint, note string) (*domain.SalesOrder, error) {
+	order, err := uc.salesRepo.FindByID(id)
 	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != domain.OrderStatusPendingApproval {
+		return nil, errors.New("order is not pending approval")
+	}
+	if err := uc.checkApprover(order, approverID); err != nil {
 		return nil, err
 	}
 
-	// Update Customer Balance
-	customer.Balance += order.NetAmount
-	_ = uc.customerRepo.Update(customer) // Ignore error? Or handle it? Ideally transactional.
+	now := time.Now()
+	order.Status = domain.OrderStatusCancelled
+	order.ApprovedBy = &approverID
+	order.ApprovedAt = &now
+	order.ApprovalNote = note
+	if err := uc.salesRepo.Update(order, statusChanged(order, domain.OrderStatusPendingApproval, approverID)); err != nil {
+		return nil, err
+	}
 
+	uc.notifyCreator(order, "تم رفض الطلب: "+order.OrderNumber, "error")
 	return order, nil
 }
 
+// RecordPayment books a payment in the order currency. The customer balance is relieved at the
+// order's booked rate and any difference to the payment-date rate is kept as realised FX.
+func (uc *SalesUseCase) RecordPayment(orderID uint, req *domain.RecordPaymentRequest, userID uint) (*domain.SalesPayment, error) {
+	order, err := uc.salesRepo.FindByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status == domain.OrderStatusPendingApproval || order.Status == domain.OrderStatusCreditHold || order.Status == domain.OrderStatusCancelled {
+		return nil, errors.New("payments can only be booked on approved orders")
+	}
+	if !req.Amount.IsPositive() {
+		return nil, errors.New("payment amount must be positive")
+	}
+	if req.Amount.GreaterThan(order.NetAmount.Sub(order.PaidAmount)) {
+		return nil, errors.New("payment exceeds the outstanding amount")
+	}
+
+	rate := req.ExchangeRate
+	if rate == 0 {
+		if rate, err = uc.rateAt(order.Currency, req.PaymentDate); err != nil {
+			return nil, err
+		}
+	}
+	if order.Currency == money.DefaultCurrency {
+		rate = 1
+	}
+
+	bookedRate := order.ExchangeRate
+	if bookedRate == 0 {
+		bookedRate = 1
+	}
+	carrying := services.ToBase(req.Amount, bookedRate)
+	if req.Amount == order.NetAmount.Sub(order.PaidAmount) {
+		// The final payment relieves exactly what is left of the booked base amount
+		carrying = order.BaseNetAmount
+		for _, p := range order.Payments {
+			carrying = carrying.Sub(p.BaseAmount.Sub(p.FXDifference))
+		}
+	}
+
+	payment := &domain.SalesPayment{
+		OrderID:      order.ID,
+		Amount:       req.Amount,
+		Currency:     order.Currency,
+		ExchangeRate: rate,
+		BaseAmount:   services.ToBase(req.Amount, rate),
+		PaymentDate:  req.PaymentDate,
+		Method:       req.Method,
+		Reference:    req.Reference,
+		CreatedBy:    userID,
+	}
+	payment.FXDifference = payment.BaseAmount.Sub(carrying)
+
+	order.PaidAmount = order.PaidAmount.Add(req.Amount)
+	received := domain.EventFunc(func() domain.Event {
+		return domain.PaymentReceived{
+			PaymentID:   payment.ID,
+			OrderID:     order.ID,
+			OrderNumber: order.OrderNumber,
+			CustomerID:  order.CustomerID,
+			Amount:      payment.Amount,
+			Currency:    payment.Currency,
+			Method:      payment.Method,
+			ReceivedBy:  userID,
+		}
+	})
+	if err := uc.salesRepo.CreatePayment(payment, order, received, balanceChanged(order, carrying.Neg(), userID)); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (uc *SalesUseCase) GetPayments(orderID uint) ([]domain.SalesPayment, error) {
+	return uc.salesRepo.FindPaymentsByOrderID(orderID)
+}
+
+// statusChanged is the event for an order that has just moved from the given status
+func statusChanged(order *domain.SalesOrder, from string, userID uint) domain.OrderStatusChanged {
+	return domain.OrderStatusChanged{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		CustomerID:  order.CustomerID,
+		From:        from,
+		To:          order.Status,
+		ChangedBy:   userID,
+	}
+}
+
+// balanceChanged is the event that moves the order's customer balance by amount
+func balanceChanged(order *domain.SalesOrder, amount money.Money, userID uint) domain.CustomerBalanceChanged {
+	return domain.CustomerBalanceChanged{
+		CustomerID: order.CustomerID,
+		OrderID:    order.ID,
+		Amount:     amount,
+		ChangedBy:  userID,
+	}
+}
+
+func (uc *SalesUseCase) notifyCreator(order *domain.SalesOrder, title, notifType string) {
+	if uc.notifRepo == nil || order.CreatedBy == 0 {
+		return
+	}
+	_ = uc.notifRepo.Create(&domain.Notification{
+		UserID:  order.CreatedBy,
+		Title:   title,
+		Message: order.ApprovalNote,
+		Type:    notifType,
+		Link:    fmt.Sprintf("/sales/%d", order.ID),
+	})
+}
+
 func (uc *SalesUseCase) GetOrders(page, limit int, status string, customerID uint) ([]domain.SalesOrder, int64, error) {
 	if page < 1 {
 		page = 1
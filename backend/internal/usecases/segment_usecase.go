@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+type SegmentUseCase struct {
+	segmentRepo  repositories.SegmentRepository
+	customerRepo repositories.CustomerRepository
+	notifService *services.NotificationService
+}
+
+func NewSegmentUseCase(segmentRepo repositories.SegmentRepository, customerRepo repositories.CustomerRepository, notifService *services.NotificationService) *SegmentUseCase {
+	return &SegmentUseCase{
+		segmentRepo:  segmentRepo,
+		customerRepo: customerRepo,
+		notifService: notifService,
+	}
+}
+
+func (uc *SegmentUseCase) CreateTag(req *domain.CreateTagRequest) (*domain.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("tag name is required")
+	}
+	if _, err := uc.segmentRepo.FindTagByName(name); err == nil {
+		return nil, errors.New("tag already exists")
+	}
+	tag := &domain.Tag{Name: name, Color: req.Color}
+	if err := uc.segmentRepo.CreateTag(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (uc *SegmentUseCase) GetTags() ([]domain.Tag, error) {
+	return uc.segmentRepo.FindAllTags()
+}
+
+func (uc *SegmentUseCase) DeleteTag(id uint) error {
+	if _, err := uc.segmentRepo.FindTagByID(id); err != nil {
+		return errors.New("tag not found")
+	}
+	return uc.segmentRepo.DeleteTag(id)
+}
+
+// TagCustomer adds tags to a customer by name, creating tags that don't exist yet
+func (uc *SegmentUseCase) TagCustomer(customerID uint, req *domain.CustomerTagsRequest) ([]domain.Tag, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil || customer.DeletedAt != nil {
+		return nil, errors.New("customer not found")
+	}
+
+	for _, name := range req.Tags {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tag, err := uc.segmentRepo.FindTagByName(name)
+		if err != nil {
+			tag = &domain.Tag{Name: name}
+			if err := uc.segmentRepo.CreateTag(tag); err != nil {
+				return nil, err
+			}
+		}
+		if err := uc.segmentRepo.AddCustomerTag(customerID, tag.ID); err != nil {
+			return nil, err
+		}
+	}
+	return uc.segmentRepo.FindCustomerTags(customerID)
+}
+
+func (uc *SegmentUseCase) UntagCustomer(customerID, tagID uint) error {
+	return uc.segmentRepo.RemoveCustomerTag(customerID, tagID)
+}
+
+func (uc *SegmentUseCase) GetCustomerTags(customerID uint) ([]domain.Tag, error) {
+	return uc.segmentRepo.FindCustomerTags(customerID)
+}
+
+// buildSegment validates a request into a segment
+func buildSegment(segment *domain.Segment, req *domain.SaveSegmentRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("segment name is required")
+	}
+	match := req.Match
+	if match == "" {
+		match = domain.SegmentMatchAll
+	}
+	if match != domain.SegmentMatchAll && match != domain.SegmentMatchAny {
+		return errors.New("match must be all or any")
+	}
+	if len(req.Rules) == 0 {
+		return errors.New("a segment needs at least one rule")
+	}
+	for _, rule := range req.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	segment.Name = name
+	segment.Description = req.Description
+	segment.Match = match
+	segment.Rules = req.Rules
+	return nil
+}
+
+func (uc *SegmentUseCase) CreateSegment(req *domain.SaveSegmentRequest, userID uint) (*domain.Segment, error) {
+	segment := &domain.Segment{CreatedBy: userID}
+	if err := buildSegment(segment, req); err != nil {
+		return nil, err
+	}
+	if err := uc.segmentRepo.CreateSegment(segment); err != nil {
+		return nil, err
+	}
+	segment.MemberCount, _ = uc.segmentRepo.CountMembers(segment)
+	return segment, nil
+}
+
+func (uc *SegmentUseCase) UpdateSegment(id uint, req *domain.SaveSegmentRequest) (*domain.Segment, error) {
+	segment, err := uc.segmentRepo.FindSegmentByID(id)
+	if err != nil {
+		return nil, errors.New("segment not found")
+	}
+	if err := buildSegment(segment, req); err != nil {
+		return nil, err
+	}
+	if err := uc.segmentRepo.UpdateSegment(segment); err != nil {
+		return nil, err
+	}
+	segment.MemberCount, _ = uc.segmentRepo.CountMembers(segment)
+	return segment, nil
+}
+
+func (uc *SegmentUseCase) DeleteSegment(id uint) error {
+	if _, err := uc.segmentRepo.FindSegmentByID(id); err != nil {
+		return errors.New("segment not found")
+	}
+	return uc.segmentRepo.DeleteSegment(id)
+}
+
+func (uc *SegmentUseCase) GetSegment(id uint) (*domain.Segment, error) {
+	segment, err := uc.segmentRepo.FindSegmentByID(id)
+	if err != nil {
+		return nil, errors.New("segment not found")
+	}
+	segment.MemberCount, err = uc.segmentRepo.CountMembers(segment)
+	return segment, err
+}
+
+// GetSegments lists segments with their current member counts
+func (uc *SegmentUseCase) GetSegments() ([]domain.Segment, error) {
+	segments, err := uc.segmentRepo.FindAllSegments()
+	if err != nil {
+		return nil, err
+	}
+	for i := range segments {
+		if segments[i].MemberCount, err = uc.segmentRepo.CountMembers(&segments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return segments, nil
+}
+
+// PreviewSegment counts the customers unsaved rules would match
+func (uc *SegmentUseCase) PreviewSegment(req *domain.SaveSegmentRequest) (int64, error) {
+	segment := &domain.Segment{}
+	if err := buildSegment(segment, req); err != nil {
+		return 0, err
+	}
+	return uc.segmentRepo.CountMembers(segment)
+}
+
+func (uc *SegmentUseCase) GetMembers(id uint, page, limit int) ([]domain.Customer, int64, error) {
+	segment, err := uc.segmentRepo.FindSegmentByID(id)
+	if err != nil {
+		return nil, 0, errors.New("segment not found")
+	}
+	return uc.segmentRepo.FindMembers(segment, page, limit)
+}
+
+// SendCampaign records a WhatsApp campaign to a segment and sends it in the background.
+// Members who disabled WhatsApp or have no phone number are skipped.
+func (uc *SegmentUseCase) SendCampaign(segmentID uint, req *domain.SendCampaignRequest, userID uint) (*domain.WhatsAppCampaign, error) {
+	if uc.notifService == nil {
+		return nil, errors.New("WhatsApp is not configured")
+	}
+	segment, err := uc.segmentRepo.FindSegmentByID(segmentID)
+	if err != nil {
+		return nil, errors.New("segment not found")
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return nil, errors.New("message is required")
+	}
+
+	campaign := &domain.WhatsAppCampaign{
+		SegmentID:   segment.ID,
+		SegmentName: segment.Name,
+		Message:     message,
+		Status:      domain.CampaignStatusSending,
+		CreatedBy:   userID,
+	}
+	if err := uc.segmentRepo.CreateCampaign(campaign); err != nil {
+		return nil, err
+	}
+
+	go uc.sendCampaign(*campaign, segment)
+	return campaign, nil
+}
+
+func (uc *SegmentUseCase) sendCampaign(campaign domain.WhatsAppCampaign, segment *domain.Segment) {
+	err := uc.segmentRepo.EachMember(segment, func(customer *domain.Customer) error {
+		campaign.Recipients++
+		phone := customer.Mobile
+		if phone == "" {
+			phone = customer.Phone
+		}
+		if !customer.IsWhatsAppEnabled || phone == "" {
+			campaign.SkippedCount++
+			return nil
+		}
+		text := strings.ReplaceAll(campaign.Message, "{name}", customer.Name)
+		if err := uc.notifService.SendWhatsApp(phone, text); err != nil {
+			log.Printf("⚠️ Campaign #%d failed for customer #%d: %v", campaign.ID, customer.ID, err)
+			campaign.FailedCount++
+			return nil
+		}
+		campaign.SentCount++
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Campaign #%d stopped: %v", campaign.ID, err)
+	}
+
+	now := time.Now()
+	campaign.Status = domain.CampaignStatusCompleted
+	campaign.CompletedAt = &now
+	if err := uc.segmentRepo.UpdateCampaign(&campaign); err != nil {
+		log.Printf("⚠️ Failed to save campaign #%d: %v", campaign.ID, err)
+	}
+}
+
+func (uc *SegmentUseCase) GetCampaigns(segmentID uint) ([]domain.WhatsAppCampaign, error) {
+	return uc.segmentRepo.FindCampaigns(segmentID)
+}
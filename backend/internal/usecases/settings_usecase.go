@@ -3,23 +3,79 @@ package usecases
 import (
 	"erp-system/internal/domain"
 	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/pkg/storage"
+	"strings"
+	"time"
 )
 
+// logoURLExpiry is how long a logo link stays valid; clients fetch settings again well before
+const logoURLExpiry = 24 * time.Hour
+
+// MaskedSecret stands in for a secret setting that is set. Posting it back, or an empty
+// value, keeps the stored one.
+const MaskedSecret = "********"
+
+// secretSettings are credentials that are never sent back in full
+var secretSettings = map[string]bool{
+	domain.SettingS3AccessKey:         true,
+	domain.SettingS3SecretKey:         true,
+	domain.SettingSMTPPassword:        true,
+	domain.SettingSMSToken:            true,
+	domain.SettingWhatsAppToken:       true,
+	domain.SettingWhatsAppAppSecret:   true,
+	domain.SettingWhatsAppVerifyToken: true,
+	domain.SettingEventWebhookSecret:  true,
+}
+
 type SettingsUseCase struct {
-	repo repositories.SettingsRepository
+	repo    repositories.SettingsRepository
+	storage *services.StorageService
 }
 
-func NewSettingsUseCase(repo repositories.SettingsRepository) *SettingsUseCase {
-	return &SettingsUseCase{repo: repo}
+func NewSettingsUseCase(repo repositories.SettingsRepository, storage *services.StorageService) *SettingsUseCase {
+	return &SettingsUseCase{repo: repo, storage: storage}
 }
 
 func (uc *SettingsUseCase) UpdateSettings(settings map[string]string) error {
 	for key, value := range settings {
+		// The link signing key never leaves the server
+		if key == domain.SettingStorageSigningKey {
+			continue
+		}
+		// A masked or blank secret is what the settings page shows; the stored one stays
+		if secretSettings[key] && (value == MaskedSecret || strings.TrimSpace(value) == "") {
+			continue
+		}
+
 		// Determine group based on key prefix or list
 		group := "general"
-		if key == domain.SettingWhatsAppURL || key == domain.SettingWhatsAppToken {
+		switch key {
+		case domain.SettingWhatsAppProvider, domain.SettingWhatsAppURL, domain.SettingWhatsAppToken,
+			domain.SettingWhatsAppPhoneNumberID, domain.SettingWhatsAppCloudURL,
+			domain.SettingWhatsAppAppSecret, domain.SettingWhatsAppVerifyToken, domain.SettingWhatsAppOptOut,
+			domain.SettingSMSURL, domain.SettingSMSToken, domain.SettingSMSSender,
+			domain.SettingSMTPHost, domain.SettingSMTPPort, domain.SettingSMTPUsername, domain.SettingSMTPPassword,
+			domain.SettingSMTPFrom, domain.SettingChannelOrder,
+			domain.SettingEventWebhookURL, domain.SettingEventWebhookSecret:
 			group = "integration"
 		}
+		if key == domain.SettingTaxPricingMode || key == domain.SettingTaxRounding {
+			group = "tax"
+		}
+		if key == domain.SettingPDFFont || key == domain.SettingPDFFontBold {
+			group = "documents"
+		}
+		if key == domain.SettingCreditWarningPercent || key == domain.SettingCreditGraceDays || key == domain.SettingCreditApproverRole {
+			group = "credit"
+		}
+		if key == domain.SettingExportMaxRows || key == domain.SettingDocumentMaxSizeMB || key == domain.SettingDocumentAllowedTypes {
+			group = "documents"
+		}
+		if key == domain.SettingStorageBackend || key == domain.SettingS3Endpoint || key == domain.SettingS3Region || key == domain.SettingS3Bucket ||
+			key == domain.SettingS3AccessKey || key == domain.SettingS3SecretKey || key == domain.SettingS3PathStyle {
+			group = "storage"
+		}
 
 		err := uc.repo.Set(key, value, group)
 		if err != nil {
@@ -37,7 +93,49 @@ func (uc *SettingsUseCase) GetSettings() (map[string]string, error) {
 
 	result := make(map[string]string)
 	for _, s := range settingsList {
-		result[s.Key] = s.Value
+		switch {
+		case s.Key == domain.SettingStorageSigningKey:
+		case secretSettings[s.Key] && s.Value != "":
+			result[s.Key] = MaskedSecret
+		default:
+			result[s.Key] = s.Value
+		}
 	}
 	return result, nil
 }
+
+// UploadLogo stores a new company logo and returns a link to it. The previous logo is
+// removed once the setting points at the new one.
+func (uc *SettingsUseCase) UploadLogo(filename string, data []byte) (string, error) {
+	info, err := uc.storage.CheckLogo(filename, data)
+	if err != nil {
+		return "", err
+	}
+	key, err := uc.storage.Save("settings", info, data)
+	if err != nil {
+		return "", err
+	}
+
+	previous, _ := uc.repo.Get(domain.SettingCompanyLogo)
+	if err := uc.repo.Set(domain.SettingCompanyLogo, key, "general"); err != nil {
+		uc.storage.Delete(key)
+		return "", err
+	}
+	if previous != nil && previous.Value != "" && previous.Value != key {
+		uc.storage.Delete(previous.Value)
+	}
+	return uc.LogoURL(), nil
+}
+
+// LogoURL returns a signed link to the company logo, or "" when there is none
+func (uc *SettingsUseCase) LogoURL() string {
+	logo, err := uc.repo.Get(domain.SettingCompanyLogo)
+	if err != nil || logo.Value == "" || uc.storage == nil {
+		return ""
+	}
+	url, err := uc.storage.URL(logo.Value, logoURLExpiry, storage.URLOptions{Inline: true})
+	if err != nil {
+		return ""
+	}
+	return url
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"errors"
+	"strings"
+)
+
+type TaxUseCase struct {
+	taxRepo repositories.TaxRepository
+}
+
+func NewTaxUseCase(repo repositories.TaxRepository) *TaxUseCase {
+	return &TaxUseCase{taxRepo: repo}
+}
+
+func (uc *TaxUseCase) CreateTaxCode(req *domain.CreateTaxCodeRequest) (*domain.TaxCode, error) {
+	code := &domain.TaxCode{
+		Code:      strings.ToUpper(strings.TrimSpace(req.Code)),
+		Name:      req.Name,
+		NameEn:    req.NameEn,
+		Type:      req.Type,
+		Rate:      req.Rate,
+		IsDefault: req.IsDefault,
+		IsActive:  true,
+	}
+	if code.Type == "" {
+		code.Type = domain.TaxTypeStandard
+	}
+	if code.Type != domain.TaxTypeStandard {
+		code.Rate = 0
+	}
+
+	if err := uc.taxRepo.Create(code); err != nil {
+		return nil, err
+	}
+	if code.IsDefault {
+		if err := uc.taxRepo.ClearDefault(code.ID); err != nil {
+			return nil, err
+		}
+	}
+	return code, nil
+}
+
+func (uc *TaxUseCase) UpdateTaxCode(id uint, req *domain.UpdateTaxCodeRequest) (*domain.TaxCode, error) {
+	code, err := uc.taxRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("tax code not found")
+	}
+
+	if req.Name != "" {
+		code.Name = req.Name
+	}
+	if req.NameEn != "" {
+		code.NameEn = req.NameEn
+	}
+	if req.Type != "" {
+		code.Type = req.Type
+	}
+	if req.Rate != nil {
+		code.Rate = *req.Rate
+	}
+	if req.IsDefault != nil {
+		code.IsDefault = *req.IsDefault
+	}
+	if req.IsActive != nil {
+		code.IsActive = *req.IsActive
+	}
+	if code.Type != domain.TaxTypeStandard {
+		code.Rate = 0
+	}
+
+	if err := uc.taxRepo.Update(code); err != nil {
+		return nil, err
+	}
+	if code.IsDefault {
+		if err := uc.taxRepo.ClearDefault(code.ID); err != nil {
+			return nil, err
+		}
+	}
+	return code, nil
+}
+
+func (uc *TaxUseCase) DeleteTaxCode(id uint) error {
+	return uc.taxRepo.Delete(id)
+}
+
+func (uc *TaxUseCase) GetTaxCode(id uint) (*domain.TaxCode, error) {
+	return uc.taxRepo.FindByID(id)
+}
+
+func (uc *TaxUseCase) GetTaxCodes() ([]domain.TaxCode, error) {
+	return uc.taxRepo.FindAll()
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"encoding/base64"
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimelineLimit = 20
+	maxTimelineLimit     = 100
+)
+
+type TimelineUseCase struct {
+	timelineRepo repositories.TimelineRepository
+	customerRepo repositories.CustomerRepository
+}
+
+func NewTimelineUseCase(timelineRepo repositories.TimelineRepository, customerRepo repositories.CustomerRepository) *TimelineUseCase {
+	return &TimelineUseCase{timelineRepo: timelineRepo, customerRepo: customerRepo}
+}
+
+// encodeTimelineCursor makes an opaque cursor from the last entry of a page
+func encodeTimelineCursor(e domain.TimelineEntry) string {
+	raw := fmt.Sprintf("%d|%s|%d", e.OccurredAt.UnixNano(), e.Source, e.RefID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTimelineCursor(cursor string) (*domain.TimelineCursor, error) {
+	invalid := errors.New("invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, invalid
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, invalid
+	}
+	return &domain.TimelineCursor{At: time.Unix(0, nanos), Source: parts[1], ID: uint(id)}, nil
+}
+
+// timelineTypes validates a type filter; an empty filter means every type
+func timelineTypes(filter []string) ([]string, error) {
+	if len(filter) == 0 {
+		return domain.TimelineTypes, nil
+	}
+	var types []string
+	for _, t := range filter {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		known := false
+		for _, k := range domain.TimelineTypes {
+			known = known || k == t
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown timeline type %q, use %s", t, strings.Join(domain.TimelineTypes, ", "))
+		}
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		return domain.TimelineTypes, nil
+	}
+	return types, nil
+}
+
+// GetTimeline returns a page of a customer's history, newest first, merged from activities,
+// documents, orders, status changes, payments and notifications. The first page also carries
+// the customer's summary figures.
+func (uc *TimelineUseCase) GetTimeline(customerID uint, cursor string, limit int, types []string) (*domain.CustomerTimeline, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil || customer.DeletedAt != nil {
+		return nil, errors.New("customer not found")
+	}
+	types, err = timelineTypes(types)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultTimelineLimit
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	var after *domain.TimelineCursor
+	if cursor != "" {
+		if after, err = decodeTimelineCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each source returns one extra entry so a further page can be detected
+	entries, err := uc.timelineRepo.FindEntries(customerID, types, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Before(entries[j]) })
+
+	timeline := &domain.CustomerTimeline{Entries: entries}
+	if len(entries) > limit {
+		timeline.Entries = entries[:limit]
+		timeline.NextCursor = encodeTimelineCursor(entries[limit-1])
+	}
+	if timeline.Entries == nil {
+		timeline.Entries = []domain.TimelineEntry{}
+	}
+
+	if after == nil {
+		if timeline.Summary, err = uc.timelineRepo.GetSummary(customerID); err != nil {
+			return nil, err
+		}
+	}
+	return timeline, nil
+}
+
+func (uc *TimelineUseCase) GetSummary(customerID uint) (*domain.CustomerSummary, error) {
+	customer, err := uc.customerRepo.FindByID(customerID)
+	if err != nil || customer.DeletedAt != nil {
+		return nil, errors.New("customer not found")
+	}
+	return uc.timelineRepo.GetSummary(customerID)
+}
@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"erp-system/internal/usecases"
+	"log"
+	"time"
+)
+
+// StartCreditWorker places and lifts overdue credit holds and retries blocked orders every hour
+func StartCreditWorker(creditUseCase *usecases.CreditUseCase) {
+	log.Println("💳 Credit Worker Started...")
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for range ticker.C {
+			result, err := creditUseCase.EvaluateCredit()
+			if err != nil {
+				log.Println("❌ Worker Error evaluating credit:", err)
+				continue
+			}
+			if result.HoldsPlaced+result.HoldsReleased+result.OrdersReleased > 0 {
+				log.Printf("💳 Credit holds placed: %d, released: %d, orders released: %d", result.HoldsPlaced, result.HoldsReleased, result.OrdersReleased)
+			}
+		}
+	}()
+}
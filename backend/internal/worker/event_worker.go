@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"erp-system/internal/services"
+	"log"
+	"time"
+)
+
+// StartEventWorker hands committed domain events to their subscribers every few seconds
+func StartEventWorker(bus *services.EventBus) {
+	log.Println("📣 Event Worker Started...")
+	ticker := time.NewTicker(2 * time.Second)
+	go func() {
+		for range ticker.C {
+			DrainEvents(bus)
+		}
+	}()
+}
+
+// DrainEvents delivers the events that are due until none are left and returns how many
+// were handled. Events a subscriber fails are rescheduled and not retried in the same run.
+func DrainEvents(bus *services.EventBus) int {
+	handled := 0
+	for {
+		n, err := bus.ProcessDue(100)
+		if err != nil {
+			log.Println("❌ Worker Error processing events:", err)
+			return handled
+		}
+		if n == 0 {
+			return handled
+		}
+		handled += n
+	}
+}
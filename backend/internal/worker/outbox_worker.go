@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/usecases"
+	"log"
+	"sync"
+	"time"
+)
+
+// StartOutboxWorker delivers queued customer messages every few seconds with a pool of workers
+func StartOutboxWorker(outbox *usecases.OutboxUseCase, workers int) {
+	log.Println("📤 Outbox Worker Started...")
+	ticker := time.NewTicker(5 * time.Second)
+	go func() {
+		for range ticker.C {
+			DrainOutbox(outbox, workers)
+		}
+	}()
+}
+
+// DrainOutbox delivers the messages that are due, workers at a time, until none are left,
+// and returns how many attempts were made. Messages that fail are rescheduled and not
+// retried in the same run.
+func DrainOutbox(outbox *usecases.OutboxUseCase, workers int) int {
+	if workers < 1 {
+		workers = 1
+	}
+	attempts := 0
+	for {
+		messages, err := outbox.ClaimDue(workers * 10)
+		if err != nil {
+			log.Println("❌ Worker Error claiming outbox messages:", err)
+			return attempts
+		}
+		if len(messages) == 0 {
+			return attempts
+		}
+
+		jobs := make(chan *domain.OutboundMessage)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for msg := range jobs {
+					if err := outbox.Deliver(msg); err != nil {
+						log.Printf("⚠️ Failed to record delivery of message #%d: %v", msg.ID, err)
+					}
+				}
+			}()
+		}
+		for i := range messages {
+			jobs <- &messages[i]
+		}
+		close(jobs)
+		wg.Wait()
+		attempts += len(messages)
+	}
+}
@@ -3,6 +3,8 @@ package worker
 import (
 	"erp-system/internal/domain"
 	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"fmt"
 	"log"
 	"time"
 
@@ -10,22 +12,23 @@ import (
 )
 
 // StartReminderWorker starts a background goroutine that checks for reminders every minute
-func StartReminderWorker(db *gorm.DB, notifService *services.NotificationService) {
+func StartReminderWorker(db *gorm.DB, outbox *usecases.OutboxUseCase, dispatcher *services.NotificationDispatcher) {
 	log.Println("⏰ Reminder Worker Started...")
 	ticker := time.NewTicker(1 * time.Minute)
 	go func() {
 		for range ticker.C {
-			processReminders(db, notifService)
+			ProcessReminders(db, outbox, dispatcher)
 		}
 	}()
 }
 
-func processReminders(db *gorm.DB, notifService *services.NotificationService) {
+// ProcessReminders delivers the reminders that are due
+func ProcessReminders(db *gorm.DB, outbox *usecases.OutboxUseCase, dispatcher *services.NotificationDispatcher) {
 	var activities []domain.CustomerActivity
 	now := time.Now()
 
-	// Find pending reminders due now or in the past
-	err := db.Where("type = ? AND is_completed = ? AND reminder_date <= ?", "reminder", false, now).
+	// Find open activities whose reminder is due and not yet delivered
+	err := db.Where("is_completed = ? AND notification_enabled = ? AND notified_at IS NULL AND reminder_date <= ?", false, true, now).
 		Preload("Customer").
 		Find(&activities).Error
 
@@ -35,28 +38,35 @@ func processReminders(db *gorm.DB, notifService *services.NotificationService) {
 	}
 
 	for _, act := range activities {
+		if act.Customer == nil {
+			continue
+		}
 		log.Printf("🔔 Processing reminder #%d: %s for Customer: %s", act.ID, act.Description, act.Customer.Name)
 
-		// 1. Send WhatsApp to Customer
-		// Only if customer has phone and api is configured
-		if act.Customer.Phone != "" {
-			err := notifService.SendWhatsApp(act.Customer.Phone, "تذكير: "+act.Description)
-			if err != nil {
-				log.Printf("⚠️ Failed to send WhatsApp for reminder #%d: %v", act.ID, err)
-			}
+		// 1. Queue a message to the customer for customer-facing reminders, on their preferred channel
+		// Only if a configured channel can reach them; the key keeps a rescheduled reminder apart
+		if act.Type == "reminder" {
+			vars := map[string]string{"description": act.Description, "reminder_date": act.ReminderDate.Format("2006-01-02 15:04")}
+			key := fmt.Sprintf("reminder:%d:%d", act.ID, act.ReminderDate.Unix())
+			outbox.NotifyCustomer(act.Customer, domain.TemplateActivityReminder, nil, vars, domain.MessageSourceReminder, key, act.CreatedBy)
 		}
 
-		// 2. Create Internal Notification for Admin (UserID 1)
-		notif := domain.Notification{
-			UserID:  1, // TODO: Assign to the user who created the activity
-			Title:   "تذكير مستحق: " + act.Customer.Name,
-			Message: act.Description,
-			Type:    "warning",
-			IsRead:  false,
+		// 2. Tell the assignee, or the creator if unassigned, on the channels they chose
+		recipient := act.CreatedBy
+		if act.AssignedTo != nil {
+			recipient = *act.AssignedTo
 		}
-		db.Create(&notif)
+		dispatcher.Dispatch(domain.NotificationEvent{
+			Type:      domain.EventReminderDue,
+			Title:     "تذكير مستحق: " + act.Customer.Name,
+			Message:   act.Description,
+			Link:      fmt.Sprintf("/customers/%d", act.CustomerID),
+			NotifType: "warning",
+			Audience:  []uint{recipient},
+			Key:       fmt.Sprintf("%d:%d", act.ID, act.ReminderDate.Unix()),
+		})
 
-		// 3. Mark as Completed to avoid repeat
-		db.Model(&act).Update("is_completed", true)
+		// 3. Mark as delivered to avoid repeat; the activity stays open until completed
+		db.Model(&domain.CustomerActivity{}).Where("id = ?", act.ID).Update("notified_at", now)
 	}
 }
@@ -0,0 +1,47 @@
+-- Migration: Store monetary amounts as integer minor units
+-- Date: 2026-10-19
+-- Purpose: Replace REAL major-unit amounts with fixed-point piastres so totals reconcile exactly
+-- Note: The server applies the same conversion on startup (pkg/database), guarded by the
+--       schema_money_minor_units system setting. Run this file only on databases migrated by hand.
+
+-- ============================================================================
+-- CUSTOMERS
+-- ============================================================================
+
+UPDATE customers SET
+    credit_limit = CAST(ROUND(credit_limit * 100) AS INTEGER),
+    balance      = CAST(ROUND(balance * 100) AS INTEGER);
+
+-- ============================================================================
+-- SALES ORDERS
+-- ============================================================================
+
+UPDATE sales_orders SET
+    total_amount    = CAST(ROUND(total_amount * 100) AS INTEGER),
+    tax_amount      = CAST(ROUND(tax_amount * 100) AS INTEGER),
+    discount_amount = CAST(ROUND(discount_amount * 100) AS INTEGER),
+    net_amount      = CAST(ROUND(net_amount * 100) AS INTEGER);
+
+UPDATE sales_order_items SET
+    unit_price         = CAST(ROUND(unit_price * 100) AS INTEGER),
+    promotion_discount = CAST(ROUND(promotion_discount * 100) AS INTEGER),
+    tax_amount         = CAST(ROUND(tax_amount * 100) AS INTEGER),
+    total              = CAST(ROUND(total * 100) AS INTEGER);
+
+UPDATE sales_order_taxes SET
+    taxable_amount = CAST(ROUND(taxable_amount * 100) AS INTEGER),
+    tax_amount     = CAST(ROUND(tax_amount * 100) AS INTEGER);
+
+-- ============================================================================
+-- PRODUCTS & PROMOTIONS
+-- ============================================================================
+
+UPDATE products SET
+    cost_price    = CAST(ROUND(cost_price * 100) AS INTEGER),
+    selling_price = CAST(ROUND(selling_price * 100) AS INTEGER);
+
+UPDATE promotions SET
+    min_order_value = CAST(ROUND(min_order_value * 100) AS INTEGER);
+
+INSERT INTO system_settings (key, value, "group", updated_at)
+VALUES ('schema_money_minor_units', 'true', 'system', CURRENT_TIMESTAMP);
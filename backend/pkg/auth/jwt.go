@@ -10,11 +10,18 @@ import (
 
 var jwtSecret = []byte("your-super-secret-key-change-this-in-production")
 
+// Token types, so a refresh token cannot be used where an access token is expected
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 // Claims represents JWT claims
 type Claims struct {
 	UserID uint   `json:"user_id"`
 	Email  string `json:"email"`
 	RoleID uint   `json:"role_id"`
+	Type   string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
@@ -24,6 +31,7 @@ func GenerateAccessToken(userID uint, email string, roleID uint) (string, error)
 		UserID: userID,
 		Email:  email,
 		RoleID: roleID,
+		Type:   TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -39,6 +47,7 @@ func GenerateRefreshToken(userID uint) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		UserID: userID,
+		Type:   TokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(168 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -76,6 +85,7 @@ func GenerateRefreshTokenWithExpiry(userID uint, expiresAt time.Time) (string, e
 	now := time.Now()
 	claims := Claims{
 		UserID: userID,
+		Type:   TokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
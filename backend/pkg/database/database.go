@@ -2,6 +2,7 @@ package database
 
 import (
 	"erp-system/internal/domain"
+	"erp-system/pkg/money"
 	"log"
 	"time"
 
@@ -77,6 +78,9 @@ func Connect(dbPath string) (*gorm.DB, error) {
 		&domain.Customer{},
 		&domain.SalesOrder{},
 		&domain.SalesOrderItem{},
+		&domain.SalesOrderTax{},
+		&domain.SalesOrderRevision{},
+		&domain.TaxCode{},
 		&domain.Product{},
 		&domain.Category{},
 		&domain.Warehouse{},
@@ -87,10 +91,44 @@ func Connect(dbPath string) (*gorm.DB, error) {
 		&domain.CustomerDocument{},
 		&domain.SystemSetting{},
 		&domain.Notification{},
+		&domain.NotificationSubscription{},
+		&domain.NotificationPreference{},
+		&domain.Promotion{},
+		&domain.DiscountPolicy{},
+		&domain.ExchangeRate{},
+		&domain.SalesPayment{},
+		&domain.CreditOverride{},
+		&domain.CreditEvent{},
+		&domain.CustomerMerge{},
+		&domain.FollowUpCadence{},
+		&domain.ImportJob{},
+		&domain.Tag{},
+		&domain.Segment{},
+		&domain.WhatsAppCampaign{},
+		&domain.OutboundMessage{},
+		&domain.MessageDelivery{},
+		&domain.MessageTemplate{},
+		&domain.WarehouseStock{},
+		&domain.DeliveryNote{},
+		&domain.DeliveryNoteItem{},
+		&domain.DomainEvent{},
 	); err != nil {
 		return nil, err
 	}
 
+	// Convert legacy float amounts to integer minor units
+	if err := migrateMoneyToMinorUnits(db); err != nil {
+		return nil, err
+	}
+
+	// Orders created before multi-currency are in the base currency
+	db.Model(&domain.SalesOrder{}).
+		Where("base_net_amount = 0 AND net_amount <> 0 AND (currency = ? OR currency IS NULL)", money.DefaultCurrency).
+		Update("base_net_amount", gorm.Expr("net_amount"))
+
+	// Documents uploaded before versioning are their own first version
+	db.Model(&domain.CustomerDocument{}).Where("group_id = 0 OR group_id IS NULL").Update("group_id", gorm.Expr("id"))
+
 	// Seed default data
 	seedDefaultData(db)
 
@@ -98,6 +136,39 @@ func Connect(dbPath string) (*gorm.DB, error) {
 	return db, nil
 }
 
+// moneyColumns lists every column holding a money.Money amount
+var moneyColumns = map[string][]string{
+	"customers":         {"credit_limit", "balance"},
+	"sales_orders":      {"total_amount", "tax_amount", "discount_amount", "net_amount"},
+	"sales_order_items": {"unit_price", "promotion_discount", "tax_amount", "total"},
+	"sales_order_taxes": {"taxable_amount", "tax_amount"},
+	"products":          {"cost_price", "selling_price"},
+	"promotions":        {"min_order_value"},
+}
+
+// migrateMoneyToMinorUnits rewrites amounts stored as REAL major units into integer
+// minor units. It runs once per database, guarded by a system setting marker.
+func migrateMoneyToMinorUnits(db *gorm.DB) error {
+	var count int64
+	db.Model(&domain.SystemSetting{}).Where("key = ?", domain.SettingMoneyMinorUnits).Count(&count)
+	if count > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for table, columns := range moneyColumns {
+			for _, column := range columns {
+				sql := "UPDATE " + table + " SET " + column + " = CAST(ROUND(" + column + " * 100) AS INTEGER) WHERE " + column + " IS NOT NULL"
+				if err := tx.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+		}
+		log.Println("✅ Monetary amounts converted to minor units")
+		return tx.Create(&domain.SystemSetting{Key: domain.SettingMoneyMinorUnits, Value: "true", Group: "system"}).Error
+	})
+}
+
 func seedDefaultData(db *gorm.DB) {
 	// Check if roles exist
 	var count int64
@@ -125,4 +196,18 @@ func seedDefaultData(db *gorm.DB) {
 		db.Create(&adminUser)
 		log.Println("✅ Default admin user created (email: admin@erp.local, password: admin123)")
 	}
+
+	// Check if tax codes exist
+	db.Model(&domain.TaxCode{}).Count(&count)
+
+	// None is the default, so orders keep their own tax_rate until one is chosen
+	if count == 0 {
+		taxCodes := []domain.TaxCode{
+			{Code: "VAT14", Name: "ضريبة القيمة المضافة 14%", NameEn: "VAT 14%", Type: domain.TaxTypeStandard, Rate: 14, IsActive: true},
+			{Code: "EXEMPT", Name: "معفى", NameEn: "Exempt", Type: domain.TaxTypeExempt, IsActive: true},
+			{Code: "ZERO", Name: "خاضع بنسبة صفر", NameEn: "Zero-rated", Type: domain.TaxTypeZeroRated, IsActive: true},
+		}
+		db.Create(&taxCodes)
+		log.Println("✅ Default tax codes created")
+	}
 }
@@ -0,0 +1,114 @@
+package messaging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of an inbound webhook body, as "sha256=<hex>"
+const SignatureHeader = "X-Hub-Signature-256"
+
+// InboundMessage is a message a customer sent us
+type InboundMessage struct {
+	ID         string // Provider message ID, used to ignore redelivered webhooks
+	From       string // Sender phone number as the provider reports it
+	Body       string
+	ReceivedAt time.Time
+}
+
+// VerifySignature checks a SignatureHeader value against the HMAC-SHA256 of body keyed with
+// secret. An empty secret never verifies.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// Sign returns the SignatureHeader value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseWhatsAppCloud reads the text messages out of a WhatsApp Cloud API webhook. Status
+// updates and non-text messages are skipped; media and locations keep their caption if any.
+func ParseWhatsAppCloud(body []byte) ([]InboundMessage, error) {
+	var payload struct {
+		Entry []struct {
+			Changes []struct {
+				Value struct {
+					Messages []struct {
+						ID        string `json:"id"`
+						From      string `json:"from"`
+						Timestamp string `json:"timestamp"`
+						Type      string `json:"type"`
+						Text      struct {
+							Body string `json:"body"`
+						} `json:"text"`
+						Button struct {
+							Text string `json:"text"`
+						} `json:"button"`
+						Image struct {
+							Caption string `json:"caption"`
+						} `json:"image"`
+					} `json:"messages"`
+				} `json:"value"`
+			} `json:"changes"`
+		} `json:"entry"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, errors.New("invalid WhatsApp webhook payload")
+	}
+
+	var messages []InboundMessage
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			for _, m := range change.Value.Messages {
+				text := m.Text.Body
+				if text == "" {
+					text = m.Button.Text
+				}
+				if text == "" {
+					text = m.Image.Caption
+				}
+				if text == "" {
+					text = "[" + m.Type + "]"
+				}
+				received := time.Now()
+				if ts, err := strconv.ParseInt(m.Timestamp, 10, 64); err == nil {
+					received = time.Unix(ts, 0)
+				}
+				messages = append(messages, InboundMessage{ID: m.ID, From: m.From, Body: text, ReceivedAt: received})
+			}
+		}
+	}
+	return messages, nil
+}
+
+// ParseWebhook reads a {"phone", "message", "id"} message, the format Webhook sends, posted
+// back by a gateway or automation tool
+func ParseWebhook(body []byte) ([]InboundMessage, error) {
+	var payload struct {
+		ID      string `json:"id"`
+		Phone   string `json:"phone"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Phone == "" {
+		return nil, errors.New("invalid webhook payload, expected phone and message")
+	}
+	return []InboundMessage{{ID: payload.ID, From: payload.Phone, Body: payload.Message, ReceivedAt: time.Now()}}, nil
+}
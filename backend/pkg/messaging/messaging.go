@@ -0,0 +1,65 @@
+// Package messaging delivers text messages to customers over pluggable channels: the
+// WhatsApp Cloud API, a generic HTTP webhook, SMTP email and an SMS gateway.
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var ErrNoRecipient = errors.New("messaging: no recipient")
+
+// Message is a single text message. Subject is only used by channels that have one.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Channel sends messages over one transport
+type Channel interface {
+	Name() string
+	Send(msg Message) error
+}
+
+// postJSON sends payload with an optional bearer token and fails on error statuses
+func postJSON(client *http.Client, url, token string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if msg := strings.TrimSpace(string(detail)); msg != "" {
+			return fmt.Errorf("API returned error status: %s: %s", resp.Status, msg)
+		}
+		return fmt.Errorf("API returned error status: %s", resp.Status)
+	}
+	return nil
+}
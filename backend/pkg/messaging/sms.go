@@ -0,0 +1,38 @@
+package messaging
+
+import (
+	"errors"
+	"net/http"
+)
+
+// SMS sends text messages through an HTTP SMS gateway that accepts
+// {"to", "from", "message"} JSON with a bearer token
+type SMS struct {
+	URL    string
+	Token  string
+	Sender string // Sender ID or number, if the gateway needs one
+	Client *http.Client
+}
+
+func (s *SMS) Name() string { return "sms" }
+
+func (s *SMS) Send(msg Message) error {
+	if s.URL == "" {
+		return errors.New("SMS gateway URL not configured")
+	}
+	to := normalizePhone(msg.To)
+	if to == "" {
+		return ErrNoRecipient
+	}
+	payload := map[string]string{
+		"to":      to,
+		"message": msg.Body,
+	}
+	if s.Sender != "" {
+		payload["from"] = s.Sender
+	}
+	if err := postJSON(s.Client, s.URL, s.Token, payload); err != nil {
+		return errors.New("SMS " + err.Error())
+	}
+	return nil
+}
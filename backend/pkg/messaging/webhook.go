@@ -0,0 +1,42 @@
+package messaging
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Webhook posts messages as {"phone", "message"} JSON to a URL, the format of most
+// WhatsApp gateway providers and of automation tools such as n8n
+type Webhook struct {
+	ChannelName string // Name reported for the channel, default "webhook"
+	URL         string
+	Token       string // Sent as a bearer token when set
+	Client      *http.Client
+}
+
+func (w *Webhook) Name() string {
+	if w.ChannelName != "" {
+		return w.ChannelName
+	}
+	return "webhook"
+}
+
+func (w *Webhook) Send(msg Message) error {
+	if w.URL == "" {
+		return errors.New("webhook URL not configured")
+	}
+	if msg.To == "" {
+		return ErrNoRecipient
+	}
+	payload := map[string]string{
+		"phone":   msg.To,
+		"message": msg.Body,
+	}
+	if msg.Subject != "" {
+		payload["subject"] = msg.Subject
+	}
+	if err := postJSON(w.Client, w.URL, w.Token, payload); err != nil {
+		return errors.New("webhook " + err.Error())
+	}
+	return nil
+}
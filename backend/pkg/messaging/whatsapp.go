@@ -0,0 +1,58 @@
+package messaging
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// DefaultWhatsAppCloudURL is the Graph API base used when no endpoint is configured
+const DefaultWhatsAppCloudURL = "https://graph.facebook.com/v19.0"
+
+// WhatsAppCloud sends text messages through Meta's WhatsApp Cloud API
+type WhatsAppCloud struct {
+	Endpoint      string // Graph API base URL, default DefaultWhatsAppCloudURL
+	PhoneNumberID string // The business phone number messages are sent from
+	Token         string // Permanent or system user access token
+	Client        *http.Client
+}
+
+func (c *WhatsAppCloud) Name() string { return "whatsapp" }
+
+func (c *WhatsAppCloud) Send(msg Message) error {
+	if c.PhoneNumberID == "" || c.Token == "" {
+		return errors.New("WhatsApp Cloud API configuration incomplete")
+	}
+	to := normalizePhone(msg.To)
+	if to == "" {
+		return ErrNoRecipient
+	}
+	endpoint := strings.TrimRight(c.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultWhatsAppCloudURL
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "text",
+		"text":              map[string]interface{}{"body": msg.Body, "preview_url": false},
+	}
+	if err := postJSON(c.Client, endpoint+"/"+c.PhoneNumberID+"/messages", c.Token, payload); err != nil {
+		return errors.New("WhatsApp " + err.Error())
+	}
+	return nil
+}
+
+// normalizePhone keeps the digits of a phone number, the format the Cloud API and most
+// SMS gateways expect (country code included, no + or spaces)
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the company base currency
+const DefaultCurrency = "EGP"
+
+// Money is a fixed-point amount stored in minor units (piastres, cents). It carries no
+// currency; records keep theirs next to their amounts. It is persisted as an integer column
+// holding the minor units and serialised to JSON as a decimal number with two places,
+// e.g. 1234.50.
+type Money struct {
+	Minor int64
+}
+
+// Zero is an empty amount
+var Zero = Money{}
+
+// FromMinor creates an amount from minor units
+func FromMinor(minor int64) Money {
+	return Money{Minor: minor}
+}
+
+// FromFloat converts a major-unit float, rounding half away from zero to the nearest minor unit
+func FromFloat(v float64) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'f', -1, 64))
+	if !ok {
+		return Zero
+	}
+	return Money{Minor: roundRat(r.Mul(r, big.NewRat(100, 1)))}
+}
+
+// Parse reads a decimal string in major units such as "1234.5" or "-0.75"
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Zero, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("money: invalid amount %q", s)
+	}
+	return Money{Minor: roundRat(r.Mul(r, big.NewRat(100, 1)))}, nil
+}
+
+// Sum adds amounts together
+func Sum(amounts ...Money) Money {
+	total := Zero
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) Money {
+	return Money{Minor: m.Minor + o.Minor}
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) Money {
+	return Money{Minor: m.Minor - o.Minor}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Minor: -m.Minor}
+}
+
+// MulFloat multiplies by a quantity, rounding half away from zero
+func (m Money) MulFloat(f float64) Money {
+	return m.Ratio(f, 1)
+}
+
+// Percent returns p percent of m, rounding half away from zero
+func (m Money) Percent(p float64) Money {
+	return m.Ratio(p, 100)
+}
+
+// Ratio returns m * num / den computed exactly, then rounded half away from zero
+func (m Money) Ratio(num, den float64) Money {
+	n, ok1 := new(big.Rat).SetString(strconv.FormatFloat(num, 'f', -1, 64))
+	d, ok2 := new(big.Rat).SetString(strconv.FormatFloat(den, 'f', -1, 64))
+	if !ok1 || !ok2 || d.Sign() == 0 {
+		return Zero
+	}
+	r := new(big.Rat).SetInt64(m.Minor)
+	r.Mul(r, n).Quo(r, d)
+	return Money{Minor: roundRat(r)}
+}
+
+// Div divides by a count, rounding half away from zero
+func (m Money) Div(n int64) Money {
+	if n == 0 {
+		return Zero
+	}
+	return Money{Minor: roundRat(big.NewRat(m.Minor, n))}
+}
+
+// Cmp compares two amounts and returns -1, 0 or 1
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.Minor < o.Minor:
+		return -1
+	case m.Minor > o.Minor:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) GreaterThan(o Money) bool { return m.Minor > o.Minor }
+func (m Money) LessThan(o Money) bool    { return m.Minor < o.Minor }
+func (m Money) IsZero() bool             { return m.Minor == 0 }
+func (m Money) IsPositive() bool         { return m.Minor > 0 }
+func (m Money) IsNegative() bool         { return m.Minor < 0 }
+
+// Min returns the smaller of two amounts
+func Min(a, b Money) Money {
+	if b.LessThan(a) {
+		return b
+	}
+	return a
+}
+
+// Float64 returns the amount in major units; use only for ratios and display
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / 100
+}
+
+// String formats the amount in major units with two decimals, e.g. "-12.05"
+func (m Money) String() string {
+	sign := ""
+	minor := m.Minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
+// MarshalJSON writes the amount as a decimal number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string in major units
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" {
+		*m = Zero
+		return nil
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	m.Minor = parsed.Minor
+	return nil
+}
+
+// Value stores the minor units as an integer
+func (m Money) Value() (driver.Value, error) {
+	return m.Minor, nil
+}
+
+// Scan reads minor units from an integer column
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		m.Minor = 0
+	case int64:
+		m.Minor = v
+	case float64:
+		// Aggregates such as AVG or SUM(price * quantity) come back as REAL
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("money: invalid stored value %v", v)
+		}
+		m.Minor = roundRat(new(big.Rat).SetFloat64(v))
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return errors.New("money: unsupported scan type " + fmt.Sprintf("%T", value))
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return fmt.Errorf("money: invalid stored value %q", s)
+	}
+	m.Minor = roundRat(r)
+	return nil
+}
+
+// GormDataType makes GORM create integer columns
+func (Money) GormDataType() string {
+	return "bigint"
+}
+
+// roundRat rounds a rational to the nearest integer, half away from zero
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	// (2|num| + den) / (2den)
+	num.Mul(num, big.NewInt(2)).Add(num, den)
+	q := new(big.Int).Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
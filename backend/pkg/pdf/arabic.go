@@ -0,0 +1,287 @@
+package pdf
+
+import "unicode"
+
+// arabicForms holds the presentation forms of a letter: isolated, final, initial, medial.
+// Right-joining letters have no initial or medial form.
+type arabicForms [4]rune
+
+var arabicLetters = map[rune]arabicForms{
+	0x0621: {0xFE80, 0, 0, 0},
+	0x0622: {0xFE81, 0xFE82, 0, 0},
+	0x0623: {0xFE83, 0xFE84, 0, 0},
+	0x0624: {0xFE85, 0xFE86, 0, 0},
+	0x0625: {0xFE87, 0xFE88, 0, 0},
+	0x0626: {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C},
+	0x0627: {0xFE8D, 0xFE8E, 0, 0},
+	0x0628: {0xFE8F, 0xFE90, 0xFE91, 0xFE92},
+	0x0629: {0xFE93, 0xFE94, 0, 0},
+	0x062A: {0xFE95, 0xFE96, 0xFE97, 0xFE98},
+	0x062B: {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C},
+	0x062C: {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0},
+	0x062D: {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4},
+	0x062E: {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8},
+	0x062F: {0xFEA9, 0xFEAA, 0, 0},
+	0x0630: {0xFEAB, 0xFEAC, 0, 0},
+	0x0631: {0xFEAD, 0xFEAE, 0, 0},
+	0x0632: {0xFEAF, 0xFEB0, 0, 0},
+	0x0633: {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4},
+	0x0634: {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8},
+	0x0635: {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC},
+	0x0636: {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0},
+	0x0637: {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4},
+	0x0638: {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8},
+	0x0639: {0xFEC9, 0xFECA, 0xFECB, 0xFECC},
+	0x063A: {0xFECD, 0xFECE, 0xFECF, 0xFED0},
+	0x0641: {0xFED1, 0xFED2, 0xFED3, 0xFED4},
+	0x0642: {0xFED5, 0xFED6, 0xFED7, 0xFED8},
+	0x0643: {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC},
+	0x0644: {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0},
+	0x0645: {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4},
+	0x0646: {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8},
+	0x0647: {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC},
+	0x0648: {0xFEED, 0xFEEE, 0, 0},
+	0x0649: {0xFEEF, 0xFEF0, 0, 0},
+	0x064A: {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4},
+	0x067E: {0xFB56, 0xFB57, 0xFB58, 0xFB59},
+	0x0686: {0xFB7A, 0xFB7B, 0xFB7C, 0xFB7D},
+	0x06A9: {0xFB8E, 0xFB8F, 0xFB90, 0xFB91},
+	0x06AF: {0xFB92, 0xFB93, 0xFB94, 0xFB95},
+	0x06CC: {0xFBFC, 0xFBFD, 0xFBFE, 0xFBFF},
+}
+
+// lamAlef maps the alef following a lam to the ligature's isolated and final forms
+var lamAlef = map[rune][2]rune{
+	0x0622: {0xFEF5, 0xFEF6},
+	0x0623: {0xFEF7, 0xFEF8},
+	0x0625: {0xFEF9, 0xFEFA},
+	0x0627: {0xFEFB, 0xFEFC},
+}
+
+const (
+	arabicLam     = 0x0644
+	arabicTatweel = 0x0640
+)
+
+// isArabicMark reports combining marks (harakat) that are skipped when joining
+func isArabicMark(r rune) bool {
+	return (r >= 0x064B && r <= 0x065F) || r == 0x0670 || (r >= 0x06D6 && r <= 0x06ED)
+}
+
+func joinsLeft(r rune) bool {
+	if r == arabicTatweel {
+		return true
+	}
+	f, ok := arabicLetters[r]
+	return ok && f[2] != 0
+}
+
+func joinsRight(r rune) bool {
+	if r == arabicTatweel {
+		return true
+	}
+	_, ok := arabicLetters[r]
+	return ok
+}
+
+// ShapeArabic replaces Arabic letters with their contextual presentation forms and
+// applies the mandatory lam-alef ligatures. Input and output are in logical order.
+func ShapeArabic(s string) string {
+	runes := []rune(s)
+	out := make([]rune, 0, len(runes))
+
+	neighbour := func(i, step int) rune {
+		for j := i + step; j >= 0 && j < len(runes); j += step {
+			if !isArabicMark(runes[j]) {
+				return runes[j]
+			}
+		}
+		return 0
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		forms, ok := arabicLetters[r]
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+
+		prevJoins := joinsLeft(neighbour(i, -1))
+
+		if r == arabicLam {
+			// Find the next non-mark character for a lam-alef ligature
+			j := i + 1
+			for j < len(runes) && isArabicMark(runes[j]) {
+				j++
+			}
+			if j < len(runes) {
+				if lig, ok := lamAlef[runes[j]]; ok {
+					if prevJoins {
+						out = append(out, lig[1])
+					} else {
+						out = append(out, lig[0])
+					}
+					out = append(out, runes[i+1:j]...)
+					i = j
+					continue
+				}
+			}
+		}
+
+		nextJoins := joinsRight(neighbour(i, 1))
+		dual := forms[2] != 0
+
+		switch {
+		case prevJoins && nextJoins && dual:
+			out = append(out, forms[3])
+		case prevJoins && forms[1] != 0:
+			out = append(out, forms[1])
+		case nextJoins && dual:
+			out = append(out, forms[2])
+		default:
+			out = append(out, forms[0])
+		}
+	}
+	return string(out)
+}
+
+// isRTL reports strong right-to-left characters (Hebrew, Arabic and their presentation forms)
+func isRTL(r rune) bool {
+	return (r >= 0x0590 && r <= 0x08FF) || (r >= 0xFB1D && r <= 0xFDFF) || (r >= 0xFE70 && r <= 0xFEFF)
+}
+
+// ContainsRTL reports whether the text has any right-to-left characters
+func ContainsRTL(s string) bool {
+	for _, r := range s {
+		if isRTL(r) {
+			return true
+		}
+	}
+	return false
+}
+
+var mirrored = map[rune]rune{'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<', '«': '»', '»': '«'}
+
+// direction classes used by Visual
+const (
+	dirNeutral = iota
+	dirLTR
+	dirRTL
+)
+
+func classify(r rune) int {
+	switch {
+	case isRTL(r):
+		return dirRTL
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return dirLTR
+	}
+	return dirNeutral
+}
+
+// Visual shapes Arabic text and reorders a single line from logical to visual
+// (left-to-right drawing) order. It implements the subset of the bidi algorithm
+// needed for labels and names: the paragraph direction comes from the first strong
+// character, numbers and Latin words stay left-to-right inside Arabic text, and
+// neutrals take the direction of their surroundings.
+func Visual(s string) string {
+	if !ContainsRTL(s) {
+		return s
+	}
+	runes := []rune(ShapeArabic(s))
+
+	base := dirLTR
+	for _, r := range runes {
+		if c := classify(r); c != dirNeutral {
+			base = c
+			break
+		}
+	}
+
+	// Resolve neutrals: between two characters of the same direction they follow it,
+	// otherwise they take the paragraph direction
+	dirs := make([]int, len(runes))
+	for i, r := range runes {
+		dirs[i] = classify(r)
+		if isArabicMark(r) {
+			dirs[i] = dirRTL
+		}
+	}
+	for i := 0; i < len(dirs); i++ {
+		if dirs[i] != dirNeutral {
+			continue
+		}
+		j := i
+		for j < len(dirs) && dirs[j] == dirNeutral {
+			j++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = dirs[i-1]
+		}
+		if j < len(dirs) {
+			after = dirs[j]
+		}
+		resolved := base
+		if before == after {
+			resolved = before
+		}
+		for k := i; k < j; k++ {
+			dirs[k] = resolved
+		}
+		i = j - 1
+	}
+
+	// Split into directional runs
+	type run struct {
+		dir   int
+		runes []rune
+	}
+	var runs []run
+	for i, r := range runes {
+		if len(runs) == 0 || runs[len(runs)-1].dir != dirs[i] {
+			runs = append(runs, run{dir: dirs[i]})
+		}
+		runs[len(runs)-1].runes = append(runs[len(runs)-1].runes, r)
+	}
+
+	for i := range runs {
+		if runs[i].dir == dirRTL {
+			runs[i].runes = reverseClusters(runs[i].runes)
+		}
+	}
+	if base == dirRTL {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+
+	out := make([]rune, 0, len(runes))
+	for _, r := range runs {
+		out = append(out, r.runes...)
+	}
+	return string(out)
+}
+
+// reverseClusters reverses a right-to-left run while keeping each combining mark
+// after its base letter, so zero-width marks are drawn over the right glyph
+func reverseClusters(runes []rune) []rune {
+	var clusters [][]rune
+	for _, r := range runes {
+		if isArabicMark(r) && len(clusters) > 0 {
+			clusters[len(clusters)-1] = append(clusters[len(clusters)-1], r)
+			continue
+		}
+		if m, ok := mirrored[r]; ok {
+			r = m
+		}
+		clusters = append(clusters, []rune{r})
+	}
+
+	out := make([]rune, 0, len(runes))
+	for i := len(clusters) - 1; i >= 0; i-- {
+		out = append(out, clusters[i]...)
+	}
+	return out
+}
@@ -0,0 +1,359 @@
+// Package pdf is a small PDF 1.4 writer for business documents. It supports
+// embedded TrueType fonts (required for Arabic), right-to-left text, lines,
+// rectangles and PNG/JPEG images. Coordinates are in points with the origin at
+// the top-left corner of the page; text y positions are baselines.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+type page struct {
+	content bytes.Buffer
+}
+
+// Document is a PDF under construction
+type Document struct {
+	width, height float64
+	pages         []*page
+	current       *page
+
+	fonts     map[string]*embeddedFont
+	fontOrder []string
+	font      *embeddedFont
+	fontSize  float64
+
+	images []*image
+}
+
+type embeddedFont struct {
+	ref  string // Resource name, e.g. F1
+	ttf  *TrueTypeFont
+	used map[uint16]rune
+}
+
+// New creates an empty A4 portrait document
+func New() *Document {
+	return &Document{width: A4Width, height: A4Height, fonts: map[string]*embeddedFont{}, fontSize: 10}
+}
+
+// Width returns the page width
+func (d *Document) Width() float64 { return d.width }
+
+// Height returns the page height
+func (d *Document) Height() float64 { return d.height }
+
+// AddFont registers a TrueType font under a name for use with SetFont
+func (d *Document) AddFont(name string, ttf *TrueTypeFont) {
+	if _, ok := d.fonts[name]; !ok {
+		d.fontOrder = append(d.fontOrder, name)
+	}
+	d.fonts[name] = &embeddedFont{ref: fmt.Sprintf("F%d", len(d.fontOrder)), ttf: ttf, used: map[uint16]rune{}}
+}
+
+// SetFont selects a registered font and size for the following text
+func (d *Document) SetFont(name string, size float64) error {
+	f, ok := d.fonts[name]
+	if !ok {
+		return fmt.Errorf("pdf: font %q not registered", name)
+	}
+	d.font, d.fontSize = f, size
+	return nil
+}
+
+// AddPage starts a new page and makes it current
+func (d *Document) AddPage() {
+	p := &page{}
+	d.pages = append(d.pages, p)
+	d.current = p
+}
+
+// PageCount returns the number of pages
+func (d *Document) PageCount() int { return len(d.pages) }
+
+// SetPage makes an existing page (1-based) current, e.g. to add page numbers
+func (d *Document) SetPage(n int) {
+	if n >= 1 && n <= len(d.pages) {
+		d.current = d.pages[n-1]
+	}
+}
+
+func (d *Document) y(top float64) float64 { return d.height - top }
+
+func (d *Document) op(format string, args ...interface{}) {
+	if d.current == nil {
+		d.AddPage()
+	}
+	fmt.Fprintf(&d.current.content, format+"\n", args...)
+}
+
+// SetFillColor sets the color used for text and filled shapes
+func (d *Document) SetFillColor(r, g, b uint8) {
+	d.op("%s %s %s rg", num(float64(r)/255), num(float64(g)/255), num(float64(b)/255))
+}
+
+// SetStrokeColor sets the color used for lines and rectangle borders
+func (d *Document) SetStrokeColor(r, g, b uint8) {
+	d.op("%s %s %s RG", num(float64(r)/255), num(float64(g)/255), num(float64(b)/255))
+}
+
+// SetLineWidth sets the stroke width in points
+func (d *Document) SetLineWidth(w float64) {
+	d.op("%s w", num(w))
+}
+
+// Line draws a straight line
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	d.op("%s %s m %s %s l S", num(x1), num(d.y(y1)), num(x2), num(d.y(y2)))
+}
+
+// Rect draws a rectangle whose top-left corner is (x, y); it is filled or stroked
+func (d *Document) Rect(x, y, w, h float64, fill bool) {
+	style := "S"
+	if fill {
+		style = "f"
+	}
+	d.op("%s %s %s %s re %s", num(x), num(d.y(y+h)), num(w), num(h), style)
+}
+
+// TextWidth returns the width of a string in the current font and size
+func (d *Document) TextWidth(s string) float64 {
+	if d.font == nil {
+		return 0
+	}
+	var w float64
+	for _, r := range Visual(s) {
+		w += d.font.ttf.advance(d.font.ttf.GlyphID(r))
+	}
+	return w * d.fontSize / 1000
+}
+
+// Text draws a string with its left edge at x. Right-to-left text is shaped and reordered.
+func (d *Document) Text(x, y float64, s string) {
+	if d.font == nil || s == "" {
+		return
+	}
+	var hex strings.Builder
+	for _, r := range Visual(s) {
+		g := d.font.ttf.GlyphID(r)
+		d.font.used[g] = r
+		fmt.Fprintf(&hex, "%04X", g)
+	}
+	d.op("BT /%s %s Tf %s %s Td <%s> Tj ET", d.font.ref, num(d.fontSize), num(x), num(d.y(y)), hex.String())
+}
+
+// TextRight draws a string with its right edge at x
+func (d *Document) TextRight(x, y float64, s string) {
+	d.Text(x-d.TextWidth(s), y, s)
+}
+
+// TextCenter draws a string centered on x
+func (d *Document) TextCenter(x, y float64, s string) {
+	d.Text(x-d.TextWidth(s)/2, y, s)
+}
+
+// WrapText splits a string into lines no wider than width, breaking between words
+func (d *Document) WrapText(s string, width float64) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return nil
+	}
+	var lines []string
+	line := words[0]
+	for _, w := range words[1:] {
+		if d.TextWidth(line+" "+w) > width {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line += " " + w
+	}
+	return append(lines, line)
+}
+
+// Output writes the finished document
+func (d *Document) Output(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	pw := &writer{}
+	catalog := pw.alloc()
+	pagesRef := pw.alloc()
+
+	fontRefs := map[string]int{}
+	for _, name := range d.fontOrder {
+		fontRefs[name] = pw.alloc()
+	}
+	imageRefs := make([]int, len(d.images))
+	for i := range d.images {
+		imageRefs[i] = pw.alloc()
+	}
+
+	// Resources shared by every page
+	var res strings.Builder
+	res.WriteString("<< /Font <<")
+	for _, name := range d.fontOrder {
+		fmt.Fprintf(&res, " /%s %d 0 R", d.fonts[name].ref, fontRefs[name])
+	}
+	res.WriteString(" >> /XObject <<")
+	for i, img := range d.images {
+		fmt.Fprintf(&res, " /%s %d 0 R", img.ref, imageRefs[i])
+	}
+	res.WriteString(" >> >>")
+
+	var kids []string
+	for _, p := range d.pages {
+		pageRef := pw.alloc()
+		contentRef := pw.alloc()
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageRef))
+		pw.object(pageRef, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pagesRef, num(d.width), num(d.height), res.String(), contentRef))
+		pw.stream(contentRef, "", p.content.Bytes(), true)
+	}
+
+	pw.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRef))
+	pw.object(pagesRef, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+
+	for _, name := range d.fontOrder {
+		d.fonts[name].write(pw, fontRefs[name])
+	}
+	for i, img := range d.images {
+		img.write(pw, imageRefs[i])
+	}
+
+	_, err := w.Write(pw.finish(catalog))
+	return err
+}
+
+// Bytes renders the document into memory
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write embeds the font as a Type0 / CIDFontType2 font with Identity-H encoding
+func (f *embeddedFont) write(pw *writer, ref int) {
+	cidRef, descRef, fileRef, toUnicodeRef := pw.alloc(), pw.alloc(), pw.alloc(), pw.alloc()
+	ttf := f.ttf
+	name := strings.ReplaceAll(ttf.Name, " ", "")
+
+	glyphs := make([]int, 0, len(f.used))
+	for g := range f.used {
+		glyphs = append(glyphs, int(g))
+	}
+	sort.Ints(glyphs)
+
+	var widths strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", g, int(ttf.advance(uint16(g))))
+	}
+
+	pw.object(ref, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cidRef, toUnicodeRef))
+	pw.object(cidRef, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>",
+		name, descRef, widths.String()))
+	pw.object(descRef, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, ttf.scale(ttf.bbox[0]), ttf.scale(ttf.bbox[1]), ttf.scale(ttf.bbox[2]), ttf.scale(ttf.bbox[3]),
+		ttf.scale(ttf.ascent), ttf.scale(ttf.descent), ttf.scale(ttf.ascent), fileRef))
+	pw.stream(fileRef, fmt.Sprintf("/Length1 %d", len(ttf.data)), ttf.data, true)
+
+	// ToUnicode lets viewers copy and search the text
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n")
+	cmap.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	cmap.WriteString("/CMapName /Adobe-Identity-UCS def /CMapType 2 def\n")
+	cmap.WriteString("1 begincodespacerange <0000> <FFFF> endcodespacerange\n")
+	for start := 0; start < len(glyphs); start += 100 {
+		end := start + 100
+		if end > len(glyphs) {
+			end = len(glyphs)
+		}
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, g := range glyphs[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <%s>\n", g, utf16Hex(f.used[uint16(g)]))
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap CMapName currentdict /CMap defineresource pop end end")
+	pw.stream(toUnicodeRef, "", []byte(cmap.String()), true)
+}
+
+func utf16Hex(r rune) string {
+	if r < 0x10000 {
+		return fmt.Sprintf("%04X", r)
+	}
+	r -= 0x10000
+	return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+}
+
+// num formats a number compactly with at most 3 decimals
+func num(v float64) string {
+	s := fmt.Sprintf("%.3f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// writer assembles numbered objects and the cross-reference table
+type writer struct {
+	buf     bytes.Buffer
+	next    int
+	offsets map[int]int
+}
+
+func (pw *writer) alloc() int {
+	if pw.offsets == nil {
+		pw.offsets = map[int]int{}
+		pw.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	}
+	pw.next++
+	return pw.next
+}
+
+func (pw *writer) object(ref int, body string) {
+	pw.offsets[ref] = pw.buf.Len()
+	fmt.Fprintf(&pw.buf, "%d 0 obj\n%s\nendobj\n", ref, body)
+}
+
+func (pw *writer) stream(ref int, dict string, data []byte, compress bool) {
+	filter := ""
+	if compress {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(data)
+		zw.Close()
+		data = z.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+	pw.offsets[ref] = pw.buf.Len()
+	fmt.Fprintf(&pw.buf, "%d 0 obj\n<< /Length %d%s %s >>\nstream\n", ref, len(data), filter, dict)
+	pw.buf.Write(data)
+	pw.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (pw *writer) finish(root int) []byte {
+	xref := pw.buf.Len()
+	fmt.Fprintf(&pw.buf, "xref\n0 %d\n0000000000 65535 f \n", pw.next+1)
+	for i := 1; i <= pw.next; i++ {
+		fmt.Fprintf(&pw.buf, "%010d 00000 n \n", pw.offsets[i])
+	}
+	fmt.Fprintf(&pw.buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", pw.next+1, root, xref)
+	return pw.buf.Bytes()
+}
@@ -0,0 +1,210 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TrueTypeFont is a parsed TrueType font ready to be embedded as a CID font
+type TrueTypeFont struct {
+	Name       string
+	data       []byte
+	unitsPerEm uint16
+	ascent     int16
+	descent    int16
+	bbox       [4]int16
+	advances   []uint16
+	cmap       func(r rune) uint16
+	glyphs     map[rune]uint16
+}
+
+// LoadTrueTypeFont reads and parses a .ttf file
+func LoadTrueTypeFont(name, path string) (*TrueTypeFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrueTypeFont(name, data)
+}
+
+// ParseTrueTypeFont parses the tables needed for layout and embedding
+func ParseTrueTypeFont(name string, data []byte) (*TrueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, errors.New("pdf: font file too short")
+	}
+
+	tables := map[string][]byte{}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, errors.New("pdf: truncated table directory")
+		}
+		tag := string(data[rec : rec+4])
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset+length > len(data) {
+			return nil, fmt.Errorf("pdf: table %s out of range", tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("pdf: font has no %s table", tag)
+		}
+	}
+
+	f := &TrueTypeFont{Name: name, data: data, glyphs: map[rune]uint16{}}
+
+	head := tables["head"]
+	f.unitsPerEm = binary.BigEndian.Uint16(head[18:])
+	for i := 0; i < 4; i++ {
+		f.bbox[i] = int16(binary.BigEndian.Uint16(head[36+2*i:]))
+	}
+
+	hhea := tables["hhea"]
+	f.ascent = int16(binary.BigEndian.Uint16(hhea[4:]))
+	f.descent = int16(binary.BigEndian.Uint16(hhea[6:]))
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+
+	numGlyphs := int(binary.BigEndian.Uint16(tables["maxp"][4:]))
+	hmtx := tables["hmtx"]
+	f.advances = make([]uint16, numGlyphs)
+	var last uint16
+	for g := 0; g < numGlyphs; g++ {
+		if g < numHMetrics && 4*g+2 <= len(hmtx) {
+			last = binary.BigEndian.Uint16(hmtx[4*g:])
+		}
+		f.advances[g] = last
+	}
+
+	cmap, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.cmap = cmap
+	return f, nil
+}
+
+// GlyphID returns the glyph of a rune, 0 (.notdef) when the font lacks it
+func (f *TrueTypeFont) GlyphID(r rune) uint16 {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	g := f.cmap(r)
+	f.glyphs[r] = g
+	return g
+}
+
+// HasGlyph reports whether the font can draw the rune
+func (f *TrueTypeFont) HasGlyph(r rune) bool {
+	return f.GlyphID(r) != 0
+}
+
+// advance returns the advance width of a glyph in 1/1000 em
+func (f *TrueTypeFont) advance(g uint16) float64 {
+	if int(g) >= len(f.advances) {
+		return 0
+	}
+	return float64(f.advances[g]) * 1000 / float64(f.unitsPerEm)
+}
+
+func (f *TrueTypeFont) scale(v int16) int {
+	return int(float64(v) * 1000 / float64(f.unitsPerEm))
+}
+
+// parseCmap picks the best Unicode subtable: format 12 (full range) or format 4 (BMP)
+func parseCmap(cmap []byte) (func(rune) uint16, error) {
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	var fmt4, fmt12 []byte
+	for i := 0; i < numTables; i++ {
+		rec := 4 + 8*i
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		if offset >= len(cmap) {
+			continue
+		}
+		sub := cmap[offset:]
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode {
+			continue
+		}
+		switch binary.BigEndian.Uint16(sub) {
+		case 4:
+			fmt4 = sub
+		case 12:
+			fmt12 = sub
+		}
+	}
+
+	if fmt12 != nil {
+		return cmapFormat12(fmt12), nil
+	}
+	if fmt4 != nil {
+		return cmapFormat4(fmt4), nil
+	}
+	return nil, errors.New("pdf: font has no Unicode cmap")
+}
+
+func cmapFormat4(sub []byte) func(rune) uint16 {
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + 2*segCount + 2
+	idDeltas := startCodes + 2*segCount
+	idRangeOffsets := idDeltas + 2*segCount
+
+	return func(r rune) uint16 {
+		if r > 0xFFFF {
+			return 0
+		}
+		c := uint16(r)
+		for i := 0; i < segCount; i++ {
+			end := binary.BigEndian.Uint16(sub[endCodes+2*i:])
+			if c > end {
+				continue
+			}
+			start := binary.BigEndian.Uint16(sub[startCodes+2*i:])
+			if c < start {
+				return 0
+			}
+			delta := binary.BigEndian.Uint16(sub[idDeltas+2*i:])
+			rangeOffset := int(binary.BigEndian.Uint16(sub[idRangeOffsets+2*i:]))
+			if rangeOffset == 0 {
+				return c + delta
+			}
+			addr := idRangeOffsets + 2*i + rangeOffset + 2*int(c-start)
+			if addr+2 > len(sub) {
+				return 0
+			}
+			g := binary.BigEndian.Uint16(sub[addr:])
+			if g == 0 {
+				return 0
+			}
+			return g + delta
+		}
+		return 0
+	}
+}
+
+func cmapFormat12(sub []byte) func(rune) uint16 {
+	numGroups := int(binary.BigEndian.Uint32(sub[12:]))
+	return func(r rune) uint16 {
+		c := uint32(r)
+		for i := 0; i < numGroups; i++ {
+			group := 16 + 12*i
+			if group+12 > len(sub) {
+				return 0
+			}
+			start := binary.BigEndian.Uint32(sub[group:])
+			end := binary.BigEndian.Uint32(sub[group+4:])
+			if c >= start && c <= end {
+				return uint16(binary.BigEndian.Uint32(sub[group+8:]) + c - start)
+			}
+		}
+		return 0
+	}
+}
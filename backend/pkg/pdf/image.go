@@ -0,0 +1,101 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+)
+
+type image struct {
+	ref           string
+	width, height int
+	jpeg          []byte // Embedded as is with DCTDecode
+	colorSpace    string
+	rgb, alpha    []byte // Raw samples for decoded images
+}
+
+// Image draws a PNG or JPEG with its top-left corner at (x, y). When w or h is
+// zero it is derived from the other to keep the aspect ratio.
+func (d *Document) Image(data []byte, x, y, w, h float64) error {
+	img, err := d.loadImage(data)
+	if err != nil {
+		return err
+	}
+	if w == 0 && h == 0 {
+		w, h = float64(img.width), float64(img.height)
+	} else if w == 0 {
+		w = h * float64(img.width) / float64(img.height)
+	} else if h == 0 {
+		h = w * float64(img.height) / float64(img.width)
+	}
+	d.op("q %s 0 0 %s %s %s cm /%s Do Q", num(w), num(h), num(x), num(d.y(y+h)), img.ref)
+	return nil
+}
+
+func (d *Document) loadImage(data []byte) (*image, error) {
+	ref := fmt.Sprintf("Im%d", len(d.images)+1)
+
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(data)); err == nil {
+		img := &image{ref: ref, width: cfg.Width, height: cfg.Height, jpeg: data, colorSpace: "DeviceRGB"}
+		switch cfg.ColorModel {
+		case color.GrayModel:
+			img.colorSpace = "DeviceGray"
+		case color.CMYKModel:
+			img.colorSpace = "DeviceCMYK"
+		}
+		d.images = append(d.images, img)
+		return img, nil
+	}
+
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("pdf: unsupported image, expected PNG or JPEG")
+	}
+
+	bounds := src.Bounds()
+	img := &image{ref: ref, width: bounds.Dx(), height: bounds.Dy(), colorSpace: "DeviceRGB"}
+	img.rgb = make([]byte, 0, img.width*img.height*3)
+	alpha := make([]byte, 0, img.width*img.height)
+	opaque := true
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			r, g, b, a := src.At(px, py).RGBA()
+			if a > 0 && a < 0xFFFF {
+				// Undo alpha premultiplication
+				r, g, b = r*0xFFFF/a, g*0xFFFF/a, b*0xFFFF/a
+			}
+			img.rgb = append(img.rgb, byte(r>>8), byte(g>>8), byte(b>>8))
+			alpha = append(alpha, byte(a>>8))
+			if a != 0xFFFF {
+				opaque = false
+			}
+		}
+	}
+	if !opaque {
+		img.alpha = alpha
+	}
+	d.images = append(d.images, img)
+	return img, nil
+}
+
+func (img *image) write(pw *writer, ref int) {
+	if img.jpeg != nil {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode",
+			img.width, img.height, img.colorSpace)
+		pw.stream(ref, dict, img.jpeg, false)
+		return
+	}
+
+	smask := ""
+	if img.alpha != nil {
+		maskRef := pw.alloc()
+		pw.stream(maskRef, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8",
+			img.width, img.height), img.alpha, true)
+		smask = fmt.Sprintf(" /SMask %d 0 R", maskRef)
+	}
+	pw.stream(ref, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8%s",
+		img.width, img.height, smask), img.rgb, true)
+}
@@ -0,0 +1,421 @@
+// Package spreadsheet reads CSV and XLSX uploads into rows of text cells. XLSX support
+// covers the first worksheet with shared, inline and formula-cached strings, which is what
+// Excel, LibreOffice and Google Sheets write; styles and dates are not interpreted.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Sheet limits, the same as Excel's. Cell references beyond them are rejected before
+// anything is allocated for them.
+const (
+	MaxRows    = 1 << 20 // Row 1048576
+	MaxColumns = 1 << 14 // Column XFD
+)
+
+// Memory limits for a single file. MaxCells counts every cell up to the last one used in
+// each row, so sparse references such as XFD1 cannot pad rows out to millions of empty
+// strings. MaxPartSize caps each unpacked XLSX part, so a small zip cannot expand without bound.
+const (
+	MaxCells    = 1 << 21
+	MaxPartSize = 64 << 20
+)
+
+// ErrUnsupportedFormat is returned for files that are neither CSV nor XLSX
+var ErrUnsupportedFormat = errors.New("spreadsheet: unsupported file format, use .csv or .xlsx")
+
+// ErrTooManyRows is returned as soon as a file turns out to have more rows than the caller
+// accepts; the rest of the file is not read
+var ErrTooManyRows = errors.New("spreadsheet: too many rows")
+
+// Read parses a CSV or XLSX file, chosen by its extension, into at most maxRows rows of
+// cells; 0 allows up to MaxRows. Trailing empty rows are dropped and every row is trimmed
+// of surrounding spaces.
+func Read(filename string, data []byte, maxRows int) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".txt":
+		rows, err = ReadCSV(bytes.NewReader(data), maxRows)
+	case ".xlsx":
+		rows, err = ReadXLSX(data, maxRows)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		for j := range rows[i] {
+			rows[i][j] = strings.TrimSpace(rows[i][j])
+		}
+	}
+	for len(rows) > 0 && isBlank(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+func isBlank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// rowLimit turns a caller's row cap into one within the sheet limits
+func rowLimit(maxRows int) int {
+	if maxRows <= 0 || maxRows > MaxRows {
+		return MaxRows
+	}
+	return maxRows
+}
+
+// ErrTooLarge is returned for files holding more cells, or unpacking to more data, than
+// the memory limits allow
+var ErrTooLarge = errors.New("spreadsheet: file is too large")
+
+func tooManyCells() error {
+	return fmt.Errorf("%w, it has more than %d cells", ErrTooLarge, MaxCells)
+}
+
+func tooManyRows(maxRows int) error {
+	return fmt.Errorf("%w, the limit is %d", ErrTooManyRows, maxRows)
+}
+
+// ReadCSV reads at most maxRows rows of comma or semicolon separated values; 0 allows up
+// to MaxRows. A UTF-8 byte order mark, which Excel adds when saving Arabic text as CSV,
+// is skipped. Blank lines past the cap are ignored.
+func ReadCSV(r io.Reader, maxRows int) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	maxRows = rowLimit(maxRows)
+	var rows [][]string
+	cellCount := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("spreadsheet: invalid CSV: %w", err)
+		}
+		if len(record) > MaxColumns {
+			return nil, fmt.Errorf("spreadsheet: row %d has more than %d columns", len(rows)+1, MaxColumns)
+		}
+		if len(rows) >= maxRows {
+			if isBlank(record) {
+				continue
+			}
+			return nil, tooManyRows(maxRows)
+		}
+		if cellCount += len(record); cellCount > MaxCells {
+			return nil, tooManyCells()
+		}
+		rows = append(rows, record)
+	}
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a rich or plain string: plain text sits in <t>, rich text in runs of <r><t>
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// xlsxRow is one <row> of a worksheet's sheetData
+type xlsxRow struct {
+	R     int `xml:"r,attr"`
+	Cells []struct {
+		Ref    string   `xml:"r,attr"`
+		Type   string   `xml:"t,attr"`
+		Value  string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// isEmpty reports whether a row holds no values, like the formatted empty rows Excel writes
+func (r xlsxRow) isEmpty() bool {
+	for _, c := range r.Cells {
+		if c.Value != "" || c.Inline.String() != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadXLSX reads at most maxRows rows of the first worksheet of an XLSX workbook; 0 allows
+// up to MaxRows. The sheet is read a row at a time and reading stops at the first row
+// past the cap that holds a value.
+func ReadXLSX(data []byte, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("spreadsheet: invalid XLSX: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, errors.New("spreadsheet: invalid XLSX: no worksheet found")
+	}
+	rc, err := openPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	maxRows = rowLimit(maxRows)
+	var rows [][]string
+	cellCount := 0
+	decoder := xml.NewDecoder(rc)
+	for {
+		var row xlsxRow
+		if err := nextElement(decoder, "row", &row); err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("spreadsheet: invalid XLSX part %s: %w", f.Name, err)
+		}
+
+		index := len(rows)
+		if row.R > 0 {
+			index = row.R - 1
+		}
+		if index >= maxRows {
+			if row.isEmpty() {
+				continue
+			}
+			return nil, tooManyRows(maxRows)
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+		var cells []string
+		for _, c := range row.Cells {
+			col := len(cells)
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			if col >= MaxColumns {
+				return nil, fmt.Errorf("spreadsheet: row %d has more than %d columns", index+1, MaxColumns)
+			}
+			if cellCount+col >= MaxCells {
+				return nil, tooManyCells()
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("spreadsheet: invalid shared string in cell %s", c.Ref)
+				}
+				cells[col] = shared[i]
+			case "inlineStr":
+				cells[col] = c.Inline.String()
+			case "b":
+				cells[col] = map[string]string{"1": "true", "0": "false"}[c.Value]
+			case "", "n":
+				cells[col] = formatNumber(c.Value)
+			default:
+				cells[col] = c.Value
+			}
+		}
+		// A row listed twice replaces its earlier cells
+		cellCount += len(cells) - len(rows[index])
+		rows[index] = cells
+	}
+}
+
+// readSharedStrings reads the workbook's string table a string at a time, holding at most
+// MaxCells strings
+func readSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := openPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var shared []string
+	decoder := xml.NewDecoder(rc)
+	for {
+		var text xlsxText
+		if err := nextElement(decoder, "si", &text); err == io.EOF {
+			return shared, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("spreadsheet: invalid XLSX part %s: %w", f.Name, err)
+		}
+		if len(shared) >= MaxCells {
+			return nil, tooManyCells()
+		}
+		shared = append(shared, text.String())
+	}
+}
+
+// nextElement decodes the next element named local into v, or returns io.EOF at the end
+// of the document
+func nextElement(decoder *xml.Decoder, local string, v any) error {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == local {
+			return decoder.DecodeElement(v, &start)
+		}
+	}
+}
+
+// openPart opens a zip entry that fails with ErrTooLarge once it unpacks past MaxPartSize,
+// whatever size its header claims
+func openPart(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > MaxPartSize {
+		return nil, fmt.Errorf("%w, %s unpacks to more than %d bytes", ErrTooLarge, f.Name, MaxPartSize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &partReader{ReadCloser: rc, name: f.Name, left: MaxPartSize + 1}, nil
+}
+
+type partReader struct {
+	io.ReadCloser
+	name string
+	left int64
+}
+
+func (r *partReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.left {
+		p = p[:r.left]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if r.left -= int64(n); r.left == 0 {
+		return n, fmt.Errorf("%w, %s unpacks to more than %d bytes", ErrTooLarge, r.name, MaxPartSize)
+	}
+	return n, err
+}
+
+// firstSheetPath resolves the first sheet listed in the workbook, falling back to the
+// conventional name when the workbook relationships are missing
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+	var wb xlsxWorkbook
+	var rels xlsxRelationships
+	wf, ok1 := files["xl/workbook.xml"]
+	rf, ok2 := files["xl/_rels/workbook.xml.rels"]
+	if !ok1 || !ok2 || decodeXML(wf, &wb) != nil || decodeXML(rf, &rels) != nil || len(wb.Sheets) == 0 {
+		return fallback
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[0].ID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/")
+			}
+			return path.Join("xl", rel.Target)
+		}
+	}
+	return fallback
+}
+
+func decodeXML(f *zip.File, v any) error {
+	rc, err := openPart(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("spreadsheet: invalid XLSX part %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex converts a cell reference such as "AB12" to a zero-based column
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for i, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			if col = col*26 + int(r-'A'+1); col > MaxColumns {
+				return 0, fmt.Errorf("spreadsheet: cell %s is past the last column", ref)
+			}
+			continue
+		}
+		if i == 0 {
+			break
+		}
+		return col - 1, nil
+	}
+	return 0, fmt.Errorf("spreadsheet: invalid cell reference %q", ref)
+}
+
+// formatNumber drops the binary noise Excel stores for decimals, so 0.1+0.2 reads as 0.3
+func formatNumber(v string) string {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,269 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Writer streams rows to a CSV or XLSX file without holding them in memory
+type Writer interface {
+	// WriteHeader writes the column titles; XLSX shows them in bold
+	WriteHeader(titles []string) error
+	// WriteRow writes one row. Strings are text; integers and floats are numbers;
+	// bools, times and nil are formatted for the target format.
+	WriteRow(values []any) error
+	// Close finishes the file; the underlying writer is not closed
+	Close() error
+}
+
+// Options control how a file is written
+type Options struct {
+	SheetName   string // XLSX sheet name, at most 31 characters
+	RightToLeft bool   // XLSX sheet direction, for Arabic headers
+}
+
+// NewWriter returns a writer for FormatCSV or FormatXLSX
+func NewWriter(format string, w io.Writer, opts Options) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w)
+	case FormatXLSX:
+		return NewXLSXWriter(w, opts)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+const timeLayout = "2006-01-02 15:04"
+
+// formatValue renders a value as text; ok is false for nil
+func formatValue(v any) (string, bool) {
+	switch x := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return x, true
+	case bool:
+		if x {
+			return "true", true
+		}
+		return "false", true
+	case time.Time:
+		if x.IsZero() {
+			return "", false
+		}
+		return x.Format(timeLayout), true
+	case *time.Time:
+		if x == nil || x.IsZero() {
+			return "", false
+		}
+		return x.Format(timeLayout), true
+	case int:
+		return strconv.Itoa(x), true
+	case int64:
+		return strconv.FormatInt(x, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(x), 10), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	return fmt.Sprint(v), true
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter writes UTF-8 CSV with a byte order mark so Excel shows Arabic correctly
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteHeader(titles []string) error {
+	return c.w.Write(titles)
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		s, _ := formatValue(v)
+		// Text that looks like a formula is quoted so spreadsheet apps don't evaluate it
+		if _, isText := v.(string); isText && s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+			s = "'" + s
+		}
+		record[i] = s
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+const (
+	nsMain  = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	nsRel   = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	nsPkg   = "http://schemas.openxmlformats.org/package/2006/relationships"
+	xmlDecl = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+)
+
+// NewXLSXWriter writes a single-sheet workbook; the sheet is streamed as the last zip entry
+func NewXLSXWriter(w io.Writer, opts Options) (Writer, error) {
+	name := sheetName(opts.SheetName)
+	zw := zip.NewWriter(w)
+
+	var nameXML strings.Builder
+	xml.EscapeText(&nameXML, []byte(name))
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="` + nsPkg + `">` +
+			`<Relationship Id="rId1" Type="` + nsRel + `/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="` + nsMain + `" xmlns:r="` + nsRel + `">` +
+			`<sheets><sheet name="` + nameXML.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="` + nsPkg + `">` +
+			`<Relationship Id="rId1" Type="` + nsRel + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="` + nsRel + `/styles" Target="styles.xml"/></Relationships>`},
+		// Style 1 is bold, for the header row
+		{"xl/styles.xml", `<styleSheet xmlns="` + nsMain + `">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, xmlDecl+p.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	sheet.WriteString(xmlDecl + `<worksheet xmlns="` + nsMain + `">`)
+	if opts.RightToLeft {
+		sheet.WriteString(`<sheetViews><sheetView rightToLeft="1" workbookViewId="0"/></sheetViews>`)
+	}
+	sheet.WriteString(`<sheetData>`)
+	return &xlsxWriter{zw: zw, sheet: sheet}, nil
+}
+
+// sheetName strips the characters Excel rejects in sheet names
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if r := []rune(name); len(r) > 31 {
+		name = string(r[:31])
+	}
+	if strings.TrimSpace(name) == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// columnName converts a zero-based column to its letters, e.g. 27 to AB
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func (x *xlsxWriter) WriteHeader(titles []string) error {
+	values := make([]any, len(titles))
+	for i, t := range titles {
+		values[i] = t
+	}
+	return x.writeRow(values, ` s="1"`)
+}
+
+func (x *xlsxWriter) WriteRow(values []any) error {
+	return x.writeRow(values, "")
+}
+
+func (x *xlsxWriter) writeRow(values []any, style string) error {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+	for i, v := range values {
+		s, ok := formatValue(v)
+		if !ok {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(x.row)
+		switch v.(type) {
+		case int, int64, uint, float64:
+			fmt.Fprintf(x.sheet, `<c r="%s"%s><v>%s</v></c>`, ref, style, s)
+		case bool:
+			b := "0"
+			if v.(bool) {
+				b = "1"
+			}
+			fmt.Fprintf(x.sheet, `<c r="%s"%s t="b"><v>%s</v></c>`, ref, style, b)
+		default:
+			fmt.Fprintf(x.sheet, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, ref, style)
+			xml.EscapeText(x.sheet, []byte(s))
+			x.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
@@ -0,0 +1,97 @@
+// Package storage keeps uploaded files in a pluggable backend: the local disk or an
+// S3-compatible object store. Files are addressed by slash-separated keys and handed to
+// browsers through signed, expiring URLs.
+package storage
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+	ErrBadURL     = errors.New("storage: invalid or expired link")
+)
+
+// Object describes a stored file
+type Object struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// URLOptions control how a browser treats a signed download
+type URLOptions struct {
+	Filename    string // Suggested file name
+	Inline      bool   // Display in the browser instead of saving
+	ContentType string // Overrides the stored content type
+}
+
+// Backend stores files by key
+type Backend interface {
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// Get opens a stored file; the caller closes it. A missing file returns ErrNotFound.
+	Get(key string) (io.ReadCloser, *Object, error)
+	// Delete removes a file; a missing file is not an error
+	Delete(key string) error
+	// SignedURL returns a URL that downloads the file without further authentication
+	// until it expires
+	SignedURL(key string, expiry time.Duration, opts URLOptions) (string, error)
+}
+
+// CleanKey normalises a key and rejects keys that would escape the store. Paths of files
+// uploaded before keys existed start with /uploads/ and are accepted.
+func CleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(strings.ReplaceAll(key, "\\", "/"), "/uploads/")
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return path.Clean(key), nil
+}
+
+// disposition builds a Content-Disposition value from URL options
+func disposition(opts URLOptions) string {
+	kind := "attachment"
+	if opts.Inline {
+		kind = "inline"
+	}
+	if opts.Filename == "" {
+		return kind
+	}
+	// RFC 6266: a plain fallback plus the UTF-8 name
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, opts.Filename)
+	return kind + `; filename="` + fallback + `"; filename*=UTF-8''` + escape(opts.Filename, true)
+}
+
+// escape percent-encodes everything but unreserved characters, and slashes unless encodeSlash
+func escape(s string, encodeSlash bool) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,162 @@
+// Package textmatch normalises and compares names, phone numbers and emails typed by hand,
+// with the Arabic spelling variants that commonly differ between data-entry clerks.
+package textmatch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// arabicFolding maps letter variants to one canonical letter
+var arabicFolding = map[rune]rune{
+	'أ': 'ا', 'إ': 'ا', 'آ': 'ا', 'ٱ': 'ا',
+	'ة': 'ه',
+	'ى': 'ي', 'ئ': 'ي', 'ی': 'ي',
+	'ؤ': 'و',
+	'ک': 'ك',
+}
+
+// companyWords are legal-form and filler words ignored when comparing names
+var companyWords = map[string]bool{
+	"شركه": true, "مؤسسه": true, "موسسه": true, "مكتب": true, "محل": true, "معرض": true,
+	"شمم": true, "ذمم": true, "co": true, "company": true, "ltd": true, "llc": true, "inc": true, "corp": true,
+	"est": true, "and": true, "the": true,
+}
+
+// digitValue converts ASCII, Arabic-Indic and Extended Arabic-Indic digits
+func digitValue(r rune) (rune, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		return r, true
+	case r >= '٠' && r <= '٩':
+		return '0' + (r - '٠'), true
+	case r >= '۰' && r <= '۹':
+		return '0' + (r - '۰'), true
+	}
+	return 0, false
+}
+
+// NormalizeName folds case, Arabic letter variants, diacritics and tatweel, drops punctuation
+// and company words and collapses whitespace
+func NormalizeName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if d, ok := digitValue(r); ok {
+			b.WriteRune(d)
+			continue
+		}
+		if (r >= 0x064B && r <= 0x065F) || r == 0x0670 || r == 0x0640 {
+			continue // harakat, superscript alef, tatweel
+		}
+		if f, ok := arabicFolding[r]; ok {
+			r = f
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			b.WriteRune(r)
+		case r == '.' || r == '\'' || r == '’':
+			// "Co." and "ش.م.م" collapse into a single word
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	kept := words[:0]
+	for _, w := range words {
+		if !companyWords[w] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+	return strings.Join(kept, " ")
+}
+
+// NormalizePhone keeps the digits of a phone number and strips the Egyptian country code,
+// so +20 100 123 4567, 0020-1001234567 and ٠١٠٠١٢٣٤٥٦٧ all become 01001234567.
+// Numbers shorter than seven digits are treated as empty.
+func NormalizePhone(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if d, ok := digitValue(r); ok {
+			b.WriteRune(d)
+		}
+	}
+	digits := strings.TrimPrefix(b.String(), "00")
+	if strings.HasPrefix(digits, "20") && len(digits) >= 11 {
+		digits = "0" + digits[2:]
+	}
+	if len(digits) < 7 {
+		return ""
+	}
+	return digits
+}
+
+// NormalizeEmail lowercases and trims an email address
+func NormalizeEmail(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// Similarity returns how alike two normalised names are, from 0 to 1. It takes the better of
+// the edit-distance ratio and the word overlap, so reordered words still match.
+func Similarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	edit := 1 - float64(levenshtein(ra, rb))/float64(longest)
+
+	return max(edit, wordOverlap(strings.Fields(a), strings.Fields(b)))
+}
+
+// wordOverlap is the Dice coefficient of the two word sets, counting words one edit apart as equal
+func wordOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	used := make([]bool, len(b))
+	matches := 0
+	for _, wa := range a {
+		for j, wb := range b {
+			if used[j] {
+				continue
+			}
+			if wa == wb || (len([]rune(wa)) > 3 && levenshtein([]rune(wa), []rune(wb)) <= 1) {
+				used[j] = true
+				matches++
+				break
+			}
+		}
+	}
+	return 2 * float64(matches) / float64(len(a)+len(b))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -22,9 +22,39 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 		&domain.CustomerActivity{},
 		&domain.CustomerDocument{},
 		&domain.Notification{},
+		&domain.NotificationSubscription{},
+		&domain.NotificationPreference{},
 		&domain.SalesOrder{},
+		&domain.SalesOrderItem{},
+		&domain.SalesOrderTax{},
+		&domain.SalesOrderRevision{},
+		&domain.TaxCode{},
+		&domain.SystemSetting{},
+		&domain.Promotion{},
+		&domain.DiscountPolicy{},
+		&domain.ExchangeRate{},
+		&domain.SalesPayment{},
+		&domain.CreditOverride{},
+		&domain.CreditEvent{},
+		&domain.CustomerMerge{},
+		&domain.FollowUpCadence{},
+		&domain.ImportJob{},
+		&domain.Tag{},
+		&domain.Segment{},
+		&domain.WhatsAppCampaign{},
+		&domain.OutboundMessage{},
+		&domain.MessageDelivery{},
+		&domain.MessageTemplate{},
+		&domain.AuditLog{},
+		&domain.Warehouse{},
+		&domain.WarehouseStock{},
+		&domain.DeliveryNote{},
+		&domain.DeliveryNoteItem{},
+		&domain.DomainEvent{},
 		&domain.Product{},
+		&domain.Category{},
 		&domain.ProductionOrder{},
+		&domain.ProductionBatch{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
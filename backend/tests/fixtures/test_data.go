@@ -2,6 +2,7 @@ package fixtures
 
 import (
 	"erp-system/internal/domain"
+	"erp-system/pkg/money"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -57,8 +58,7 @@ func TestCustomers() []*domain.Customer {
 			Country:           "Egypt",
 			PostalCode:        "11511",
 			TaxNumber:         "123-456-789",
-			CreditLimit:       50000,
-			Balance:           0,
+			CreditLimit:       money.FromFloat(50000),
 			Type:              "vip",
 			Status:            "active",
 			IsWhatsAppEnabled: true,
@@ -79,8 +79,7 @@ func TestCustomers() []*domain.Customer {
 			Country:           "Egypt",
 			PostalCode:        "21500",
 			TaxNumber:         "987-654-321",
-			CreditLimit:       30000,
-			Balance:           0,
+			CreditLimit:       money.FromFloat(30000),
 			Type:              "regular",
 			Status:            "active",
 			IsWhatsAppEnabled: false,
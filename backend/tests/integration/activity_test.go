@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/tests/fixtures"
+)
+
+// TestCustomerActivities_Integration verifies activity editing, assignment, completion,
+// snoozing and the per-salesperson open list
+func TestCustomerActivities_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	customerUC := usecases.NewCustomerUseCase(repositories.NewCustomerRepository(db), repositories.NewCustomerActivityRepository(db),
+		repositories.NewCustomerDocumentRepository(db), repositories.NewUserRepository(db), nil, nil, nil)
+
+	due := time.Now().Add(-time.Hour)
+	salesperson := uint(2)
+	disabled := false
+	call, err := customerUC.AddActivity(2, &domain.SaveActivityRequest{
+		Type: "call", Description: "Follow up on the quote", ReminderDate: &due, AssignedTo: &salesperson, NotificationEnabled: &disabled,
+	}, 1)
+	if err != nil {
+		t.Fatalf("AddActivity failed: %v", err)
+	}
+	if call.ReminderDate == nil || call.AssignedTo == nil || *call.AssignedTo != 2 || call.Assignee == nil {
+		t.Errorf("Expected the reminder date and assignee to be saved, got %+v", call)
+	}
+	if call.NotificationEnabled {
+		t.Error("Expected notifications to stay disabled")
+	}
+
+	note, err := customerUC.AddActivity(1, &domain.SaveActivityRequest{Type: "note", Description: "Prefers mornings"}, 1)
+	if err != nil {
+		t.Fatalf("AddActivity failed: %v", err)
+	}
+	if note.AssignedTo == nil || *note.AssignedTo != 1 {
+		t.Errorf("Expected the creator as default assignee, got %v", note.AssignedTo)
+	}
+
+	stranger := uint(99)
+	if _, err := customerUC.AddActivity(1, &domain.SaveActivityRequest{Type: "call", Description: "x", AssignedTo: &stranger}, 1); err == nil {
+		t.Error("Expected an unknown assignee to fail")
+	}
+	if _, err := customerUC.AddActivity(999, &domain.SaveActivityRequest{Type: "call", Description: "x"}, 1); err == nil {
+		t.Error("Expected an unknown customer to fail")
+	}
+
+	mine, total, err := customerUC.ListActivities(domain.ActivityFilter{AssignedTo: &salesperson, Status: domain.ActivityStatusOpen}, 1, 20)
+	if err != nil {
+		t.Fatalf("ListActivities failed: %v", err)
+	}
+	if total != 1 || mine[0].ID != call.ID || mine[0].Customer == nil || mine[0].Customer.ID != 2 {
+		t.Fatalf("Expected the call with its customer as the salesperson's only open activity, got %d %+v", total, mine)
+	}
+	_, overdue, _ := customerUC.ListActivities(domain.ActivityFilter{AssignedTo: &salesperson, Status: domain.ActivityStatusOverdue}, 1, 20)
+	if overdue != 1 {
+		t.Errorf("Expected the call to be overdue, got %d", overdue)
+	}
+
+	// Snoozing moves the reminder and makes it deliverable again
+	db.Model(&domain.CustomerActivity{}).Where("id = ?", call.ID).Update("notified_at", time.Now())
+	if _, err := customerUC.SnoozeActivity(call.ID, &domain.SnoozeActivityRequest{}); err == nil {
+		t.Error("Expected a snooze without a time to fail")
+	}
+	snoozed, err := customerUC.SnoozeActivity(call.ID, &domain.SnoozeActivityRequest{Minutes: 30})
+	if err != nil {
+		t.Fatalf("SnoozeActivity failed: %v", err)
+	}
+	if snoozed.NotifiedAt != nil || !snoozed.ReminderDate.After(time.Now().Add(29*time.Minute)) {
+		t.Errorf("Expected the reminder 30 minutes out and undelivered, got %+v", snoozed)
+	}
+	_, overdue, _ = customerUC.ListActivities(domain.ActivityFilter{AssignedTo: &salesperson, Status: domain.ActivityStatusOverdue}, 1, 20)
+	if overdue != 0 {
+		t.Errorf("Expected no overdue activities after snoozing, got %d", overdue)
+	}
+
+	// Editing can reassign
+	admin := uint(1)
+	updated, err := customerUC.UpdateActivity(call.ID, &domain.SaveActivityRequest{Type: "meeting", Description: "Visit the showroom", AssignedTo: &admin})
+	if err != nil {
+		t.Fatalf("UpdateActivity failed: %v", err)
+	}
+	if updated.Type != "meeting" || *updated.AssignedTo != 1 || updated.ReminderDate != nil {
+		t.Errorf("Expected the activity replaced and reassigned, got %+v", updated)
+	}
+
+	done, err := customerUC.CompleteActivity(call.ID, &domain.CompleteActivityRequest{Outcome: "Ordered"}, 1)
+	if err != nil {
+		t.Fatalf("CompleteActivity failed: %v", err)
+	}
+	if !done.IsCompleted || done.CompletedAt == nil || done.Outcome != "Ordered" {
+		t.Errorf("Expected the activity completed, got %+v", done)
+	}
+	if _, err := customerUC.CompleteActivity(call.ID, &domain.CompleteActivityRequest{}, 1); err == nil {
+		t.Error("Expected completing twice to fail")
+	}
+	if _, err := customerUC.SnoozeActivity(call.ID, &domain.SnoozeActivityRequest{Minutes: 5}); err == nil {
+		t.Error("Expected snoozing a completed activity to fail")
+	}
+	if reopened, err := customerUC.ReopenActivity(call.ID); err != nil || reopened.IsCompleted || reopened.CompletedAt != nil {
+		t.Errorf("Expected the activity reopened, got %+v %v", reopened, err)
+	}
+
+	if err := customerUC.DeleteActivity(note.ID); err != nil {
+		t.Fatalf("DeleteActivity failed: %v", err)
+	}
+	if _, err := customerUC.GetActivity(note.ID); err == nil {
+		t.Error("Expected the deleted activity to be gone")
+	}
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/tests/fixtures"
+)
+
+// TestActivityFollowUps_Integration verifies that completing a recurring activity schedules
+// the next occurrence and that cadences schedule their steps when their trigger completes
+func TestActivityFollowUps_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	customerUC := usecases.NewCustomerUseCase(repositories.NewCustomerRepository(db), repositories.NewCustomerActivityRepository(db),
+		repositories.NewCustomerDocumentRepository(db), repositories.NewUserRepository(db), nil, nil, nil)
+
+	if _, err := customerUC.AddActivity(1, &domain.SaveActivityRequest{
+		Type: "call", Description: "x", Recurrence: &domain.ActivityRecurrence{Frequency: "hourly"},
+	}, 1); err == nil {
+		t.Error("Expected an unknown frequency to fail")
+	}
+
+	// A weekly check-in limited to two occurrences
+	first := time.Now().Add(-time.Hour)
+	manager := uint(2)
+	checkIn, err := customerUC.AddActivity(1, &domain.SaveActivityRequest{
+		Type: "call", Description: "Weekly stock check", ReminderDate: &first, AssignedTo: &manager,
+		Recurrence: &domain.ActivityRecurrence{Frequency: domain.RecurrenceWeekly, Count: 2},
+	}, 1)
+	if err != nil {
+		t.Fatalf("AddActivity failed: %v", err)
+	}
+	if checkIn.Recurrence == nil || checkIn.Recurrence.Occurrence != 1 || checkIn.Recurrence.Interval != 1 {
+		t.Fatalf("Expected the first occurrence of a weekly rule, got %+v", checkIn.Recurrence)
+	}
+
+	done, err := customerUC.CompleteActivity(checkIn.ID, &domain.CompleteActivityRequest{}, 1)
+	if err != nil {
+		t.Fatalf("CompleteActivity failed: %v", err)
+	}
+	if len(done.FollowUps) != 1 {
+		t.Fatalf("Expected the next occurrence, got %+v", done.FollowUps)
+	}
+	second := done.FollowUps[0]
+	if !second.ReminderDate.Equal(first.AddDate(0, 0, 7)) || second.Recurrence.Occurrence != 2 ||
+		*second.AssignedTo != manager || *second.PreviousID != checkIn.ID || second.IsCompleted {
+		t.Errorf("Expected an open second occurrence a week later for the same assignee, got %+v", second)
+	}
+
+	// Completing again after reopening does not duplicate the next occurrence
+	customerUC.ReopenActivity(checkIn.ID)
+	again, _ := customerUC.CompleteActivity(checkIn.ID, &domain.CompleteActivityRequest{}, 1)
+	if len(again.FollowUps) != 1 || again.FollowUps[0].ID != second.ID {
+		t.Errorf("Expected the existing follow-up, got %+v", again.FollowUps)
+	}
+
+	// The second occurrence is the last
+	last, err := customerUC.CompleteActivity(second.ID, &domain.CompleteActivityRequest{}, 1)
+	if err != nil {
+		t.Fatalf("CompleteActivity failed: %v", err)
+	}
+	if len(last.FollowUps) != 0 {
+		t.Errorf("Expected the series to end after two occurrences, got %+v", last.FollowUps)
+	}
+
+	// Cadences
+	if _, err := customerUC.CreateCadence(&domain.SaveCadenceRequest{Name: "Bad", TriggerType: "quote", Steps: []domain.CadenceStep{{AfterDays: -1, Type: "call", Description: "x"}}}, 1); err == nil {
+		t.Error("Expected negative step days to fail")
+	}
+	cadence, err := customerUC.CreateCadence(&domain.SaveCadenceRequest{
+		Name: "Quote follow-up", TriggerType: "quote",
+		Steps: []domain.CadenceStep{{AfterDays: 3, Type: "call", Description: "Discuss the quote"}, {AfterDays: 10, Type: "call", Description: "Last chance"}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateCadence failed: %v", err)
+	}
+	if !cadence.Active {
+		t.Error("Expected cadences to be active by default")
+	}
+	if _, err := customerUC.CreateCadence(&domain.SaveCadenceRequest{Name: "Quote follow-up", TriggerType: "quote", Steps: cadence.Steps}, 1); err == nil {
+		t.Error("Expected a duplicate name to fail")
+	}
+	inactive := false
+	if _, err := customerUC.CreateCadence(&domain.SaveCadenceRequest{Name: "Paused", TriggerType: "quote", Active: &inactive, Steps: cadence.Steps}, 1); err != nil {
+		t.Fatalf("CreateCadence failed: %v", err)
+	}
+
+	quote, _ := customerUC.AddActivity(2, &domain.SaveActivityRequest{Type: "quote", Description: "Sent quote Q-17"}, 1)
+	completed, err := customerUC.CompleteActivity(quote.ID, &domain.CompleteActivityRequest{Outcome: "Sent"}, 1)
+	if err != nil {
+		t.Fatalf("CompleteActivity failed: %v", err)
+	}
+	if len(completed.FollowUps) != 2 {
+		t.Fatalf("Expected the two steps of the active cadence, got %+v", completed.FollowUps)
+	}
+	for i, days := range []int{3, 10} {
+		f := completed.FollowUps[i]
+		if f.CadenceID == nil || *f.CadenceID != cadence.ID || f.CustomerID != 2 || !f.ReminderDate.Equal(completed.CompletedAt.AddDate(0, 0, days)) {
+			t.Errorf("Step %d: expected a call %d days after completion, got %+v", i, days, f)
+		}
+	}
+
+	// An updated cadence applies to later completions
+	cadence, err = customerUC.UpdateCadence(cadence.ID, &domain.SaveCadenceRequest{
+		Name: "Quote follow-up", TriggerType: "quote", Steps: []domain.CadenceStep{{AfterDays: 1, Type: "call", Description: "Call tomorrow"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateCadence failed: %v", err)
+	}
+	quote2, _ := customerUC.AddActivity(2, &domain.SaveActivityRequest{Type: "quote", Description: "Sent quote Q-18"}, 1)
+	completed2, _ := customerUC.CompleteActivity(quote2.ID, &domain.CompleteActivityRequest{}, 1)
+	if len(completed2.FollowUps) != 1 || completed2.FollowUps[0].Description != "Call tomorrow" {
+		t.Errorf("Expected the updated single step, got %+v", completed2.FollowUps)
+	}
+
+	if err := customerUC.DeleteCadence(cadence.ID); err != nil {
+		t.Fatalf("DeleteCadence failed: %v", err)
+	}
+	cadences, _ := customerUC.GetCadences()
+	if len(cadences) != 1 || cadences[0].Name != "Paused" {
+		t.Errorf("Expected only the paused cadence left, got %+v", cadences)
+	}
+}
@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/messaging"
+	"erp-system/tests/fixtures"
+)
+
+// TestConversation_Integration verifies inbound WhatsApp webhooks: signature checks, matching
+// senders to customers, logging, redelivery, opt-out keywords and the two-way conversation
+func TestConversation_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	activityRepo := repositories.NewCustomerActivityRepository(db)
+	notifRepo := repositories.NewNotificationRepository(db)
+	outboxRepo := repositories.NewOutboundMessageRepository(db)
+	uc := usecases.NewConversationUseCase(customerRepo, activityRepo, notifRepo, outboxRepo, settingsRepo)
+
+	send := func(payload map[string]string) (*domain.InboundResult, error) {
+		body, _ := json.Marshal(payload)
+		return uc.Receive(body, messaging.Sign("app-secret", body))
+	}
+
+	// Rejected until a secret is configured, then only when signed with it
+	if _, err := send(map[string]string{"phone": "01012345678", "message": "hi"}); !errors.Is(err, usecases.ErrInvalidSignature) {
+		t.Fatalf("Expected webhooks to be rejected without a secret, got %v", err)
+	}
+	settingsRepo.Set(domain.SettingWhatsAppAppSecret, "app-secret", "integration")
+	if _, err := uc.Receive([]byte(`{"phone":"01012345678","message":"hi"}`), "sha256=00"); !errors.Is(err, usecases.ErrInvalidSignature) {
+		t.Errorf("Expected a bad signature to be rejected, got %v", err)
+	}
+
+	// Customer 1's open follow-up is assigned to user 2, who hears about the message
+	salesperson := uint(2)
+	if err := activityRepo.Create(&domain.CustomerActivity{CustomerID: 1, Type: "call", Description: "Follow up", AssignedTo: &salesperson, CreatedBy: 1}); err != nil {
+		t.Fatalf("Create activity failed: %v", err)
+	}
+	customerID := uint(1)
+	outboxRepo.Create(&domain.OutboundMessage{Channel: domain.ChannelWhatsApp, Recipient: "01012345678", Body: "Your order is ready",
+		CustomerID: &customerID, Status: domain.MessageStatusSent, CreatedAt: time.Now().Add(-time.Hour)})
+
+	result, err := send(map[string]string{"id": "gw-1", "phone": "+20 101 234 5678", "message": "متى التركيب؟"})
+	if err != nil || result.Logged != 1 {
+		t.Fatalf("Expected the message to be logged on customer 1, got %+v (%v)", result, err)
+	}
+	if result, _ := send(map[string]string{"id": "gw-1", "phone": "+20 101 234 5678", "message": "متى التركيب؟"}); result.Duplicate != 1 || result.Logged != 0 {
+		t.Errorf("Expected a redelivered message to be ignored, got %+v", result)
+	}
+	if result, _ := send(map[string]string{"id": "gw-2", "phone": "01200000000", "message": "hello"}); result.Unmatched != 1 {
+		t.Errorf("Expected an unknown sender to be unmatched, got %+v", result)
+	}
+
+	notifs, _ := notifRepo.GetUnreadByUserID(2)
+	if len(notifs) != 1 || notifs[0].Message != "متى التركيب؟" || notifs[0].Link != "/customers/1" {
+		t.Errorf("Expected the salesperson to be notified, got %+v", notifs)
+	}
+
+	conversation, err := uc.GetConversation(1)
+	if err != nil || len(conversation) != 2 {
+		t.Fatalf("Expected 2 conversation entries, got %d (%v)", len(conversation), err)
+	}
+	if conversation[0].Direction != domain.DirectionOut || conversation[1].Direction != domain.DirectionIn || conversation[1].Body != "متى التركيب؟" {
+		t.Errorf("Expected the sent message then the reply, got %+v", conversation)
+	}
+
+	// Opt-out keywords turn WhatsApp off; customer 3 was created by user 1
+	if result, _ := send(map[string]string{"id": "gw-3", "phone": "01155443322", "message": " STOP! "}); result.OptedOut != 1 {
+		t.Errorf("Expected STOP to opt the customer out, got %+v", result)
+	}
+	if customer, _ := customerRepo.FindByID(3); customer.IsWhatsAppEnabled {
+		t.Error("Expected WhatsApp to be disabled for customer 3")
+	}
+	if notifs, _ := notifRepo.GetUnreadByUserID(1); len(notifs) != 1 || notifs[0].Type != "warning" {
+		t.Errorf("Expected the creator to be warned about the opt-out, got %+v", notifs)
+	}
+
+	settingsRepo.Set(domain.SettingWhatsAppOptOut, "cancel", "integration")
+	if result, _ := send(map[string]string{"phone": "01012345678", "message": "stop"}); result.OptedOut != 0 || result.Logged != 1 {
+		t.Errorf("Expected configured keywords to replace the defaults, got %+v", result)
+	}
+
+	// Cloud API subscription check
+	settingsRepo.Set(domain.SettingWhatsAppVerifyToken, "verify-me", "integration")
+	if challenge, err := uc.VerifySubscription("subscribe", "verify-me", "42"); err != nil || challenge != "42" {
+		t.Errorf("Expected the challenge back, got %q (%v)", challenge, err)
+	}
+	if _, err := uc.VerifySubscription("subscribe", "wrong", "42"); err == nil {
+		t.Error("Expected a wrong verify token to be refused")
+	}
+}
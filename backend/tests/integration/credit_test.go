@@ -0,0 +1,220 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"erp-system/api/routes"
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/auth"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func newCreditUseCase(db *gorm.DB) *usecases.CreditUseCase {
+	return usecases.NewCreditUseCase(
+		repositories.NewCreditRepository(db),
+		repositories.NewCustomerRepository(db),
+		repositories.NewSalesRepository(db),
+		newCreditService(db),
+	)
+}
+
+// statusAs sends a bodiless request as a user of the given role and returns the status code
+func statusAs(router *gin.Engine, method, path string, roleID uint) int {
+	req := httptest.NewRequest(method, path, nil)
+	token, _ := auth.GenerateAccessToken(1, "user@erp.local", roleID)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func hasCreditEvent(events []domain.CreditEvent, eventType string) bool {
+	for _, e := range events {
+		if e.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// TestCreditLimitQueueAndOverride_Integration verifies orders over the limit wait in the
+// blocked queue until a sufficient manager override releases them
+func TestCreditLimitQueueAndOverride_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	salesUC := newSalesUseCase(db)
+	creditUC := newCreditUseCase(db)
+	custRepo := repositories.NewCustomerRepository(db)
+
+	// Customer 2 has a 30000 limit and no balance
+	order, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 2,
+		OrderDate:  time.Now(),
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(31000)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if order.Status != domain.OrderStatusCreditHold || order.CreditNote == "" {
+		t.Fatalf("Expected order on credit hold with a reason, got %s %q", order.Status, order.CreditNote)
+	}
+	if customer, _ := custRepo.FindByID(2); !customer.Balance.IsZero() {
+		t.Errorf("Blocked orders must not be booked, balance is %s", customer.Balance)
+	}
+
+	var notifications int64
+	db.Model(&domain.Notification{}).Where("user_id = ?", 2).Count(&notifications)
+	if notifications == 0 {
+		t.Error("Expected the manager to be notified about the blocked order")
+	}
+
+	if _, err := creditUC.ReleaseOrder(order.ID, "", 2); err == nil {
+		t.Error("Expected release to fail without an override")
+	}
+
+	// An override capped at 500 above the limit does not cover 1000
+	if _, err := creditUC.GrantOverride(2, &domain.GrantCreditOverrideRequest{Reason: "Trusted", MaxAmount: money.FromFloat(500)}, 2); err != nil {
+		t.Fatalf("GrantOverride failed: %v", err)
+	}
+	blocked, _ := creditUC.GetBlockedOrders(2)
+	if len(blocked) != 1 {
+		t.Fatalf("Expected the order to stay blocked, got %d blocked orders", len(blocked))
+	}
+
+	if _, err := creditUC.GrantOverride(2, &domain.GrantCreditOverrideRequest{Reason: "Year-end deal", Hours: 2}, 2); err != nil {
+		t.Fatalf("GrantOverride failed: %v", err)
+	}
+	released, _ := salesUC.GetOrder(order.ID)
+	if released.Status != domain.OrderStatusDraft {
+		t.Errorf("Expected the override to release the order, got %s", released.Status)
+	}
+	customer, _ := custRepo.FindByID(2)
+	if customer.Balance != money.FromFloat(31000) {
+		t.Errorf("Expected balance 31000 after release, got %s", customer.Balance)
+	}
+
+	if _, err := creditUC.GrantOverride(2, &domain.GrantCreditOverrideRequest{Reason: "Too long", Hours: 1000}, 2); err == nil {
+		t.Error("Expected error for an override longer than a week")
+	}
+
+	history, _ := creditUC.GetCreditHistory(2, 0)
+	for _, eventType := range []string{domain.CreditEventOrderBlocked, domain.CreditEventOverrideGranted, domain.CreditEventOrderReleased, domain.CreditEventOverrideUsed, domain.CreditEventWarning} {
+		if !hasCreditEvent(history, eventType) {
+			t.Errorf("Expected %s in the credit history", eventType)
+		}
+	}
+}
+
+// TestCreditOverdueHold_Integration verifies overdue invoices put the customer on hold
+// and that settling them lifts the hold and releases queued orders
+func TestCreditOverdueHold_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	salesUC := newSalesUseCase(db)
+	creditUC := newCreditUseCase(db)
+
+	// Payment terms default to 30 days
+	old, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now().AddDate(0, 0, -45),
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(1000)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	result, err := creditUC.EvaluateCredit()
+	if err != nil {
+		t.Fatalf("EvaluateCredit failed: %v", err)
+	}
+	if result.HoldsPlaced != 1 {
+		t.Errorf("Expected one hold, got %+v", result)
+	}
+
+	status, _ := creditUC.GetCreditStatus(1)
+	if !status.OnHold || status.OverdueCount != 1 || status.OverdueAmount != money.FromFloat(1000) {
+		t.Errorf("Unexpected credit status %+v", status)
+	}
+
+	queued, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(100)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if queued.Status != domain.OrderStatusCreditHold {
+		t.Errorf("Expected new order on credit hold, got %s", queued.Status)
+	}
+
+	if _, err := salesUC.RecordPayment(old.ID, &domain.RecordPaymentRequest{Amount: old.NetAmount, PaymentDate: time.Now()}, 1); err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+
+	result, err = creditUC.EvaluateCredit()
+	if err != nil {
+		t.Fatalf("EvaluateCredit failed: %v", err)
+	}
+	if result.HoldsReleased != 1 || result.OrdersReleased != 1 {
+		t.Errorf("Expected the hold and the queued order to be released, got %+v", result)
+	}
+
+	// Manual holds are not lifted by the scan
+	if _, err := creditUC.PlaceHold(1, "Disputed invoices", 2); err != nil {
+		t.Fatalf("PlaceHold failed: %v", err)
+	}
+	if _, err := creditUC.EvaluateCredit(); err != nil {
+		t.Fatalf("EvaluateCredit failed: %v", err)
+	}
+	if status, _ := creditUC.GetCreditStatus(1); !status.OnHold {
+		t.Error("Expected the manual hold to stay in place")
+	}
+}
+
+// TestCreditRoutesRequireManager_Integration verifies only managers and admins can place or
+// release holds, grant or revoke overrides and release or reject blocked orders
+func TestCreditRoutesRequireManager_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupCreditRoutes(router, handlers.NewCreditHandler(newCreditUseCase(db)))
+
+	const salesRole = 3
+	guarded := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/customers/1/credit/hold"},
+		{http.MethodDelete, "/api/v1/customers/1/credit/hold"},
+		{http.MethodPost, "/api/v1/customers/1/credit/overrides"},
+		{http.MethodPost, "/api/v1/credit/blocked-orders/1/release"},
+		{http.MethodPost, "/api/v1/credit/blocked-orders/1/reject"},
+		{http.MethodDelete, "/api/v1/credit/overrides/1"},
+	}
+	for _, r := range guarded {
+		if code := statusAs(router, r.method, r.path, salesRole); code != http.StatusForbidden {
+			t.Errorf("%s %s: expected a sales user to get 403, got %d", r.method, r.path, code)
+		}
+		if code := statusAs(router, r.method, r.path, domain.RoleManager); code == http.StatusForbidden {
+			t.Errorf("%s %s: expected a manager to be let through", r.method, r.path)
+		}
+	}
+	if code := statusAs(router, http.MethodGet, "/api/v1/customers/1/credit", salesRole); code != http.StatusOK {
+		t.Errorf("Expected a sales user to see the credit status, got %d", code)
+	}
+}
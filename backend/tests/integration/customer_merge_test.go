@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+)
+
+// TestCustomerDuplicateMerge_Integration verifies a re-entered customer is detected and that
+// merging moves orders, activities, documents and balance onto the survivor
+func TestCustomerDuplicateMerge_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	customerRepo := repositories.NewCustomerRepository(db)
+	activityRepo := repositories.NewCustomerActivityRepository(db)
+	documentRepo := repositories.NewCustomerDocumentRepository(db)
+	customerUC := usecases.NewCustomerUseCase(customerRepo, activityRepo, documentRepo, nil, nil, nil, nil)
+
+	// The same person as fixture customer 1, typed differently
+	dup := &domain.Customer{
+		Code:        "CUST-09001",
+		Name:        "احمد محمّد",
+		Phone:       "+20 10 1234 5678",
+		CreditLimit: money.FromFloat(10000),
+		Balance:     money.FromFloat(1500),
+		Status:      "active",
+		CreatedBy:   1,
+	}
+	if err := customerRepo.Create(dup); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	order := &domain.SalesOrder{OrderNumber: "SO-DUP-1", CustomerID: dup.ID, OrderDate: time.Now(), Status: domain.OrderStatusDraft, CreatedBy: 1}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("Create order failed: %v", err)
+	}
+	db.Create(&domain.CustomerActivity{CustomerID: dup.ID, Type: "note", Description: "Called", CreatedBy: 1})
+	db.Create(&domain.CustomerDocument{CustomerID: dup.ID, Title: "ID", FilePath: "id.pdf"})
+	message := &domain.OutboundMessage{Channel: domain.ChannelWhatsApp, Recipient: "01012345678", Body: "Hello", CustomerID: &dup.ID}
+	db.Create(message)
+
+	candidates, err := customerUC.FindDuplicates(1, 0, 0)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Duplicate.ID != dup.ID {
+		t.Fatalf("Expected the re-entered customer as the only duplicate, got %+v", candidates)
+	}
+	if candidates[0].Score < 0.95 || len(candidates[0].Reasons) < 2 {
+		t.Errorf("Expected phone and name to match, got %.2f %v", candidates[0].Score, candidates[0].Reasons)
+	}
+
+	// Names alike through a typo are found without comparing every pair of customers
+	typo := []*domain.Customer{
+		{Code: "CUST-09002", Name: "Delta Glass Trading", Email: "sales@deltaglass.example", Status: "active", CreatedBy: 1},
+		{Code: "CUST-09003", Name: "Delta Glas Trading", Email: "info@deltaglas.example", Status: "active", CreatedBy: 1},
+	}
+	for _, c := range typo {
+		if err := customerRepo.Create(c); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	all, err := customerUC.FindDuplicates(0, 0, 0)
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	found := false
+	for _, c := range all {
+		found = found || (c.Customer.ID == typo[0].ID && c.Duplicate.ID == typo[1].ID)
+	}
+	if !found {
+		t.Errorf("Expected the misspelt name to be reported, got %+v", all)
+	}
+
+	if _, err := customerUC.MergeCustomers(1, &domain.MergeCustomersRequest{MergeID: 1}, 1); err == nil {
+		t.Error("Expected merging a customer into itself to fail")
+	}
+
+	merge, err := customerUC.MergeCustomers(1, &domain.MergeCustomersRequest{MergeID: dup.ID, Reason: "Entered twice"}, 1)
+	if err != nil {
+		t.Fatalf("MergeCustomers failed: %v", err)
+	}
+	if merge.OrdersMoved != 1 || merge.ActivitiesMoved != 1 || merge.DocumentsMoved != 1 {
+		t.Errorf("Unexpected moved counts %+v", merge)
+	}
+
+	var moved domain.SalesOrder
+	db.First(&moved, order.ID)
+	if moved.CustomerID != 1 {
+		t.Errorf("Expected order to move to the survivor, got customer %d", moved.CustomerID)
+	}
+	var movedMessage domain.OutboundMessage
+	db.First(&movedMessage, message.ID)
+	if movedMessage.CustomerID == nil || *movedMessage.CustomerID != 1 {
+		t.Errorf("Expected messages to move to the survivor, got customer %v", movedMessage.CustomerID)
+	}
+	survivor, _ := customerRepo.FindByID(1)
+	if survivor.Balance != money.FromFloat(1500) {
+		t.Errorf("Expected survivor balance 1500.00, got %s", survivor.Balance)
+	}
+	merged, _ := customerRepo.FindByID(dup.ID)
+	if merged.DeletedAt == nil || merged.MergedIntoID == nil || *merged.MergedIntoID != 1 || !merged.Balance.IsZero() {
+		t.Errorf("Expected merged customer soft-deleted and pointing at the survivor, got %+v", merged)
+	}
+	if _, total, _ := customerUC.GetCustomers(1, 50, "CUST-09001"); total != 0 {
+		t.Errorf("Merged customer should not be listed, got %d", total)
+	}
+
+	var audits int64
+	db.Model(&domain.AuditLog{}).Where("action = ? AND resource = ?", "MERGE", "customers").Count(&audits)
+	if audits != 1 {
+		t.Errorf("Expected one audit entry, got %d", audits)
+	}
+	if merges, _ := customerUC.GetMerges(1); len(merges) != 1 {
+		t.Errorf("Expected one merge in history, got %d", len(merges))
+	}
+	if _, err := customerUC.MergeCustomers(1, &domain.MergeCustomersRequest{MergeID: dup.ID}, 1); err == nil {
+		t.Error("Expected merging an already merged customer to fail")
+	}
+}
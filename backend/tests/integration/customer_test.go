@@ -5,6 +5,7 @@ import (
 "erp-system/internal/domain"
 "erp-system/internal/repositories"
 "erp-system/internal/usecases"
+"erp-system/pkg/money"
 "erp-system/tests/fixtures"
 )
 
@@ -36,7 +37,7 @@ fixtures.SeedTestDB(t, db)
 customerRepo := repositories.NewCustomerRepository(db)
 activityRepo := repositories.NewCustomerActivityRepository(db)
 documentRepo := repositories.NewCustomerDocumentRepository(db)
-customerUC := usecases.NewCustomerUseCase(customerRepo, activityRepo, documentRepo, nil)
+customerUC := usecases.NewCustomerUseCase(customerRepo, activityRepo, documentRepo, nil, nil, nil, nil)
 
 req := domain.CreateCustomerRequest{
 Name:        "شركة الاختبار",
@@ -46,7 +47,7 @@ Mobile:      "01122334455",
 Address:     "عنوان الاختبار",
 City:        "القاهرة",
 Country:     "مصر",
-CreditLimit: 50000,
+CreditLimit: money.FromFloat(50000),
 Type:        "corporate",
 }
 
@@ -71,7 +72,7 @@ defer fixtures.TeardownTestDB(db)
 fixtures.SeedTestDB(t, db)
 
 customerRepo := repositories.NewCustomerRepository(db)
-customerUC := usecases.NewCustomerUseCase(customerRepo, nil, nil, nil)
+customerUC := usecases.NewCustomerUseCase(customerRepo, nil, nil, nil, nil, nil, nil)
 
 customers, total, err := customerUC.GetCustomers(1, 10, "")
 if err != nil {
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+)
+
+// TestDeliveryPartialShipments_Integration verifies partial delivery notes deduct warehouse
+// stock and only advance the order once every line is fully shipped and delivered
+func TestDeliveryPartialShipments_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	invRepo := repositories.NewInventoryRepository(db)
+	invUC := usecases.NewInventoryUseCase(invRepo)
+	product, err := invUC.CreateProduct(&domain.CreateProductRequest{SKU: "BOLT-10", Name: "Bolt", SellingPrice: money.FromFloat(10)})
+	if err != nil {
+		t.Fatalf("CreateProduct failed: %v", err)
+	}
+	warehouse, err := invUC.CreateWarehouse(&domain.CreateWarehouseRequest{Code: "WH-1", Name: "Main Warehouse"})
+	if err != nil {
+		t.Fatalf("CreateWarehouse failed: %v", err)
+	}
+	if _, err := invUC.SetWarehouseStock(warehouse.ID, &domain.SetWarehouseStockRequest{ProductID: product.ID, Quantity: 8}); err != nil {
+		t.Fatalf("SetWarehouseStock failed: %v", err)
+	}
+
+	order, err := newSalesUseCase(db).CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		Items:      []domain.CreateOrderItemRequest{{ProductID: product.ID, Quantity: 10, UnitPrice: money.FromFloat(10)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	lineID := order.Items[0].ID
+
+	salesRepo := repositories.NewSalesRepository(db)
+	deliveryUC := usecases.NewDeliveryUseCase(repositories.NewDeliveryRepository(db), salesRepo, invRepo)
+
+	first, err := deliveryUC.CreateDeliveryNote(&domain.CreateDeliveryNoteRequest{
+		OrderID:     order.ID,
+		WarehouseID: warehouse.ID,
+		DriverName:  "Hassan",
+		Items:       []domain.DeliveryNoteItemRequest{{OrderItemID: lineID, Quantity: 6}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateDeliveryNote failed: %v", err)
+	}
+
+	updated, _ := salesRepo.FindByID(order.ID)
+	if updated.Status != domain.OrderStatusConfirmed {
+		t.Errorf("Expected partially shipped order to be confirmed, got %s", updated.Status)
+	}
+	if updated.Items[0].ShippedQuantity != 6 {
+		t.Errorf("Expected shipped quantity 6, got %g", updated.Items[0].ShippedQuantity)
+	}
+	if level, _ := invRepo.FindStockLevel(warehouse.ID, product.ID); level != 2 {
+		t.Errorf("Expected 2 left in the warehouse, got %g", level)
+	}
+
+	// Only 2 left in the warehouse, and only 4 outstanding on the order
+	if _, err := deliveryUC.CreateDeliveryNote(&domain.CreateDeliveryNoteRequest{OrderID: order.ID, WarehouseID: warehouse.ID}, 1); err == nil {
+		t.Error("Expected error for insufficient warehouse stock")
+	}
+	if _, err := deliveryUC.CreateDeliveryNote(&domain.CreateDeliveryNoteRequest{
+		OrderID:     order.ID,
+		WarehouseID: warehouse.ID,
+		Items:       []domain.DeliveryNoteItemRequest{{OrderItemID: lineID, Quantity: 5}},
+	}, 1); err == nil {
+		t.Error("Expected error for shipping more than outstanding")
+	}
+
+	// A note prepared alongside another one can't ship past the order once the other is in
+	raced := &domain.DeliveryNote{
+		NoteNumber:  "DN-RACE-1",
+		OrderID:     order.ID,
+		WarehouseID: warehouse.ID,
+		Status:      domain.DeliveryStatusShipped,
+		Items:       []domain.DeliveryNoteItem{{OrderItemID: lineID, ProductID: product.ID, Quantity: 5}},
+		CreatedBy:   1,
+	}
+	invUC.SetWarehouseStock(warehouse.ID, &domain.SetWarehouseStockRequest{ProductID: product.ID, Quantity: 10})
+	if err := repositories.NewDeliveryRepository(db).Ship(raced, domain.OrderStatusShipped); !errors.Is(err, repositories.ErrOverShipped) {
+		t.Errorf("Expected shipping past the order to fail, got %v", err)
+	}
+	if updated, _ := salesRepo.FindByID(order.ID); updated.Items[0].ShippedQuantity != 6 {
+		t.Errorf("Expected the shipped quantity to stay 6, got %g", updated.Items[0].ShippedQuantity)
+	}
+	invUC.SetWarehouseStock(warehouse.ID, &domain.SetWarehouseStockRequest{ProductID: product.ID, Quantity: 2})
+
+	// Product stock is whole units and must keep matching the warehouse ledger
+	if _, err := deliveryUC.CreateDeliveryNote(&domain.CreateDeliveryNoteRequest{
+		OrderID:     order.ID,
+		WarehouseID: warehouse.ID,
+		Items:       []domain.DeliveryNoteItemRequest{{OrderItemID: lineID, Quantity: 0.5}},
+	}, 1); err == nil {
+		t.Error("Expected error for shipping a fractional quantity")
+	}
+	if stocked, _ := invRepo.FindProductByID(product.ID); stocked.StockQuantity != 2 {
+		t.Errorf("Expected product stock 2 to match the warehouse, got %d", stocked.StockQuantity)
+	}
+
+	if _, err := invUC.SetWarehouseStock(warehouse.ID, &domain.SetWarehouseStockRequest{ProductID: product.ID, Quantity: 4}); err != nil {
+		t.Fatalf("SetWarehouseStock failed: %v", err)
+	}
+	second, err := deliveryUC.CreateDeliveryNote(&domain.CreateDeliveryNoteRequest{OrderID: order.ID, WarehouseID: warehouse.ID}, 1)
+	if err != nil {
+		t.Fatalf("CreateDeliveryNote for the remainder failed: %v", err)
+	}
+	if second.Items[0].Quantity != 4 {
+		t.Errorf("Expected the remaining 4 to be shipped, got %g", second.Items[0].Quantity)
+	}
+
+	updated, _ = salesRepo.FindByID(order.ID)
+	if updated.Status != domain.OrderStatusShipped {
+		t.Errorf("Expected fully shipped order to be shipped, got %s", updated.Status)
+	}
+
+	if _, err := deliveryUC.ConfirmDelivery(first.ID, &domain.ConfirmDeliveryRequest{ReceivedBy: "Store keeper"}); err != nil {
+		t.Fatalf("ConfirmDelivery failed: %v", err)
+	}
+	updated, _ = salesRepo.FindByID(order.ID)
+	if updated.Status != domain.OrderStatusShipped {
+		t.Errorf("Expected order to stay shipped until all notes are delivered, got %s", updated.Status)
+	}
+
+	if _, err := deliveryUC.ConfirmDelivery(second.ID, &domain.ConfirmDeliveryRequest{}); err != nil {
+		t.Fatalf("ConfirmDelivery failed: %v", err)
+	}
+	updated, _ = salesRepo.FindByID(order.ID)
+	if updated.Status != domain.OrderStatusDelivered {
+		t.Errorf("Expected order to be delivered, got %s", updated.Status)
+	}
+	if _, err := deliveryUC.ConfirmDelivery(second.ID, &domain.ConfirmDeliveryRequest{}); err == nil {
+		t.Error("Expected error when confirming a note twice")
+	}
+}
@@ -0,0 +1,274 @@
+package integration
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/storage"
+	"erp-system/tests/fixtures"
+)
+
+// TestCustomerDocuments_Integration verifies upload checks, deduplication, access control
+// and deletion of customer documents
+func TestCustomerDocuments_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	root := t.TempDir()
+	settingsRepo := repositories.NewSettingsRepository(db)
+	docRepo := repositories.NewCustomerDocumentRepository(db)
+	local := storage.NewLocal(root, services.LocalFilesPrefix, []byte("secret"))
+	customerUC := usecases.NewCustomerUseCase(repositories.NewCustomerRepository(db), repositories.NewCustomerActivityRepository(db),
+		docRepo, repositories.NewUserRepository(db), services.NewStorageService(settingsRepo, local), services.NewThumbnailService(""), nil)
+
+	pdf := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+	// The content must match the extension
+	if _, _, err := customerUC.UploadDocument(1, "Invoice", "", "invoice.pdf", []byte("MZ\x90\x00 not a pdf"), 1); !errors.Is(err, services.ErrFileType) {
+		t.Errorf("Expected a file type error for disguised content, got %v", err)
+	}
+	if _, _, err := customerUC.UploadDocument(1, "Script", "", "run.sh", []byte("#!/bin/sh\necho hi\n"), 1); !errors.Is(err, services.ErrFileType) {
+		t.Errorf("Expected a file type error for a shell script, got %v", err)
+	}
+
+	doc, duplicate, err := customerUC.UploadDocument(1, "", "", "../../contract.pdf", pdf, 1)
+	if err != nil {
+		t.Fatalf("UploadDocument failed: %v", err)
+	}
+	if duplicate || doc.Title != "../../contract.pdf" || doc.OriginalName != "contract.pdf" || doc.ContentType != "application/pdf" ||
+		doc.FileType != "pdf" || doc.Size != int64(len(pdf)) || len(doc.Checksum) != 64 || doc.UploadedBy != 1 {
+		t.Errorf("Unexpected document %+v", doc)
+	}
+	if strings.Contains(doc.FilePath, "contract") || !strings.HasPrefix(doc.FilePath, "documents/") {
+		t.Errorf("Expected a random storage key, got %q", doc.FilePath)
+	}
+	if stored, err := os.ReadFile(filepath.Join(root, doc.FilePath)); err != nil || !bytes.Equal(stored, pdf) {
+		t.Errorf("Expected the file under the storage root, got %v", err)
+	}
+
+	// The same content for the same customer returns the existing document
+	again, duplicate, err := customerUC.UploadDocument(1, "Copy", "", "copy.pdf", pdf, 1)
+	if err != nil || !duplicate || again.ID != doc.ID {
+		t.Errorf("Expected the existing document, got %+v, %v, %v", again, duplicate, err)
+	}
+
+	// Another customer gets its own record sharing the stored file
+	shared, duplicate, err := customerUC.UploadDocument(2, "Contract", "", "contract.pdf", pdf, 1)
+	if err != nil || duplicate || shared.ID == doc.ID || shared.FilePath != doc.FilePath {
+		t.Errorf("Expected a new record sharing the file, got %+v, %v, %v", shared, duplicate, err)
+	}
+
+	// Size limit comes from settings
+	settingsRepo.Set(domain.SettingDocumentMaxSizeMB, "1", "documents")
+	big := append([]byte("%PDF-1.4\n"), make([]byte, 1<<20)...)
+	if _, _, err := customerUC.UploadDocument(1, "Big", "", "big.pdf", big, 1); !errors.Is(err, services.ErrFileTooLarge) {
+		t.Errorf("Expected a size error, got %v", err)
+	}
+
+	// Allowed types come from settings
+	settingsRepo.Set(domain.SettingDocumentAllowedTypes, "image/png", "documents")
+	if _, _, err := customerUC.UploadDocument(1, "Other", "", "other.pdf", append(pdf, '\n'), 1); !errors.Is(err, services.ErrFileType) {
+		t.Errorf("Expected PDFs to be refused once only PNGs are allowed, got %v", err)
+	}
+
+	// A user of another branch may not open the customer's documents
+	branchA := domain.Branch{Code: "A", Name: "Branch A"}
+	branchB := domain.Branch{Code: "B", Name: "Branch B"}
+	db.Create(&branchA)
+	db.Create(&branchB)
+	db.Model(&domain.Customer{}).Where("id = ?", 1).Update("branch_id", branchA.ID)
+	clerk := domain.User{Username: "clerk", Email: "clerk@example.com", PasswordHash: "x", RoleID: 3, BranchID: &branchB.ID, IsActive: true}
+	db.Create(&clerk)
+
+	if _, _, _, err := customerUC.OpenDocument(1, doc.ID, clerk.ID); !errors.Is(err, usecases.ErrDocumentAccess) {
+		t.Errorf("Expected access to be denied, got %v", err)
+	}
+	if _, _, _, err := customerUC.OpenDocument(2, doc.ID, 1); err == nil || errors.Is(err, usecases.ErrDocumentAccess) {
+		t.Errorf("Expected a document of another customer to be not found, got %v", err)
+	}
+
+	db.Model(&clerk).Update("branch_id", branchA.ID)
+	opened, f, _, err := customerUC.OpenDocument(1, doc.ID, clerk.ID)
+	if err != nil {
+		t.Fatalf("OpenDocument failed: %v", err)
+	}
+	content, _ := io.ReadAll(f)
+	f.Close()
+	if opened.ID != doc.ID || !bytes.Equal(content, pdf) {
+		t.Errorf("Expected the document content, got %d bytes", len(content))
+	}
+
+	// Links are signed for the document's key and name
+	link, expiresAt, err := customerUC.DocumentURL(1, doc.ID, clerk.ID, false)
+	if err != nil || expiresAt.Before(time.Now()) {
+		t.Fatalf("DocumentURL failed: %v", err)
+	}
+	u, _ := url.Parse(link)
+	key, _ := strings.CutPrefix(u.Path, services.LocalFilesPrefix+"/")
+	if opts, err := local.Verify(key, u.Query()); err != nil || key != doc.FilePath || opts.Filename != "contract.pdf" || opts.Inline {
+		t.Errorf("Expected a valid link to %q, got %q, %+v, %v", doc.FilePath, link, opts, err)
+	}
+	db.Model(&clerk).Update("is_active", false)
+	if _, _, err := customerUC.DocumentURL(1, doc.ID, clerk.ID, false); !errors.Is(err, usecases.ErrDocumentAccess) {
+		t.Errorf("Expected inactive users to be refused a link, got %v", err)
+	}
+
+	// The shared file stays until its last record is purged
+	for _, step := range []struct {
+		customerID, docID uint
+	}{{1, doc.ID}, {1, doc.ID}, {2, shared.ID}} {
+		if _, err := customerUC.DeleteDocument(step.customerID, step.docID, 1); err != nil {
+			t.Fatalf("DeleteDocument failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, doc.FilePath)); err != nil {
+			t.Errorf("Expected the shared file to be kept, got %v", err)
+		}
+	}
+	if _, err := customerUC.DeleteDocument(2, shared.ID, 1); err != nil {
+		t.Fatalf("DeleteDocument failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, doc.FilePath)); !os.IsNotExist(err) {
+		t.Errorf("Expected the file to be removed, got %v", err)
+	}
+}
+
+// TestCustomerDocumentVersions_Integration verifies categories, versions, the trash and previews
+func TestCustomerDocumentVersions_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	root := t.TempDir()
+	settingsRepo := repositories.NewSettingsRepository(db)
+	storageService := services.NewStorageService(settingsRepo, storage.NewLocal(root, services.LocalFilesPrefix, []byte("secret")))
+	newCustomerUC := func(pdfPreviewCommand string) *usecases.CustomerUseCase {
+		return usecases.NewCustomerUseCase(repositories.NewCustomerRepository(db), repositories.NewCustomerActivityRepository(db),
+			repositories.NewCustomerDocumentRepository(db), repositories.NewUserRepository(db), storageService,
+			services.NewThumbnailService(pdfPreviewCommand), nil)
+	}
+	customerUC := newCustomerUC("")
+
+	photo := testPNG(t, 800, 400, color.RGBA{200, 30, 30, 255})
+	if _, _, err := customerUC.UploadDocument(1, "Site", "blueprint", "site.png", photo, 1); err == nil {
+		t.Error("Expected an unknown category to fail")
+	}
+
+	first, _, err := customerUC.UploadDocument(1, "Site", domain.DocumentCategoryPhoto, "site.png", photo, 1)
+	if err != nil {
+		t.Fatalf("UploadDocument failed: %v", err)
+	}
+	if first.Category != domain.DocumentCategoryPhoto || first.Version != 1 || first.GroupID != first.ID || !first.HasThumbnail {
+		t.Errorf("Unexpected first version %+v", first)
+	}
+
+	// Images get a JPEG thumbnail that fits in 320 pixels
+	r, _, err := customerUC.OpenThumbnail(1, first.ID, 1)
+	if err != nil {
+		t.Fatalf("OpenThumbnail failed: %v", err)
+	}
+	thumb, err := jpeg.Decode(r)
+	r.Close()
+	if err != nil || thumb.Bounds().Dx() != 320 || thumb.Bounds().Dy() != 160 {
+		t.Errorf("Expected a 320x160 JPEG thumbnail, got %v, %v", thumb, err)
+	}
+
+	// A new version keeps the title and category and replaces the old one in the list
+	second, duplicate, err := customerUC.ReplaceDocument(1, first.ID, "site-v2.png", testPNG(t, 100, 100, color.RGBA{0, 0, 200, 255}), 2)
+	if err != nil || duplicate {
+		t.Fatalf("ReplaceDocument failed: %v", err)
+	}
+	if second.Version != 2 || second.GroupID != first.ID || second.Title != "Site" || second.Category != domain.DocumentCategoryPhoto || second.UploadedBy != 2 {
+		t.Errorf("Unexpected second version %+v", second)
+	}
+	if _, _, err := customerUC.ReplaceDocument(1, first.ID, "site-v3.png", testPNG(t, 10, 10, color.White), 1); err == nil {
+		t.Error("Expected replacing an old version to fail")
+	}
+	if same, duplicate, err := customerUC.ReplaceDocument(1, second.ID, "again.png", testPNG(t, 100, 100, color.RGBA{0, 0, 200, 255}), 1); err != nil || !duplicate || same.ID != second.ID {
+		t.Errorf("Expected the current version back, got %+v, %v, %v", same, duplicate, err)
+	}
+
+	versions, _ := customerUC.GetDocumentVersions(1, first.ID)
+	if len(versions) != 2 || versions[0].ID != second.ID || versions[1].ID != first.ID || versions[1].ReplacedAt == nil {
+		t.Errorf("Expected both versions newest first, got %+v", versions)
+	}
+	photos, _ := customerUC.GetDocuments(1, domain.DocumentFilter{Category: domain.DocumentCategoryPhoto})
+	if len(photos) != 1 || photos[0].ID != second.ID {
+		t.Errorf("Expected only the latest version listed, got %+v", photos)
+	}
+
+	// Re-uploading an old version's content makes a new document, not a duplicate
+	if again, duplicate, err := customerUC.UploadDocument(1, "Old site", "", "site.png", photo, 1); err != nil || duplicate || again.FilePath != first.FilePath || !again.HasThumbnail {
+		t.Errorf("Expected a new document sharing the old file, got %+v, %v, %v", again, duplicate, err)
+	}
+
+	updated, err := customerUC.UpdateDocument(1, second.ID, &domain.UpdateDocumentRequest{Title: "Site survey", Category: domain.DocumentCategoryMeasurementSheet})
+	if err != nil || updated.Title != "Site survey" || updated.Category != domain.DocumentCategoryMeasurementSheet {
+		t.Errorf("Unexpected update %+v, %v", updated, err)
+	}
+	if _, err := customerUC.UpdateDocument(1, first.ID, &domain.UpdateDocumentRequest{Title: "x"}); err == nil {
+		t.Error("Expected editing an old version to fail")
+	}
+
+	// Deleting moves every version to the trash, and restoring brings them back
+	if purged, err := customerUC.DeleteDocument(1, second.ID, 1); err != nil || purged {
+		t.Fatalf("DeleteDocument failed: %v, %v", purged, err)
+	}
+	sheets, _ := customerUC.GetDocuments(1, domain.DocumentFilter{Category: domain.DocumentCategoryMeasurementSheet})
+	trash, _ := customerUC.GetDocuments(1, domain.DocumentFilter{Deleted: true})
+	if len(sheets) != 0 || len(trash) != 1 || trash[0].ID != second.ID || trash[0].DeletedBy == nil {
+		t.Errorf("Expected the document in the trash only, got %+v and %+v", sheets, trash)
+	}
+	if _, _, err := customerUC.ReplaceDocument(1, second.ID, "x.png", testPNG(t, 5, 5, color.Black), 1); err == nil {
+		t.Error("Expected replacing a deleted document to fail")
+	}
+	if restored, err := customerUC.RestoreDocument(1, second.ID, 1); err != nil || restored.DeletedAt != nil {
+		t.Fatalf("RestoreDocument failed: %v", err)
+	}
+	if versions, _ := customerUC.GetDocumentVersions(1, second.ID); versions[1].DeletedAt != nil {
+		t.Error("Expected earlier versions to be restored too")
+	}
+	if _, err := customerUC.RestoreDocument(1, second.ID, 1); err == nil {
+		t.Error("Expected restoring a live document to fail")
+	}
+
+	// PDFs get a preview of their first page from the configured renderer
+	page := filepath.Join(t.TempDir(), "page.png")
+	os.WriteFile(page, testPNG(t, 600, 800, color.White), 0o600)
+	renderer := filepath.Join(t.TempDir(), "render.sh")
+	os.WriteFile(renderer, []byte("#!/bin/sh\nfor arg; do out=$arg; done\ncp "+page+" \"$out.png\"\n"), 0o700)
+
+	contract, _, err := newCustomerUC(renderer).UploadDocument(1, "Contract", domain.DocumentCategoryContract, "contract.pdf", []byte("%PDF-1.4\n%%EOF\n"), 1)
+	if err != nil || !contract.HasThumbnail {
+		t.Errorf("Expected a PDF preview, got %+v, %v", contract, err)
+	}
+	if invoice, _, err := newCustomerUC(filepath.Join(t.TempDir(), "missing")).UploadDocument(1, "Invoice", domain.DocumentCategoryInvoice, "invoice.pdf", []byte("%PDF-1.4\n1\n%%EOF\n"), 1); err != nil || invoice.HasThumbnail {
+		t.Errorf("Expected the upload to succeed without a preview, got %+v, %v", invoice, err)
+	}
+}
+
+func testPNG(t *testing.T, w, h int, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
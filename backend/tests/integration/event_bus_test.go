@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/internal/worker"
+	"erp-system/pkg/messaging"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+)
+
+// TestEventBus_Integration verifies events are stored with the writes that raise them,
+// reach the audit log and the signed webhook once committed, and that a failing subscriber
+// is retried on its own
+func TestEventBus_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	var mu sync.Mutex
+	var posts []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		posts = append(posts, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	settingsRepo.Set(domain.SettingEventWebhookURL, server.URL, "integration")
+	settingsRepo.Set(domain.SettingEventWebhookSecret, "s3cret", "integration")
+
+	eventRepo := repositories.NewEventRepository(db)
+	bus := newEventBus(db)
+	services.SubscribeAudit(bus, repositories.NewAuditRepository(db))
+	webhook := services.NewEventWebhook(settingsRepo, true)
+	webhook.Subscribe(bus)
+	flaky := 0
+	services.On(bus, "flaky", func(event *domain.DomainEvent, e domain.OrderCreated) error {
+		flaky++
+		if flaky == 1 {
+			return errors.New("temporarily down")
+		}
+		return nil
+	})
+
+	invRepo := repositories.NewInventoryRepository(db)
+	invUC := usecases.NewInventoryUseCase(invRepo)
+	product, _ := invUC.CreateProduct(&domain.CreateProductRequest{SKU: "EVT-1", Name: "Frame", SellingPrice: money.FromFloat(50)})
+	warehouse, _ := invUC.CreateWarehouse(&domain.CreateWarehouseRequest{Code: "WH-E", Name: "Events"})
+	invUC.SetWarehouseStock(warehouse.ID, &domain.SetWarehouseStockRequest{ProductID: product.ID, Quantity: 2})
+
+	order, err := newSalesUseCase(db).CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		Items:      []domain.CreateOrderItemRequest{{ProductID: product.ID, Quantity: 5, UnitPrice: money.FromFloat(50)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	// Stored with the order, but nobody hears of it until the worker runs
+	events, total, _ := eventRepo.FindAll(domain.DomainEventFilter{Type: domain.EventTypeOrderCreated}, 1, 10)
+	if total != 1 || events[0].Status != domain.EventStatusPending || events[0].ActorID != 1 {
+		t.Fatalf("Expected one pending order.created event, got %+v", events)
+	}
+	if len(posts) != 0 || flaky != 0 {
+		t.Fatal("Expected no subscriber to run before the events are processed")
+	}
+
+	// Booking the order moves the balance in the same transaction and says so
+	changes, total, _ := eventRepo.FindAll(domain.DomainEventFilter{Type: domain.EventTypeBalanceChanged}, 1, 10)
+	if total != 1 || !strings.Contains(changes[0].Payload, `"amount":250.00`) {
+		t.Errorf("Expected one balance change of 250, got %+v", changes)
+	}
+	if customer, _ := repositories.NewCustomerRepository(db).FindByID(1); customer.Balance != money.FromFloat(250) {
+		t.Errorf("Expected balance 250 after the order, got %s", customer.Balance)
+	}
+
+	worker.DrainEvents(bus)
+	event, _ := eventRepo.FindByID(events[0].ID)
+	if len(posts) != 2 || posts[0].Header.Get("X-Event-Type") != domain.EventTypeOrderCreated ||
+		!messaging.VerifySignature("s3cret", bodies[0], posts[0].Header.Get(messaging.SignatureHeader)) {
+		t.Errorf("Expected a signed webhook post per event, got %d", len(posts))
+	}
+	var audit domain.AuditLog
+	if err := db.Where("request_id = ?", fmt.Sprintf("event-%d", event.ID)).First(&audit).Error; err != nil || audit.Resource != domain.EventTypeOrderCreated {
+		t.Errorf("Expected the event in the audit log, got %+v (%v)", audit, err)
+	}
+
+	// Only the failing subscriber is retried, after a backoff
+	if event.Status != domain.EventStatusPending || event.Attempts != 1 || len(event.Pending) != 1 || event.Pending[0] != "flaky" ||
+		!event.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("Expected the event to wait for the flaky subscriber, got %+v", event)
+	}
+	db.Model(&domain.DomainEvent{}).Where("id = ?", event.ID).Update("next_attempt_at", time.Now())
+	worker.DrainEvents(bus)
+	event, _ = eventRepo.FindByID(event.ID)
+	if event.Status != domain.EventStatusProcessed || flaky != 2 || len(posts) != 2 {
+		t.Errorf("Expected the retry to reach only the flaky subscriber, got %+v after %d posts", event, len(posts))
+	}
+	if _, err := bus.Retry(event.ID); err == nil {
+		t.Error("Expected a processed event not to be retried")
+	}
+
+	// A write that rolls back stores no events
+	note := &domain.DeliveryNote{
+		NoteNumber:  "DN-EVT-1",
+		OrderID:     order.ID,
+		WarehouseID: warehouse.ID,
+		Status:      domain.DeliveryStatusShipped,
+		Items:       []domain.DeliveryNoteItem{{OrderItemID: order.Items[0].ID, ProductID: product.ID, Quantity: 5}},
+		CreatedBy:   1,
+	}
+	err = repositories.NewDeliveryRepository(db).Ship(note, domain.OrderStatusShipped,
+		domain.OrderStatusChanged{OrderID: order.ID, From: order.Status, To: domain.OrderStatusShipped, ChangedBy: 1})
+	if !errors.Is(err, repositories.ErrInsufficientStock) {
+		t.Fatalf("Expected shipping more than the warehouse holds to fail, got %v", err)
+	}
+	if _, total, _ := eventRepo.FindAll(domain.DomainEventFilter{Type: domain.EventTypeOrderStatusChanged}, 1, 10); total != 0 {
+		t.Errorf("Expected no status change event for a rolled back shipment, got %d", total)
+	}
+
+	// Webhooks only go to http(s) URLs, and by default only to public addresses
+	if err := services.NewEventWebhook(settingsRepo, false).Post(event); err == nil {
+		t.Error("Expected a webhook to a loopback address to be refused")
+	}
+	settingsRepo.Set(domain.SettingEventWebhookURL, "file:///etc/passwd", "integration")
+	if err := webhook.Post(event); err == nil {
+		t.Error("Expected a webhook URL that isn't http(s) to be refused")
+	}
+	if len(posts) != 2 {
+		t.Errorf("Expected refused webhooks not to be posted, got %d posts", len(posts))
+	}
+}
@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"bytes"
+	"testing"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/spreadsheet"
+	"erp-system/tests/fixtures"
+)
+
+// TestExport_Integration verifies exports honour list filters, Arabic headers and the row cap
+func TestExport_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	exportUC := usecases.NewExportUseCase(repositories.NewExportRepository(db), settingsRepo)
+
+	if err := exportUC.CheckExport("users", spreadsheet.FormatCSV); err == nil {
+		t.Error("Expected an unknown list to be rejected")
+	}
+	if err := exportUC.CheckExport(domain.ExportCustomers, "pdf"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+
+	var buf bytes.Buffer
+	err := exportUC.Export(domain.ExportCustomers, spreadsheet.FormatXLSX, domain.ExportFilter{Search: "fatima"}, true, &buf)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	rows, err := spreadsheet.ReadXLSX(buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("ReadXLSX failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "الكود" || rows[1][0] != "CUST-00002" {
+		t.Fatalf("Expected Arabic headers and only the matching customer, got %q", rows)
+	}
+	if rows[1][12] != "30000" {
+		t.Errorf("Expected the credit limit as a number, got %q", rows[1][12])
+	}
+
+	settingsRepo.Set(domain.SettingExportMaxRows, "2", "documents")
+	buf.Reset()
+	if err := exportUC.Export(domain.ExportCustomers, spreadsheet.FormatCSV, domain.ExportFilter{}, false, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	rows, _ = spreadsheet.ReadCSV(&buf, 0)
+	if len(rows) != 3 || rows[0][0] != "Code" {
+		t.Errorf("Expected the header and 2 capped rows, got %d rows", len(rows))
+	}
+}
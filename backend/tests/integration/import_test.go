@@ -0,0 +1,158 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+
+	"gorm.io/gorm"
+)
+
+func newImportUseCase(db *gorm.DB) *usecases.ImportUseCase {
+	return usecases.NewImportUseCase(
+		repositories.NewImportRepository(db),
+		repositories.NewCustomerRepository(db),
+		repositories.NewInventoryRepository(db),
+	)
+}
+
+// waitForImport polls the job the way a client would until it finishes
+func waitForImport(t *testing.T, uc *usecases.ImportUseCase, id uint) *domain.ImportJob {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := uc.GetJob(id)
+		if err != nil {
+			t.Fatalf("GetJob failed: %v", err)
+		}
+		if job.Done() {
+			return job
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Import job %d did not finish", id)
+	return nil
+}
+
+// TestCustomerImport_Integration verifies a dry run previews per-row errors without writing,
+// and the real run creates new customers and updates existing ones by code
+func TestCustomerImport_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	importUC := newImportUseCase(db)
+	custRepo := repositories.NewCustomerRepository(db)
+
+	csv := []byte("Code,Name,Email,Phone,Credit Limit,Payment Terms Days\n" +
+		"CUST-00001,,,01000000001,75000,\n" +
+		"CUST-00001,أحمد محمد,,,,\n" +
+		"CUST-00002,فاطمة علي,,01099999999,\"40,000\",45\n" +
+		",شركة النور للتجارة,info@alnoor.example,0225550000,20000,60\n" +
+		",Bad Row,not-an-email,,abc,\n" +
+		"CUST-07777,عميل مستورد,,,,\n")
+
+	if _, err := importUC.StartImport("suppliers", "x.csv", csv, false, 1); err == nil {
+		t.Error("Expected an unknown import type to be rejected")
+	}
+	if _, err := importUC.StartImport(domain.ImportTypeCustomers, "x.csv", []byte("email\na@b.co\n"), false, 1); err == nil {
+		t.Error("Expected a file without a name column to be rejected")
+	}
+
+	preview, err := importUC.StartImport(domain.ImportTypeCustomers, "customers.csv", csv, true, 1)
+	if err != nil {
+		t.Fatalf("StartImport failed: %v", err)
+	}
+	preview = waitForImport(t, importUC, preview.ID)
+	if preview.Status != domain.ImportStatusCompleted || preview.TotalRows != 6 || len(preview.Results) != 6 {
+		t.Fatalf("Unexpected preview %+v", preview)
+	}
+	if preview.CreatedCount != 2 || preview.UpdatedCount != 1 || preview.FailedCount != 3 {
+		t.Errorf("Expected 2 creates, 1 update, 3 failures, got %d/%d/%d", preview.CreatedCount, preview.UpdatedCount, preview.FailedCount)
+	}
+	results := preview.Results
+	if results[0].Row != 2 || results[0].Action != domain.ImportActionError {
+		t.Errorf("Expected spreadsheet row 2 to fail for a missing name, got %+v", results[0])
+	}
+	if results[1].Action != domain.ImportActionError {
+		t.Errorf("Expected a repeated code to fail, got %+v", results[1])
+	}
+	if len(results[4].Errors) != 2 {
+		t.Errorf("Expected email and credit limit errors on row 6, got %v", results[4].Errors)
+	}
+	var count int64
+	db.Model(&domain.Customer{}).Count(&count)
+	if customer, _ := custRepo.FindByID(2); count != 3 || customer.PaymentTermsDays != 30 {
+		t.Fatalf("Dry run must not write, found %d customers", count)
+	}
+
+	job, err := importUC.StartImport(domain.ImportTypeCustomers, "customers.csv", csv, false, 1)
+	if err != nil {
+		t.Fatalf("StartImport failed: %v", err)
+	}
+	job = waitForImport(t, importUC, job.ID)
+	if job.CreatedCount != 2 || job.UpdatedCount != 1 || job.FailedCount != 3 || job.ProcessedRows != 6 {
+		t.Fatalf("Unexpected import counts %+v", job)
+	}
+
+	updated, _ := custRepo.FindByID(2)
+	if updated.Phone != "01099999999" || updated.CreditLimit != money.FromFloat(40000) || updated.PaymentTermsDays != 45 {
+		t.Errorf("Expected customer 2 updated from the file, got %s %s %d", updated.Phone, updated.CreditLimit, updated.PaymentTermsDays)
+	}
+	if updated.Email != "fatima@example.com" || updated.City != "Alexandria" {
+		t.Error("Empty cells must keep existing values")
+	}
+	imported, err := custRepo.FindByCode("CUST-07777")
+	if err != nil || imported.Name != "عميل مستورد" || imported.Type != "regular" {
+		t.Errorf("Expected customer created with the given code, got %+v (%v)", imported, err)
+	}
+	if job.Results[3].ID == 0 || job.Results[3].Key == "" {
+		t.Errorf("Expected a generated code for the new customer, got %+v", job.Results[3])
+	}
+
+	jobs, _ := importUC.GetJobs(domain.ImportTypeCustomers)
+	if len(jobs) != 2 || len(jobs[0].Results) != 0 {
+		t.Errorf("Expected two jobs listed without results, got %d", len(jobs))
+	}
+}
+
+// TestProductImport_Integration verifies products upsert by SKU
+func TestProductImport_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	importUC := newImportUseCase(db)
+	invRepo := repositories.NewInventoryRepository(db)
+	existing := &domain.Product{SKU: "SKU-1", Name: "Old name", SellingPrice: money.FromFloat(10), StockQuantity: 5, IsActive: true}
+	if err := invRepo.CreateProduct(existing); err != nil {
+		t.Fatalf("CreateProduct failed: %v", err)
+	}
+
+	csv := []byte("sku,name,selling_price,stock_quantity\n" +
+		"SKU-1,New name,12.50,\n" +
+		"SKU-2,Widget,99,20\n" +
+		"SKU-3,Broken,-1,2.5\n" +
+		",No SKU,1,1\n")
+	job, err := importUC.StartImport(domain.ImportTypeProducts, "products.csv", csv, false, 1)
+	if err != nil {
+		t.Fatalf("StartImport failed: %v", err)
+	}
+	job = waitForImport(t, importUC, job.ID)
+	if job.CreatedCount != 1 || job.UpdatedCount != 1 || job.FailedCount != 2 {
+		t.Fatalf("Unexpected counts %d/%d/%d: %+v", job.CreatedCount, job.UpdatedCount, job.FailedCount, job.Results)
+	}
+	if errs := job.Results[2].Errors; len(errs) != 2 {
+		t.Errorf("Expected price and quantity errors for SKU-3, got %v", errs)
+	}
+
+	product, _ := invRepo.FindProductByID(existing.ID)
+	if product.Name != "New name" || product.SellingPrice != money.FromFloat(12.5) || product.StockQuantity != 5 {
+		t.Errorf("Expected SKU-1 updated with stock untouched, got %s %s %d", product.Name, product.SellingPrice, product.StockQuantity)
+	}
+}
@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"erp-system/api/routes"
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/internal/worker"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestMessageTemplates_Integration verifies default and customised templates, language
+// variants, previews, and template messages for payments and order status changes
+func TestMessageTemplates_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	settingsRepo.Set(domain.SettingWhatsAppURL, "http://127.0.0.1:1/send", "integration")
+	settingsRepo.Set(domain.SettingWhatsAppToken, "token", "integration")
+	settingsRepo.Set(domain.SettingCompanyName, "Glass Co", "general")
+
+	templateService := services.NewTemplateService(repositories.NewMessageTemplateRepository(db), settingsRepo)
+	outbox := usecases.NewOutboxUseCase(repositories.NewOutboundMessageRepository(db), services.NewNotificationService(settingsRepo), templateService)
+	salesRepo := repositories.NewSalesRepository(db)
+	templateUC := usecases.NewMessageTemplateUseCase(templateService, repositories.NewCustomerRepository(db), salesRepo)
+	bus := newEventBus(db)
+	usecases.SubscribeCustomerMessages(bus, outbox, salesRepo)
+
+	templates, err := templateUC.GetTemplates()
+	if err != nil || len(templates) != 4 || !templates[0].IsDefault || templates[0].Key != domain.TemplateActivityAlert {
+		t.Fatalf("Expected the four default templates by key, got %+v (%v)", templates, err)
+	}
+	if _, err := templateUC.GetTemplate("birthday"); !errors.Is(err, services.ErrUnknownTemplate) {
+		t.Errorf("Expected an unknown key to fail, got %v", err)
+	}
+
+	// Customer 1 reads English; the English variant falls back to Arabic when it is empty
+	db.Model(&domain.Customer{}).Where("id = ?", 1).Update("language", domain.LanguageEnglish)
+	if _, err := templateUC.UpdateTemplate(domain.TemplatePaymentReceipt, &domain.SaveMessageTemplateRequest{
+		SubjectAr: "إيصال {order_number}",
+		BodyAr:    "شكراً {customer_name}، استلمنا {amount_paid}. المتبقي {amount_due}",
+		SubjectEn: "Receipt {order_number}",
+		BodyEn:    "{company_name}: thanks {customer_name}, we received {amount_paid}. Due: {amount_due}. {unknown}",
+	}, 1); err != nil {
+		t.Fatalf("UpdateTemplate failed: %v", err)
+	}
+	if _, err := templateUC.UpdateTemplate(domain.TemplateOrderStatus, &domain.SaveMessageTemplateRequest{
+		BodyAr: "طلب {order_number}: {order_status}، التركيب {installation_date}",
+	}, 1); err != nil {
+		t.Fatalf("UpdateTemplate failed: %v", err)
+	}
+	if tmpl, _ := templateUC.GetTemplate(domain.TemplateOrderStatus); tmpl.IsDefault || tmpl.Name != "تغير حالة الطلب" {
+		t.Errorf("Expected the customised template to keep its name, got %+v", tmpl)
+	}
+
+	product, _ := usecases.NewInventoryUseCase(repositories.NewInventoryRepository(db)).CreateProduct(&domain.CreateProductRequest{
+		SKU: "PANEL-1", Name: "Panel", SellingPrice: money.FromFloat(100),
+	})
+	installation := time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC)
+	salesUC := newSalesUseCase(db)
+	order, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID:   1,
+		OrderDate:    time.Now(),
+		DeliveryDate: &installation,
+		Items:        []domain.CreateOrderItemRequest{{ProductID: product.ID, Quantity: 10, UnitPrice: money.FromFloat(100)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	preview, err := templateUC.Preview(&domain.PreviewTemplateRequest{Key: domain.TemplateOrderStatus, OrderID: order.ID})
+	if err != nil || preview.Language != domain.LanguageArabic || preview.Body != "طلب "+order.OrderNumber+": قيد التجهيز، التركيب 2026-11-03" {
+		t.Errorf("Expected the Arabic fallback for an empty English variant, got %+v (%v)", preview, err)
+	}
+	preview, err = templateUC.Preview(&domain.PreviewTemplateRequest{
+		Template:  &domain.SaveMessageTemplateRequest{BodyAr: "مرحباً {customer_name}", BodyEn: "Hi {customer_name}, {extra}"},
+		Language:  domain.LanguageEnglish,
+		Variables: map[string]string{"extra": "welcome"},
+	})
+	if err != nil || preview.Body != "Hi أحمد محمد, welcome" {
+		t.Errorf("Expected a draft preview with sample values, got %+v (%v)", preview, err)
+	}
+
+	payment, err := salesUC.RecordPayment(order.ID, &domain.RecordPaymentRequest{Amount: money.FromFloat(400), PaymentDate: time.Now()}, 1)
+	if err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	worker.DrainEvents(bus)
+	messages, _, _, _ := outbox.GetMessages(domain.OutboundMessageFilter{Source: domain.MessageSourcePayment}, 1, 20)
+	wantDue := order.NetAmount.Sub(money.FromFloat(400)).String() + " EGP"
+	if len(messages) != 1 || messages[0].Template != domain.TemplatePaymentReceipt || messages[0].Subject != "Receipt "+order.OrderNumber ||
+		messages[0].Body != "Glass Co: thanks أحمد محمد, we received 400.00 EGP. Due: "+wantDue+". {unknown}" {
+		t.Errorf("Unexpected receipt for payment #%d: %+v", payment.ID, messages)
+	}
+
+	warehouse, _ := usecases.NewInventoryUseCase(repositories.NewInventoryRepository(db)).CreateWarehouse(&domain.CreateWarehouseRequest{Code: "WH-T", Name: "Main"})
+	usecases.NewInventoryUseCase(repositories.NewInventoryRepository(db)).SetWarehouseStock(warehouse.ID, &domain.SetWarehouseStockRequest{ProductID: product.ID, Quantity: 10})
+	deliveryUC := usecases.NewDeliveryUseCase(repositories.NewDeliveryRepository(db), salesRepo, repositories.NewInventoryRepository(db))
+	note, err := deliveryUC.CreateDeliveryNote(&domain.CreateDeliveryNoteRequest{OrderID: order.ID, WarehouseID: warehouse.ID}, 1)
+	if err != nil {
+		t.Fatalf("CreateDeliveryNote failed: %v", err)
+	}
+	if _, err := deliveryUC.ConfirmDelivery(note.ID, &domain.ConfirmDeliveryRequest{ReceivedBy: "Owner"}); err != nil {
+		t.Fatalf("ConfirmDelivery failed: %v", err)
+	}
+	worker.DrainEvents(bus)
+	messages, _, _, _ = outbox.GetMessages(domain.OutboundMessageFilter{Source: domain.MessageSourceOrder}, 1, 20)
+	if len(messages) != 2 || !strings.Contains(messages[1].Body, ": تم الشحن،") || !strings.Contains(messages[0].Body, ": تم التسليم،") {
+		t.Errorf("Expected shipped and delivered messages, got %+v", messages)
+	}
+
+	reset, err := templateUC.ResetTemplate(domain.TemplateOrderStatus)
+	if err != nil || !reset.IsDefault || !strings.Contains(reset.BodyEn, "{order_status}") {
+		t.Errorf("Expected the default template back, got %+v (%v)", reset, err)
+	}
+}
+
+// TestMessageTemplateRoutesRequireManager_Integration verifies a sales user can preview
+// templates but not change or reset them
+func TestMessageTemplateRoutesRequireManager_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	templateService := services.NewTemplateService(repositories.NewMessageTemplateRepository(db), settingsRepo)
+	templateUC := usecases.NewMessageTemplateUseCase(templateService, repositories.NewCustomerRepository(db), repositories.NewSalesRepository(db))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupMessageTemplateRoutes(router, handlers.NewMessageTemplateHandler(templateUC))
+
+	const salesRole = 3
+	path := "/api/v1/message-templates/" + domain.TemplateActivityAlert
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		if code := statusAs(router, method, path, salesRole); code != http.StatusForbidden {
+			t.Errorf("%s %s: expected a sales user to get 403, got %d", method, path, code)
+		}
+		if code := statusAs(router, method, path, domain.RoleManager); code == http.StatusForbidden {
+			t.Errorf("%s %s: expected a manager to be let through", method, path)
+		}
+	}
+	if code := statusAs(router, http.MethodGet, path, salesRole); code != http.StatusOK {
+		t.Errorf("Expected a sales user to read a template, got %d", code)
+	}
+}
@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/tests/fixtures"
+)
+
+// TestNotificationChannels_Integration verifies channel configuration from settings and
+// channel selection by customer preference
+func TestNotificationChannels_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	var mu sync.Mutex
+	var received []string
+	record := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			to, _ := payload["to"].(string)
+			if phone, ok := payload["phone"].(string); ok {
+				to = phone
+			}
+			mu.Lock()
+			received = append(received, name+" "+r.URL.Path+" "+to)
+			mu.Unlock()
+		}))
+	}
+	whatsapp := record("whatsapp")
+	defer whatsapp.Close()
+	sms := record("sms")
+	defer sms.Close()
+	last := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(received) == 0 {
+			return ""
+		}
+		return received[len(received)-1]
+	}
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	notif := services.NewNotificationService(settingsRepo)
+	customer := &domain.Customer{Name: "Test", Mobile: "01012345678", Email: "test@example.com", IsWhatsAppEnabled: true}
+
+	if _, err := notif.SendToCustomer(customer, "", "hello"); !errors.Is(err, services.ErrNoChannel) {
+		t.Errorf("Expected no channel before any is configured, got %v", err)
+	}
+	if err := notif.SendWhatsApp("01012345678", "hello"); err == nil {
+		t.Error("Expected WhatsApp to fail while unconfigured")
+	}
+
+	// Existing installations keep posting to their WhatsApp gateway
+	settingsRepo.Set(domain.SettingWhatsAppURL, whatsapp.URL+"/send", "integration")
+	settingsRepo.Set(domain.SettingWhatsAppToken, "token", "integration")
+	settingsRepo.Set(domain.SettingSMSURL, sms.URL+"/sms", "integration")
+	if channel, err := notif.SendToCustomer(customer, "", "hello"); err != nil || channel != domain.ChannelWhatsApp || last() != "whatsapp /send 01012345678" {
+		t.Errorf("Expected WhatsApp first by default, got %q %v (%s)", channel, err, last())
+	}
+
+	// The Cloud API takes over once selected
+	settingsRepo.Set(domain.SettingWhatsAppProvider, "cloud", "integration")
+	settingsRepo.Set(domain.SettingWhatsAppPhoneNumberID, "1055", "integration")
+	settingsRepo.Set(domain.SettingWhatsAppCloudURL, whatsapp.URL+"/v19.0", "integration")
+	if channel, err := notif.SendToCustomer(customer, "", "hello"); err != nil || channel != domain.ChannelWhatsApp || last() != "whatsapp /v19.0/1055/messages 01012345678" {
+		t.Errorf("Expected the Cloud API to be used, got %q %v (%s)", channel, err, last())
+	}
+
+	customer.PreferredChannel = domain.ChannelSMS
+	if channel, err := notif.SendToCustomer(customer, "", "hello"); err != nil || channel != domain.ChannelSMS || last() != "sms /sms 01012345678" {
+		t.Errorf("Expected the preferred SMS channel, got %q %v (%s)", channel, err, last())
+	}
+
+	// Email isn't configured, so the configured order decides
+	customer.PreferredChannel = domain.ChannelEmail
+	settingsRepo.Set(domain.SettingChannelOrder, "sms, whatsapp", "integration")
+	if channel, err := notif.SendToCustomer(customer, "", "hello"); err != nil || channel != domain.ChannelSMS {
+		t.Errorf("Expected a fall back to SMS, got %q %v", channel, err)
+	}
+
+	// Customers with only an email address can't be reached until SMTP is configured
+	emailOnly := &domain.Customer{Name: "No phone", Email: "x@example.com", PreferredChannel: domain.ChannelWhatsApp}
+	if _, err := notif.SendToCustomer(emailOnly, "", "hello"); !errors.Is(err, services.ErrNoChannel) {
+		t.Errorf("Expected no channel for a customer with only an email, got %v", err)
+	}
+	if addr := services.CustomerAddress(&domain.Customer{Phone: "0100", IsWhatsAppEnabled: false}, domain.ChannelWhatsApp); addr != "" {
+		t.Errorf("Expected no WhatsApp address for an opted-out customer, got %q", addr)
+	}
+}
@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/tests/fixtures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestNotifications_Integration verifies the notification history, unread counts,
+// mark-all-read and deletion, scoped to the owning user
+func TestNotifications_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	repo := repositories.NewNotificationRepository(db)
+	uc := usecases.NewNotificationUseCase(repo, repositories.NewSubscriptionRepository(db), services.NewNotificationHub())
+	for i := 0; i < 5; i++ {
+		uc.CreateNotification(2, "Order approved", "SO-1", "success")
+	}
+	uc.CreateNotification(2, "Credit hold", "", "warning")
+	uc.CreateNotification(3, "Other user", "", "info")
+
+	if count, _ := uc.CountUnread(2); count != 6 {
+		t.Errorf("Expected 6 unread, got %d", count)
+	}
+	page, total, err := uc.GetHistory(2, domain.NotificationFilter{}, 2, 4)
+	if err != nil || total != 6 || len(page) != 2 {
+		t.Errorf("Expected the second page of 2 out of 6, got %d of %d (%v)", len(page), total, err)
+	}
+	if warnings, total, _ := uc.GetHistory(2, domain.NotificationFilter{Type: "warning"}, 1, 20); total != 1 || warnings[0].Title != "Credit hold" {
+		t.Errorf("Expected the one warning, got %+v", warnings)
+	}
+
+	first := page[0].ID
+	if err := uc.MarkAsRead(first, 3); err == nil {
+		t.Error("Expected another user's notification not to be found")
+	}
+	if err := uc.MarkAsRead(first, 2); err != nil {
+		t.Fatalf("MarkAsRead failed: %v", err)
+	}
+	read := true
+	if history, total, _ := uc.GetHistory(2, domain.NotificationFilter{IsRead: &read}, 1, 20); total != 1 || history[0].ID != first {
+		t.Errorf("Expected only the read notification in the read history, got %d", total)
+	}
+
+	if updated, err := uc.MarkAllAsRead(2); err != nil || updated != 5 {
+		t.Errorf("Expected the other 5 to be marked read, got %d (%v)", updated, err)
+	}
+	if count, _ := uc.CountUnread(2); count != 0 {
+		t.Errorf("Expected nothing unread, got %d", count)
+	}
+	if count, _ := uc.CountUnread(3); count != 1 {
+		t.Errorf("Expected user 3's notification to stay unread, got %d", count)
+	}
+
+	if err := uc.DeleteNotification(first, 3); err == nil {
+		t.Error("Expected deleting another user's notification to fail")
+	}
+	if err := uc.DeleteNotification(first, 2); err != nil {
+		t.Fatalf("DeleteNotification failed: %v", err)
+	}
+	if _, total, _ := uc.GetHistory(2, domain.NotificationFilter{}, 1, 20); total != 5 {
+		t.Errorf("Expected 5 left after deleting, got %d", total)
+	}
+}
+
+// TestNotificationStream_Integration verifies new notifications are pushed over SSE to the
+// streams of the user they are for once they are saved
+func TestNotificationStream_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	hub := services.NewNotificationHub()
+	repo := hub.Publishing(repositories.NewNotificationRepository(db))
+	repo.Create(&domain.Notification{UserID: 2, Title: "Earlier", Type: "info"})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream", func(c *gin.Context) { c.Set("user_id", uint(2)) },
+		handlers.NewNotificationHandler(usecases.NewNotificationUseCase(repo, repositories.NewSubscriptionRepository(db), hub)).Stream)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("Expected an event stream, got %q", ct)
+	}
+
+	events := make(chan [2]string, 8)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		var event string
+		for scanner.Scan() {
+			line := scanner.Text()
+			if name, ok := strings.CutPrefix(line, "event:"); ok {
+				event = name
+			}
+			if data, ok := strings.CutPrefix(line, "data:"); ok {
+				events <- [2]string{event, data}
+			}
+		}
+		close(events)
+	}()
+	next := func() [2]string {
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+		}
+		return [2]string{}
+	}
+
+	if e := next(); e[0] != "unread_count" || e[1] != `{"count":1}` {
+		t.Errorf("Expected the unread count first, got %v", e)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.Subscribers(2) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	repo.Create(&domain.Notification{UserID: 3, Title: "Not for user 2", Type: "info"})
+	repo.Create(&domain.Notification{UserID: 2, Title: "تذكير مستحق", Type: "warning"})
+
+	e := next()
+	var pushed domain.Notification
+	if err := json.Unmarshal([]byte(e[1]), &pushed); err != nil || e[0] != "notification" {
+		t.Fatalf("Expected a notification event, got %v (%v)", e, err)
+	}
+	if pushed.ID == 0 || pushed.Title != "تذكير مستحق" || pushed.UserID != 2 {
+		t.Errorf("Expected user 2's new notification, got %+v", pushed)
+	}
+
+	cancel()
+	deadline = time.Now().Add(5 * time.Second)
+	for hub.Subscribers(2) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if hub.Subscribers(2) != 0 {
+		t.Error("Expected the stream to unsubscribe when the client disconnects")
+	}
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/internal/worker"
+	"erp-system/tests/fixtures"
+)
+
+// TestOutbox_Integration verifies queuing alerts in the outbox, delivery by the worker pool,
+// retries with backoff, dead messages, idempotency and resending
+func TestOutbox_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	var mu sync.Mutex
+	failures := 0
+	var delivered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			http.Error(w, "gateway busy", http.StatusServiceUnavailable)
+			return
+		}
+		delivered = append(delivered, payload["phone"]+": "+payload["message"])
+	}))
+	defer server.Close()
+	failNext := func(n int) {
+		mu.Lock()
+		failures = n
+		mu.Unlock()
+	}
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	settingsRepo.Set(domain.SettingWhatsAppURL, server.URL, "integration")
+	settingsRepo.Set(domain.SettingWhatsAppToken, "token", "integration")
+
+	outboxRepo := repositories.NewOutboundMessageRepository(db)
+	outbox := usecases.NewOutboxUseCase(outboxRepo, services.NewNotificationService(settingsRepo),
+		services.NewTemplateService(repositories.NewMessageTemplateRepository(db), settingsRepo))
+	customerUC := usecases.NewCustomerUseCase(repositories.NewCustomerRepository(db), repositories.NewCustomerActivityRepository(db),
+		repositories.NewCustomerDocumentRepository(db), repositories.NewUserRepository(db), nil, nil, outbox)
+
+	alert, err := customerUC.AddActivity(1, &domain.SaveActivityRequest{Type: "alert", Description: "Your order is ready"}, 1)
+	if err != nil {
+		t.Fatalf("AddActivity failed: %v", err)
+	}
+	messages, total, counts, err := outbox.GetMessages(domain.OutboundMessageFilter{}, 1, 20)
+	if err != nil || total != 1 || counts[domain.MessageStatusQueued] != 1 {
+		t.Fatalf("Expected the alert queued, got %d %v (%v)", total, counts, err)
+	}
+	queued := messages[0]
+	if queued.Channel != domain.ChannelWhatsApp || queued.Recipient != "01012345678" || queued.Source != domain.MessageSourceActivity ||
+		queued.CustomerID == nil || *queued.CustomerID != 1 || queued.Body != "مرحباً أحمد محمد،\nYour order is ready" || queued.Template != domain.TemplateActivityAlert {
+		t.Errorf("Unexpected queued message %+v", queued)
+	}
+
+	// The same idempotency key doesn't queue twice
+	customer := &domain.Customer{ID: 1, Mobile: "01012345678", IsWhatsAppEnabled: true}
+	again, err := outbox.QueueForCustomer(customer, "", "duplicate", domain.MessageSourceActivity, fmt.Sprintf("activity:%d", alert.ID), 1)
+	if err != nil || again.ID != queued.ID {
+		t.Errorf("Expected the existing message for a repeated key, got %+v (%v)", again, err)
+	}
+
+	// The first attempt fails and is scheduled with backoff
+	failNext(1)
+	if n := worker.DrainOutbox(outbox, 3); n != 1 {
+		t.Errorf("Expected one attempt, got %d", n)
+	}
+	msg, _ := outbox.GetMessage(queued.ID)
+	if msg.Status != domain.MessageStatusFailed || msg.Attempts != 1 || msg.LastError == "" || len(msg.Deliveries) != 1 || msg.Deliveries[0].Success {
+		t.Errorf("Expected a failed first attempt in the log, got %+v", msg)
+	}
+	if wait := time.Until(msg.NextAttemptAt); wait < 50*time.Second || wait > usecases.RetryDelay(1) {
+		t.Errorf("Expected the retry about a minute later, got %v", wait)
+	}
+	if n := worker.DrainOutbox(outbox, 3); n != 0 {
+		t.Errorf("Expected nothing due before the retry time, got %d", n)
+	}
+
+	db.Model(&domain.OutboundMessage{}).Where("id = ?", queued.ID).Update("next_attempt_at", time.Now().Add(-time.Second))
+	worker.DrainOutbox(outbox, 3)
+	msg, _ = outbox.GetMessage(queued.ID)
+	if msg.Status != domain.MessageStatusSent || msg.SentAt == nil || msg.LastError != "" || len(msg.Deliveries) != 2 || !msg.Deliveries[1].Success {
+		t.Errorf("Expected the retry to deliver, got %+v", msg)
+	}
+
+	// A message that keeps failing is given up on
+	for i := 0; i < 5; i++ {
+		outbox.Queue(&domain.OutboundMessage{Channel: domain.ChannelWhatsApp, Recipient: fmt.Sprintf("010000000%02d", i), Body: "bulk", MaxAttempts: 2})
+	}
+	failNext(10)
+	if n := worker.DrainOutbox(outbox, 3); n != 5 {
+		t.Errorf("Expected the pool to attempt all five messages, got %d", n)
+	}
+	db.Model(&domain.OutboundMessage{}).Where("status = ?", domain.MessageStatusFailed).Update("next_attempt_at", time.Now().Add(-time.Second))
+	worker.DrainOutbox(outbox, 3)
+	dead, total, _, _ := outbox.GetMessages(domain.OutboundMessageFilter{Status: domain.MessageStatusDead}, 1, 20)
+	if total != 5 || dead[0].Attempts != 2 {
+		t.Fatalf("Expected five dead messages after two attempts, got %d", total)
+	}
+
+	resent, err := outbox.Resend(dead[0].ID, 1)
+	if err != nil || resent.Status != domain.MessageStatusQueued || resent.ResendOf == nil || *resent.ResendOf != dead[0].ID || resent.Source != domain.MessageSourceResend {
+		t.Fatalf("Expected a queued copy, got %+v (%v)", resent, err)
+	}
+	worker.DrainOutbox(outbox, 3)
+	if msg, _ := outbox.GetMessage(resent.ID); msg.Status != domain.MessageStatusSent {
+		t.Errorf("Expected the resent message delivered, got %s", msg.Status)
+	}
+	if _, err := outbox.Resend(9999, 1); err == nil {
+		t.Error("Expected resending an unknown message to fail")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 2 || delivered[0] != "01012345678: مرحباً أحمد محمد،\nYour order is ready" {
+		t.Errorf("Unexpected deliveries %v", delivered)
+	}
+
+	// Customers no channel can reach are not queued
+	if _, err := outbox.QueueForCustomer(&domain.Customer{ID: 3}, "", "x", domain.MessageSourceActivity, "", 1); err != services.ErrNoChannel {
+		t.Errorf("Expected ErrNoChannel, got %v", err)
+	}
+}
+
+func TestOutbox_RetryDelay(t *testing.T) {
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for i, d := range want {
+		if got := usecases.RetryDelay(i + 1); got != d {
+			t.Errorf("RetryDelay(%d) = %v, want %v", i+1, got, d)
+		}
+	}
+	if got := usecases.RetryDelay(30); got != 2*time.Hour {
+		t.Errorf("Expected the delay capped at two hours, got %v", got)
+	}
+}
@@ -0,0 +1,414 @@
+package integration
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"erp-system/api/routes"
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func newSalesUseCase(db *gorm.DB) *usecases.SalesUseCase {
+	return usecases.NewSalesUseCase(
+		repositories.NewSalesRepository(db),
+		repositories.NewCustomerRepository(db),
+		repositories.NewInventoryRepository(db),
+		repositories.NewPromotionRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewNotificationRepository(db),
+		services.NewTaxService(repositories.NewTaxRepository(db), repositories.NewSettingsRepository(db)),
+		services.NewCurrencyService(repositories.NewCurrencyRepository(db)),
+		newCreditService(db),
+	)
+}
+
+func newCreditService(db *gorm.DB) *services.CreditService {
+	return services.NewCreditService(
+		repositories.NewCreditRepository(db),
+		repositories.NewSettingsRepository(db),
+		repositories.NewUserRepository(db),
+		newDispatcher(db, nil),
+	)
+}
+
+func newDispatcher(db *gorm.DB, queue services.MessageQueue) *services.NotificationDispatcher {
+	return services.NewNotificationDispatcher(
+		repositories.NewSubscriptionRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewNotificationRepository(db),
+		queue,
+	)
+}
+
+func newEventBus(db *gorm.DB) *services.EventBus {
+	return services.NewEventBus(repositories.NewEventRepository(db))
+}
+
+// TestSalesCoupon_Integration verifies coupon promotions reduce the order total
+func TestSalesCoupon_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	promoUC := usecases.NewPromotionUseCase(repositories.NewPromotionRepository(db))
+	_, err := promoUC.CreatePromotion(&domain.CreatePromotionRequest{
+		Name:          "Winter Sale",
+		Code:          "winter10",
+		Type:          domain.PromotionTypePercent,
+		Value:         10,
+		MinOrderValue: money.FromFloat(500),
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreatePromotion failed: %v", err)
+	}
+
+	salesUC := newSalesUseCase(db)
+	order, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		CouponCode: "WINTER10",
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 2, UnitPrice: money.FromFloat(500)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	if order.DiscountAmount != money.FromFloat(100) {
+		t.Errorf("Expected discount 100, got %s", order.DiscountAmount)
+	}
+	if order.NetAmount != money.FromFloat(900) {
+		t.Errorf("Expected net amount 900, got %s", order.NetAmount)
+	}
+
+	// Below the minimum order value the coupon must be refused
+	_, err = salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		CouponCode: "WINTER10",
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(100)}},
+	}, 1)
+	if err == nil {
+		t.Error("Expected error for order below coupon minimum")
+	}
+}
+
+// TestSalesLegacyTaxRate_Integration verifies lines without tax codes keep their own tax_rate,
+// and that the default code only covers lines that give none
+func TestSalesLegacyTaxRate_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	salesUC := newSalesUseCase(db)
+	order := func(taxRate float64) *domain.SalesOrder {
+		t.Helper()
+		o, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+			CustomerID: 1,
+			OrderDate:  time.Now(),
+			Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(1000), TaxRate: taxRate}},
+		}, 1)
+		if err != nil {
+			t.Fatalf("CreateOrder failed: %v", err)
+		}
+		return o
+	}
+
+	if o := order(10); o.TaxAmount != money.FromFloat(100) || o.Items[0].TaxCodeID != nil {
+		t.Errorf("Expected 10%% tax without a default code, got %s", o.TaxAmount)
+	}
+
+	db.Create(&domain.TaxCode{Code: "VAT14", Name: "VAT 14%", Type: domain.TaxTypeStandard, Rate: 14, IsDefault: true, IsActive: true})
+	if o := order(10); o.TaxAmount != money.FromFloat(100) {
+		t.Errorf("Expected the line's 10%% to win over the default code, got %s", o.TaxAmount)
+	}
+	if o := order(0); o.TaxAmount != money.FromFloat(140) {
+		t.Errorf("Expected the default code for a line without a rate, got %s", o.TaxAmount)
+	}
+}
+
+// TestSalesDiscountApproval_Integration verifies discounts above the role threshold need approval
+func TestSalesDiscountApproval_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	promoUC := usecases.NewPromotionUseCase(repositories.NewPromotionRepository(db))
+	if _, err := promoUC.SetDiscountPolicy(&domain.SetDiscountPolicyRequest{RoleID: 1, MaxDiscountPercent: 5}); err != nil {
+		t.Fatalf("SetDiscountPolicy failed: %v", err)
+	}
+
+	salesUC := newSalesUseCase(db)
+	order, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(1000), Discount: 20}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	if order.Status != domain.OrderStatusPendingApproval {
+		t.Fatalf("Expected status %s, got %s", domain.OrderStatusPendingApproval, order.Status)
+	}
+
+	var count int64
+	db.Model(&domain.Notification{}).Where("user_id = ?", 2).Count(&count)
+	if count == 0 {
+		t.Error("Expected manager to be notified")
+	}
+
+	var customer domain.Customer
+	db.First(&customer, 1)
+	if !customer.Balance.IsZero() {
+		t.Errorf("Balance should not change before approval, got %s", customer.Balance)
+	}
+
+	// Only the policy's approver role decides, and never on its own orders
+	if _, err := salesUC.ApproveOrder(order.ID, 1, "self"); !errors.Is(err, usecases.ErrNotApprover) {
+		t.Errorf("Expected the creator not to approve their own order, got %v", err)
+	}
+	db.Model(&domain.User{}).Where("id = ?", 3).Update("is_active", true)
+	if _, err := salesUC.RejectOrder(order.ID, 3, "no"); !errors.Is(err, usecases.ErrNotApprover) {
+		t.Errorf("Expected a user without the approver role to be refused, got %v", err)
+	}
+
+	approved, err := salesUC.ApproveOrder(order.ID, 2, "ok")
+	if err != nil {
+		t.Fatalf("ApproveOrder failed: %v", err)
+	}
+	if approved.Status != domain.OrderStatusDraft {
+		t.Errorf("Expected status draft after approval, got %s", approved.Status)
+	}
+
+	db.First(&customer, 1)
+	if customer.Balance != order.NetAmount {
+		t.Errorf("Expected balance %s after approval, got %s", order.NetAmount, customer.Balance)
+	}
+
+	if _, err := salesUC.RejectOrder(order.ID, 2, "late"); err == nil {
+		t.Error("Expected error rejecting an already approved order")
+	}
+}
+
+// TestSalesMultiCurrency_Integration verifies foreign currency orders book at the order rate and realise FX on payment
+func TestSalesMultiCurrency_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	orderDate := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	currencyUC := usecases.NewCurrencyUseCase(repositories.NewCurrencyRepository(db))
+	if _, err := currencyUC.SetExchangeRate(&domain.SetExchangeRateRequest{Currency: "usd", Rate: 48, EffectiveDate: orderDate.AddDate(0, 0, -1)}, 1); err != nil {
+		t.Fatalf("SetExchangeRate failed: %v", err)
+	}
+	if _, err := currencyUC.SetExchangeRate(&domain.SetExchangeRateRequest{Currency: "USD", Rate: 50, EffectiveDate: orderDate.AddDate(0, 1, 0)}, 1); err != nil {
+		t.Fatalf("SetExchangeRate failed: %v", err)
+	}
+
+	salesUC := newSalesUseCase(db)
+	order, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  orderDate,
+		Currency:   "USD",
+		Items:      []domain.CreateOrderItemRequest{{ProductID: 1, Quantity: 1, UnitPrice: money.FromFloat(100)}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if order.ExchangeRate != 48 || order.BaseNetAmount != money.FromFloat(4800) {
+		t.Errorf("Expected rate 48 and base 4800, got %v / %s", order.ExchangeRate, order.BaseNetAmount)
+	}
+
+	var customer domain.Customer
+	db.First(&customer, 1)
+	if customer.Balance != money.FromFloat(4800) {
+		t.Errorf("Expected balance 4800 in base currency, got %s", customer.Balance)
+	}
+
+	// Paid a month later at 50: the customer settles 4800 of receivable and 200 is a realised gain
+	payment, err := salesUC.RecordPayment(order.ID, &domain.RecordPaymentRequest{
+		Amount:      money.FromFloat(100),
+		PaymentDate: orderDate.AddDate(0, 1, 1),
+	}, 1)
+	if err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	if payment.BaseAmount != money.FromFloat(5000) || payment.FXDifference != money.FromFloat(200) {
+		t.Errorf("Expected base 5000 and FX gain 200, got %s / %s", payment.BaseAmount, payment.FXDifference)
+	}
+
+	db.First(&customer, 1)
+	if !customer.Balance.IsZero() {
+		t.Errorf("Expected balance 0 after full payment, got %s", customer.Balance)
+	}
+
+	if _, err := salesUC.RecordPayment(order.ID, &domain.RecordPaymentRequest{Amount: money.FromFloat(1), PaymentDate: orderDate}, 1); err == nil {
+		t.Error("Expected error paying more than the outstanding amount")
+	}
+}
+
+// TestSalesOrderEditing_Integration verifies draft edits reprice the order and adjust the
+// customer balance, while confirmed orders are changed through diffable revisions
+func TestSalesOrderEditing_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	salesUC := newSalesUseCase(db)
+	custRepo := repositories.NewCustomerRepository(db)
+	before, _ := custRepo.FindByID(1)
+
+	order, err := salesUC.CreateOrder(&domain.CreateOrderRequest{
+		CustomerID: 1,
+		OrderDate:  time.Now(),
+		Items: []domain.CreateOrderItemRequest{
+			{ProductID: 1, Quantity: 2, UnitPrice: money.FromFloat(100)},
+			{ProductID: 2, Quantity: 1, UnitPrice: money.FromFloat(50)},
+		},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	// Change the first line, drop the second and add a third
+	order, err = salesUC.UpdateOrder(order.ID, &domain.UpdateOrderRequest{
+		Items: []domain.UpdateOrderItemRequest{
+			{ID: order.Items[0].ID, CreateOrderItemRequest: domain.CreateOrderItemRequest{ProductID: 1, Quantity: 3, UnitPrice: money.FromFloat(100)}},
+			{CreateOrderItemRequest: domain.CreateOrderItemRequest{ProductID: 3, Quantity: 1, UnitPrice: money.FromFloat(20)}},
+		},
+	}, 1)
+	if err != nil {
+		t.Fatalf("UpdateOrder failed: %v", err)
+	}
+	if len(order.Items) != 2 || order.NetAmount != money.FromFloat(320) {
+		t.Errorf("Expected 2 lines totalling 320, got %d lines totalling %s", len(order.Items), order.NetAmount)
+	}
+	if order.Revision != 1 {
+		t.Errorf("Draft edits should not create revisions, got revision %d", order.Revision)
+	}
+	after, _ := custRepo.FindByID(1)
+	if got := after.Balance.Sub(before.Balance); got != money.FromFloat(320) {
+		t.Errorf("Expected balance to grow by 320, got %s", got)
+	}
+
+	// Confirm the order and make sure edits now require a revision
+	if err := repositories.NewSalesRepository(db).UpdateStatus(order.ID, domain.OrderStatusConfirmed); err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	edit := &domain.UpdateOrderRequest{
+		Items: []domain.UpdateOrderItemRequest{
+			{ID: order.Items[0].ID, CreateOrderItemRequest: domain.CreateOrderItemRequest{ProductID: 1, Quantity: 4, UnitPrice: money.FromFloat(100)}},
+		},
+	}
+	if _, err := salesUC.UpdateOrder(order.ID, edit, 1); err == nil {
+		t.Error("Expected error editing a confirmed order without a revision")
+	}
+	if _, err := salesUC.ReviseOrder(order.ID, edit, 1); err == nil {
+		t.Error("Expected error revising without a reason")
+	}
+
+	edit.Reason = "Customer increased quantity"
+	revised, err := salesUC.ReviseOrder(order.ID, edit, 1)
+	if err != nil {
+		t.Fatalf("ReviseOrder failed: %v", err)
+	}
+	if revised.Revision != 2 || revised.NetAmount != money.FromFloat(400) {
+		t.Errorf("Expected revision 2 totalling 400, got revision %d totalling %s", revised.Revision, revised.NetAmount)
+	}
+
+	revisions, _ := salesUC.GetRevisions(order.ID)
+	if len(revisions) != 1 || revisions[0].NetAmount != money.FromFloat(320) {
+		t.Fatalf("Expected one snapshot of the 320 version, got %+v", revisions)
+	}
+
+	diff, err := salesUC.DiffRevisions(order.ID, 0, 0)
+	if err != nil {
+		t.Fatalf("DiffRevisions failed: %v", err)
+	}
+	changes := map[string]int{}
+	for _, item := range diff.Items {
+		changes[item.Change]++
+	}
+	if len(diff.Items) != 2 || changes[domain.ItemChangeChanged] != 1 || changes[domain.ItemChangeRemoved] != 1 {
+		t.Errorf("Expected one changed and one removed line, got %+v", diff.Items)
+	}
+	var netChanged bool
+	for _, f := range diff.Fields {
+		if f.Field == "net_amount" && f.From == "320.00" && f.To == "400.00" {
+			netChanged = true
+		}
+	}
+	if !netChanged {
+		t.Errorf("Expected net_amount 320.00 -> 400.00 in %+v", diff.Fields)
+	}
+}
+
+// TestDiscountPolicyRoutesRequireManager_Integration verifies a sales user cannot change
+// promotions or raise their own discount limit
+func TestDiscountPolicyRoutesRequireManager_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupPromotionRoutes(router, handlers.NewPromotionHandler(usecases.NewPromotionUseCase(repositories.NewPromotionRepository(db))))
+
+	const salesRole = 3
+	guarded := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/promotions"},
+		{http.MethodPut, "/api/v1/promotions/1"},
+		{http.MethodDelete, "/api/v1/promotions/1"},
+		{http.MethodPut, "/api/v1/discount-policies"},
+	}
+	for _, r := range guarded {
+		if code := statusAs(router, r.method, r.path, salesRole); code != http.StatusForbidden {
+			t.Errorf("%s %s: expected a sales user to get 403, got %d", r.method, r.path, code)
+		}
+		if code := statusAs(router, r.method, r.path, domain.RoleManager); code == http.StatusForbidden {
+			t.Errorf("%s %s: expected a manager to be let through", r.method, r.path)
+		}
+	}
+	if code := statusAs(router, http.MethodGet, "/api/v1/discount-policies", salesRole); code != http.StatusOK {
+		t.Errorf("Expected a sales user to see the discount policies, got %d", code)
+	}
+}
+
+// TestExchangeRateRoutesRequireManager_Integration verifies a sales user can read exchange
+// rates but not set or delete them
+func TestExchangeRateRoutesRequireManager_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupCurrencyRoutes(router, handlers.NewCurrencyHandler(usecases.NewCurrencyUseCase(repositories.NewCurrencyRepository(db))))
+
+	const salesRole = 3
+	for _, r := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/exchange-rates"},
+		{http.MethodDelete, "/api/v1/exchange-rates/1"},
+	} {
+		if code := statusAs(router, r.method, r.path, salesRole); code != http.StatusForbidden {
+			t.Errorf("%s %s: expected a sales user to get 403, got %d", r.method, r.path, code)
+		}
+		if code := statusAs(router, r.method, r.path, domain.RoleManager); code == http.StatusForbidden {
+			t.Errorf("%s %s: expected a manager to be let through", r.method, r.path)
+		}
+	}
+	if code := statusAs(router, http.MethodGet, "/api/v1/exchange-rates", salesRole); code != http.StatusOK {
+		t.Errorf("Expected a sales user to see the exchange rates, got %d", code)
+	}
+}
@@ -0,0 +1,195 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"erp-system/api/routes"
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+
+	"github.com/gin-gonic/gin"
+)
+
+func segmentMemberIDs(t *testing.T, uc *usecases.SegmentUseCase, segmentID uint) []uint {
+	t.Helper()
+	customers, _, err := uc.GetMembers(segmentID, 1, 50)
+	if err != nil {
+		t.Fatalf("GetMembers failed: %v", err)
+	}
+	ids := make([]uint, 0, len(customers))
+	for _, c := range customers {
+		ids = append(ids, c.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// TestCustomerSegments_Integration verifies tags, rule-based segments, segment and tag list
+// filters, and WhatsApp campaigns to a segment
+func TestCustomerSegments_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	var mu sync.Mutex
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		sent = append(sent, payload["phone"]+": "+payload["message"])
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	settingsRepo.Set(domain.SettingWhatsAppURL, server.URL, "integration")
+	settingsRepo.Set(domain.SettingWhatsAppToken, "token", "integration")
+
+	customerRepo := repositories.NewCustomerRepository(db)
+	segmentUC := usecases.NewSegmentUseCase(repositories.NewSegmentRepository(db), customerRepo, services.NewNotificationService(settingsRepo))
+	customerUC := usecases.NewCustomerUseCase(customerRepo, nil, nil, nil, nil, nil, nil)
+
+	db.Model(&domain.Customer{}).Where("id = ?", 2).Update("IsWhatsAppEnabled", false)
+	orders := []domain.SalesOrder{
+		{OrderNumber: "SO-SEG-1", CustomerID: 1, OrderDate: time.Now().AddDate(0, 0, -10), Status: domain.OrderStatusConfirmed, BaseNetAmount: money.FromFloat(20000)},
+		{OrderNumber: "SO-SEG-2", CustomerID: 2, OrderDate: time.Now().AddDate(0, 0, -200), Status: domain.OrderStatusDelivered, BaseNetAmount: money.FromFloat(5000)},
+		{OrderNumber: "SO-SEG-3", CustomerID: 2, OrderDate: time.Now(), Status: domain.OrderStatusCancelled, BaseNetAmount: money.FromFloat(100000)},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("Create order failed: %v", err)
+		}
+	}
+
+	tags, err := segmentUC.TagCustomer(1, &domain.CustomerTagsRequest{Tags: []string{"east", " key-account "}})
+	if err != nil || len(tags) != 2 {
+		t.Fatalf("TagCustomer failed: %v %v", tags, err)
+	}
+	if _, err := segmentUC.TagCustomer(3, &domain.CustomerTagsRequest{Tags: []string{"east"}}); err != nil {
+		t.Fatalf("TagCustomer failed: %v", err)
+	}
+	if all, _ := segmentUC.GetTags(); len(all) != 2 || all[0].Name != "east" || all[0].CustomerCount != 2 {
+		t.Errorf("Expected two tags with east on 2 customers, got %+v", all)
+	}
+
+	if _, err := segmentUC.CreateSegment(&domain.SaveSegmentRequest{
+		Name:  "Bad",
+		Rules: []domain.SegmentRule{{Field: "balance", Operator: "contains", Value: "1"}},
+	}, 1); err == nil {
+		t.Error("Expected an operator the field doesn't support to be rejected")
+	}
+
+	bigSpenders, err := segmentUC.CreateSegment(&domain.SaveSegmentRequest{
+		Name:  "Big spenders",
+		Rules: []domain.SegmentRule{{Field: domain.SegmentFieldTotalPurchases, Operator: "gte", Value: "10000"}},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateSegment failed: %v", err)
+	}
+	if bigSpenders.MemberCount != 1 {
+		t.Errorf("Expected cancelled orders not to count as purchases, got %d members", bigSpenders.MemberCount)
+	}
+
+	lapsed, _ := segmentUC.CreateSegment(&domain.SaveSegmentRequest{
+		Name:  "Lapsed",
+		Rules: []domain.SegmentRule{{Field: domain.SegmentFieldLastOrderDate, Operator: "not_within_days", Value: "90"}},
+	}, 1)
+	if ids := segmentMemberIDs(t, segmentUC, lapsed.ID); len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Errorf("Expected customers 2 and 3 (never ordered) lapsed, got %v", ids)
+	}
+
+	campaignSegment, err := segmentUC.CreateSegment(&domain.SaveSegmentRequest{
+		Name:  "Alexandria or east",
+		Match: domain.SegmentMatchAny,
+		Rules: []domain.SegmentRule{
+			{Field: domain.SegmentFieldCity, Operator: "eq", Value: "Alexandria"},
+			{Field: domain.SegmentFieldTag, Operator: "has", Value: "east"},
+		},
+	}, 1)
+	if err != nil {
+		t.Fatalf("CreateSegment failed: %v", err)
+	}
+	if ids := segmentMemberIDs(t, segmentUC, campaignSegment.ID); len(ids) != 3 {
+		t.Errorf("Expected all three customers to match any rule, got %v", ids)
+	}
+	if count, _ := segmentUC.PreviewSegment(&domain.SaveSegmentRequest{
+		Name:  "Preview",
+		Rules: []domain.SegmentRule{{Field: domain.SegmentFieldTag, Operator: "has", Value: "east"}, {Field: domain.SegmentFieldType, Operator: "in", Value: "vip, regular"}},
+	}); count != 1 {
+		t.Errorf("Expected preview to match customer 1 only, got %d", count)
+	}
+
+	customers, total, err := customerUC.GetCustomersFiltered(1, 10, domain.CustomerFilter{SegmentID: lapsed.ID, Tag: "east"})
+	if err != nil || total != 1 || customers[0].ID != 3 || len(customers[0].Tags) != 1 {
+		t.Errorf("Expected customer 3 with its tag when filtering lapsed + east, got %d (%v)", total, err)
+	}
+
+	campaign, err := segmentUC.SendCampaign(campaignSegment.ID, &domain.SendCampaignRequest{Message: "أهلاً {name}"}, 1)
+	if err != nil {
+		t.Fatalf("SendCampaign failed: %v", err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for campaign.Status != domain.CampaignStatusCompleted && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		campaigns, _ := segmentUC.GetCampaigns(campaignSegment.ID)
+		campaign = &campaigns[0]
+	}
+	if campaign.Recipients != 3 || campaign.SentCount != 2 || campaign.SkippedCount != 1 {
+		t.Errorf("Expected 2 sent and customer 2 skipped, got %+v", campaign)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 2 || sent[0] != "01012345678: أهلاً أحمد محمد" {
+		t.Errorf("Unexpected messages %v", sent)
+	}
+}
+
+// TestSegmentRoutesRequireManager_Integration verifies a sales user can look at tags and
+// segments but not change them or send a campaign
+func TestSegmentRoutesRequireManager_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	segmentUC := usecases.NewSegmentUseCase(repositories.NewSegmentRepository(db), repositories.NewCustomerRepository(db), services.NewNotificationService(settingsRepo))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupSegmentRoutes(router, handlers.NewSegmentHandler(segmentUC))
+
+	const salesRole = 3
+	guarded := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/tags"},
+		{http.MethodDelete, "/api/v1/tags/1"},
+		{http.MethodPost, "/api/v1/customers/1/tags"},
+		{http.MethodDelete, "/api/v1/customers/1/tags/1"},
+		{http.MethodPost, "/api/v1/segments"},
+		{http.MethodPut, "/api/v1/segments/1"},
+		{http.MethodDelete, "/api/v1/segments/1"},
+		{http.MethodPost, "/api/v1/segments/1/campaigns"},
+	}
+	for _, r := range guarded {
+		if code := statusAs(router, r.method, r.path, salesRole); code != http.StatusForbidden {
+			t.Errorf("%s %s: expected a sales user to get 403, got %d", r.method, r.path, code)
+		}
+		if code := statusAs(router, r.method, r.path, domain.RoleManager); code == http.StatusForbidden {
+			t.Errorf("%s %s: expected a manager to be let through", r.method, r.path)
+		}
+	}
+	if code := statusAs(router, http.MethodGet, "/api/v1/segments", salesRole); code != http.StatusOK {
+		t.Errorf("Expected a sales user to list segments, got %d", code)
+	}
+}
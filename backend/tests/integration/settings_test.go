@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"erp-system/api/routes"
+	"erp-system/internal/domain"
+	"erp-system/internal/handlers"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/auth"
+	"erp-system/tests/fixtures"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestSettingsSecrets_Integration verifies credentials are masked in responses, survive a
+// masked value being posted back, and that only admins reach the settings
+func TestSettingsSecrets_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	settingsRepo := repositories.NewSettingsRepository(db)
+	uc := usecases.NewSettingsUseCase(settingsRepo, nil)
+	secrets := map[string]string{
+		domain.SettingS3AccessKey:         "AKIA123",
+		domain.SettingS3SecretKey:         "s3-secret",
+		domain.SettingSMTPPassword:        "smtp-pass",
+		domain.SettingSMSToken:            "sms-token",
+		domain.SettingWhatsAppToken:       "wa-token",
+		domain.SettingWhatsAppAppSecret:   "wa-app-secret",
+		domain.SettingWhatsAppVerifyToken: "wa-verify",
+		domain.SettingEventWebhookSecret:  "event-secret",
+	}
+	update := map[string]string{domain.SettingCompanyName: "Glass Co", domain.SettingS3Bucket: "files"}
+	for key, value := range secrets {
+		update[key] = value
+	}
+	if err := uc.UpdateSettings(update); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	settings, err := uc.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings failed: %v", err)
+	}
+	for key := range secrets {
+		if settings[key] != usecases.MaskedSecret {
+			t.Errorf("Expected %s to be masked, got %q", key, settings[key])
+		}
+	}
+	if settings[domain.SettingS3Bucket] != "files" {
+		t.Errorf("Expected other settings in full, got %q", settings[domain.SettingS3Bucket])
+	}
+
+	// The settings page posts the masked values back with its other changes, and a blank
+	// password field doesn't clear the password
+	settings[domain.SettingCompanyName] = "Glass Co Ltd"
+	settings[domain.SettingSMTPPassword] = ""
+	if err := uc.UpdateSettings(settings); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	for key, value := range secrets {
+		if stored, _ := settingsRepo.Get(key); stored.Value != value {
+			t.Errorf("Expected %s to keep its value, got %q", key, stored.Value)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupSettingsRoutes(router, handlers.NewSettingsHandler(uc))
+	get := func(roleID uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/settings", nil)
+		if roleID != 0 {
+			token, _ := auth.GenerateAccessToken(1, "user@erp.local", roleID)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	if w := get(0); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous callers to be rejected, got %d", w.Code)
+	}
+	if w := get(domain.RoleManager); w.Code != http.StatusForbidden {
+		t.Errorf("Expected managers to be rejected, got %d", w.Code)
+	}
+	w := get(domain.RoleAdmin)
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); w.Code != http.StatusOK || err != nil || body.Data[domain.SettingS3SecretKey] != usecases.MaskedSecret {
+		t.Errorf("Expected admins to get masked settings, got %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,217 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/services"
+	"erp-system/internal/usecases"
+	"erp-system/internal/worker"
+	"erp-system/tests/fixtures"
+)
+
+// TestNotificationSubscriptions_Integration verifies events reach their audience and
+// subscribers on the channels each chose, honouring opt-outs, branches, the actor and quiet hours
+func TestNotificationSubscriptions_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	notifRepo := repositories.NewNotificationRepository(db)
+	subRepo := repositories.NewSubscriptionRepository(db)
+	settingsRepo := repositories.NewSettingsRepository(db)
+	outboxRepo := repositories.NewOutboundMessageRepository(db)
+	outbox := usecases.NewOutboxUseCase(outboxRepo, services.NewNotificationService(settingsRepo),
+		services.NewTemplateService(repositories.NewMessageTemplateRepository(db), settingsRepo))
+	dispatcher := newDispatcher(db, outbox)
+	bus := newEventBus(db)
+	services.SubscribeNotifications(bus, dispatcher)
+	prefsUC := usecases.NewNotificationUseCase(notifRepo, subRepo, services.NewNotificationHub())
+
+	countNotifications := func(userID uint) int64 {
+		n, _ := notifRepo.CountUnread(userID)
+		return n
+	}
+
+	// The audience hears about an event in the app by default; nobody else does
+	event := domain.NotificationEvent{Type: domain.EventCreditBreach, Title: "Credit hold", Audience: []uint{2}, Key: "c1"}
+	if n := dispatcher.Dispatch(event); n != 1 || countNotifications(2) != 1 || countNotifications(1) != 0 {
+		t.Fatalf("Expected only the audience to be notified, got %d deliveries", n)
+	}
+
+	// User 1 subscribes by email, user 2 turns the event off
+	if _, err := prefsUC.UpdatePreferences(1, &domain.UpdatePreferencesRequest{
+		QuietHoursStart: "00:00",
+		QuietHoursEnd:   "23:59",
+		Subscriptions:   []domain.SubscriptionRequest{{Event: domain.EventCreditBreach, Enabled: true, Channels: []string{"email"}}},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	if _, err := prefsUC.UpdatePreferences(2, &domain.UpdatePreferencesRequest{
+		Subscriptions: []domain.SubscriptionRequest{{Event: domain.EventCreditBreach, Enabled: false}},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	event.Key = "c2"
+	if n := dispatcher.Dispatch(event); n != 1 || countNotifications(2) != 1 {
+		t.Fatalf("Expected the opted-out audience to be skipped and the subscriber emailed, got %d deliveries", n)
+	}
+	queued, _, _ := outboxRepo.FindAll(domain.OutboundMessageFilter{Channel: domain.ChannelEmail}, 1, 10)
+	if len(queued) != 1 || queued[0].Recipient != "admin@erp.local" || queued[0].Source != domain.MessageSourceEvent {
+		t.Fatalf("Expected one event email to the admin, got %+v", queued)
+	}
+	if !queued[0].NextAttemptAt.After(time.Now()) {
+		t.Errorf("Expected quiet hours to hold the email, next attempt %v", queued[0].NextAttemptAt)
+	}
+	dispatcher.Dispatch(event)
+	if queued, _, _ := outboxRepo.FindAll(domain.OutboundMessageFilter{Channel: domain.ChannelEmail}, 1, 10); len(queued) != 1 {
+		t.Errorf("Expected a repeated event to be queued once, got %d emails", len(queued))
+	}
+
+	// The actor isn't told about their own action, and subscribers of other branches are skipped
+	if _, err := prefsUC.UpdatePreferences(1, &domain.UpdatePreferencesRequest{
+		Subscriptions: []domain.SubscriptionRequest{{Event: domain.EventOrderCreated, Enabled: true}},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	if n := dispatcher.Dispatch(domain.NotificationEvent{Type: domain.EventOrderCreated, Title: "New order", Actor: 1}); n != 0 {
+		t.Errorf("Expected the actor to be skipped, got %d deliveries", n)
+	}
+	branch := domain.Branch{Code: "ALX", Name: "Alexandria"}
+	db.Create(&branch)
+	otherBranch := branch.ID + 1
+	db.Model(&domain.User{}).Where("id = ?", 1).Update("branch_id", branch.ID)
+	if n := dispatcher.Dispatch(domain.NotificationEvent{Type: domain.EventOrderCreated, Title: "New order", BranchID: &otherBranch}); n != 0 {
+		t.Errorf("Expected subscribers of another branch to be skipped, got %d deliveries", n)
+	}
+	if n := dispatcher.Dispatch(domain.NotificationEvent{Type: domain.EventOrderCreated, Title: "New order", BranchID: &branch.ID}); n != 1 {
+		t.Errorf("Expected the branch's subscriber to be notified, got %d deliveries", n)
+	}
+
+	// WhatsApp needs a number
+	if _, err := prefsUC.UpdatePreferences(1, &domain.UpdatePreferencesRequest{
+		Subscriptions: []domain.SubscriptionRequest{{Event: domain.EventLowStock, Enabled: true, Channels: []string{"whatsapp"}}},
+	}); err == nil {
+		t.Error("Expected WhatsApp notifications without a number to be rejected")
+	}
+	if _, err := prefsUC.UpdatePreferences(1, &domain.UpdatePreferencesRequest{
+		WhatsAppNumber: "01000000001",
+		Subscriptions:  []domain.SubscriptionRequest{{Event: domain.EventLowStock, Enabled: true, Channels: []string{"whatsapp", "in_app"}}},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+
+	// Low stock fires when stock crosses the reorder level, not while it stays low
+	invUC := usecases.NewInventoryUseCase(repositories.NewInventoryRepository(db))
+	product, err := invUC.CreateProduct(&domain.CreateProductRequest{SKU: "PIPE-1", Name: "Pipe", ReorderLevel: 5, StockQuantity: 10})
+	if err != nil {
+		t.Fatalf("CreateProduct failed: %v", err)
+	}
+	before := countNotifications(1)
+	for _, qty := range []int{4, 3} {
+		qty := qty
+		if _, err := invUC.UpdateProduct(product.ID, &domain.UpdateProductRequest{StockQuantity: &qty}); err != nil {
+			t.Fatalf("UpdateProduct failed: %v", err)
+		}
+	}
+	worker.DrainEvents(bus)
+	if got := countNotifications(1) - before; got != 1 {
+		t.Errorf("Expected one low stock notification, got %d", got)
+	}
+	whatsapp, _, _ := outboxRepo.FindAll(domain.OutboundMessageFilter{Channel: domain.ChannelWhatsApp}, 1, 10)
+	if len(whatsapp) != 1 || whatsapp[0].Recipient != "01000000001" {
+		t.Errorf("Expected one low stock WhatsApp message, got %+v", whatsapp)
+	}
+
+	// Production completion reaches whoever created the order
+	productionUC := usecases.NewProductionUseCase(repositories.NewProductionRepository(db))
+	order, err := productionUC.CreateOrder(&domain.CreateProductionOrderRequest{ProductID: product.ID, Quantity: 2, StartDate: time.Now()}, 2)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	before = countNotifications(2)
+	productionUC.UpdateOrderStatus(order.ID, "completed", 1)
+	productionUC.UpdateOrderStatus(order.ID, "completed", 1)
+	worker.DrainEvents(bus)
+	if got := countNotifications(2) - before; got != 1 {
+		t.Errorf("Expected one production completed notification, got %d", got)
+	}
+
+	// Due reminders reach the assignee, not the admin
+	db.Model(&domain.CustomerActivity{}).Where("notified_at IS NULL").Update("notified_at", time.Now())
+	assignee := uint(2)
+	due := time.Now().Add(-time.Minute)
+	db.Create(&domain.CustomerActivity{CustomerID: 1, Type: "call", Description: "Call back", AssignedTo: &assignee, CreatedBy: 1,
+		ReminderDate: &due, NotificationEnabled: true})
+	before, adminBefore := countNotifications(2), countNotifications(1)
+	worker.ProcessReminders(db, outbox, dispatcher)
+	if countNotifications(2)-before != 1 || countNotifications(1) != adminBefore {
+		t.Error("Expected the due reminder to notify only its assignee")
+	}
+}
+
+// TestNotificationPreferences_Integration verifies the effective settings and validation
+func TestNotificationPreferences_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	uc := usecases.NewNotificationUseCase(repositories.NewNotificationRepository(db), repositories.NewSubscriptionRepository(db), services.NewNotificationHub())
+
+	prefs, err := uc.GetPreferences(1)
+	if err != nil {
+		t.Fatalf("GetPreferences failed: %v", err)
+	}
+	if len(prefs.Subscriptions) != len(domain.NotificationEventTypes) {
+		t.Fatalf("Expected a setting for every event, got %d", len(prefs.Subscriptions))
+	}
+	for _, s := range prefs.Subscriptions {
+		if s.Custom || s.Enabled != s.AudienceOnly || len(s.Channels) != 1 || s.Channels[0] != domain.DeliveryInApp {
+			t.Errorf("Unexpected default for %s: %+v", s.Key, s)
+		}
+	}
+
+	invalid := []domain.UpdatePreferencesRequest{
+		{QuietHoursStart: "22:00"},
+		{QuietHoursStart: "25:00", QuietHoursEnd: "07:00"},
+		{Subscriptions: []domain.SubscriptionRequest{{Event: "unknown", Enabled: true}}},
+		{Subscriptions: []domain.SubscriptionRequest{{Event: domain.EventLowStock, Enabled: true, Channels: []string{"sms"}}}},
+	}
+	for i := range invalid {
+		if _, err := uc.UpdatePreferences(1, &invalid[i]); err == nil {
+			t.Errorf("Expected request %d to be rejected", i)
+		}
+	}
+
+	prefs, err = uc.UpdatePreferences(1, &domain.UpdatePreferencesRequest{
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+		Subscriptions:   []domain.SubscriptionRequest{{Event: domain.EventLowStock, Enabled: true, Channels: []string{"email", "email"}}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	if prefs.QuietHoursStart != "22:00" || prefs.QuietHoursEnd != "07:00" {
+		t.Errorf("Expected quiet hours to be saved, got %+v", prefs.NotificationPreference)
+	}
+	for _, s := range prefs.Subscriptions {
+		if s.Key == domain.EventLowStock && (!s.Custom || !s.Enabled || len(s.Channels) != 1 || s.Channels[0] != domain.ChannelEmail) {
+			t.Errorf("Expected the low stock subscription to be saved, got %+v", s)
+		}
+	}
+
+	// Quiet hours spanning midnight hold messages until the morning
+	at := func(h, m int) time.Time { return time.Date(2024, 5, 1, h, m, 0, 0, time.UTC) }
+	pref := &prefs.NotificationPreference
+	if got := pref.QuietUntil(at(23, 0)); !got.Equal(time.Date(2024, 5, 2, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 23:00 to wait until 07:00 the next day, got %v", got)
+	}
+	if got := pref.QuietUntil(at(6, 30)); !got.Equal(at(7, 0)) {
+		t.Errorf("Expected 06:30 to wait until 07:00, got %v", got)
+	}
+	if got := pref.QuietUntil(at(12, 0)); !got.IsZero() {
+		t.Errorf("Expected midday to be outside quiet hours, got %v", got)
+	}
+}
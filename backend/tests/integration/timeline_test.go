@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"erp-system/internal/domain"
+	"erp-system/internal/repositories"
+	"erp-system/internal/usecases"
+	"erp-system/pkg/money"
+	"erp-system/tests/fixtures"
+)
+
+// TestCustomerTimeline_Integration verifies the merged timeline is complete and ordered across
+// cursor pages, that type filters apply, and the summary figures
+func TestCustomerTimeline_Integration(t *testing.T) {
+	db := fixtures.SetupTestDB(t)
+	defer fixtures.TeardownTestDB(db)
+	fixtures.SeedTestDB(t, db)
+
+	timelineUC := usecases.NewTimelineUseCase(repositories.NewTimelineRepository(db), repositories.NewCustomerRepository(db))
+
+	now := time.Now()
+	approvedAt := now.Add(-3 * time.Hour)
+	confirmed := &domain.SalesOrder{
+		OrderNumber: "SO-TL-1", CustomerID: 1, OrderDate: now.AddDate(0, 0, -1), Status: domain.OrderStatusConfirmed,
+		NetAmount: money.FromFloat(1000), BaseNetAmount: money.FromFloat(1000), Currency: "EGP",
+		ApprovedAt: &approvedAt, CreatedBy: 1, CreatedAt: now.Add(-4 * time.Hour),
+	}
+	draft := &domain.SalesOrder{
+		OrderNumber: "SO-TL-2", CustomerID: 1, OrderDate: now, Status: domain.OrderStatusDraft,
+		NetAmount: money.FromFloat(500), BaseNetAmount: money.FromFloat(500), Currency: "EGP", CreatedBy: 1,
+	}
+	other := &domain.SalesOrder{OrderNumber: "SO-TL-3", CustomerID: 2, OrderDate: now, Status: domain.OrderStatusConfirmed, CreatedBy: 1}
+	for _, o := range []*domain.SalesOrder{confirmed, draft, other} {
+		if err := db.Create(o).Error; err != nil {
+			t.Fatalf("Create order failed: %v", err)
+		}
+	}
+	db.Create(&domain.SalesPayment{OrderID: confirmed.ID, Amount: money.FromFloat(400), BaseAmount: money.FromFloat(400), Currency: "EGP", PaymentDate: now.Add(-time.Hour), Method: "cash"})
+	db.Create(&domain.CreditEvent{CustomerID: 1, Type: domain.CreditEventWarning, Reason: "Near the limit"})
+	db.Create(&domain.CreditEvent{CustomerID: 2, Type: domain.CreditEventWarning})
+	// Sent to two approvers, stored once per user
+	for _, userID := range []uint{1, 2} {
+		db.Create(&domain.Notification{UserID: userID, Title: "طلب موقوف", Message: "SO-TL-1", Type: "warning", Link: fmt.Sprintf("/sales/%d", confirmed.ID)})
+	}
+	db.Create(&domain.Notification{UserID: 1, Title: "Other", Type: "info", Link: fmt.Sprintf("/sales/%d", other.ID)})
+
+	// Fixture activities and documents, two orders, an approval, a credit event, a payment
+	// and one notification
+	const expected = 4 + 2 + 2 + 1 + 1 + 1 + 1
+
+	var all []domain.TimelineEntry
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > expected {
+			t.Fatal("Pagination did not terminate")
+		}
+		page, err := timelineUC.GetTimeline(1, cursor, 3, nil)
+		if err != nil {
+			t.Fatalf("GetTimeline failed: %v", err)
+		}
+		if (cursor == "") != (page.Summary != nil) {
+			t.Error("Expected the summary on the first page only")
+		}
+		all = append(all, page.Entries...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(all) != expected {
+		t.Fatalf("Expected %d entries, got %d: %+v", expected, len(all), all)
+	}
+	seen := map[string]bool{}
+	counts := map[string]int{}
+	for i, e := range all {
+		key := fmt.Sprintf("%s/%d", e.Source, e.RefID)
+		if seen[key] {
+			t.Errorf("Entry %s returned twice", key)
+		}
+		seen[key] = true
+		counts[e.Type]++
+		if i > 0 && e.OccurredAt.After(all[i-1].OccurredAt) {
+			t.Errorf("Entry %d (%s) is newer than the one before it", i, key)
+		}
+	}
+	if counts[domain.TimelineStatus] != 2 || counts[domain.TimelineNotification] != 1 || counts[domain.TimelinePayment] != 1 {
+		t.Errorf("Unexpected entry counts %v", counts)
+	}
+
+	filtered, err := timelineUC.GetTimeline(1, "", 50, []string{domain.TimelineOrder, domain.TimelinePayment})
+	if err != nil {
+		t.Fatalf("GetTimeline filtered failed: %v", err)
+	}
+	if len(filtered.Entries) != 3 || filtered.NextCursor != "" {
+		t.Errorf("Expected two orders and a payment, got %+v", filtered.Entries)
+	}
+	for _, e := range filtered.Entries {
+		if e.Type == domain.TimelinePayment && (e.Amount == nil || e.Amount.Float64() != 400) {
+			t.Errorf("Expected the payment amount, got %+v", e)
+		}
+	}
+
+	if _, err := timelineUC.GetTimeline(1, "", 10, []string{"invoice"}); err == nil {
+		t.Error("Expected an unknown type to fail")
+	}
+	if _, err := timelineUC.GetTimeline(1, "not-a-cursor", 10, nil); err == nil {
+		t.Error("Expected an invalid cursor to fail")
+	}
+	if _, err := timelineUC.GetTimeline(999, "", 10, nil); err == nil {
+		t.Error("Expected an unknown customer to fail")
+	}
+
+	summary, err := timelineUC.GetSummary(1)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.OrderCount != 1 || summary.LifetimeValue.Float64() != 1000 || summary.AverageOrderValue.Float64() != 1000 {
+		t.Errorf("Expected one purchase of 1000, got %+v", summary)
+	}
+	if summary.TotalPaid.Float64() != 400 {
+		t.Errorf("Expected 400 paid, got %v", summary.TotalPaid)
+	}
+	if summary.LastContactType != "call" || summary.LastContactAt == nil {
+		t.Errorf("Expected the call two hours ago as last contact, got %q %v", summary.LastContactType, summary.LastContactAt)
+	}
+	if summary.LastOrderAt == nil || summary.FirstOrderAt == nil {
+		t.Error("Expected first and last order dates")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"erp-system/internal/middleware"
+	"erp-system/pkg/auth"
 
 	"github.com/gin-gonic/gin"
 )
@@ -61,3 +62,108 @@ func TestSecurityHeaders(t *testing.T) {
 		t.Error("X-Content-Type-Options not set")
 	}
 }
+
+func TestRequireAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/test", middleware.RequireAuth(), func(c *gin.Context) {
+		c.JSON(200, gin.H{"user_id": c.GetUint("user_id")})
+	})
+
+	token, err := auth.GenerateAccessToken(7, "user@example.com", 3)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+
+	refresh, err := auth.GenerateRefreshToken(7)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken failed: %v", err)
+	}
+
+	cases := []struct {
+		header string
+		want   int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer not-a-token", http.StatusUnauthorized},
+		{token, http.StatusUnauthorized},
+		{"Bearer " + refresh, http.StatusUnauthorized},
+		{"Bearer " + token, http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/test", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Errorf("Authorization %q: expected %d, got %d", tc.header, tc.want, w.Code)
+		}
+		if tc.want == http.StatusOK && w.Body.String() != `{"user_id":7}` {
+			t.Errorf("Expected the user ID in the context, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", middleware.RequireAuth(), middleware.RequireRole(1), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for role, want := range map[uint]int{1: http.StatusNoContent, 3: http.StatusForbidden} {
+		token, err := auth.GenerateAccessToken(7, "user@example.com", role)
+		if err != nil {
+			t.Fatalf("GenerateAccessToken failed: %v", err)
+		}
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Role %d: expected %d, got %d", role, want, w.Code)
+		}
+	}
+}
+
+func TestRequireAuthExcept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequireAuthExcept("/api/v1/auth/login", "/api/v1/webhooks/"))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.POST("/api/v1/auth/login", ok)
+	router.POST("/api/v1/webhooks/whatsapp", ok)
+	router.GET("/api/v1/customers", ok)
+	router.GET("/health", ok)
+
+	token, err := auth.GenerateAccessToken(7, "user@example.com", 3)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken failed: %v", err)
+	}
+
+	cases := []struct {
+		method, path, token string
+		want                int
+	}{
+		{"POST", "/api/v1/auth/login", "", http.StatusNoContent},
+		{"POST", "/api/v1/webhooks/whatsapp", "", http.StatusNoContent},
+		{"GET", "/health", "", http.StatusNoContent},
+		{"GET", "/api/v1/customers", "", http.StatusUnauthorized},
+		{"GET", "/api/v1/customers", token, http.StatusNoContent},
+		{"GET", "/api/v1/unknown", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s %s (token %t): expected %d, got %d", tc.method, tc.path, tc.token != "", tc.want, w.Code)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package unit
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+
+	"erp-system/pkg/messaging"
+)
+
+// capture records the last JSON request a test server received
+type capture struct {
+	path, auth string
+	payload    map[string]interface{}
+}
+
+func captureServer(t *testing.T, c *capture, status int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.path, c.auth = r.URL.Path, r.Header.Get("Authorization")
+		c.payload = nil
+		json.NewDecoder(r.Body).Decode(&c.payload)
+		w.WriteHeader(status)
+		if status >= 400 {
+			io.WriteString(w, `{"error":{"message":"Invalid parameter"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMessaging_WhatsAppCloud(t *testing.T) {
+	var got capture
+	server := captureServer(t, &got, http.StatusOK)
+
+	ch := &messaging.WhatsAppCloud{Endpoint: server.URL + "/v19.0/", PhoneNumberID: "1055", Token: "EAAG"}
+	if err := ch.Send(messaging.Message{To: "+20 101-234-5678", Body: "مرحباً"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	text, _ := got.payload["text"].(map[string]interface{})
+	if got.path != "/v19.0/1055/messages" || got.auth != "Bearer EAAG" || got.payload["messaging_product"] != "whatsapp" ||
+		got.payload["to"] != "201012345678" || got.payload["type"] != "text" || text["body"] != "مرحباً" {
+		t.Errorf("Unexpected request %s %q %v", got.path, got.auth, got.payload)
+	}
+
+	if err := (&messaging.WhatsAppCloud{Endpoint: server.URL, PhoneNumberID: "1055"}).Send(messaging.Message{To: "1", Body: "x"}); err == nil {
+		t.Error("Expected a missing token to fail")
+	}
+	rejected := captureServer(t, &capture{}, http.StatusBadRequest)
+	err := (&messaging.WhatsAppCloud{Endpoint: rejected.URL, PhoneNumberID: "1055", Token: "EAAG"}).Send(messaging.Message{To: "1", Body: "x"})
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "Invalid parameter") {
+		t.Errorf("Expected the API error to be reported, got %v", err)
+	}
+}
+
+func TestMessaging_WebhookAndSMS(t *testing.T) {
+	var hook capture
+	server := captureServer(t, &hook, http.StatusOK)
+	webhook := &messaging.Webhook{ChannelName: "whatsapp", URL: server.URL + "/send", Token: "token"}
+	if err := webhook.Send(messaging.Message{To: "01012345678", Body: "hello"}); err != nil {
+		t.Fatalf("Webhook send failed: %v", err)
+	}
+	if webhook.Name() != "whatsapp" || hook.auth != "Bearer token" || hook.payload["phone"] != "01012345678" ||
+		hook.payload["message"] != "hello" || hook.payload["subject"] != nil {
+		t.Errorf("Unexpected webhook request %q %v", hook.auth, hook.payload)
+	}
+
+	var sms capture
+	gateway := captureServer(t, &sms, http.StatusAccepted)
+	if err := (&messaging.SMS{URL: gateway.URL, Sender: "Company"}).Send(messaging.Message{To: "+20 10 1234 5678", Body: "code 1234"}); err != nil {
+		t.Fatalf("SMS send failed: %v", err)
+	}
+	if sms.auth != "" || sms.payload["to"] != "201012345678" || sms.payload["from"] != "Company" || sms.payload["message"] != "code 1234" {
+		t.Errorf("Unexpected SMS request %q %v", sms.auth, sms.payload)
+	}
+	if err := (&messaging.SMS{URL: gateway.URL}).Send(messaging.Message{To: "n/a", Body: "x"}); err != messaging.ErrNoRecipient {
+		t.Errorf("Expected a number without digits to be rejected, got %v", err)
+	}
+}
+
+// fakeSMTP accepts one message over plain SMTP with AUTH PLAIN
+type fakeSMTP struct {
+	auth, from, to string
+	data           string
+}
+
+func startFakeSMTP(t *testing.T, f *fakeSMTP) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case cmd == "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case cmd == "AUTH":
+				decoded, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, "AUTH PLAIN "))
+				f.auth = string(decoded)
+				reply("235 Authenticated")
+			case cmd == "MAIL":
+				f.from = line
+				reply("250 OK")
+			case cmd == "RCPT":
+				f.to = line
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 Go ahead")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				f.data = data.String()
+				reply("250 Queued")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Unknown command")
+			}
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestMessaging_SMTP(t *testing.T) {
+	var got fakeSMTP
+	host, port, _ := net.SplitHostPort(startFakeSMTP(t, &got))
+	portNumber, _ := strconv.Atoi(port)
+	ch := &messaging.SMTP{Host: host, Port: portNumber, From: "الشركة <info@example.com>", Username: "info", Password: "secret"}
+
+	err := ch.Send(messaging.Message{To: "customer@example.com", Subject: "تذكير بالموعد", Body: "موعدكم غداً\nشكراً"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got.auth != "\x00info\x00secret" || got.from != "MAIL FROM:<info@example.com>" || !strings.HasPrefix(got.to, "RCPT TO:<customer@example.com>") {
+		t.Errorf("Unexpected envelope %q %q %q", got.auth, got.from, got.to)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("Invalid message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	from, _ := mail.ParseAddress(msg.Header.Get("From"))
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if subject != "تذكير بالموعد" || from == nil || from.Name != "الشركة" || strings.TrimSpace(string(body)) != "موعدكم غداً\r\nشكراً" {
+		t.Errorf("Unexpected message %q from %v: %q", subject, from, body)
+	}
+
+	if err := (&messaging.SMTP{Host: host, Port: ch.Port, From: "info@example.com"}).Send(messaging.Message{To: "not an address", Body: "x"}); err == nil {
+		t.Error("Expected an invalid recipient to fail")
+	}
+}
+
+func TestMessaging_Inbound(t *testing.T) {
+	body := []byte(`{"entry":[{"changes":[{"value":{"messages":[
+		{"id":"wamid.1","from":"201012345678","timestamp":"1700000000","type":"text","text":{"body":"مرحبا"}},
+		{"id":"wamid.2","from":"201012345678","timestamp":"1700000060","type":"image","image":{"caption":"القياسات"}},
+		{"id":"wamid.3","from":"201012345678","timestamp":"1700000120","type":"sticker"}
+	]}}]}]}`)
+
+	signature := messaging.Sign("secret", body)
+	if !messaging.VerifySignature("secret", body, signature) {
+		t.Error("Expected a matching signature to verify")
+	}
+	if messaging.VerifySignature("other", body, signature) || messaging.VerifySignature("", body, signature) {
+		t.Error("Expected a wrong or empty secret not to verify")
+	}
+	if messaging.VerifySignature("secret", append(body, ' '), signature) || messaging.VerifySignature("secret", body, "sha256=zz") {
+		t.Error("Expected a changed body or malformed signature not to verify")
+	}
+
+	messages, err := messaging.ParseWhatsAppCloud(body)
+	if err != nil || len(messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d (%v)", len(messages), err)
+	}
+	if messages[0].ID != "wamid.1" || messages[0].From != "201012345678" || messages[0].Body != "مرحبا" || messages[0].ReceivedAt.Unix() != 1700000000 {
+		t.Errorf("Unexpected text message %+v", messages[0])
+	}
+	if messages[1].Body != "القياسات" || messages[2].Body != "[sticker]" {
+		t.Errorf("Expected the caption and a type marker, got %q and %q", messages[1].Body, messages[2].Body)
+	}
+	if status, _ := messaging.ParseWhatsAppCloud([]byte(`{"entry":[{"changes":[{"value":{"statuses":[{"id":"wamid.9"}]}}]}]}`)); len(status) != 0 {
+		t.Errorf("Expected status updates to be skipped, got %+v", status)
+	}
+
+	if messages, err := messaging.ParseWebhook([]byte(`{"id":"gw-1","phone":"01012345678","message":"hi"}`)); err != nil || messages[0].Body != "hi" {
+		t.Errorf("Unexpected webhook message %+v (%v)", messages, err)
+	}
+	if _, err := messaging.ParseWebhook([]byte(`{"message":"hi"}`)); err == nil {
+		t.Error("Expected a webhook message without a phone to be rejected")
+	}
+}
@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"encoding/json"
+	"erp-system/pkg/money"
+	"math"
+	"testing"
+)
+
+func TestMoney_Rounding(t *testing.T) {
+	tests := []struct {
+		name string
+		got  money.Money
+		want int64
+	}{
+		{"float half up", money.FromFloat(0.125), 13},
+		{"float half away from zero", money.FromFloat(-0.125), -13},
+		{"float binary noise", money.FromFloat(1.005), 101},
+		{"percent", money.FromFloat(100.05).Percent(14), 1401},
+		{"quantity", money.FromFloat(19.99).MulFloat(3), 5997},
+		{"fractional quantity", money.FromFloat(10).MulFloat(0.333), 333},
+		{"ratio", money.FromFloat(114).Ratio(100, 114), 10000},
+		{"div", money.FromFloat(10).Div(3), 333},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Minor != tt.want {
+				t.Errorf("Expected %d minor units, got %d", tt.want, tt.got.Minor)
+			}
+		})
+	}
+}
+
+func TestMoney_ParseAndString(t *testing.T) {
+	m, err := money.Parse("1234.5")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if m.String() != "1234.50" {
+		t.Errorf("Expected 1234.50, got %s", m)
+	}
+	if money.FromMinor(-5).String() != "-0.05" {
+		t.Errorf("Expected -0.05, got %s", money.FromMinor(-5))
+	}
+	if _, err := money.Parse("abc"); err == nil {
+		t.Error("Expected error for invalid amount")
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var req struct {
+		Price  money.Money `json:"price"`
+		Quoted money.Money `json:"quoted"`
+	}
+	if err := json.Unmarshal([]byte(`{"price": 99.99, "quoted": "10.5"}`), &req); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if req.Price.Minor != 9999 || req.Quoted.Minor != 1050 {
+		t.Errorf("Unexpected amounts: %d / %d", req.Price.Minor, req.Quoted.Minor)
+	}
+
+	out, _ := json.Marshal(req)
+	if string(out) != `{"price":99.99,"quoted":10.50}` {
+		t.Errorf("Unexpected JSON: %s", out)
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var m money.Money
+	if err := m.Scan(int64(12345)); err != nil || m.Minor != 12345 {
+		t.Errorf("Scan int64: got %d, err %v", m.Minor, err)
+	}
+	if err := m.Scan(float64(12344.6)); err != nil || m.Minor != 12345 {
+		t.Errorf("Scan float64: got %d, err %v", m.Minor, err)
+	}
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if err := m.Scan(bad); err == nil {
+			t.Errorf("Scan %v: expected an error", bad)
+		}
+	}
+	if err := m.Scan(nil); err != nil || !m.IsZero() {
+		t.Errorf("Scan nil: got %d, err %v", m.Minor, err)
+	}
+	if v, _ := money.FromFloat(12.34).Value(); v != int64(1234) {
+		t.Errorf("Value: expected 1234, got %v", v)
+	}
+}
@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"bytes"
+	"erp-system/pkg/pdf"
+	"testing"
+)
+
+func TestShapeArabic_ContextualForms(t *testing.T) {
+	// محمد: meem initial, hah medial, meem medial, dal final
+	got := []rune(pdf.ShapeArabic("محمد"))
+	want := []rune{0xFEE3, 0xFEA4, 0xFEE4, 0xFEAA}
+	if string(got) != string(want) {
+		t.Errorf("Expected %U, got %U", want, got)
+	}
+
+	// Lam followed by alef becomes the mandatory ligature
+	if got := []rune(pdf.ShapeArabic("لا")); len(got) != 1 || got[0] != 0xFEFB {
+		t.Errorf("Expected lam-alef ligature U+FEFB, got %U", got)
+	}
+}
+
+func TestVisual_BidiOrder(t *testing.T) {
+	// Latin text is left untouched
+	if got := pdf.Visual("Invoice 123"); got != "Invoice 123" {
+		t.Errorf("Expected unchanged LTR text, got %q", got)
+	}
+
+	// Arabic is reversed for left-to-right drawing while numbers keep their order;
+	// qaf takes its initial form because reh never joins to the left
+	got := []rune(pdf.Visual("رقم 125"))
+	want := []rune{'1', '2', '5', ' ', 0xFEE2, 0xFED7, 0xFEAD}
+	if string(got) != string(want) {
+		t.Errorf("Expected %U, got %U", want, got)
+	}
+
+	// In an Arabic paragraph the English label sits on the left
+	if got := pdf.Visual("العميل / Customer"); !bytes.HasPrefix([]byte(got), []byte("Customer / ")) {
+		t.Errorf("Expected English run first in visual order, got %q", got)
+	}
+}
+
+func TestDocument_Output(t *testing.T) {
+	doc := pdf.New()
+	doc.AddPage()
+	doc.Rect(40, 40, 100, 20, true)
+	doc.Line(40, 70, 200, 70)
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Output failed: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Error("Output is not a complete PDF file")
+	}
+	if !bytes.Contains(data, []byte("/Count 1")) {
+		t.Error("Expected a single page")
+	}
+}
@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"erp-system/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestActivityRecurrence_Next(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 10, 0, 0, 0, time.UTC) }
+	until := day(2025, time.March, 31)
+
+	tests := []struct {
+		name       string
+		rule       domain.ActivityRecurrence
+		from, now  time.Time
+		want       time.Time
+		occurrence int
+		ok         bool
+	}{
+		{"daily", domain.ActivityRecurrence{Frequency: domain.RecurrenceDaily, Interval: 1, Occurrence: 1}, day(2025, 1, 1), day(2025, 1, 1), day(2025, 1, 2), 2, true},
+		{"weekly", domain.ActivityRecurrence{Frequency: domain.RecurrenceWeekly, Interval: 2, Occurrence: 1}, day(2025, 1, 1), day(2025, 1, 1), day(2025, 1, 15), 2, true},
+		{"every n days", domain.ActivityRecurrence{Frequency: domain.RecurrenceDays, Interval: 10, Occurrence: 3}, day(2025, 1, 1), day(2025, 1, 1), day(2025, 1, 11), 4, true},
+		{"monthly keeps the last day", domain.ActivityRecurrence{Frequency: domain.RecurrenceMonthly, Interval: 1, Occurrence: 1}, day(2025, 1, 31), day(2025, 1, 31), day(2025, 2, 28), 2, true},
+		{"missed occurrences are skipped", domain.ActivityRecurrence{Frequency: domain.RecurrenceDaily, Interval: 1, Occurrence: 1}, day(2025, 1, 1), day(2025, 1, 4).Add(time.Hour), day(2025, 1, 5), 5, true},
+		{"count reached", domain.ActivityRecurrence{Frequency: domain.RecurrenceDaily, Interval: 1, Count: 3, Occurrence: 3}, day(2025, 1, 1), day(2025, 1, 1), time.Time{}, 0, false},
+		{"past until", domain.ActivityRecurrence{Frequency: domain.RecurrenceMonthly, Interval: 1, Until: &until, Occurrence: 1}, day(2025, 3, 15), day(2025, 3, 15), time.Time{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		next, at, ok := tt.rule.Next(tt.from, tt.now)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !at.Equal(tt.want) || next.Occurrence != tt.occurrence {
+			t.Errorf("%s: got %v occurrence %d, want %v occurrence %d", tt.name, at, next.Occurrence, tt.want, tt.occurrence)
+		}
+	}
+}
+
+func TestActivityRecurrence_Validate(t *testing.T) {
+	r := domain.ActivityRecurrence{Frequency: domain.RecurrenceWeekly}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if r.Interval != 1 || r.Occurrence != 1 {
+		t.Errorf("Expected interval and occurrence defaults of 1, got %+v", r)
+	}
+	for _, bad := range []domain.ActivityRecurrence{{Frequency: "yearly"}, {Frequency: domain.RecurrenceDaily, Interval: -1}, {Frequency: domain.RecurrenceDaily, Count: -2}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package unit
+
+import (
+	"archive/zip"
+	"bytes"
+	"erp-system/pkg/spreadsheet"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildXLSX writes a minimal workbook with the given sheet XML and shared strings
+func buildXLSX(t *testing.T, sheet, shared string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Data" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + sheet + `</sheetData></worksheet>`,
+		"xl/sharedStrings.xml":   `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` + shared + `</sst>`,
+	}
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSpreadsheet_ReadCSV(t *testing.T) {
+	data := []byte("\xef\xbb\xbfname;credit_limit\n\"شركة النور\";1500,50\n;\n")
+	rows, err := spreadsheet.Read("customers.csv", data, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	want := [][]string{{"name", "credit_limit"}, {"شركة النور", "1500,50"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Expected %v, got %v", want, rows)
+	}
+}
+
+func TestSpreadsheet_ReadXLSX(t *testing.T) {
+	data := buildXLSX(t,
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>active</t></is></c></row>`+
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>0.30000000000000004</v></c><c r="D3" t="b"><v>1</v></c></row>`,
+		`<si><t>sku</t></si><si><t>price</t></si><si><r><t>Widget </t></r><r><t>Pro</t></r></si>`)
+
+	rows, err := spreadsheet.Read("products.XLSX", data, 0)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	want := [][]string{
+		{"sku", "price", "", "active"},
+		nil,
+		{"Widget Pro", "0.3", "", "true"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Expected %q, got %q", want, rows)
+	}
+}
+
+func TestSpreadsheet_UnsupportedFormat(t *testing.T) {
+	if _, err := spreadsheet.Read("customers.xls", []byte("x"), 0); err != spreadsheet.ErrUnsupportedFormat {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := spreadsheet.Read("customers.xlsx", []byte("not a zip"), 0); err == nil {
+		t.Error("Expected an error for a corrupt workbook")
+	}
+}
+
+func TestSpreadsheet_Limits(t *testing.T) {
+	// A far-off row or column is refused without allocating room for it
+	far := buildXLSX(t, `<row r="1"><c r="A1" t="inlineStr"><is><t>name</t></is></c></row>`+
+		`<row r="1048576"><c r="A1048576" t="inlineStr"><is><t>x</t></is></c></row>`, "")
+	if _, err := spreadsheet.ReadXLSX(far, 100); !errors.Is(err, spreadsheet.ErrTooManyRows) {
+		t.Errorf("Expected a row past the cap to be refused, got %v", err)
+	}
+	wide := buildXLSX(t, `<row r="1"><c r="XFE1" t="inlineStr"><is><t>x</t></is></c></row>`, "")
+	if _, err := spreadsheet.ReadXLSX(wide, 0); err == nil {
+		t.Error("Expected a cell past column XFD to be refused")
+	}
+	huge := buildXLSX(t, `<row r="1"><c r="AAAAAAAAAAAAAAAAAAAAAAAAA1"><v>1</v></c></row>`, "")
+	if _, err := spreadsheet.ReadXLSX(huge, 0); err == nil {
+		t.Error("Expected an overflowing cell reference to be refused")
+	}
+
+	// Formatted empty rows past the cap don't count
+	styled := buildXLSX(t, `<row r="1"><c r="A1"><v>1</v></c></row><row r="2"><c r="A2"><v>2</v></c></row>`+
+		`<row r="500"><c r="A500" s="1"/></row>`, "")
+	if rows, err := spreadsheet.ReadXLSX(styled, 2); err != nil || len(rows) != 2 {
+		t.Errorf("Expected two rows, got %q (%v)", rows, err)
+	}
+
+	// Sparse cells far to the right can't pad the sheet out past the cell limit
+	var sparse strings.Builder
+	for r := 1; r <= spreadsheet.MaxCells/spreadsheet.MaxColumns+1; r++ {
+		fmt.Fprintf(&sparse, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, r, r)
+	}
+	if _, err := spreadsheet.ReadXLSX(buildXLSX(t, sparse.String(), ""), 0); !errors.Is(err, spreadsheet.ErrTooLarge) {
+		t.Errorf("Expected sparse cells past the cell limit to be refused, got %v", err)
+	}
+
+	// A part that unpacks past the size limit is refused however well it compresses
+	bomb := buildXLSX(t, strings.Repeat(" ", spreadsheet.MaxPartSize+1), "")
+	if _, err := spreadsheet.ReadXLSX(bomb, 0); !errors.Is(err, spreadsheet.ErrTooLarge) {
+		t.Errorf("Expected an oversized part to be refused, got %v", err)
+	}
+
+	csv := "name\na\nb\nc\n"
+	if _, err := spreadsheet.Read("customers.csv", []byte(csv), 3); !errors.Is(err, spreadsheet.ErrTooManyRows) {
+		t.Errorf("Expected a CSV past the cap to be refused, got %v", err)
+	}
+	if rows, err := spreadsheet.Read("customers.csv", []byte(csv), 4); err != nil || len(rows) != 4 {
+		t.Errorf("Expected four rows within the cap, got %q (%v)", rows, err)
+	}
+}
+
+func TestSpreadsheet_XLSXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := spreadsheet.NewWriter(spreadsheet.FormatXLSX, &buf, spreadsheet.Options{SheetName: "sales/orders", RightToLeft: true})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	w.WriteHeader([]string{"الاسم", "الرصيد", "نشط", "ملاحظات"})
+	w.WriteRow([]any{"أحمد <محمد> & شركاه", 1500.5, true, nil})
+	w.WriteRow([]any{"Fatima", 0, false, "=SUM(A1)"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	rows, err := spreadsheet.ReadXLSX(buf.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("ReadXLSX failed: %v", err)
+	}
+	want := [][]string{
+		{"الاسم", "الرصيد", "نشط", "ملاحظات"},
+		{"أحمد <محمد> & شركاه", "1500.5", "true"},
+		{"Fatima", "0", "false", "=SUM(A1)"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Expected %q, got %q", want, rows)
+	}
+}
+
+func TestSpreadsheet_CSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := spreadsheet.NewWriter(spreadsheet.FormatCSV, &buf, spreadsheet.Options{})
+	w.WriteHeader([]string{"name", "amount"})
+	w.WriteRow([]any{"=HYPERLINK(\"x\")", -12.5})
+	w.Close()
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\xef\xbb\xbf")) {
+		t.Error("Expected a UTF-8 byte order mark")
+	}
+	rows, _ := spreadsheet.ReadCSV(&buf, 0)
+	if rows[1][0] != "'=HYPERLINK(\"x\")" || rows[1][1] != "-12.5" {
+		t.Errorf("Expected formula text escaped and numbers untouched, got %q", rows[1])
+	}
+}
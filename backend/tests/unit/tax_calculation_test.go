@@ -0,0 +1,66 @@
+package unit
+
+import (
+	"erp-system/internal/domain"
+	"erp-system/internal/services"
+	"erp-system/pkg/money"
+	"testing"
+)
+
+func TestCalculateTaxes_ExclusiveLineRounding(t *testing.T) {
+	vat := &domain.TaxCode{ID: 1, Code: "VAT14", Type: domain.TaxTypeStandard, Rate: 14}
+	exempt := &domain.TaxCode{ID: 2, Code: "EXEMPT", Type: domain.TaxTypeExempt, Rate: 14}
+
+	result := services.CalculateTaxes([]services.TaxLine{
+		{TaxCode: vat, Gross: money.FromFloat(100.05)},
+		{TaxCode: vat, Gross: money.FromFloat(200), Discount: money.FromFloat(20)},
+		{TaxCode: exempt, Gross: money.FromFloat(50)},
+	}, domain.TaxPricingExclusive, domain.TaxRoundingLine)
+
+	// 100.05 * 14% = 14.007 -> 14.01, 180 * 14% = 25.20
+	if result.TotalTax != money.FromFloat(39.21) {
+		t.Errorf("Expected total tax 39.21, got %s", result.TotalTax)
+	}
+	if len(result.Summary) != 2 {
+		t.Fatalf("Expected 2 summary groups, got %d", len(result.Summary))
+	}
+	if result.Summary[0].Code != "VAT14" || result.Summary[0].TaxableAmount != money.FromFloat(280.05) {
+		t.Errorf("Unexpected VAT group: %+v", result.Summary[0])
+	}
+	if !result.Summary[1].TaxAmount.IsZero() {
+		t.Errorf("Exempt group should carry no tax, got %s", result.Summary[1].TaxAmount)
+	}
+}
+
+func TestCalculateTaxes_DocumentRounding(t *testing.T) {
+	vat := &domain.TaxCode{ID: 1, Code: "VAT14", Type: domain.TaxTypeStandard, Rate: 14}
+
+	lines := []services.TaxLine{
+		{TaxCode: vat, Gross: money.FromMinor(25)},
+		{TaxCode: vat, Gross: money.FromMinor(25)},
+		{TaxCode: vat, Gross: money.FromMinor(25)},
+	}
+	// 0.035 per line: line rounding gives 0.12 (0.04 x 3), document rounding gives 0.11 (0.105)
+	if got := services.CalculateTaxes(lines, domain.TaxPricingExclusive, domain.TaxRoundingLine).TotalTax; got != money.FromMinor(12) {
+		t.Errorf("Line rounding: expected 0.12, got %s", got)
+	}
+	if got := services.CalculateTaxes(lines, domain.TaxPricingExclusive, domain.TaxRoundingDocument).TotalTax; got != money.FromMinor(11) {
+		t.Errorf("Document rounding: expected 0.11, got %s", got)
+	}
+}
+
+func TestCalculateTaxes_Inclusive(t *testing.T) {
+	vat := &domain.TaxCode{ID: 1, Code: "VAT14", Type: domain.TaxTypeStandard, Rate: 14}
+
+	result := services.CalculateTaxes([]services.TaxLine{
+		{TaxCode: vat, Gross: money.FromFloat(114)},
+	}, domain.TaxPricingInclusive, domain.TaxRoundingLine)
+
+	line := result.Lines[0]
+	if line.Tax != money.FromFloat(14) || line.Taxable != money.FromFloat(100) {
+		t.Errorf("Expected taxable 100 and tax 14, got %s / %s", line.Taxable, line.Tax)
+	}
+	if line.Taxable.Add(line.Tax) != money.FromFloat(114) {
+		t.Errorf("Inclusive line must still total 114, got %s", line.Taxable.Add(line.Tax))
+	}
+}
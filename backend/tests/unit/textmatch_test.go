@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"erp-system/pkg/textmatch"
+	"testing"
+)
+
+func TestTextmatch_NormalizeName(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"أحمد محمد", "احمد محمد"},
+		{"شركة النور للتجارة", "النور للتجارة"},
+		{"مؤسسة فاطمة", "فاطمه"},
+		{"مصطفى", "مصطفي"},
+		{"محمّد", "محمد"},
+		{"Nile Trading Co.", "nile trading"},
+	}
+
+	for _, tt := range tests {
+		if got, want := textmatch.NormalizeName(tt.a), textmatch.NormalizeName(tt.b); got != want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", tt.a, got, want)
+		}
+	}
+}
+
+func TestTextmatch_NormalizePhone(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"010 1234 5678", "01012345678"},
+		{"+20 101 234 5678", "01012345678"},
+		{"0020-1012345678", "01012345678"},
+		{"٠١٠١٢٣٤٥٦٧٨", "01012345678"},
+		{"123", ""},
+	}
+
+	for _, tt := range tests {
+		if got := textmatch.NormalizePhone(tt.in); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTextmatch_Similarity(t *testing.T) {
+	if s := textmatch.Similarity(textmatch.NormalizeName("أحمد محمد علي"), textmatch.NormalizeName("احمد محمد على")); s < 0.95 {
+		t.Errorf("Expected spelling variants to match closely, got %.2f", s)
+	}
+	if s := textmatch.Similarity(textmatch.NormalizeName("محمد أحمد"), textmatch.NormalizeName("أحمد محمد")); s < 0.9 {
+		t.Errorf("Expected reordered names to match, got %.2f", s)
+	}
+	if s := textmatch.Similarity(textmatch.NormalizeName("أحمد محمد"), textmatch.NormalizeName("خالد سعيد")); s > 0.5 {
+		t.Errorf("Expected different names not to match, got %.2f", s)
+	}
+}
@@ -15,6 +15,7 @@ import {
     UserSwitchOutlined,
     ApartmentOutlined,
     TeamOutlined,
+    DeleteOutlined,
 } from '@ant-design/icons';
 import { Outlet, useNavigate, useLocation } from 'react-router-dom';
 import { authService } from '../services/auth.service';
@@ -35,6 +36,8 @@ export default function MainLayout() {
     const notifications = useAppStore((state) => state.notifications);
     const unreadCount = useAppStore((state) => state.unreadCount);
     const markAsRead = useAppStore((state) => state.markAsRead);
+    const markAllAsRead = useAppStore((state) => state.markAllAsRead);
+    const removeNotification = useAppStore((state) => state.removeNotification);
 
     // Enable live notifications
     useNotifications();
@@ -137,31 +140,58 @@ export default function MainLayout() {
                                     maxHeight: 400,
                                     overflow: 'hidden'
                                 }}>
-                                    <div style={{ padding: '12px 16px', borderBottom: '1px solid #f0f0f0', fontWeight: 600 }}>
-                                        الإشعارات ({unreadCount})
+                                    <div style={{ padding: '12px 16px', borderBottom: '1px solid #f0f0f0', fontWeight: 600, display: 'flex', justifyContent: 'space-between', alignItems: 'center' }}>
+                                        <span>الإشعارات ({unreadCount})</span>
+                                        <Button
+                                            type="link"
+                                            size="small"
+                                            disabled={unreadCount === 0}
+                                            onClick={async () => {
+                                                await notificationService.markAllAsRead();
+                                                markAllAsRead();
+                                            }}
+                                        >
+                                            تعليم الكل كمقروء
+                                        </Button>
                                     </div>
                                     <div style={{ maxHeight: 320, overflowY: 'auto' }}>
                                         {notifications.length > 0 ? (
                                             notifications.map((notif) => (
                                                 <div
-                                                    key={notif.ID}
+                                                    key={notif.id}
                                                     style={{
                                                         padding: '12px 16px',
                                                         borderBottom: '1px solid #f0f0f0',
                                                         cursor: 'pointer',
-                                                        background: notif.IsRead ? 'white' : '#f6ffed'
+                                                        background: notif.is_read ? 'white' : '#f6ffed'
                                                     }}
                                                     onClick={async () => {
-                                                        if (!notif.IsRead) {
-                                                            await notificationService.markAsRead(notif.ID);
-                                                            markAsRead(notif.ID);
+                                                        if (!notif.is_read) {
+                                                            await notificationService.markAsRead(notif.id);
+                                                            markAsRead(notif.id);
+                                                        }
+                                                        if (notif.link) {
+                                                            setNotifDropdownVisible(false);
+                                                            navigate(notif.link);
                                                         }
                                                     }}
                                                 >
-                                                    <div style={{ fontWeight: 500, marginBottom: 4 }}>{notif.Title}</div>
-                                                    <div style={{ fontSize: 12, color: '#666' }}>{notif.Message}</div>
+                                                    <div style={{ display: 'flex', justifyContent: 'space-between' }}>
+                                                        <div style={{ fontWeight: 500, marginBottom: 4 }}>{notif.title}</div>
+                                                        <Button
+                                                            type="text"
+                                                            size="small"
+                                                            icon={<DeleteOutlined />}
+                                                            onClick={async (e) => {
+                                                                e.stopPropagation();
+                                                                await notificationService.delete(notif.id);
+                                                                removeNotification(notif.id);
+                                                            }}
+                                                        />
+                                                    </div>
+                                                    <div style={{ fontSize: 12, color: '#666' }}>{notif.message}</div>
                                                     <div style={{ fontSize: 11, color: '#999', marginTop: 4 }}>
-                                                        {new Date(notif.CreatedAt).toLocaleString('ar-EG')}
+                                                        {new Date(notif.created_at).toLocaleString('ar-EG')}
                                                     </div>
                                                 </div>
                                             ))
@@ -2,7 +2,7 @@ import { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import {
     Card, Descriptions, Button, Space, Tabs, Table, Tag,
-    Modal, Form, Input, Select, Upload, message, Typography
+    Modal, Form, Input, Select, Upload, message, Typography, Switch, Popconfirm, Image
 } from 'antd';
 import {
     ArrowLeftOutlined, EditOutlined, WhatsAppOutlined,
@@ -11,16 +11,51 @@ import {
 import type { ColumnsType } from 'antd/es/table';
 import dayjs from 'dayjs';
 import { customerService } from '../services/customer.service';
-import type { Customer, CustomerActivity, CustomerDocument } from '../types';
+import type { Customer, CustomerActivity, CustomerDocument, ConversationEntry, DocumentCategory } from '../types';
 
 const { Title, Text } = Typography;
 
+const documentCategories: Record<DocumentCategory, { label: string; color: string }> = {
+    contract: { label: 'عقد', color: 'blue' },
+    measurement_sheet: { label: 'كشف مقاسات', color: 'purple' },
+    photo: { label: 'صورة', color: 'green' },
+    invoice: { label: 'فاتورة', color: 'gold' },
+    other: { label: 'أخرى', color: 'default' },
+};
+
+// Previews are served to signed-in users only, so they are fetched as blobs
+function DocumentThumbnail({ customerId, doc }: { customerId: number; doc: CustomerDocument }) {
+    const [url, setUrl] = useState<string>();
+
+    useEffect(() => {
+        if (!doc.has_thumbnail) return;
+        let objectUrl: string | undefined;
+        customerService.getDocumentThumbnail(customerId, doc.id)
+            .then((blob) => {
+                objectUrl = URL.createObjectURL(blob);
+                setUrl(objectUrl);
+            })
+            .catch(() => setUrl(undefined));
+        return () => {
+            if (objectUrl) URL.revokeObjectURL(objectUrl);
+        };
+    }, [customerId, doc.id, doc.has_thumbnail]);
+
+    if (!url) return <Tag>{doc.file_type}</Tag>;
+    return <Image src={url} width={48} height={48} style={{ objectFit: 'cover' }} preview={false} />;
+}
+
 export default function CustomerProfilePage() {
     const { id } = useParams<{ id: string }>();
     const navigate = useNavigate();
     const [customer, setCustomer] = useState<Customer | null>(null);
     const [activities, setActivities] = useState<CustomerActivity[]>([]);
+    const [conversation, setConversation] = useState<ConversationEntry[]>([]);
     const [documents, setDocuments] = useState<CustomerDocument[]>([]);
+    const [documentCategory, setDocumentCategory] = useState<DocumentCategory>('other');
+    const [showTrash, setShowTrash] = useState(false);
+    const [versions, setVersions] = useState<CustomerDocument[]>([]);
+    const [versionsVisible, setVersionsVisible] = useState(false);
     const [loading, setLoading] = useState(false);
     const [activityModalVisible, setActivityModalVisible] = useState(false);
     const [form] = Form.useForm();
@@ -29,10 +64,16 @@ export default function CustomerProfilePage() {
         if (id) {
             fetchCustomer();
             fetchActivities();
-            fetchDocuments();
+            fetchConversation();
         }
     }, [id]);
 
+    useEffect(() => {
+        if (id) {
+            fetchDocuments();
+        }
+    }, [id, showTrash]);
+
     const fetchCustomer = async () => {
         setLoading(true);
         try {
@@ -58,9 +99,20 @@ export default function CustomerProfilePage() {
         }
     };
 
+    const fetchConversation = async () => {
+        try {
+            const response = await customerService.getConversation(Number(id));
+            if (response.success && response.data) {
+                setConversation(response.data);
+            }
+        } catch (error) {
+            console.error('Failed to fetch conversation');
+        }
+    };
+
     const fetchDocuments = async () => {
         try {
-            const response = await customerService.getDocuments(Number(id));
+            const response = await customerService.getDocuments(Number(id), { deleted: showTrash });
             if (response.success && response.data) {
                 setDocuments(response.data);
             }
@@ -85,6 +137,7 @@ export default function CustomerProfilePage() {
         const formData = new FormData();
         formData.append('file', file);
         formData.append('title', file.name);
+        formData.append('category', documentCategory);
 
         try {
             await customerService.uploadDocument(Number(id), formData);
@@ -96,6 +149,73 @@ export default function CustomerProfilePage() {
         return false;
     };
 
+    const handleViewDocument = async (doc: CustomerDocument) => {
+        try {
+            const response = await customerService.getDocumentLink(Number(id), doc.id, true);
+            if (response.success && response.data) {
+                const url = response.data.url;
+                window.open(url.startsWith('http') ? url : `http://localhost:8080${url}`, '_blank');
+            }
+        } catch (error) {
+            message.error('فشل فتح المستند');
+        }
+    };
+
+    const handleReplaceDocument = async (doc: CustomerDocument, file: File) => {
+        const formData = new FormData();
+        formData.append('file', file);
+
+        try {
+            await customerService.replaceDocument(Number(id), doc.id, formData);
+            message.success('تم رفع الإصدار الجديد');
+            fetchDocuments();
+        } catch (error) {
+            message.error('فشل رفع الإصدار الجديد');
+        }
+        return false;
+    };
+
+    const handleShowVersions = async (doc: CustomerDocument) => {
+        try {
+            const response = await customerService.getDocumentVersions(Number(id), doc.id);
+            if (response.success && response.data) {
+                setVersions(response.data);
+                setVersionsVisible(true);
+            }
+        } catch (error) {
+            message.error('فشل تحميل الإصدارات');
+        }
+    };
+
+    const handleChangeCategory = async (doc: CustomerDocument, category: DocumentCategory) => {
+        try {
+            await customerService.updateDocument(Number(id), doc.id, { category });
+            fetchDocuments();
+        } catch (error) {
+            message.error('فشل تعديل التصنيف');
+        }
+    };
+
+    const handleDeleteDocument = async (doc: CustomerDocument) => {
+        try {
+            const response = await customerService.deleteDocument(Number(id), doc.id);
+            message.success(showTrash ? 'تم حذف المستند نهائياً' : 'تم نقل المستند إلى المحذوفات');
+            if (response.success) fetchDocuments();
+        } catch (error) {
+            message.error('فشل حذف المستند');
+        }
+    };
+
+    const handleRestoreDocument = async (doc: CustomerDocument) => {
+        try {
+            await customerService.restoreDocument(Number(id), doc.id);
+            message.success('تم استرجاع المستند');
+            fetchDocuments();
+        } catch (error) {
+            message.error('فشل استرجاع المستند');
+        }
+    };
+
     const activityColumns: ColumnsType<CustomerActivity> = [
         {
             title: 'النوع',
@@ -108,6 +228,7 @@ export default function CustomerProfilePage() {
                     meeting: 'purple',
                     alert: 'red',
                     reminder: 'orange',
+                    whatsapp: 'cyan',
                 };
                 return <Tag color={colors[type]}>{type}</Tag>;
             },
@@ -126,16 +247,40 @@ export default function CustomerProfilePage() {
     ];
 
     const documentColumns: ColumnsType<CustomerDocument> = [
+        {
+            title: '',
+            key: 'thumbnail',
+            width: 64,
+            render: (_, record) => <DocumentThumbnail customerId={Number(id)} doc={record} />,
+        },
         {
             title: 'العنوان',
             dataIndex: 'title',
             key: 'title',
         },
         {
-            title: 'النوع',
-            dataIndex: 'file_type',
-            key: 'file_type',
-            render: (type) => <Tag>{type}</Tag>,
+            title: 'التصنيف',
+            dataIndex: 'category',
+            key: 'category',
+            render: (category: DocumentCategory, record) => showTrash ? (
+                <Tag color={documentCategories[category]?.color}>{documentCategories[category]?.label ?? category}</Tag>
+            ) : (
+                <Select
+                    value={category}
+                    size="small"
+                    style={{ width: 130 }}
+                    onChange={(value) => handleChangeCategory(record, value)}
+                    options={Object.entries(documentCategories).map(([value, c]) => ({ value, label: c.label }))}
+                />
+            ),
+        },
+        {
+            title: 'الإصدار',
+            dataIndex: 'version',
+            key: 'version',
+            render: (version, record) => (
+                <Button type="link" size="small" onClick={() => handleShowVersions(record)}>v{version}</Button>
+            ),
         },
         {
             title: 'تاريخ الرفع',
@@ -147,12 +292,30 @@ export default function CustomerProfilePage() {
             title: 'الإجراءات',
             key: 'actions',
             render: (_, record) => (
-                <Button
-                    type="link"
-                    onClick={() => window.open(`http://localhost:8080${record.file_path}`, '_blank')}
-                >
-                    عرض
-                </Button>
+                <Space>
+                    <Button type="link" onClick={() => handleViewDocument(record)}>
+                        عرض
+                    </Button>
+                    {showTrash ? (
+                        <Button type="link" onClick={() => handleRestoreDocument(record)}>
+                            استرجاع
+                        </Button>
+                    ) : (
+                        <Upload beforeUpload={(file) => handleReplaceDocument(record, file)} showUploadList={false}>
+                            <Button type="link">إصدار جديد</Button>
+                        </Upload>
+                    )}
+                    <Popconfirm
+                        title={showTrash ? 'حذف المستند نهائياً؟' : 'نقل المستند إلى المحذوفات؟'}
+                        onConfirm={() => handleDeleteDocument(record)}
+                        okText="نعم"
+                        cancelText="لا"
+                    >
+                        <Button type="link" danger>
+                            {showTrash ? 'حذف نهائي' : 'حذف'}
+                        </Button>
+                    </Popconfirm>
+                </Space>
             ),
         },
     ];
@@ -251,16 +414,53 @@ export default function CustomerProfilePage() {
                                 </>
                             )
                         },
+                        {
+                            key: 'conversation',
+                            label: 'محادثة واتساب',
+                            children: conversation.length === 0 ? (
+                                <Text type="secondary">لا توجد رسائل</Text>
+                            ) : (
+                                <Space direction="vertical" style={{ width: '100%' }}>
+                                    {conversation.map((entry) => (
+                                        <div
+                                            key={`${entry.direction}-${entry.activity_id || entry.message_id}`}
+                                            style={{ display: 'flex', justifyContent: entry.direction === 'in' ? 'flex-start' : 'flex-end' }}
+                                        >
+                                            <Card
+                                                size="small"
+                                                style={{ maxWidth: '70%', background: entry.direction === 'in' ? '#fff' : '#e6f7e6' }}
+                                            >
+                                                <div style={{ whiteSpace: 'pre-line' }}>{entry.body}</div>
+                                                <Text type="secondary" style={{ fontSize: 12 }}>
+                                                    {dayjs(entry.at).format('YYYY/MM/DD HH:mm')}
+                                                    {entry.status && entry.status !== 'sent' && ` · ${entry.status}`}
+                                                </Text>
+                                            </Card>
+                                        </div>
+                                    ))}
+                                </Space>
+                            )
+                        },
                         {
                             key: 'documents',
                             label: 'المستندات',
                             children: (
                                 <>
-                                    <Upload beforeUpload={handleUploadDocument} showUploadList={false}>
-                                        <Button icon={<UploadOutlined />} style={{ marginBottom: 16 }}>
-                                            رفع مستند
-                                        </Button>
-                                    </Upload>
+                                    <Space style={{ marginBottom: 16 }}>
+                                        <Select
+                                            value={documentCategory}
+                                            style={{ width: 150 }}
+                                            onChange={setDocumentCategory}
+                                            options={Object.entries(documentCategories).map(([value, c]) => ({ value, label: c.label }))}
+                                        />
+                                        <Upload beforeUpload={handleUploadDocument} showUploadList={false}>
+                                            <Button icon={<UploadOutlined />} disabled={showTrash}>
+                                                رفع مستند
+                                            </Button>
+                                        </Upload>
+                                        <Switch checked={showTrash} onChange={setShowTrash} />
+                                        <Text>المحذوفات</Text>
+                                    </Space>
                                     <Table
                                         columns={documentColumns}
                                         dataSource={documents}
@@ -274,6 +474,31 @@ export default function CustomerProfilePage() {
                 />
             </Card>
 
+            <Modal
+                title="إصدارات المستند"
+                open={versionsVisible}
+                onCancel={() => setVersionsVisible(false)}
+                footer={null}
+            >
+                <Table
+                    dataSource={versions}
+                    rowKey="id"
+                    pagination={false}
+                    columns={[
+                        { title: 'الإصدار', dataIndex: 'version', key: 'version', render: (v) => `v${v}` },
+                        { title: 'الملف', dataIndex: 'original_name', key: 'original_name' },
+                        { title: 'تاريخ الرفع', dataIndex: 'uploaded_at', key: 'uploaded_at', render: (d) => dayjs(d).format('YYYY/MM/DD HH:mm') },
+                        {
+                            title: '',
+                            key: 'view',
+                            render: (_, record: CustomerDocument) => (
+                                <Button type="link" onClick={() => handleViewDocument(record)}>عرض</Button>
+                            ),
+                        },
+                    ]}
+                />
+            </Modal>
+
             <Modal
                 title="إضافة نشاط جديد"
                 open={activityModalVisible}
@@ -44,7 +44,7 @@ export default function CustomersPage() {
     const handleAdd = () => {
         setEditingCustomer(null);
         form.resetFields();
-        form.setFieldsValue({ is_whatsapp_enabled: true, type: 'regular' });
+        form.setFieldsValue({ is_whatsapp_enabled: true, type: 'regular', language: 'ar' });
         setModalVisible(true);
     };
 
@@ -278,6 +278,23 @@ export default function CustomersPage() {
                             <Switch checkedChildren="مفعل" unCheckedChildren="معطل" />
                         </Form.Item>
                     </Col>
+                    <Col span={8}>
+                        <Form.Item name="preferred_channel" label="قناة التواصل المفضلة">
+                            <Select placeholder="حسب إعدادات النظام" allowClear>
+                                <Option value="whatsapp">واتساب</Option>
+                                <Option value="sms">رسالة نصية</Option>
+                                <Option value="email">البريد الإلكتروني</Option>
+                            </Select>
+                        </Form.Item>
+                    </Col>
+                    <Col span={8}>
+                        <Form.Item name="language" label="لغة الرسائل">
+                            <Select>
+                                <Option value="ar">العربية</Option>
+                                <Option value="en">English</Option>
+                            </Select>
+                        </Form.Item>
+                    </Col>
                 </Row>
             </FormModal>
         </div>
@@ -1,14 +1,20 @@
 import { useState, useEffect } from 'react';
 import {
     Card, Form, Input, Button, message, Upload,
-    Typography, Row, Col, Select, Tabs, Image
+    Typography, Row, Col, Select, Tabs, Image, Space, Tag, Alert,
+    Switch, Checkbox, TimePicker, Table
 } from 'antd';
+import dayjs from 'dayjs';
 import {
-    SaveOutlined, UploadOutlined,
-    SettingOutlined, WhatsAppOutlined
+    SaveOutlined, UploadOutlined, EyeOutlined, UndoOutlined,
+    SettingOutlined, WhatsAppOutlined, MessageOutlined, BellOutlined
 } from '@ant-design/icons';
 import { settingsService } from '../services/settings.service';
-import type { SystemSettings } from '../types';
+import { notificationService } from '../services/notification.service';
+import type {
+    SystemSettings, MessageTemplate, RenderedMessage,
+    NotificationPreferences, NotificationSubscription, NotificationChannel
+} from '../types';
 
 const { Title, Text } = Typography;
 
@@ -17,19 +23,125 @@ export default function SettingsPage() {
     const [loading, setLoading] = useState(false);
     const [settings, setSettings] = useState<SystemSettings>({});
     const [logoUrl, setLogoUrl] = useState<string>('');
+    const [templateForm] = Form.useForm();
+    const [templates, setTemplates] = useState<MessageTemplate[]>([]);
+    const [placeholders, setPlaceholders] = useState<Record<string, string>>({});
+    const [templateKey, setTemplateKey] = useState<string>();
+    const [preview, setPreview] = useState<RenderedMessage | null>(null);
+    const [preferences, setPreferences] = useState<NotificationPreferences | null>(null);
 
     useEffect(() => {
         fetchSettings();
+        fetchTemplates();
+        fetchPreferences();
     }, []);
 
+    const fetchPreferences = async () => {
+        try {
+            const response = await notificationService.getPreferences();
+            if (response.success && response.data) {
+                setPreferences(response.data);
+            }
+        } catch (error) {
+            message.error('فشل تحميل تفضيلات الإشعارات');
+        }
+    };
+
+    const updateSubscription = (key: string, changes: Partial<NotificationSubscription>) => {
+        if (!preferences) return;
+        setPreferences({
+            ...preferences,
+            subscriptions: preferences.subscriptions.map(s => (s.key === key ? { ...s, ...changes } : s)),
+        });
+    };
+
+    const handlePreferencesSave = async () => {
+        if (!preferences) return;
+        try {
+            const response = await notificationService.updatePreferences({
+                quiet_hours_start: preferences.quiet_hours_start,
+                quiet_hours_end: preferences.quiet_hours_end,
+                whatsapp_number: preferences.whatsapp_number,
+                subscriptions: preferences.subscriptions.map(s => ({ event: s.key, enabled: s.enabled, channels: s.channels })),
+            });
+            if (response.success && response.data) {
+                setPreferences(response.data);
+                message.success('تم حفظ تفضيلات الإشعارات');
+            }
+        } catch (error: any) {
+            message.error(error.response?.data?.message || 'فشل حفظ تفضيلات الإشعارات');
+        }
+    };
+
+    const fetchTemplates = async (selectKey?: string) => {
+        try {
+            const response = await settingsService.getTemplates();
+            if (response.success && response.data) {
+                setTemplates(response.data.templates);
+                setPlaceholders(response.data.placeholders);
+                const key = selectKey || templateKey || response.data.templates[0]?.key;
+                selectTemplate(key, response.data.templates);
+            }
+        } catch (error) {
+            message.error('فشل تحميل قوالب الرسائل');
+        }
+    };
+
+    const selectTemplate = (key: string | undefined, list: MessageTemplate[] = templates) => {
+        const template = list.find(t => t.key === key);
+        setTemplateKey(key);
+        setPreview(null);
+        if (template) {
+            templateForm.setFieldsValue(template);
+        }
+    };
+
+    const handleTemplateSave = async (values: Partial<MessageTemplate>) => {
+        if (!templateKey) return;
+        try {
+            const response = await settingsService.updateTemplate(templateKey, values);
+            if (response.success) {
+                message.success('تم حفظ القالب');
+                fetchTemplates(templateKey);
+            }
+        } catch (error) {
+            message.error('فشل حفظ القالب');
+        }
+    };
+
+    const handleTemplateReset = async () => {
+        if (!templateKey) return;
+        try {
+            const response = await settingsService.resetTemplate(templateKey);
+            if (response.success) {
+                message.success('تمت استعادة النص الافتراضي');
+                fetchTemplates(templateKey);
+            }
+        } catch (error) {
+            message.error('فشل استعادة القالب');
+        }
+    };
+
+    const handleTemplatePreview = async (language: 'ar' | 'en') => {
+        try {
+            const response = await settingsService.previewTemplate(templateForm.getFieldsValue(), language);
+            if (response.success && response.data) {
+                setPreview(response.data);
+            }
+        } catch (error) {
+            message.error('فشل عرض المعاينة');
+        }
+    };
+
     const fetchSettings = async () => {
         try {
             const response = await settingsService.get();
             if (response.success && response.data) {
                 setSettings(response.data);
                 form.setFieldsValue(response.data);
-                if (response.data.company_logo) {
-                    setLogoUrl(`http://localhost:8080${response.data.company_logo}`);
+                const url = response.data.company_logo_url;
+                if (url) {
+                    setLogoUrl(url.startsWith('http') ? url : `http://localhost:8080${url}`);
                 }
             }
         } catch (error) {
@@ -143,6 +255,8 @@ export default function SettingsPage() {
         </Card>
     );
 
+    const whatsappProvider = Form.useWatch('whatsapp_provider', form) || 'webhook';
+
     const whatsappContent = (
         <Card>
             <Form
@@ -160,17 +274,55 @@ export default function SettingsPage() {
 
                     <Col xs={24} md={12}>
                         <Form.Item
-                            name="whatsapp_api_url"
-                            label="رابط API"
+                            name="whatsapp_provider"
+                            label="مزود الخدمة"
+                            initialValue="webhook"
                         >
-                            <Input
-                                size="large"
-                                placeholder="https://api.whatsapp-provider.com/send"
-                                dir="ltr"
-                            />
+                            <Select size="large">
+                                <Select.Option value="webhook">بوابة عامة (Webhook)</Select.Option>
+                                <Select.Option value="cloud">WhatsApp Cloud API</Select.Option>
+                            </Select>
                         </Form.Item>
                     </Col>
 
+                    {whatsappProvider === 'cloud' ? (
+                        <>
+                            <Col xs={24} md={12}>
+                                <Form.Item
+                                    name="whatsapp_phone_number_id"
+                                    label="معرف رقم الهاتف (Phone number ID)"
+                                >
+                                    <Input size="large" dir="ltr" />
+                                </Form.Item>
+                            </Col>
+                            <Col xs={24} md={12}>
+                                <Form.Item
+                                    name="whatsapp_cloud_url"
+                                    label="رابط Graph API"
+                                >
+                                    <Input
+                                        size="large"
+                                        placeholder="https://graph.facebook.com/v19.0"
+                                        dir="ltr"
+                                    />
+                                </Form.Item>
+                            </Col>
+                        </>
+                    ) : (
+                        <Col xs={24} md={12}>
+                            <Form.Item
+                                name="whatsapp_api_url"
+                                label="رابط API"
+                            >
+                                <Input
+                                    size="large"
+                                    placeholder="https://api.whatsapp-provider.com/send"
+                                    dir="ltr"
+                                />
+                            </Form.Item>
+                        </Col>
+                    )}
+
                     <Col xs={24} md={12}>
                         <Form.Item
                             name="whatsapp_api_token"
@@ -184,19 +336,160 @@ export default function SettingsPage() {
                         </Form.Item>
                     </Col>
 
+                    <Col span={24}>
+                        <Title level={5}>الرسائل الواردة</Title>
+                        <Text type="secondary" dir="ltr">
+                            /api/v1/webhooks/whatsapp
+                        </Text>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="whatsapp_app_secret"
+                            label="المفتاح السري للتطبيق (App secret)"
+                            tooltip="تُرفض الرسائل الواردة غير الموقعة بهذا المفتاح"
+                        >
+                            <Input.Password size="large" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+                    {whatsappProvider === 'cloud' && (
+                        <Col xs={24} md={12}>
+                            <Form.Item
+                                name="whatsapp_verify_token"
+                                label="رمز التحقق (Verify token)"
+                            >
+                                <Input size="large" dir="ltr" />
+                            </Form.Item>
+                        </Col>
+                    )}
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="whatsapp_opt_out_keywords"
+                            label="كلمات إلغاء الاشتراك"
+                            tooltip="إذا رد العميل بإحدى هذه الكلمات تتوقف رسائل واتساب له"
+                        >
+                            <Input size="large" placeholder="stop,unsubscribe,إيقاف,إلغاء" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col span={24}>
+                        <Title level={4}>الرسائل النصية (SMS)</Title>
+                    </Col>
+
                     <Col xs={24} md={12}>
                         <Form.Item
-                            name="whatsapp_sender"
-                            label="رقم المرسل"
+                            name="sms_api_url"
+                            label="رابط بوابة الرسائل"
                         >
                             <Input
                                 size="large"
-                                placeholder="+201xxxxxxxxx"
+                                placeholder="https://sms-gateway.example.com/send"
                                 dir="ltr"
                             />
                         </Form.Item>
                     </Col>
 
+                    <Col xs={24} md={6}>
+                        <Form.Item
+                            name="sms_api_token"
+                            label="مفتاح API"
+                        >
+                            <Input.Password size="large" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={6}>
+                        <Form.Item
+                            name="sms_sender"
+                            label="اسم المرسل"
+                        >
+                            <Input size="large" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col span={24}>
+                        <Title level={4}>البريد الإلكتروني (SMTP)</Title>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="smtp_host"
+                            label="خادم SMTP"
+                        >
+                            <Input size="large" placeholder="smtp.example.com" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={4}>
+                        <Form.Item
+                            name="smtp_port"
+                            label="المنفذ"
+                        >
+                            <Input size="large" placeholder="587" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={8}>
+                        <Form.Item
+                            name="smtp_from"
+                            label="المرسل"
+                        >
+                            <Input size="large" placeholder="Company <info@example.com>" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="smtp_username"
+                            label="اسم المستخدم"
+                        >
+                            <Input size="large" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="smtp_password"
+                            label="كلمة المرور"
+                        >
+                            <Input.Password size="large" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="messaging_channel_order"
+                            label="ترتيب القنوات للعملاء بدون تفضيل"
+                            extra="مثال: whatsapp,sms,email"
+                        >
+                            <Input size="large" placeholder="whatsapp,sms,email" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col span={24}>
+                        <Title level={4}>أحداث النظام (Webhook)</Title>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="event_webhook_url"
+                            label="رابط استقبال الأحداث"
+                            tooltip="يُرسل إليه كل حدث (طلب جديد، تغير حالة، دفعة، مخزون منخفض، اكتمال إنتاج) بصيغة JSON"
+                        >
+                            <Input size="large" placeholder="https://example.com/hooks/erp" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    <Col xs={24} md={12}>
+                        <Form.Item
+                            name="event_webhook_secret"
+                            label="المفتاح السري للتوقيع"
+                            tooltip="يُوقَّع كل طلب في ترويسة X-Hub-Signature-256"
+                        >
+                            <Input.Password size="large" dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
                     <Col span={24}>
                         <Button
                             type="primary"
@@ -213,6 +506,182 @@ export default function SettingsPage() {
         </Card>
     );
 
+    const selectedTemplate = templates.find(t => t.key === templateKey);
+
+    const templatesContent = (
+        <Card>
+            <Row gutter={24}>
+                <Col span={24}>
+                    <Title level={4}>قوالب الرسائل</Title>
+                    <Text type="secondary">
+                        تُرسل الرسائل بلغة العميل؛ إذا كان النص الإنجليزي فارغاً يُستخدم النص العربي
+                    </Text>
+                </Col>
+                <Col xs={24} md={12} style={{ marginTop: 16 }}>
+                    <Select
+                        size="large"
+                        style={{ width: '100%' }}
+                        value={templateKey}
+                        onChange={(key) => selectTemplate(key)}
+                        options={templates.map(t => ({ value: t.key, label: t.name }))}
+                    />
+                </Col>
+                <Col xs={24} md={12} style={{ marginTop: 16 }}>
+                    {selectedTemplate && (
+                        <Tag color={selectedTemplate.is_default ? 'default' : 'blue'}>
+                            {selectedTemplate.is_default ? 'النص الافتراضي' : 'معدل'}
+                        </Tag>
+                    )}
+                </Col>
+                <Col span={24} style={{ marginTop: 16 }}>
+                    <Space wrap>
+                        {Object.entries(placeholders).map(([name, description]) => (
+                            <Tag key={name} title={description}>{`{${name}}`}</Tag>
+                        ))}
+                    </Space>
+                </Col>
+            </Row>
+
+            <Form
+                form={templateForm}
+                layout="vertical"
+                onFinish={handleTemplateSave}
+                style={{ marginTop: 16 }}
+            >
+                <Row gutter={24}>
+                    <Col xs={24} md={12}>
+                        <Form.Item name="subject_ar" label="العنوان (عربي)">
+                            <Input size="large" />
+                        </Form.Item>
+                        <Form.Item name="body_ar" label="النص (عربي)" rules={[{ required: true, message: 'النص العربي مطلوب' }]}>
+                            <Input.TextArea rows={5} />
+                        </Form.Item>
+                    </Col>
+                    <Col xs={24} md={12}>
+                        <Form.Item name="subject_en" label="العنوان (إنجليزي)">
+                            <Input size="large" dir="ltr" />
+                        </Form.Item>
+                        <Form.Item name="body_en" label="النص (إنجليزي)">
+                            <Input.TextArea rows={5} dir="ltr" />
+                        </Form.Item>
+                    </Col>
+
+                    {preview && (
+                        <Col span={24}>
+                            <Alert
+                                type="info"
+                                style={{ marginBottom: 16, whiteSpace: 'pre-line' }}
+                                message={preview.subject || 'معاينة'}
+                                description={<div dir={preview.language === 'en' ? 'ltr' : 'rtl'}>{preview.body}</div>}
+                            />
+                        </Col>
+                    )}
+
+                    <Col span={24}>
+                        <Space wrap>
+                            <Button type="primary" htmlType="submit" icon={<SaveOutlined />} size="large">
+                                حفظ القالب
+                            </Button>
+                            <Button icon={<EyeOutlined />} size="large" onClick={() => handleTemplatePreview('ar')}>
+                                معاينة عربي
+                            </Button>
+                            <Button icon={<EyeOutlined />} size="large" onClick={() => handleTemplatePreview('en')}>
+                                معاينة إنجليزي
+                            </Button>
+                            <Button
+                                icon={<UndoOutlined />}
+                                size="large"
+                                disabled={selectedTemplate?.is_default}
+                                onClick={handleTemplateReset}
+                            >
+                                استعادة الافتراضي
+                            </Button>
+                        </Space>
+                    </Col>
+                </Row>
+            </Form>
+        </Card>
+    );
+
+    const quietHours = preferences?.quiet_hours_start && preferences.quiet_hours_end
+        ? [dayjs(`2000-01-01T${preferences.quiet_hours_start}`), dayjs(`2000-01-01T${preferences.quiet_hours_end}`)] as [dayjs.Dayjs, dayjs.Dayjs]
+        : null;
+
+    const preferencesContent = (
+        <Card>
+            <Title level={4}>تفضيلات الإشعارات</Title>
+            <Text type="secondary">
+                اختر الأحداث التي تصلك وقنوات وصولها؛ تصلك تذكيراتك وما يخصك داخل النظام ما لم توقفها
+            </Text>
+
+            <Table<NotificationSubscription>
+                style={{ marginTop: 16 }}
+                rowKey="key"
+                pagination={false}
+                dataSource={preferences?.subscriptions || []}
+                columns={[
+                    { title: 'الحدث', dataIndex: 'name' },
+                    {
+                        title: 'مفعل',
+                        dataIndex: 'enabled',
+                        render: (enabled: boolean, record) => (
+                            <Switch checked={enabled} onChange={(checked) => updateSubscription(record.key, { enabled: checked })} />
+                        ),
+                    },
+                    {
+                        title: 'القنوات',
+                        dataIndex: 'channels',
+                        render: (channels: NotificationChannel[], record) => (
+                            <Checkbox.Group
+                                value={channels}
+                                disabled={!record.enabled}
+                                onChange={(values) => updateSubscription(record.key, { channels: values as NotificationChannel[] })}
+                                options={[
+                                    { value: 'in_app', label: 'داخل النظام' },
+                                    { value: 'email', label: 'البريد الإلكتروني' },
+                                    { value: 'whatsapp', label: 'واتساب' },
+                                ]}
+                            />
+                        ),
+                    },
+                ]}
+            />
+
+            <Row gutter={24} style={{ marginTop: 24 }}>
+                <Col xs={24} md={12}>
+                    <Form.Item label="ساعات الهدوء" extra="تؤجل رسائل البريد وواتساب حتى نهاية ساعات الهدوء">
+                        <TimePicker.RangePicker
+                            size="large"
+                            format="HH:mm"
+                            order={false}
+                            value={quietHours}
+                            onChange={(range) => preferences && setPreferences({
+                                ...preferences,
+                                quiet_hours_start: range?.[0]?.format('HH:mm') || '',
+                                quiet_hours_end: range?.[1]?.format('HH:mm') || '',
+                            })}
+                        />
+                    </Form.Item>
+                </Col>
+                <Col xs={24} md={12}>
+                    <Form.Item label="رقم واتساب للإشعارات">
+                        <Input
+                            size="large"
+                            dir="ltr"
+                            value={preferences?.whatsapp_number}
+                            onChange={(e) => preferences && setPreferences({ ...preferences, whatsapp_number: e.target.value })}
+                        />
+                    </Form.Item>
+                </Col>
+                <Col span={24}>
+                    <Button type="primary" icon={<SaveOutlined />} size="large" onClick={handlePreferencesSave}>
+                        حفظ التفضيلات
+                    </Button>
+                </Col>
+            </Row>
+        </Card>
+    );
+
     const items = [
         {
             key: 'general',
@@ -229,11 +698,31 @@ export default function SettingsPage() {
             label: (
                 <span>
                     <WhatsAppOutlined />
-                    تكامل الواتساب
+                    قنوات المراسلة
                 </span>
             ),
             children: whatsappContent,
         },
+        {
+            key: 'templates',
+            label: (
+                <span>
+                    <MessageOutlined />
+                    قوالب الرسائل
+                </span>
+            ),
+            children: templatesContent,
+        },
+        {
+            key: 'notifications',
+            label: (
+                <span>
+                    <BellOutlined />
+                    تفضيلات الإشعارات
+                </span>
+            ),
+            children: preferencesContent,
+        },
     ];
 
     return (
@@ -1,5 +1,5 @@
 import apiClient from './api';
-import type { Customer, CustomerActivity, CustomerDocument, ApiResponse } from '../types';
+import type { Customer, CustomerActivity, CustomerDocument, ConversationEntry, ApiResponse } from '../types';
 
 export const customerService = {
     getAll: async (page = 1, limit = 10, search = '') => {
@@ -35,14 +35,19 @@ export const customerService = {
         return response.data;
     },
 
+    getConversation: async (customerId: number) => {
+        const response = await apiClient.get<ApiResponse<ConversationEntry[]>>(`/customers/${customerId}/conversation`);
+        return response.data;
+    },
+
     addActivity: async (customerId: number, data: Partial<CustomerActivity>) => {
         const response = await apiClient.post<ApiResponse>(`/customers/${customerId}/activities`, data);
         return response.data;
     },
 
     // Documents
-    getDocuments: async (customerId: number) => {
-        const response = await apiClient.get<ApiResponse<CustomerDocument[]>>(`/customers/${customerId}/documents`);
+    getDocuments: async (customerId: number, params?: { category?: string; deleted?: boolean }) => {
+        const response = await apiClient.get<ApiResponse<CustomerDocument[]>>(`/customers/${customerId}/documents`, { params });
         return response.data;
     },
 
@@ -52,4 +57,55 @@ export const customerService = {
         });
         return response.data;
     },
+
+    // Documents are served only to signed-in users, so they are fetched as a blob
+    downloadDocument: async (customerId: number, documentId: number) => {
+        const response = await apiClient.get<Blob>(`/customers/${customerId}/documents/${documentId}/download`, {
+            params: { inline: 1 },
+            responseType: 'blob',
+        });
+        return response.data;
+    },
+
+    getDocumentLink: async (customerId: number, documentId: number, inline = false) => {
+        const response = await apiClient.get<ApiResponse<{ url: string; expires_at: string }>>(
+            `/customers/${customerId}/documents/${documentId}/link`,
+            { params: inline ? { inline: 1 } : undefined },
+        );
+        return response.data;
+    },
+
+    getDocumentThumbnail: async (customerId: number, documentId: number) => {
+        const response = await apiClient.get<Blob>(`/customers/${customerId}/documents/${documentId}/thumbnail`, {
+            responseType: 'blob',
+        });
+        return response.data;
+    },
+
+    getDocumentVersions: async (customerId: number, documentId: number) => {
+        const response = await apiClient.get<ApiResponse<CustomerDocument[]>>(`/customers/${customerId}/documents/${documentId}/versions`);
+        return response.data;
+    },
+
+    replaceDocument: async (customerId: number, documentId: number, formData: FormData) => {
+        const response = await apiClient.post<ApiResponse<CustomerDocument>>(`/customers/${customerId}/documents/${documentId}/versions`, formData, {
+            headers: { 'Content-Type': 'multipart/form-data' },
+        });
+        return response.data;
+    },
+
+    updateDocument: async (customerId: number, documentId: number, data: { title?: string; category?: string }) => {
+        const response = await apiClient.put<ApiResponse<CustomerDocument>>(`/customers/${customerId}/documents/${documentId}`, data);
+        return response.data;
+    },
+
+    restoreDocument: async (customerId: number, documentId: number) => {
+        const response = await apiClient.post<ApiResponse<CustomerDocument>>(`/customers/${customerId}/documents/${documentId}/restore`);
+        return response.data;
+    },
+
+    deleteDocument: async (customerId: number, documentId: number) => {
+        const response = await apiClient.delete<ApiResponse>(`/customers/${customerId}/documents/${documentId}`);
+        return response.data;
+    },
 };
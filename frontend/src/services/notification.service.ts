@@ -1,5 +1,5 @@
 import apiClient from './api';
-import type { ApiResponse, Notification } from '../types';
+import type { ApiResponse, Notification, NotificationPreferences, NotificationChannel } from '../types';
 
 export const notificationService = {
     getUnread: async () => {
@@ -7,8 +7,77 @@ export const notificationService = {
         return response.data;
     },
 
+    getHistory: async (page = 1, limit = 20, status: '' | 'read' | 'unread' = '') => {
+        const response = await apiClient.get<ApiResponse<{ notifications: Notification[]; total: number }>>('/notifications/history', {
+            params: { page, limit, status },
+        });
+        return response.data;
+    },
+
+    getUnreadCount: async () => {
+        const response = await apiClient.get<ApiResponse<{ count: number }>>('/notifications/unread-count');
+        return response.data;
+    },
+
     markAsRead: async (id: number) => {
         const response = await apiClient.post<ApiResponse>(`/notifications/${id}/read`);
         return response.data;
     },
+
+    markAllAsRead: async () => {
+        const response = await apiClient.post<ApiResponse>('/notifications/read-all');
+        return response.data;
+    },
+
+    delete: async (id: number) => {
+        const response = await apiClient.delete<ApiResponse>(`/notifications/${id}`);
+        return response.data;
+    },
+
+    getPreferences: async () => {
+        const response = await apiClient.get<ApiResponse<NotificationPreferences>>('/notifications/preferences');
+        return response.data;
+    },
+
+    updatePreferences: async (data: {
+        quiet_hours_start: string;
+        quiet_hours_end: string;
+        whatsapp_number: string;
+        subscriptions: { event: string; enabled: boolean; channels: NotificationChannel[] }[];
+    }) => {
+        const response = await apiClient.put<ApiResponse<NotificationPreferences>>('/notifications/preferences', data);
+        return response.data;
+    },
+
+    // EventSource can't send the bearer token, so the stream is read with fetch. Calls
+    // onEvent for each server-sent event until the signal aborts or the stream ends.
+    stream: async (onEvent: (event: string, data: unknown) => void, signal: AbortSignal) => {
+        const response = await fetch(`${apiClient.defaults.baseURL}/notifications/stream`, {
+            headers: { Authorization: `Bearer ${localStorage.getItem('access_token')}` },
+            signal,
+        });
+        if (!response.ok || !response.body) {
+            throw new Error(`Notification stream failed: ${response.status}`);
+        }
+
+        const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+        let buffer = '';
+        for (;;) {
+            const { value, done } = await reader.read();
+            if (done) return;
+            buffer += value;
+            let end;
+            while ((end = buffer.indexOf('\n\n')) >= 0) {
+                const block = buffer.slice(0, end);
+                buffer = buffer.slice(end + 2);
+                let event = 'message';
+                let data = '';
+                for (const line of block.split('\n')) {
+                    if (line.startsWith('event:')) event = line.slice(6).trim();
+                    if (line.startsWith('data:')) data += line.slice(5);
+                }
+                if (data) onEvent(event, JSON.parse(data));
+            }
+        }
+    },
 };
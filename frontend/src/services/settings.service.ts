@@ -1,5 +1,5 @@
 import apiClient from './api';
-import type { SystemSettings, ApiResponse } from '../types';
+import type { SystemSettings, ApiResponse, MessageTemplate, RenderedMessage } from '../types';
 
 export const settingsService = {
     get: async () => {
@@ -23,4 +23,28 @@ export const settingsService = {
         });
         return response.data;
     },
+
+    getTemplates: async () => {
+        const response = await apiClient.get<ApiResponse<{ templates: MessageTemplate[]; placeholders: Record<string, string> }>>('/message-templates');
+        return response.data;
+    },
+
+    updateTemplate: async (key: string, template: Partial<MessageTemplate>) => {
+        const response = await apiClient.put<ApiResponse<MessageTemplate>>(`/message-templates/${key}`, template);
+        return response.data;
+    },
+
+    resetTemplate: async (key: string) => {
+        const response = await apiClient.delete<ApiResponse<MessageTemplate>>(`/message-templates/${key}`);
+        return response.data;
+    },
+
+    previewTemplate: async (template: Partial<MessageTemplate>, language: 'ar' | 'en', customerId?: number) => {
+        const response = await apiClient.post<ApiResponse<RenderedMessage>>('/message-templates/preview', {
+            template,
+            language,
+            customer_id: customerId,
+        });
+        return response.data;
+    },
 };
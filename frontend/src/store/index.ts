@@ -7,7 +7,11 @@ interface AppState {
     unreadCount: number;
     setUser: (user: User | null) => void;
     setNotifications: (notifications: Notification[]) => void;
+    setUnreadCount: (count: number) => void;
+    addNotification: (notification: Notification) => void;
+    removeNotification: (id: number) => void;
     markAsRead: (id: number) => void;
+    markAllAsRead: () => void;
 }
 
 export const useAppStore = create<AppState>((set) => ({
@@ -20,12 +24,35 @@ export const useAppStore = create<AppState>((set) => ({
     setNotifications: (notifications) =>
         set({
             notifications,
-            unreadCount: notifications.filter((n) => !n.IsRead).length,
+            unreadCount: notifications.filter((n) => !n.is_read).length,
+        }),
+
+    setUnreadCount: (unreadCount) => set({ unreadCount }),
+
+    addNotification: (notification) =>
+        set((state) => ({
+            notifications: [notification, ...state.notifications.filter((n) => n.id !== notification.id)],
+            unreadCount: state.unreadCount + (notification.is_read ? 0 : 1),
+        })),
+
+    removeNotification: (id) =>
+        set((state) => {
+            const removed = state.notifications.find((n) => n.id === id);
+            return {
+                notifications: state.notifications.filter((n) => n.id !== id),
+                unreadCount: state.unreadCount - (removed && !removed.is_read ? 1 : 0),
+            };
         }),
 
     markAsRead: (id) =>
         set((state) => ({
-            notifications: state.notifications.map((n) => (n.ID === id ? { ...n, IsRead: true } : n)),
-            unreadCount: state.unreadCount - 1,
+            notifications: state.notifications.map((n) => (n.id === id ? { ...n, is_read: true } : n)),
+            unreadCount: Math.max(state.unreadCount - 1, 0),
+        })),
+
+    markAllAsRead: () =>
+        set((state) => ({
+            notifications: state.notifications.map((n) => ({ ...n, is_read: true })),
+            unreadCount: 0,
         })),
 }));
@@ -42,6 +42,8 @@ export interface Customer {
     type: 'regular' | 'vip' | 'wholesale';
     status: 'active' | 'inactive';
     is_whatsapp_enabled: boolean;
+    preferred_channel?: '' | 'whatsapp' | 'sms' | 'email';
+    language?: 'ar' | 'en';
     created_at: string;
     updated_at: string;
 }
@@ -49,23 +51,46 @@ export interface Customer {
 export interface CustomerActivity {
     id: number;
     customer_id: number;
-    type: 'note' | 'call' | 'meeting' | 'alert' | 'reminder';
+    type: 'note' | 'call' | 'meeting' | 'alert' | 'reminder' | 'whatsapp';
     description: string;
     reminder_date?: string;
     is_completed: boolean;
+    direction?: 'in'; // Set on WhatsApp messages the customer sent
     created_by: number;
     created_at: string;
 }
 
+export interface ConversationEntry {
+    direction: 'in' | 'out';
+    body: string;
+    status?: 'queued' | 'sent' | 'failed' | 'dead';
+    activity_id?: number;
+    message_id?: number;
+    at: string;
+}
+
 export interface CustomerDocument {
     id: number;
     customer_id: number;
     title: string;
-    file_path: string;
+    category: DocumentCategory;
     file_type: string;
+    original_name: string;
+    content_type: string;
+    size: number;
+    checksum: string;
+    has_thumbnail: boolean;
+    group_id: number;
+    version: number;
+    replaced_at?: string;
+    uploaded_by: number;
     uploaded_at: string;
+    deleted_at?: string;
+    deleted_by?: number;
 }
 
+export type DocumentCategory = 'contract' | 'measurement_sheet' | 'photo' | 'invoice' | 'other';
+
 // Sales Types
 export interface SalesOrder {
     id: number;
@@ -124,23 +149,80 @@ export interface ProductionOrder {
 
 // Notification Types
 export interface Notification {
-    ID: number;
-    UserID: number;
-    Title: string;
-    Message: string;
-    Type: 'info' | 'warning' | 'success' | 'error';
-    IsRead: boolean;
-    CreatedAt: string;
+    id: number;
+    user_id: number;
+    title: string;
+    message: string;
+    type: 'info' | 'warning' | 'success' | 'error';
+    link: string;
+    is_read: boolean;
+    created_at: string;
+}
+
+export type NotificationChannel = 'in_app' | 'email' | 'whatsapp';
+
+// A user's choice for one notification event; audience-only events only reach the users
+// they are about, such as a reminder's assignee
+export interface NotificationSubscription {
+    key: string;
+    name: string;
+    audience_only: boolean;
+    enabled: boolean;
+    channels: NotificationChannel[];
+    custom: boolean;
+}
+
+export interface NotificationPreferences {
+    quiet_hours_start: string;
+    quiet_hours_end: string;
+    whatsapp_number: string;
+    subscriptions: NotificationSubscription[];
 }
 
 // Settings Types
 export interface SystemSettings {
     company_name?: string;
     company_logo?: string;
+    company_logo_url?: string; // Signed link, absolute for object storage
     currency?: string;
     whatsapp_api_url?: string;
     whatsapp_api_token?: string;
     whatsapp_sender?: string;
+    whatsapp_provider?: 'webhook' | 'cloud';
+    whatsapp_phone_number_id?: string;
+    whatsapp_cloud_url?: string;
+    whatsapp_app_secret?: string;
+    whatsapp_verify_token?: string;
+    whatsapp_opt_out_keywords?: string;
+    sms_api_url?: string;
+    sms_api_token?: string;
+    sms_sender?: string;
+    smtp_host?: string;
+    smtp_port?: string;
+    smtp_username?: string;
+    smtp_password?: string;
+    smtp_from?: string;
+    messaging_channel_order?: string;
+    event_webhook_url?: string;
+    event_webhook_secret?: string;
+}
+
+export interface MessageTemplate {
+    id: number;
+    key: string;
+    name: string;
+    subject_ar: string;
+    body_ar: string;
+    subject_en: string;
+    body_en: string;
+    is_default: boolean;
+    updated_at: string;
+}
+
+export interface RenderedMessage {
+    language: 'ar' | 'en';
+    subject: string;
+    body: string;
 }
 
 // Branch Types
@@ -1,18 +1,45 @@
 import { useEffect } from 'react';
 import { notificationService } from '../services/notification.service';
 import { useAppStore } from '../store';
+import type { Notification } from '../types';
+
+// Delay before reconnecting a dropped notification stream
+const RECONNECT_DELAY = 5000;
 
 export function useNotifications() {
     const setNotifications = useAppStore((state) => state.setNotifications);
+    const setUnreadCount = useAppStore((state) => state.setUnreadCount);
+    const addNotification = useAppStore((state) => state.addNotification);
 
     useEffect(() => {
-        // Initial fetch
-        fetchNotifications();
+        const controller = new AbortController();
+        let retry: ReturnType<typeof setTimeout>;
 
-        // Poll every 30 seconds
-        const interval = setInterval(fetchNotifications, 30000);
+        // Initial fetch, then live updates pushed by the server
+        const connect = async () => {
+            await fetchNotifications();
+            try {
+                await notificationService.stream((event, data) => {
+                    if (event === 'unread_count') {
+                        setUnreadCount((data as { count: number }).count);
+                    } else if (event === 'notification') {
+                        addNotification(data as Notification);
+                    }
+                }, controller.signal);
+            } catch (error) {
+                if (controller.signal.aborted) return;
+                console.error('Notification stream dropped:', error);
+            }
+            if (!controller.signal.aborted) {
+                retry = setTimeout(connect, RECONNECT_DELAY);
+            }
+        };
+        connect();
 
-        return () => clearInterval(interval);
+        return () => {
+            controller.abort();
+            clearTimeout(retry);
+        };
     }, []);
 
     const fetchNotifications = async () => {